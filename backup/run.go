@@ -161,6 +161,7 @@ func initExportBackup() {
 		`If true, retrieve the CORS from DB and append at the end of GraphQL schema.
 		It also deletes the deprecated types and predicates.
 		Use this option when exporting a backup of 20.11 for loading onto 21.03.`)
+	flag.String("map", "", worker.MapOptionsHelp())
 	ee.RegisterEncFlag(flag)
 }
 
@@ -262,6 +263,10 @@ func runExportBackup() error {
 
 	encFlag := z.NewSuperFlag(ExportBackup.Conf.GetString("encryption")).
 		MergeAndCheckDefault(ee.EncDefaults)
+	mapOpts, err := worker.ParseMapOptions(ExportBackup.Conf.GetString("map"))
+	if err != nil {
+		return errors.Wrapf(err, "runExportBackup")
+	}
 	// TODO: Can probably make this procesing concurrent.
 	for gid := range latestManifest.Groups {
 		glog.Infof("Exporting group: %d", gid)
@@ -271,7 +276,7 @@ func runExportBackup() error {
 			EncryptionKeyFile: encFlag.GetPath("key-file"),
 			RestoreTs:         1,
 		}
-		if _, err := worker.RunMapper(req, mapDir); err != nil {
+		if _, err := worker.RunMapper(req, mapDir, mapOpts); err != nil {
 			return errors.Wrap(err, "Failed to map the backups")
 		}
 
@@ -538,6 +538,53 @@ const (
 		Set to true to allow backing up to S3 or Minio bucket that requires no credentials.
 		"""
 		anonymous: Boolean
+
+		"""
+		If schemaOnly is set to true then only the schema and the types are restored, without
+		any data, like for provisioning a replica of the schema.
+		"""
+		schemaOnly: Boolean
+
+		"""
+		Identifies the restore in the names of the map files and in the logs, to tell apart
+		the restores running on the same host.
+		"""
+		requestId: String
+
+		"""
+		Compression of the backup files of the groups, used instead of the compression
+		recorded in the manifests, for the backup sets whose files were recompressed.
+		"""
+		compressionOverrides: [CompressionOverride!]
+
+		"""
+		Fraction of the posting lists to restore, in (0, 1], for quick smoke tests of a restore
+		against a large backup. The same posting lists are picked every time, and the schema
+		and the types are always restored. If the value is zero or missing, all the data is
+		restored.
+		"""
+		sampleRate: Float
+
+		"""
+		Options of the map phase of the restore, as a superflag, e.g.
+		"skip-bad-keys=true; write-summary=true". See the help of the map flag of
+		dgraph export_backup for the available options. The options naming files on the
+		alphas, like heartbeat-file or checkpoint-file, are rejected.
+		"""
+		mapOptions: String
+	}
+
+	input CompressionOverride {
+
+		"""
+		ID of the group.
+		"""
+		groupId: Int!
+
+		"""
+		Compression of the backup files of the group, "gzip" or "snappy".
+		"""
+		compression: String!
 	}
 
 	type RestorePayload {
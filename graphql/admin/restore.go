@@ -48,6 +48,17 @@ type restoreInput struct {
 	VaultPath         string
 	VaultField        string
 	VaultFormat       string
+	SchemaOnly        bool
+	RequestId         string
+	// CompressionOverrides is a list, as GraphQL has no maps.
+	CompressionOverrides []compressionOverride
+	SampleRate           float64
+	MapOptions           string
+}
+
+type compressionOverride struct {
+	GroupId     uint32
+	Compression string
 }
 
 func resolveRestore(ctx context.Context, m schema.Mutation) (*resolve.Resolved, bool) {
@@ -56,8 +67,8 @@ func resolveRestore(ctx context.Context, m schema.Mutation) (*resolve.Resolved,
 		return resolve.EmptyResult(m, err), false
 	}
 	glog.Infof("Got restore request with location: %s, id: %s, num: %d, incrementalFrom: %d,"+
-		"isPartial: %v", input.Location, input.BackupId, input.BackupNum, input.IncrementalFrom,
-		input.IsPartial)
+		"isPartial: %v, requestId: %s", input.Location, input.BackupId, input.BackupNum,
+		input.IncrementalFrom, input.IsPartial, input.RequestId)
 
 	req := pb.RestoreRequest{
 		Location:          input.Location,
@@ -76,6 +87,16 @@ func resolveRestore(ctx context.Context, m schema.Mutation) (*resolve.Resolved,
 		VaultPath:         input.VaultPath,
 		VaultField:        input.VaultField,
 		VaultFormat:       input.VaultFormat,
+		SchemaOnly:        input.SchemaOnly,
+		RequestId:         input.RequestId,
+		SampleRate:        input.SampleRate,
+		MapOptions:        input.MapOptions,
+	}
+	for _, o := range input.CompressionOverrides {
+		if req.CompressionOverrides == nil {
+			req.CompressionOverrides = make(map[uint32]string)
+		}
+		req.CompressionOverrides[o.GroupId] = o.Compression
 	}
 
 	wg := &sync.WaitGroup{}
@@ -23,6 +23,7 @@ import (
 	"log"
 	"math"
 	"sort"
+	"sync"
 
 	"github.com/dgryski/go-farm"
 	"github.com/pkg/errors"
@@ -901,9 +902,32 @@ func (l *List) Length(readTs, afterUid uint64) int {
 // to be deleted, at which point the entire list will be marked for deletion.
 // As the list grows, existing parts might be split if they become too big.
 func (l *List) Rollup(alloc *z.Allocator) ([]*bpb.KV, error) {
+	return l.RollupWith(alloc, RollupOptions{})
+}
+
+// RollupOptions holds the options of RollupWith. The zero value rolls up the list the same
+// way as Rollup.
+type RollupOptions struct {
+	// Concurrency is the number of goroutines a list that needs to be split into multiple parts
+	// is split by. The output is identical to that of a single goroutine. This is only worth it
+	// for huge lists.
+	Concurrency int
+	// SplitSize, if set, replaces the default max list size as the size in bytes above which
+	// the parts are split, so the parts can be bigger than the default max list size as well
+	// as smaller. It is meant for the lists written for a cluster configured with another max
+	// list size.
+	SplitSize int
+	// MaxSize, if set, also splits the parts until they are smaller than MaxSize bytes, if it
+	// is below the split size. Only a part with more than one uid can be split, so a part with
+	// a single uid might be bigger than MaxSize.
+	MaxSize int
+}
+
+// RollupWith works like Rollup, with the options.
+func (l *List) RollupWith(alloc *z.Allocator, opts RollupOptions) ([]*bpb.KV, error) {
 	l.RLock()
 	defer l.RUnlock()
-	out, err := l.rollup(math.MaxUint64, true)
+	out, err := l.rollupWith(math.MaxUint64, true, opts)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed when calling List.rollup")
 	}
@@ -1037,6 +1061,10 @@ type rollupOutput struct {
 	parts    map[uint64]*pb.PostingList
 	newMinTs uint64
 	sranges  map[uint64]uint64
+	// splitSize, if set, replaces maxListSize as the size above which the parts are split.
+	splitSize int
+	// maxSize, if set, is the size above which the parts are split, in addition to maxListSize.
+	maxSize int
 }
 
 // A range contains [start, end], both inclusive. So, no overlap should exist
@@ -1069,21 +1097,46 @@ func (ro *rollupOutput) getRange(uid uint64) (uint64, uint64) {
 	return 1, math.MaxUint64
 }
 
+// MaxListSize returns the size in bytes above which a complete posting list is split into
+// parts by a rollup.
+func MaxListSize() int {
+	return maxListSize
+}
+
 func ShouldSplit(plist *pb.PostingList) bool {
-	if plist.Size() >= maxListSize {
+	return ShouldSplitAt(plist, maxListSize)
+}
+
+// ShouldSplitAt works like ShouldSplit, but with maxSize as the size above which the list
+// should be split.
+func ShouldSplitAt(plist *pb.PostingList, maxSize int) bool {
+	if plist.Size() >= maxSize {
 		r := sroar.FromBuffer(plist.Bitmap)
 		return r.GetCardinality() > 1
 	}
 	return false
 }
 
+// shouldSplit returns whether the part should be split, with ro.splitSize and ro.maxSize taken
+// into account.
+func (ro *rollupOutput) shouldSplit(plist *pb.PostingList) bool {
+	limit := maxListSize
+	if ro.splitSize > 0 {
+		limit = ro.splitSize
+	}
+	if ro.maxSize > 0 && ro.maxSize < limit {
+		limit = ro.maxSize
+	}
+	return ShouldSplitAt(plist, limit)
+}
+
 func (ro *rollupOutput) runSplits() error {
 	if len(ro.parts) == 0 {
 		ro.parts[1] = ro.plist
 	}
 top:
 	for startUid, pl := range ro.parts {
-		if ShouldSplit(pl) {
+		if ro.shouldSplit(pl) {
 			if err := ro.split(startUid); err != nil {
 				return err
 			}
@@ -1095,17 +1148,91 @@ top:
 }
 
 func (ro *rollupOutput) split(startUid uint64) error {
-	pl := ro.parts[startUid]
+	uid, newpl, err := splitList(ro.parts[startUid])
+	if err != nil {
+		return err
+	}
+	ro.parts[uid] = newpl
+	return nil
+}
+
+// runSplitsParallel splits the parts the same way as runSplits, but the two halves resulting
+// from a split are split further concurrently, using up to concurrency goroutines in total.
+// As every split only depends on the contents of the part being split, the resulting parts are
+// the same as the ones of runSplits.
+func (ro *rollupOutput) runSplitsParallel(concurrency int) error {
+	if len(ro.parts) == 0 {
+		ro.parts[1] = ro.plist
+	}
+	// The calling goroutine does its share of the work, so it doesn't need a slot.
+	sem := make(chan struct{}, concurrency-1)
 
+	var splitRec func(startUid uint64, pl *pb.PostingList) (map[uint64]*pb.PostingList, error)
+	splitRec = func(startUid uint64, pl *pb.PostingList) (map[uint64]*pb.PostingList, error) {
+		if !ro.shouldSplit(pl) {
+			return map[uint64]*pb.PostingList{startUid: pl}, nil
+		}
+		uid, newpl, err := splitList(pl)
+		if err != nil {
+			return nil, err
+		}
+
+		var upper map[uint64]*pb.PostingList
+		var upperErr error
+		var wg sync.WaitGroup
+		select {
+		case sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				upper, upperErr = splitRec(uid, newpl)
+			}()
+		default:
+			upper, upperErr = splitRec(uid, newpl)
+		}
+		lower, err := splitRec(startUid, pl)
+		wg.Wait()
+		if err != nil {
+			return nil, err
+		}
+		if upperErr != nil {
+			return nil, upperErr
+		}
+		for start, part := range upper {
+			lower[start] = part
+		}
+		return lower, nil
+	}
+
+	parts := make(map[uint64]*pb.PostingList, len(ro.parts))
+	for startUid, pl := range ro.parts {
+		res, err := splitRec(startUid, pl)
+		if err != nil {
+			return err
+		}
+		for start, part := range res {
+			parts[start] = part
+		}
+	}
+	ro.parts = parts
+	return nil
+}
+
+// splitList splits the list in two halves with the same number of uids. pl is updated to keep
+// the lower half, and the upper half is returned along with its start uid. The two halves
+// don't share any memory which is modified by a subsequent split.
+func splitList(pl *pb.PostingList) (uint64, *pb.PostingList, error) {
 	r := sroar.FromBuffer(pl.Bitmap)
 	num := r.GetCardinality()
 	uid, err := r.Select(uint64(num / 2))
 	if err != nil {
-		return errors.Wrapf(err, "split Select rank: %d", num/2)
+		return 0, nil, errors.Wrapf(err, "split Select rank: %d", num/2)
 	}
 
 	newpl := &pb.PostingList{}
-	ro.parts[uid] = newpl
 
 	// Remove everything from startUid to uid.
 	nr := r.Clone()
@@ -1123,7 +1250,7 @@ func (ro *rollupOutput) split(startUid uint64) error {
 	pl.Bitmap = r.ToBuffer()
 	pl.Postings = pl.Postings[:idx]
 
-	return nil
+	return uid, newpl, nil
 }
 
 /*
@@ -1202,6 +1329,11 @@ func (l *List) encode(out *rollupOutput, readTs uint64, split bool) error {
 // immutable layer. Note that readTs can be math.MaxUint64, so do NOT use it
 // directly. It should only serve as the read timestamp for iteration.
 func (l *List) rollup(readTs uint64, split bool) (*rollupOutput, error) {
+	return l.rollupWith(readTs, split, RollupOptions{})
+}
+
+// rollupWith works like rollup, with the options of RollupWith.
+func (l *List) rollupWith(readTs uint64, split bool, opts RollupOptions) (*rollupOutput, error) {
 	l.AssertRLock()
 
 	// Pick all committed entries
@@ -1214,7 +1346,9 @@ func (l *List) rollup(readTs uint64, split bool) (*rollupOutput, error) {
 		plist: &pb.PostingList{
 			Splits: l.plist.Splits,
 		},
-		parts: make(map[uint64]*pb.PostingList),
+		parts:     make(map[uint64]*pb.PostingList),
+		splitSize: opts.SplitSize,
+		maxSize:   opts.MaxSize,
 	}
 
 	if len(out.plist.Splits) > 0 || len(l.mutationMap) > 0 {
@@ -1248,7 +1382,13 @@ func (l *List) rollup(readTs uint64, split bool) (*rollupOutput, error) {
 	if split {
 		// Check if the list (or any of it's parts if it's been previously split) have
 		// become too big. Split the list if that is the case.
-		if err := out.runSplits(); err != nil {
+		runSplits := out.runSplits
+		if opts.Concurrency > 1 {
+			runSplits = func() error {
+				return out.runSplitsParallel(opts.Concurrency)
+			}
+		}
+		if err := runSplits(); err != nil {
 			return nil, err
 		}
 	} else {
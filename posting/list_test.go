@@ -17,6 +17,7 @@
 package posting
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
 	"math"
@@ -1495,6 +1496,45 @@ func TestSingleListRollup(t *testing.T) {
 	// TODO: Need more testing here.
 }
 
+func TestRollupParallel(t *testing.T) {
+	size := int(1e4)
+	ol, _ := createMultiPartList(t, size, true)
+
+	var bl pb.BackupPostingList
+	buf := z.NewBuffer(10<<10, "TestRollupParallel")
+	defer buf.Release()
+	_, err := ol.ToBackupPostingList(&bl, nil, buf)
+	require.NoError(t, err)
+
+	// For testing, set the max list size to a lower threshold.
+	defer setMaxListSize(maxListSize)
+	maxListSize = 5000
+
+	// Rollup modifies the list, so use a fresh copy of the complete list for every run.
+	key := x.DataKey(x.GalaxyAttr(uuid.New().String()), 1331)
+	kvs, err := NewList(key, FromBackupPostingList(&bl), 1).Rollup(nil)
+	require.NoError(t, err)
+	require.Greater(t, len(kvs), 2)
+	sortKVs := func(kvs []*bpb.KV) {
+		sort.Slice(kvs, func(i, j int) bool { return bytes.Compare(kvs[i].Key, kvs[j].Key) < 0 })
+	}
+	sortKVs(kvs)
+
+	for _, concurrency := range []int{2, 4, 16} {
+		pkvs, err := NewList(key, FromBackupPostingList(&bl), 1).RollupWith(nil,
+			RollupOptions{Concurrency: concurrency})
+		require.NoError(t, err)
+		sortKVs(pkvs)
+		require.Equal(t, len(kvs), len(pkvs))
+		for i := range kvs {
+			require.Equal(t, kvs[i].Key, pkvs[i].Key)
+			require.Equal(t, kvs[i].Value, pkvs[i].Value)
+			require.Equal(t, kvs[i].UserMeta, pkvs[i].UserMeta)
+			require.Equal(t, kvs[i].Version, pkvs[i].Version)
+		}
+	}
+}
+
 func TestRecursiveSplits(t *testing.T) {
 	// For testing, set the max list size to a lower threshold.
 	defer setMaxListSize(maxListSize)
@@ -233,6 +233,34 @@ func (SchemaUpdate_Directive) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_f80abaa17e25ccc8, []int{39, 0}
 }
 
+type SchemaUpdate_IndexRebuild int32
+
+const (
+	SchemaUpdate_UNSPECIFIED SchemaUpdate_IndexRebuild = 0
+	SchemaUpdate_EAGER       SchemaUpdate_IndexRebuild = 1
+	SchemaUpdate_LAZY        SchemaUpdate_IndexRebuild = 2
+)
+
+var SchemaUpdate_IndexRebuild_name = map[int32]string{
+	0: "UNSPECIFIED",
+	1: "EAGER",
+	2: "LAZY",
+}
+
+var SchemaUpdate_IndexRebuild_value = map[string]int32{
+	"UNSPECIFIED": 0,
+	"EAGER":       1,
+	"LAZY":        2,
+}
+
+func (x SchemaUpdate_IndexRebuild) String() string {
+	return proto.EnumName(SchemaUpdate_IndexRebuild_name, int32(x))
+}
+
+func (SchemaUpdate_IndexRebuild) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f80abaa17e25ccc8, []int{39, 1}
+}
+
 type NumLeaseType int32
 
 const (
@@ -2344,6 +2372,16 @@ type RestoreRequest struct {
 	BackupNum         uint64 `protobuf:"varint,16,opt,name=backup_num,json=backupNum,proto3" json:"backup_num,omitempty"`
 	IncrementalFrom   uint64 `protobuf:"varint,17,opt,name=incremental_from,json=incrementalFrom,proto3" json:"incremental_from,omitempty"`
 	IsPartial         bool   `protobuf:"varint,18,opt,name=is_partial,json=isPartial,proto3" json:"is_partial,omitempty"`
+	// Restores only the schema and the types, skipping all the data.
+	SchemaOnly bool `protobuf:"varint,19,opt,name=schema_only,json=schemaOnly,proto3" json:"schema_only,omitempty"`
+	// Identifies the restore in the names of the map files and in the logs.
+	RequestId string `protobuf:"bytes,20,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The compression of the backup files of a group, by group, over the one of the manifests.
+	CompressionOverrides map[uint32]string `protobuf:"bytes,21,rep,name=compression_overrides,json=compressionOverrides,proto3" json:"compression_overrides,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The fraction of the posting lists restored, in (0, 1], for smoke tests. Zero restores all.
+	SampleRate float64 `protobuf:"fixed64,22,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	// The options of the map phase, as a superflag, see worker.MapOptionsFromRequest.
+	MapOptions string `protobuf:"bytes,23,opt,name=map_options,json=mapOptions,proto3" json:"map_options,omitempty"`
 }
 
 func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
@@ -2505,6 +2543,41 @@ func (m *RestoreRequest) GetIsPartial() bool {
 	return false
 }
 
+func (m *RestoreRequest) GetSchemaOnly() bool {
+	if m != nil {
+		return m.SchemaOnly
+	}
+	return false
+}
+
+func (m *RestoreRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *RestoreRequest) GetCompressionOverrides() map[uint32]string {
+	if m != nil {
+		return m.CompressionOverrides
+	}
+	return nil
+}
+
+func (m *RestoreRequest) GetSampleRate() float64 {
+	if m != nil {
+		return m.SampleRate
+	}
+	return 0
+}
+
+func (m *RestoreRequest) GetMapOptions() string {
+	if m != nil {
+		return m.MapOptions
+	}
+	return ""
+}
+
 type Proposal struct {
 	Mutations *Mutations       `protobuf:"bytes,2,opt,name=mutations,proto3" json:"mutations,omitempty"`
 	Kv        []*pb.KV         `protobuf:"bytes,4,rep,name=kv,proto3" json:"kv,omitempty"`
@@ -3501,6 +3574,9 @@ type SchemaUpdate struct {
 	// name. This field stores said name.
 	ObjectTypeName string `protobuf:"bytes,12,opt,name=object_type_name,json=objectTypeName,proto3" json:"object_type_name,omitempty"`
 	NoConflict     bool   `protobuf:"varint,13,opt,name=no_conflict,json=noConflict,proto3" json:"no_conflict,omitempty"`
+	// index_rebuild is a hint to the process rebuilding the indexes after a restore. It is
+	// only set by the map phase of a restore.
+	IndexRebuild SchemaUpdate_IndexRebuild `protobuf:"varint,14,opt,name=index_rebuild,json=indexRebuild,proto3,enum=pb.SchemaUpdate_IndexRebuild" json:"index_rebuild,omitempty"`
 }
 
 func (m *SchemaUpdate) Reset()         { *m = SchemaUpdate{} }
@@ -3620,6 +3696,13 @@ func (m *SchemaUpdate) GetNoConflict() bool {
 	return false
 }
 
+func (m *SchemaUpdate) GetIndexRebuild() SchemaUpdate_IndexRebuild {
+	if m != nil {
+		return m.IndexRebuild
+	}
+	return SchemaUpdate_UNSPECIFIED
+}
+
 type TypeUpdate struct {
 	TypeName string          `protobuf:"bytes,1,opt,name=type_name,json=typeName,proto3" json:"type_name,omitempty"`
 	Fields   []*SchemaUpdate `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
@@ -3674,6 +3757,12 @@ func (m *TypeUpdate) GetFields() []*SchemaUpdate {
 
 type MapHeader struct {
 	PartitionKeys [][]byte `protobuf:"bytes,1,rep,name=partition_keys,json=partitionKeys,proto3" json:"partition_keys,omitempty"`
+	// format_version is the version of the map file format. Map files written before the
+	// version was recorded have it set to zero.
+	FormatVersion uint32 `protobuf:"varint,2,opt,name=format_version,json=formatVersion,proto3" json:"format_version,omitempty"`
+	// codec is the compression of the map file. It is empty for snappy, and set to "gzip" for
+	// gzip.
+	Codec string `protobuf:"bytes,3,opt,name=codec,proto3" json:"codec,omitempty"`
 }
 
 func (m *MapHeader) Reset()         { *m = MapHeader{} }
@@ -3716,6 +3805,20 @@ func (m *MapHeader) GetPartitionKeys() [][]byte {
 	return nil
 }
 
+func (m *MapHeader) GetFormatVersion() uint32 {
+	if m != nil {
+		return m.FormatVersion
+	}
+	return 0
+}
+
+func (m *MapHeader) GetCodec() string {
+	if m != nil {
+		return m.Codec
+	}
+	return ""
+}
+
 type MovePredicatePayload struct {
 	Predicate        string `protobuf:"bytes,1,opt,name=predicate,proto3" json:"predicate,omitempty"`
 	SourceGid        uint32 `protobuf:"varint,2,opt,name=source_gid,json=sourceGid,proto3" json:"source_gid,omitempty"`
@@ -4682,6 +4785,8 @@ func (m *BackupRequest) GetForceFull() bool {
 
 type BackupResponse struct {
 	DropOperations []*DropOperation `protobuf:"bytes,1,rep,name=drop_operations,json=dropOperations,proto3" json:"drop_operations,omitempty"`
+	// The hex encoded SHA-256 of the uncompressed, unencrypted backup file.
+	Checksum string `protobuf:"bytes,2,opt,name=checksum,proto3" json:"checksum,omitempty"`
 }
 
 func (m *BackupResponse) Reset()         { *m = BackupResponse{} }
@@ -4724,6 +4829,13 @@ func (m *BackupResponse) GetDropOperations() []*DropOperation {
 	return nil
 }
 
+func (m *BackupResponse) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
+}
+
 type DropOperation struct {
 	DropOp DropOperation_DropOp `protobuf:"varint,1,opt,name=drop_op,json=dropOp,proto3,enum=pb.DropOperation_DropOp" json:"drop_op,omitempty"`
 	// When drop_op is ATTR, drop_value will be the name of the ATTR; empty
@@ -5455,6 +5567,142 @@ func (m *TaskStatusResponse) GetTaskMeta() uint64 {
 	return 0
 }
 
+// RestoreProgressRequest asks an alpha for the progress of the map phase of a restore.
+type RestoreProgressRequest struct {
+	// The request_id of the RestoreRequest whose progress is streamed.
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+}
+
+func (m *RestoreProgressRequest) Reset()         { *m = RestoreProgressRequest{} }
+func (m *RestoreProgressRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreProgressRequest) ProtoMessage()    {}
+func (*RestoreProgressRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f80abaa17e25ccc8, []int{70}
+}
+func (m *RestoreProgressRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RestoreProgressRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RestoreProgressRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RestoreProgressRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreProgressRequest.Merge(m, src)
+}
+func (m *RestoreProgressRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *RestoreProgressRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreProgressRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreProgressRequest proto.InternalMessageInfo
+
+func (m *RestoreProgressRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+// RestoreProgress is an update of the progress of the map phase of a restore, streamed to the
+// client of the RestoreProgress RPC.
+type RestoreProgress struct {
+	BytesRead      uint64 `protobuf:"varint,1,opt,name=bytes_read,json=bytesRead,proto3" json:"bytes_read,omitempty"`
+	BytesProcessed uint64 `protobuf:"varint,2,opt,name=bytes_processed,json=bytesProcessed,proto3" json:"bytes_processed,omitempty"`
+	// rate is the number of bytes processed per second since the start of the map phase.
+	Rate uint64 `protobuf:"varint,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	// file_id is the id of the last map file created.
+	FileId uint32 `protobuf:"varint,4,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	// elapsed_ns is the time since the start of the map phase, in nanoseconds.
+	ElapsedNs int64 `protobuf:"varint,5,opt,name=elapsed_ns,json=elapsedNs,proto3" json:"elapsed_ns,omitempty"`
+	// done is set on the last update, sent once the map phase is over.
+	Done bool `protobuf:"varint,6,opt,name=done,proto3" json:"done,omitempty"`
+}
+
+func (m *RestoreProgress) Reset()         { *m = RestoreProgress{} }
+func (m *RestoreProgress) String() string { return proto.CompactTextString(m) }
+func (*RestoreProgress) ProtoMessage()    {}
+func (*RestoreProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f80abaa17e25ccc8, []int{71}
+}
+func (m *RestoreProgress) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RestoreProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RestoreProgress.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RestoreProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreProgress.Merge(m, src)
+}
+func (m *RestoreProgress) XXX_Size() int {
+	return m.Size()
+}
+func (m *RestoreProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreProgress proto.InternalMessageInfo
+
+func (m *RestoreProgress) GetBytesRead() uint64 {
+	if m != nil {
+		return m.BytesRead
+	}
+	return 0
+}
+
+func (m *RestoreProgress) GetBytesProcessed() uint64 {
+	if m != nil {
+		return m.BytesProcessed
+	}
+	return 0
+}
+
+func (m *RestoreProgress) GetRate() uint64 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+func (m *RestoreProgress) GetFileId() uint32 {
+	if m != nil {
+		return m.FileId
+	}
+	return 0
+}
+
+func (m *RestoreProgress) GetElapsedNs() int64 {
+	if m != nil {
+		return m.ElapsedNs
+	}
+	return 0
+}
+
+func (m *RestoreProgress) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("pb.DirectedEdge_Op", DirectedEdge_Op_name, DirectedEdge_Op_value)
 	proto.RegisterEnum("pb.Mutations_DropOp", Mutations_DropOp_name, Mutations_DropOp_value)
@@ -5462,6 +5710,7 @@ func init() {
 	proto.RegisterEnum("pb.Posting_ValType", Posting_ValType_name, Posting_ValType_value)
 	proto.RegisterEnum("pb.Posting_PostingType", Posting_PostingType_name, Posting_PostingType_value)
 	proto.RegisterEnum("pb.SchemaUpdate_Directive", SchemaUpdate_Directive_name, SchemaUpdate_Directive_value)
+	proto.RegisterEnum("pb.SchemaUpdate_IndexRebuild", SchemaUpdate_IndexRebuild_name, SchemaUpdate_IndexRebuild_value)
 	proto.RegisterEnum("pb.NumLeaseType", NumLeaseType_name, NumLeaseType_value)
 	proto.RegisterEnum("pb.DropOperation_DropOp", DropOperation_DropOp_name, DropOperation_DropOp_value)
 	proto.RegisterEnum("pb.BackupKey_KeyType", BackupKey_KeyType_name, BackupKey_KeyType_value)
@@ -5497,6 +5746,7 @@ func init() {
 	proto.RegisterType((*Snapshot)(nil), "pb.Snapshot")
 	proto.RegisterType((*ZeroSnapshot)(nil), "pb.ZeroSnapshot")
 	proto.RegisterType((*RestoreRequest)(nil), "pb.RestoreRequest")
+	proto.RegisterMapType((map[uint32]string)(nil), "pb.RestoreRequest.CompressionOverridesEntry")
 	proto.RegisterType((*Proposal)(nil), "pb.Proposal")
 	proto.RegisterType((*CDCState)(nil), "pb.CDCState")
 	proto.RegisterType((*KVS)(nil), "pb.KVS")
@@ -5544,349 +5794,369 @@ func init() {
 	proto.RegisterType((*DeleteNsRequest)(nil), "pb.DeleteNsRequest")
 	proto.RegisterType((*TaskStatusRequest)(nil), "pb.TaskStatusRequest")
 	proto.RegisterType((*TaskStatusResponse)(nil), "pb.TaskStatusResponse")
+	proto.RegisterType((*RestoreProgressRequest)(nil), "pb.RestoreProgressRequest")
+	proto.RegisterType((*RestoreProgress)(nil), "pb.RestoreProgress")
 }
 
 func init() { proto.RegisterFile("pb.proto", fileDescriptor_f80abaa17e25ccc8) }
 
 var fileDescriptor_f80abaa17e25ccc8 = []byte{
-	// 5383 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x7b, 0x4b, 0x6c, 0x1c, 0xe9,
-	0x71, 0x30, 0xe7, 0xdd, 0x5d, 0xf3, 0xe0, 0xf0, 0x93, 0x2c, 0x8f, 0xa9, 0x5d, 0x49, 0xdb, 0xb2,
-	0x76, 0xb9, 0xd2, 0x8a, 0x92, 0x28, 0x1b, 0xbf, 0x77, 0x0d, 0xff, 0x08, 0x1f, 0x43, 0x2d, 0x57,
-	0x14, 0x49, 0x37, 0x47, 0xf2, 0x03, 0x48, 0x06, 0xcd, 0xe9, 0x8f, 0x64, 0x9b, 0x3d, 0xdd, 0xed,
-	0xee, 0x1e, 0x9a, 0xf4, 0xcd, 0x08, 0x60, 0x23, 0x37, 0x1f, 0x73, 0xca, 0x21, 0xd7, 0x9c, 0xf3,
-	0x40, 0x90, 0xdc, 0x72, 0x08, 0x72, 0x89, 0x8f, 0x09, 0x92, 0x2c, 0x82, 0x75, 0x90, 0xc3, 0x1e,
-	0x02, 0x04, 0x39, 0x26, 0x87, 0xa0, 0xaa, 0xbe, 0x7e, 0xcd, 0x0c, 0xa5, 0x5d, 0x07, 0x39, 0xe4,
-	0x34, 0x5f, 0x55, 0x7d, 0xcf, 0xfa, 0xea, 0xab, 0x67, 0x0f, 0x68, 0xc1, 0xd1, 0x6a, 0x10, 0xfa,
-	0xb1, 0x2f, 0xca, 0xc1, 0xd1, 0xb2, 0x6e, 0x05, 0x0e, 0x83, 0xcb, 0xf7, 0x4f, 0x9c, 0xf8, 0x74,
-	0x72, 0xb4, 0x3a, 0xf2, 0xc7, 0x8f, 0xec, 0x93, 0xd0, 0x0a, 0x4e, 0x1f, 0x3a, 0xfe, 0xa3, 0x23,
-	0xcb, 0x3e, 0x91, 0xe1, 0xa3, 0xf3, 0xa7, 0x8f, 0x82, 0xa3, 0x47, 0xc9, 0xd0, 0xe5, 0x87, 0xb9,
-	0xbe, 0x27, 0xfe, 0x89, 0xff, 0x88, 0xd0, 0x47, 0x93, 0x63, 0x82, 0x08, 0xa0, 0x16, 0x77, 0x37,
-	0xfe, 0x3f, 0x54, 0x77, 0x9d, 0x28, 0x16, 0x37, 0xa0, 0x7e, 0xe4, 0xc4, 0x63, 0x2b, 0xe8, 0x95,
-	0xef, 0x94, 0x56, 0x5a, 0xa6, 0x82, 0xc4, 0x2d, 0x80, 0xc8, 0x0f, 0x63, 0x69, 0xbf, 0x74, 0xec,
-	0xa8, 0x57, 0xb9, 0x53, 0x59, 0xa9, 0x9b, 0x39, 0x8c, 0xf1, 0x02, 0xf4, 0x81, 0x15, 0x9d, 0xbd,
-	0xb2, 0xdc, 0x89, 0x14, 0x5d, 0xa8, 0x9c, 0x5b, 0x6e, 0xaf, 0x44, 0x33, 0x60, 0x53, 0xac, 0x82,
-	0x76, 0x6e, 0xb9, 0xc3, 0xf8, 0x32, 0x90, 0x34, 0x71, 0x67, 0xed, 0xda, 0x6a, 0x70, 0xb4, 0x7a,
-	0xe0, 0x47, 0xb1, 0xe3, 0x9d, 0xac, 0xbe, 0xb2, 0xdc, 0xc1, 0x65, 0x20, 0xcd, 0xc6, 0x39, 0x37,
-	0x8c, 0x7d, 0x68, 0x1e, 0x86, 0xa3, 0xed, 0x89, 0x37, 0x8a, 0x1d, 0xdf, 0x13, 0x02, 0xaa, 0x9e,
-	0x35, 0x96, 0x34, 0xa3, 0x6e, 0x52, 0x1b, 0x71, 0x56, 0x78, 0xc2, 0x7b, 0xd1, 0x4d, 0x6a, 0x8b,
-	0x1e, 0x34, 0x9c, 0x68, 0xd3, 0x9f, 0x78, 0x71, 0xaf, 0x7a, 0xa7, 0xb4, 0xa2, 0x99, 0x09, 0x68,
-	0xfc, 0x69, 0x05, 0x6a, 0xdf, 0x9d, 0xc8, 0xf0, 0x92, 0xc6, 0xc5, 0x71, 0x98, 0xcc, 0x85, 0x6d,
-	0x71, 0x1d, 0x6a, 0xae, 0xe5, 0x9d, 0x44, 0xbd, 0x32, 0x4d, 0xc6, 0x80, 0xb8, 0x09, 0xba, 0x75,
-	0x1c, 0xcb, 0x70, 0x38, 0x71, 0xec, 0x5e, 0xe5, 0x4e, 0x69, 0xa5, 0x6e, 0x6a, 0x84, 0x78, 0xe9,
-	0xd8, 0xe2, 0x6b, 0xa0, 0xd9, 0xfe, 0x70, 0x94, 0x5f, 0xcb, 0xf6, 0x69, 0x2d, 0x71, 0x17, 0xb4,
-	0x89, 0x63, 0x0f, 0x5d, 0x27, 0x8a, 0x7b, 0xb5, 0x3b, 0xa5, 0x95, 0xe6, 0x9a, 0x86, 0x87, 0x45,
-	0xfe, 0x9a, 0x8d, 0x89, 0x63, 0x13, 0xa3, 0xef, 0x83, 0x16, 0x85, 0xa3, 0xe1, 0xf1, 0xc4, 0x1b,
-	0xf5, 0xea, 0xd4, 0x69, 0x11, 0x3b, 0xe5, 0x4e, 0x6d, 0x36, 0x22, 0x06, 0xf0, 0x58, 0xa1, 0x3c,
-	0x97, 0x61, 0x24, 0x7b, 0x0d, 0x5e, 0x4a, 0x81, 0xe2, 0x31, 0x34, 0x8f, 0xad, 0x91, 0x8c, 0x87,
-	0x81, 0x15, 0x5a, 0xe3, 0x9e, 0x96, 0x4d, 0xb4, 0x8d, 0xe8, 0x03, 0xc4, 0x46, 0x26, 0x1c, 0xa7,
-	0x80, 0x78, 0x0a, 0x6d, 0x82, 0xa2, 0xe1, 0xb1, 0xe3, 0xc6, 0x32, 0xec, 0xe9, 0x34, 0xa6, 0x43,
-	0x63, 0x08, 0x33, 0x08, 0xa5, 0x34, 0x5b, 0xdc, 0x89, 0x31, 0xe2, 0x6d, 0x00, 0x79, 0x11, 0x58,
-	0x9e, 0x3d, 0xb4, 0x5c, 0xb7, 0x07, 0xb4, 0x07, 0x9d, 0x31, 0xeb, 0xae, 0x2b, 0xbe, 0x8a, 0xfb,
-	0xb3, 0xec, 0x61, 0x1c, 0xf5, 0xda, 0x77, 0x4a, 0x2b, 0x55, 0xb3, 0x8e, 0xe0, 0x20, 0x42, 0xbe,
-	0x8e, 0xac, 0xd1, 0xa9, 0xec, 0x75, 0xee, 0x94, 0x56, 0x6a, 0x26, 0x03, 0x88, 0x3d, 0x76, 0xc2,
-	0x28, 0xee, 0x2d, 0x32, 0x96, 0x00, 0x94, 0x3c, 0xff, 0xf8, 0x38, 0x92, 0x71, 0xaf, 0x4b, 0x68,
-	0x05, 0x19, 0x6b, 0xa0, 0x93, 0x54, 0x11, 0xd7, 0xee, 0x41, 0xfd, 0x1c, 0x81, 0xa8, 0x57, 0xba,
-	0x53, 0x59, 0x69, 0xae, 0xb5, 0x71, 0xdb, 0xa9, 0xe0, 0x99, 0x8a, 0x68, 0xdc, 0x02, 0x6d, 0xd7,
-	0xf2, 0x4e, 0x68, 0x88, 0x80, 0x2a, 0x5e, 0x27, 0x0d, 0xd0, 0x4d, 0x6a, 0x1b, 0xbf, 0x5f, 0x86,
-	0xba, 0x29, 0xa3, 0x89, 0x1b, 0x8b, 0xf7, 0x00, 0xf0, 0xb2, 0xc6, 0x56, 0x1c, 0x3a, 0x17, 0x6a,
-	0xd6, 0xec, 0xba, 0xf4, 0x89, 0x63, 0xbf, 0x20, 0x92, 0x78, 0x0c, 0x2d, 0x9a, 0x3d, 0xe9, 0x5a,
-	0xce, 0x36, 0x90, 0xee, 0xcf, 0x6c, 0x52, 0x17, 0x35, 0xe2, 0x06, 0xd4, 0x49, 0x3e, 0x58, 0x46,
-	0xdb, 0xa6, 0x82, 0xc4, 0x3d, 0xe8, 0x38, 0x5e, 0x8c, 0xf7, 0x37, 0x8a, 0x87, 0xb6, 0x8c, 0x12,
-	0x01, 0x6a, 0xa7, 0xd8, 0x2d, 0x19, 0xc5, 0xe2, 0x09, 0xf0, 0x25, 0x24, 0x0b, 0xd6, 0x68, 0xc1,
-	0x4e, 0x7a, 0xb9, 0x11, 0xaf, 0x48, 0x7d, 0xd4, 0x8a, 0x0f, 0xa1, 0x89, 0xe7, 0x4b, 0x46, 0xd4,
-	0x69, 0x44, 0x8b, 0x4e, 0xa3, 0xd8, 0x61, 0x02, 0x76, 0x50, 0xdd, 0x91, 0x35, 0x28, 0xa4, 0x2c,
-	0x54, 0xd4, 0x36, 0xfa, 0x50, 0xdb, 0x0f, 0x6d, 0x19, 0xce, 0x7d, 0x27, 0x02, 0xaa, 0xb6, 0x8c,
-	0x46, 0xf4, 0x84, 0x35, 0x93, 0xda, 0xd9, 0xdb, 0xa9, 0xe4, 0xde, 0x8e, 0xf1, 0x07, 0x25, 0x68,
-	0x1e, 0xfa, 0x61, 0xfc, 0x42, 0x46, 0x91, 0x75, 0x22, 0xc5, 0x6d, 0xa8, 0xf9, 0x38, 0xad, 0xe2,
-	0xb0, 0x8e, 0x7b, 0xa2, 0x75, 0x4c, 0xc6, 0x4f, 0xdd, 0x43, 0xf9, 0xea, 0x7b, 0x40, 0x99, 0xa2,
-	0x57, 0x57, 0x51, 0x32, 0x45, 0x6f, 0x2e, 0x93, 0x9e, 0x6a, 0x5e, 0x7a, 0xae, 0x14, 0x4d, 0xe3,
-	0x9b, 0x00, 0xb8, 0xbf, 0x2f, 0x29, 0x05, 0xc6, 0x2f, 0x4a, 0xd0, 0x34, 0xad, 0xe3, 0x78, 0xd3,
-	0xf7, 0x62, 0x79, 0x11, 0x8b, 0x0e, 0x94, 0x1d, 0x9b, 0x78, 0x54, 0x37, 0xcb, 0x8e, 0x8d, 0xbb,
-	0x3b, 0x09, 0xfd, 0x09, 0xab, 0xcf, 0xb6, 0xc9, 0x00, 0xf1, 0xd2, 0xb6, 0x43, 0xda, 0x32, 0xf2,
-	0xd2, 0xb6, 0x43, 0x71, 0x1b, 0x9a, 0x91, 0x67, 0x05, 0xd1, 0xa9, 0x1f, 0xe3, 0xee, 0xaa, 0xb4,
-	0x3b, 0x48, 0x50, 0x83, 0x08, 0x1f, 0x9d, 0x13, 0x0d, 0x5d, 0x69, 0x85, 0x9e, 0x0c, 0x49, 0x91,
-	0x68, 0xa6, 0xee, 0x44, 0xbb, 0x8c, 0x30, 0x7e, 0x51, 0x81, 0xfa, 0x0b, 0x39, 0x3e, 0x92, 0xe1,
-	0xcc, 0x26, 0x1e, 0x83, 0x46, 0xeb, 0x0e, 0x1d, 0x9b, 0xf7, 0xb1, 0xf1, 0x95, 0xcf, 0x3f, 0xbd,
-	0xbd, 0x44, 0xb8, 0x1d, 0xfb, 0x03, 0x7f, 0xec, 0xc4, 0x72, 0x1c, 0xc4, 0x97, 0x66, 0x43, 0xa1,
-	0xe6, 0x6e, 0xf0, 0x06, 0xd4, 0x5d, 0x69, 0xe1, 0x9d, 0xb1, 0x78, 0x2a, 0x48, 0x3c, 0x84, 0x86,
-	0x35, 0x1e, 0xda, 0xd2, 0xb2, 0x79, 0x53, 0x1b, 0xd7, 0x3f, 0xff, 0xf4, 0x76, 0xd7, 0x1a, 0x6f,
-	0x49, 0x2b, 0x3f, 0x77, 0x9d, 0x31, 0xe2, 0x43, 0x94, 0xc9, 0x28, 0x1e, 0x4e, 0x02, 0xdb, 0x8a,
-	0x25, 0xe9, 0xba, 0xea, 0x46, 0xef, 0xf3, 0x4f, 0x6f, 0x5f, 0x47, 0xf4, 0x4b, 0xc2, 0xe6, 0x86,
-	0x41, 0x86, 0x45, 0xbd, 0x97, 0x1c, 0x5f, 0xe9, 0x3d, 0x05, 0x8a, 0x1d, 0x58, 0x1a, 0xb9, 0x93,
-	0x08, 0x95, 0xb3, 0xe3, 0x1d, 0xfb, 0x43, 0xdf, 0x73, 0x2f, 0xe9, 0x82, 0xb5, 0x8d, 0xb7, 0x3f,
-	0xff, 0xf4, 0xf6, 0xd7, 0x14, 0x71, 0xc7, 0x3b, 0xf6, 0xf7, 0x3d, 0xf7, 0x32, 0x37, 0xff, 0xe2,
-	0x14, 0x49, 0xfc, 0x16, 0x74, 0x8e, 0xfd, 0x70, 0x24, 0x87, 0x29, 0xcb, 0x3a, 0x34, 0xcf, 0xf2,
-	0xe7, 0x9f, 0xde, 0xbe, 0x41, 0x94, 0x67, 0x33, 0x7c, 0x6b, 0xe5, 0xf1, 0xc6, 0x3f, 0x95, 0xa1,
-	0x46, 0x6d, 0xf1, 0x18, 0x1a, 0x63, 0xba, 0x92, 0x44, 0x3f, 0xdd, 0x40, 0x19, 0x22, 0xda, 0x2a,
-	0xdf, 0x55, 0xd4, 0xf7, 0xe2, 0xf0, 0xd2, 0x4c, 0xba, 0xe1, 0x88, 0xd8, 0x3a, 0x72, 0x65, 0x1c,
-	0x29, 0x99, 0xcf, 0x8d, 0x18, 0x30, 0x41, 0x8d, 0x50, 0xdd, 0xa6, 0xe5, 0xa6, 0x32, 0x23, 0x37,
-	0xcb, 0xa0, 0x8d, 0x4e, 0xe5, 0xe8, 0x2c, 0x9a, 0x8c, 0x95, 0x54, 0xa5, 0xb0, 0xb8, 0x0b, 0x6d,
-	0x6a, 0x07, 0xbe, 0xe3, 0xd1, 0xf0, 0x1a, 0x75, 0x68, 0x65, 0xc8, 0x41, 0xb4, 0xbc, 0x0d, 0xad,
-	0xfc, 0x66, 0xd1, 0x9c, 0x9f, 0xc9, 0x4b, 0x92, 0xaf, 0xaa, 0x89, 0x4d, 0x71, 0x07, 0x6a, 0xa4,
-	0xe8, 0x48, 0xba, 0x9a, 0x6b, 0x80, 0x7b, 0xe6, 0x21, 0x26, 0x13, 0x3e, 0x2a, 0x7f, 0xab, 0x84,
-	0xf3, 0xe4, 0x8f, 0x90, 0x9f, 0x47, 0xbf, 0x7a, 0x1e, 0x1e, 0x92, 0x9b, 0xc7, 0xf0, 0xa1, 0xb1,
-	0xeb, 0x8c, 0xa4, 0x17, 0x91, 0xd1, 0x9f, 0x44, 0x32, 0x55, 0x4a, 0xd8, 0xc6, 0xf3, 0x8e, 0xad,
-	0x8b, 0x3d, 0xdf, 0x96, 0x11, 0xcd, 0x53, 0x35, 0x53, 0x18, 0x69, 0xf2, 0x22, 0x70, 0xc2, 0xcb,
-	0x01, 0x73, 0xaa, 0x62, 0xa6, 0x30, 0x4a, 0x97, 0xf4, 0x70, 0x31, 0x3b, 0x31, 0xe0, 0x0a, 0x34,
-	0x7e, 0x5e, 0x85, 0xd6, 0x0f, 0x65, 0xe8, 0x1f, 0x84, 0x7e, 0xe0, 0x47, 0x96, 0x2b, 0xd6, 0x8b,
-	0x3c, 0xe7, 0xbb, 0xbd, 0x83, 0xbb, 0xcd, 0x77, 0x5b, 0x3d, 0x4c, 0x2f, 0x81, 0xef, 0x2c, 0x7f,
-	0x2b, 0x06, 0xd4, 0xf9, 0xce, 0xe7, 0xf0, 0x4c, 0x51, 0xb0, 0x0f, 0xdf, 0x32, 0xed, 0xb5, 0xc8,
-	0x0f, 0x45, 0xc1, 0x57, 0x39, 0xb6, 0x2e, 0x5e, 0xee, 0x6c, 0xa9, 0xbb, 0x55, 0x90, 0xe2, 0xc2,
-	0xe0, 0xc2, 0x1b, 0x24, 0x97, 0x9a, 0xc2, 0x78, 0x52, 0xe4, 0x48, 0xb4, 0xb3, 0xd5, 0x6b, 0x11,
-	0x29, 0x01, 0xc5, 0x5b, 0xa0, 0x8f, 0xad, 0x0b, 0x54, 0x68, 0x3b, 0x36, 0x3f, 0x4d, 0x33, 0x43,
-	0x88, 0x77, 0xa0, 0x12, 0x5f, 0x78, 0xf4, 0xf6, 0xd0, 0xab, 0x40, 0x47, 0x74, 0x70, 0xe1, 0x29,
-	0xd5, 0x67, 0x22, 0x0d, 0xef, 0x74, 0xe4, 0xd8, 0xe4, 0x44, 0xe8, 0x26, 0x36, 0xc5, 0x3d, 0x68,
-	0xb8, 0x7c, 0x5b, 0xe4, 0x28, 0x34, 0xd7, 0x9a, 0xac, 0x47, 0x09, 0x65, 0x26, 0x34, 0xf1, 0x01,
-	0x68, 0x09, 0x77, 0x7a, 0x4d, 0xea, 0xd7, 0x4d, 0xf8, 0x99, 0xb0, 0xd1, 0x4c, 0x7b, 0x88, 0xc7,
-	0xa0, 0xdb, 0xd2, 0x95, 0xb1, 0x1c, 0x7a, 0xac, 0xc8, 0x9b, 0xec, 0x40, 0x6e, 0x11, 0x72, 0x2f,
-	0x32, 0xe5, 0x8f, 0x27, 0x32, 0x8a, 0x4d, 0xcd, 0x56, 0x88, 0xe5, 0xef, 0xc0, 0xe2, 0xd4, 0x75,
-	0xe4, 0xe5, 0xaf, 0xcd, 0xf2, 0x77, 0x3d, 0x2f, 0x7f, 0xd5, 0x9c, 0xcc, 0x7d, 0x52, 0xd5, 0xb4,
-	0xae, 0x6e, 0xfc, 0x7b, 0x05, 0x16, 0xd5, 0x53, 0x38, 0x75, 0x82, 0xc3, 0x58, 0x29, 0x25, 0x32,
-	0x39, 0x4a, 0x0a, 0xab, 0x66, 0x02, 0x8a, 0xff, 0x07, 0x75, 0xd2, 0x21, 0xc9, 0x53, 0xbe, 0x9d,
-	0x5d, 0x71, 0x3a, 0x9c, 0x9f, 0xb6, 0x92, 0x0f, 0xd5, 0x5d, 0x7c, 0x03, 0x6a, 0x3f, 0x95, 0xa1,
-	0xcf, 0x26, 0xb4, 0xb9, 0x76, 0x6b, 0xde, 0x38, 0x64, 0x8c, 0x1a, 0xc6, 0x9d, 0xff, 0xa7, 0x92,
-	0x00, 0x5f, 0x46, 0x12, 0xbe, 0x8e, 0x66, 0x74, 0xec, 0x9f, 0x4b, 0xbb, 0xd7, 0xa0, 0x3d, 0xe6,
-	0xc5, 0x37, 0x21, 0x25, 0xc2, 0xa0, 0xcd, 0x15, 0x06, 0xfd, 0x6a, 0x61, 0x58, 0xde, 0x82, 0x66,
-	0x8e, 0x2f, 0x73, 0x2e, 0xea, 0x76, 0x51, 0x51, 0xe8, 0xa9, 0x92, 0xcc, 0xeb, 0x9b, 0x2d, 0x80,
-	0x8c, 0x4b, 0xbf, 0xa9, 0xd6, 0x32, 0x7e, 0x56, 0x82, 0xc5, 0x4d, 0xdf, 0xf3, 0x24, 0x39, 0xe1,
-	0x7c, 0xe7, 0xd9, 0xe3, 0x2d, 0x5d, 0xf9, 0x78, 0xdf, 0x87, 0x5a, 0x84, 0x9d, 0xd5, 0xec, 0xd7,
-	0xe6, 0x5c, 0xa2, 0xc9, 0x3d, 0x50, 0x85, 0x8f, 0xad, 0x8b, 0x61, 0x20, 0x3d, 0xdb, 0xf1, 0x4e,
-	0x12, 0x15, 0x3e, 0xb6, 0x2e, 0x0e, 0x18, 0x63, 0xfc, 0x59, 0x19, 0xe0, 0x63, 0x69, 0xb9, 0xf1,
-	0x29, 0x9a, 0x29, 0xbc, 0x51, 0xc7, 0x8b, 0x62, 0xcb, 0x1b, 0x25, 0x21, 0x50, 0x0a, 0xe3, 0x8d,
-	0xa2, 0xb5, 0x96, 0x11, 0x2b, 0x3f, 0xdd, 0x4c, 0x40, 0x94, 0x0f, 0x5c, 0x6e, 0x12, 0x29, 0xab,
-	0xae, 0xa0, 0xcc, 0x45, 0xa9, 0x12, 0x5a, 0xb9, 0x28, 0x3d, 0x68, 0x60, 0x48, 0xe1, 0xf8, 0x1e,
-	0x09, 0x8d, 0x6e, 0x26, 0x20, 0xce, 0x33, 0x09, 0x62, 0x67, 0xcc, 0xb6, 0xbb, 0x62, 0x2a, 0x08,
-	0x77, 0x85, 0xb6, 0xba, 0x3f, 0x3a, 0xf5, 0x49, 0x45, 0x54, 0xcc, 0x14, 0xc6, 0xd9, 0x7c, 0xef,
-	0xc4, 0xc7, 0xd3, 0x69, 0xe4, 0x16, 0x26, 0x20, 0x9f, 0xc5, 0x96, 0x17, 0x48, 0xd2, 0x89, 0x94,
-	0xc2, 0xc8, 0x17, 0x29, 0x87, 0xc7, 0xd2, 0x8a, 0x27, 0xa1, 0x8c, 0x7a, 0x40, 0x64, 0x90, 0x72,
-	0x5b, 0x61, 0xc4, 0x3b, 0xd0, 0x42, 0xc6, 0x59, 0x51, 0xe4, 0x9c, 0x78, 0xd2, 0x26, 0xc5, 0x51,
-	0x35, 0x91, 0x99, 0xeb, 0x0a, 0x65, 0xfc, 0x65, 0x19, 0xea, 0xac, 0x32, 0x0b, 0x6e, 0x50, 0xe9,
-	0x0b, 0xb9, 0x41, 0x6f, 0x81, 0x1e, 0x84, 0xd2, 0x76, 0x46, 0xc9, 0x3d, 0xea, 0x66, 0x86, 0xa0,
-	0xb8, 0x05, 0xed, 0x3e, 0xf1, 0x53, 0x33, 0x19, 0x10, 0x06, 0xb4, 0x7d, 0x6f, 0x68, 0x3b, 0xd1,
-	0xd9, 0xf0, 0xe8, 0x32, 0x96, 0x91, 0xe2, 0x45, 0xd3, 0xf7, 0xb6, 0x9c, 0xe8, 0x6c, 0x03, 0x51,
-	0xc8, 0x42, 0x7e, 0x23, 0xf4, 0x36, 0x34, 0x53, 0x41, 0xe2, 0x29, 0xe8, 0xe4, 0x9d, 0x92, 0xfb,
-	0xa2, 0x93, 0xdb, 0x71, 0xe3, 0xf3, 0x4f, 0x6f, 0x0b, 0x44, 0x4e, 0xf9, 0x2d, 0x5a, 0x82, 0x43,
-	0xff, 0x0b, 0x07, 0xa3, 0x21, 0xa2, 0x37, 0xcc, 0xfe, 0x17, 0xa2, 0x06, 0x51, 0xde, 0xff, 0x62,
-	0x8c, 0x78, 0x08, 0x62, 0xe2, 0x8d, 0xfc, 0x71, 0x80, 0x42, 0x21, 0x6d, 0xb5, 0xc9, 0x26, 0x6d,
-	0x72, 0x29, 0x4f, 0xa1, 0xad, 0x1a, 0xff, 0x58, 0x86, 0xd6, 0x96, 0x13, 0xca, 0x51, 0x2c, 0xed,
-	0xbe, 0x7d, 0x22, 0x71, 0xef, 0xd2, 0x8b, 0x9d, 0xf8, 0x52, 0x39, 0x98, 0x0a, 0x4a, 0xe3, 0x83,
-	0x72, 0x31, 0x8e, 0xe6, 0x17, 0x56, 0xa1, 0xd0, 0x9f, 0x01, 0xb1, 0x06, 0xc0, 0x91, 0x13, 0x85,
-	0xff, 0xd5, 0xab, 0xc3, 0x7f, 0x9d, 0xba, 0x61, 0x13, 0xc3, 0x6b, 0x1e, 0xe3, 0xb0, 0x97, 0x59,
-	0xa7, 0xdc, 0xc0, 0x44, 0xb2, 0xaf, 0x4a, 0x01, 0x5d, 0x83, 0x17, 0xc6, 0xb6, 0xb8, 0x0b, 0x65,
-	0x3f, 0x20, 0xe6, 0xaa, 0xa9, 0xf3, 0x47, 0x58, 0xdd, 0x0f, 0xcc, 0xb2, 0x1f, 0xe0, 0x2b, 0xe6,
-	0xa8, 0x96, 0x04, 0x0f, 0x5f, 0x31, 0x5a, 0x34, 0x8a, 0xa5, 0x4c, 0x45, 0x11, 0x06, 0xb4, 0x2c,
-	0xd7, 0xf5, 0x7f, 0x22, 0xed, 0x83, 0x50, 0xda, 0x89, 0x0c, 0x16, 0x70, 0x28, 0x25, 0x9e, 0x35,
-	0x96, 0x51, 0x60, 0x8d, 0xa4, 0x12, 0xc1, 0x0c, 0x61, 0xdc, 0x80, 0xf2, 0x7e, 0x20, 0x1a, 0x50,
-	0x39, 0xec, 0x0f, 0xba, 0x0b, 0xd8, 0xd8, 0xea, 0xef, 0x76, 0xd1, 0xa2, 0xd4, 0xbb, 0x0d, 0xe3,
-	0xb3, 0x32, 0xe8, 0x2f, 0x26, 0xb1, 0x85, 0xba, 0x25, 0xc2, 0x53, 0x16, 0x25, 0x34, 0x13, 0xc5,
-	0xaf, 0x81, 0x16, 0xc5, 0x56, 0x48, 0xfe, 0x06, 0x5b, 0xa7, 0x06, 0xc1, 0x83, 0x48, 0xbc, 0x0b,
-	0x35, 0x69, 0x9f, 0xc8, 0xc4, 0x5c, 0x74, 0xa7, 0xcf, 0x6b, 0x32, 0x59, 0xac, 0x40, 0x3d, 0x1a,
-	0x9d, 0xca, 0xb1, 0xd5, 0xab, 0x66, 0x1d, 0x0f, 0x09, 0xc3, 0x0e, 0xb6, 0xa9, 0xe8, 0xe2, 0xeb,
-	0x50, 0xc3, 0xbb, 0x89, 0x54, 0xc4, 0x48, 0x31, 0x26, 0x5e, 0x83, 0xea, 0xc6, 0x44, 0x14, 0x3c,
-	0x3b, 0xf4, 0x83, 0xa1, 0x1f, 0x10, 0xef, 0x3b, 0x6b, 0xd7, 0x49, 0xc7, 0x25, 0xa7, 0x59, 0xdd,
-	0x0a, 0xfd, 0x60, 0x3f, 0x30, 0xeb, 0x36, 0xfd, 0x62, 0xfc, 0x42, 0xdd, 0x59, 0x22, 0xd8, 0x28,
-	0xe8, 0x88, 0xe1, 0x24, 0xd1, 0x0a, 0x68, 0x63, 0x19, 0x5b, 0xb6, 0x15, 0x5b, 0xca, 0x36, 0xb4,
-	0x58, 0x65, 0x32, 0xce, 0x4c, 0xa9, 0xc6, 0x23, 0xa8, 0xf3, 0xd4, 0x42, 0x83, 0xea, 0xde, 0xfe,
-	0x5e, 0x9f, 0xd9, 0xba, 0xbe, 0xbb, 0xdb, 0x2d, 0x21, 0x6a, 0x6b, 0x7d, 0xb0, 0xde, 0x2d, 0x63,
-	0x6b, 0xf0, 0x83, 0x83, 0x7e, 0xb7, 0x62, 0xfc, 0x4d, 0x09, 0xb4, 0x64, 0x1e, 0xf1, 0x11, 0x00,
-	0x3e, 0xe1, 0xe1, 0xa9, 0xe3, 0xa5, 0xae, 0xdb, 0xcd, 0xfc, 0x4a, 0xab, 0x78, 0xab, 0x1f, 0x23,
-	0x95, 0xcd, 0x2b, 0xbd, 0x78, 0x82, 0x97, 0x0f, 0xa1, 0x53, 0x24, 0xce, 0xf1, 0x61, 0x1f, 0xe4,
-	0xad, 0x4a, 0x67, 0xed, 0x2b, 0x85, 0xa9, 0x71, 0x24, 0x89, 0x76, 0xce, 0xc0, 0x3c, 0x04, 0x2d,
-	0x41, 0x8b, 0x26, 0x34, 0xb6, 0xfa, 0xdb, 0xeb, 0x2f, 0x77, 0x51, 0x54, 0x00, 0xea, 0x87, 0x3b,
-	0x7b, 0xcf, 0x76, 0xfb, 0x7c, 0xac, 0xdd, 0x9d, 0xc3, 0x41, 0xb7, 0x6c, 0xfc, 0x49, 0x09, 0xb4,
-	0xc4, 0x93, 0x11, 0xef, 0xa3, 0xf3, 0x41, 0xee, 0x97, 0xb2, 0x44, 0x94, 0xeb, 0xc9, 0x05, 0xa4,
-	0x66, 0x42, 0xc7, 0xb7, 0x48, 0x8a, 0x35, 0xf1, 0x6d, 0x08, 0xc8, 0xc7, 0xc3, 0x95, 0x42, 0xaa,
-	0x06, 0x43, 0x7b, 0xdf, 0x93, 0xca, 0x15, 0xa6, 0x36, 0xc9, 0xa0, 0xe3, 0x8d, 0x64, 0x16, 0x28,
-	0x34, 0x08, 0x1e, 0xcc, 0x6a, 0xe2, 0xfa, 0xac, 0x26, 0x8e, 0xd9, 0x89, 0x4e, 0xf7, 0x9e, 0x6e,
-	0xa8, 0x94, 0xdf, 0xd0, 0x4c, 0x44, 0x52, 0x9e, 0x8d, 0x48, 0x32, 0xdb, 0x5a, 0x7b, 0x93, 0x6d,
-	0x35, 0xfe, 0xb3, 0x0a, 0x1d, 0x53, 0x46, 0xb1, 0x1f, 0x4a, 0xe5, 0x14, 0xbe, 0xee, 0x95, 0xbd,
-	0x0d, 0x10, 0x72, 0xe7, 0x6c, 0x69, 0x5d, 0x61, 0x38, 0x94, 0x72, 0xfd, 0x11, 0x89, 0xb7, 0x32,
-	0xa2, 0x29, 0x2c, 0x6e, 0x82, 0x7e, 0x64, 0x8d, 0xce, 0x78, 0x5a, 0x36, 0xa5, 0x1a, 0x23, 0x78,
-	0x5e, 0x6b, 0x34, 0x92, 0x51, 0x34, 0x44, 0x69, 0x61, 0x83, 0xaa, 0x33, 0xe6, 0xb9, 0xbc, 0x44,
-	0x72, 0x24, 0x47, 0xa1, 0x8c, 0x89, 0x5c, 0x67, 0x32, 0x63, 0x90, 0x7c, 0x17, 0xda, 0x91, 0x8c,
-	0xd0, 0xf8, 0x0e, 0x63, 0xff, 0x4c, 0x7a, 0x4a, 0xd5, 0xb5, 0x14, 0x72, 0x80, 0x38, 0xd4, 0x42,
-	0x96, 0xe7, 0x7b, 0x97, 0x63, 0x7f, 0x12, 0x29, 0xb3, 0x92, 0x21, 0xc4, 0x2a, 0x5c, 0x93, 0xde,
-	0x28, 0xbc, 0x0c, 0x70, 0xaf, 0xb8, 0xca, 0xf0, 0xd8, 0x71, 0xa5, 0xf2, 0xd3, 0x97, 0x32, 0xd2,
-	0x73, 0x79, 0xb9, 0xed, 0xb8, 0x12, 0x77, 0x74, 0x6e, 0x4d, 0xdc, 0x78, 0x48, 0x69, 0x00, 0xe0,
-	0x1d, 0x11, 0x66, 0xdd, 0xb6, 0x43, 0x71, 0x1f, 0x96, 0x98, 0x1c, 0xfa, 0xae, 0x74, 0x6c, 0x9e,
-	0xac, 0x49, 0xbd, 0x16, 0x89, 0x60, 0x12, 0x9e, 0xa6, 0x5a, 0x85, 0x6b, 0xdc, 0x97, 0x0f, 0x94,
-	0xf4, 0x6e, 0xf1, 0xd2, 0x44, 0x3a, 0x54, 0x94, 0xe2, 0xd2, 0x81, 0x15, 0x9f, 0x92, 0x73, 0x9f,
-	0x2c, 0x7d, 0x60, 0xc5, 0xa7, 0xe8, 0x14, 0x30, 0xf9, 0xd8, 0x91, 0x2e, 0x07, 0xe7, 0xba, 0xc9,
-	0x23, 0xb6, 0x11, 0x83, 0xa2, 0xa8, 0x3a, 0xf8, 0xe1, 0xd8, 0xe2, 0xac, 0xa2, 0x6e, 0xf2, 0xa0,
-	0x6d, 0x42, 0xe1, 0x12, 0xea, 0xae, 0xbc, 0xc9, 0x98, 0xf2, 0x8b, 0x55, 0x53, 0xdd, 0xde, 0xde,
-	0x64, 0x2c, 0xde, 0x87, 0xae, 0xe3, 0x8d, 0x42, 0x39, 0x96, 0x5e, 0x6c, 0xb9, 0xc3, 0xe3, 0xd0,
-	0x1f, 0xf7, 0x96, 0xa8, 0xd3, 0x62, 0x0e, 0xbf, 0x1d, 0xfa, 0x63, 0x95, 0x94, 0x09, 0xac, 0x30,
-	0x76, 0x2c, 0xb7, 0x27, 0x92, 0xa4, 0xcc, 0x01, 0x23, 0x8c, 0xff, 0xaa, 0x80, 0x96, 0x46, 0x8d,
-	0x0f, 0x40, 0x1f, 0x27, 0xca, 0x51, 0x79, 0x85, 0xed, 0x82, 0xc6, 0x34, 0x33, 0xba, 0x78, 0x1b,
-	0xca, 0x67, 0xe7, 0x4a, 0x51, 0xb7, 0x57, 0x39, 0xa7, 0x1f, 0x1c, 0x3d, 0x5d, 0x7d, 0xfe, 0xca,
-	0x2c, 0x9f, 0x9d, 0x7f, 0x89, 0x17, 0x20, 0xde, 0x83, 0xc5, 0x91, 0x2b, 0x2d, 0x6f, 0x98, 0xb9,
-	0x32, 0x2c, 0x61, 0x1d, 0x42, 0x1f, 0xa4, 0xfe, 0xcc, 0x3d, 0xa8, 0xd9, 0xd2, 0x8d, 0xad, 0x7c,
-	0xda, 0x78, 0x3f, 0xb4, 0x46, 0xae, 0xdc, 0x42, 0xb4, 0xc9, 0x54, 0x54, 0xd4, 0x69, 0xa4, 0x96,
-	0x53, 0xd4, 0x73, 0xa2, 0xb4, 0xf4, 0x85, 0x43, 0xfe, 0x85, 0x3f, 0x80, 0x25, 0x79, 0x11, 0x90,
-	0x75, 0x1a, 0xa6, 0x89, 0x09, 0x36, 0x9b, 0xdd, 0x84, 0xb0, 0x99, 0x24, 0x28, 0x3e, 0x40, 0xfd,
-	0x44, 0xcf, 0x8f, 0x04, 0xa6, 0xb9, 0x26, 0x48, 0xc1, 0x15, 0x1e, 0xb4, 0x99, 0x74, 0x11, 0xef,
-	0x83, 0x3e, 0xb2, 0x47, 0x43, 0xe6, 0x4c, 0x3b, 0xdb, 0xdb, 0xe6, 0xd6, 0x26, 0xb3, 0x44, 0x1b,
-	0xd9, 0x23, 0x76, 0xe1, 0x0b, 0x11, 0x64, 0xe7, 0x0b, 0x44, 0x90, 0x89, 0xaa, 0x5f, 0xcc, 0x02,
-	0x88, 0xbc, 0x4d, 0xee, 0x16, 0x6c, 0xf2, 0x27, 0x55, 0xad, 0xd1, 0xd5, 0x8c, 0xbb, 0xa0, 0x25,
-	0x4b, 0xa3, 0xa6, 0x8d, 0xa4, 0xa7, 0xf2, 0x05, 0xa4, 0x69, 0x11, 0x1c, 0x44, 0xc6, 0x08, 0x2a,
-	0xcf, 0x5f, 0x1d, 0x92, 0xc2, 0x45, 0xdb, 0x57, 0x23, 0x57, 0x89, 0xda, 0xa9, 0x12, 0x2e, 0xe7,
-	0x94, 0xf0, 0x2d, 0xb6, 0x5f, 0x74, 0x65, 0x49, 0x92, 0x35, 0x87, 0x41, 0xa6, 0xb3, 0xed, 0xae,
-	0x72, 0xfe, 0x95, 0x00, 0xe3, 0x5f, 0x2b, 0xd0, 0x50, 0xee, 0x15, 0x1e, 0x64, 0x92, 0xe6, 0x07,
-	0xb1, 0x59, 0x8c, 0x7b, 0x53, 0x3f, 0x2d, 0x5f, 0xa4, 0xa9, 0xbc, 0xb9, 0x48, 0x23, 0x3e, 0x82,
-	0x56, 0xc0, 0xb4, 0xbc, 0x67, 0xf7, 0xd5, 0xfc, 0x18, 0xf5, 0x4b, 0xe3, 0x9a, 0x41, 0x06, 0x20,
-	0x2b, 0x29, 0x53, 0x1d, 0x5b, 0x27, 0x8a, 0x03, 0x0d, 0x84, 0x07, 0xd6, 0xc9, 0x17, 0x72, 0xd3,
-	0x3a, 0xe4, 0xef, 0xb5, 0x48, 0x99, 0xa3, 0x6b, 0x97, 0xbf, 0x99, 0x76, 0xd1, 0x5b, 0xba, 0x09,
-	0xfa, 0xc8, 0x1f, 0x8f, 0x1d, 0xa2, 0x75, 0x54, 0x3e, 0x8c, 0x10, 0x83, 0xc8, 0xf8, 0x79, 0x09,
-	0x1a, 0xea, 0x5c, 0x33, 0xb6, 0x78, 0x63, 0x67, 0x6f, 0xdd, 0xfc, 0x41, 0xb7, 0x84, 0xbe, 0xc6,
-	0xce, 0xde, 0xa0, 0x5b, 0x16, 0x3a, 0xd4, 0xb6, 0x77, 0xf7, 0xd7, 0x07, 0xdd, 0x0a, 0xda, 0xe7,
-	0x8d, 0xfd, 0xfd, 0xdd, 0x6e, 0x55, 0xb4, 0x40, 0xdb, 0x5a, 0x1f, 0xf4, 0x07, 0x3b, 0x2f, 0xfa,
-	0xdd, 0x1a, 0xf6, 0x7d, 0xd6, 0xdf, 0xef, 0xd6, 0xb1, 0xf1, 0x72, 0x67, 0xab, 0xdb, 0x40, 0xfa,
-	0xc1, 0xfa, 0xe1, 0xe1, 0xf7, 0xf6, 0xcd, 0xad, 0xae, 0x46, 0x36, 0x7e, 0x60, 0xee, 0xec, 0x3d,
-	0xeb, 0xea, 0xd8, 0xde, 0xdf, 0xf8, 0xa4, 0xbf, 0x39, 0xe8, 0x82, 0xf1, 0x04, 0x9a, 0x39, 0x5e,
-	0xe1, 0x68, 0xb3, 0xbf, 0xdd, 0x5d, 0xc0, 0x25, 0x5f, 0xad, 0xef, 0xbe, 0x44, 0x97, 0xa0, 0x03,
-	0x40, 0xcd, 0xe1, 0xee, 0xfa, 0xde, 0xb3, 0x6e, 0x59, 0x39, 0x94, 0xbf, 0x57, 0x4a, 0x47, 0x52,
-	0xb9, 0xe3, 0x3d, 0xd0, 0x14, 0x9f, 0x93, 0x34, 0x44, 0x33, 0x77, 0x21, 0x66, 0x4a, 0x2c, 0xf2,
-	0xa5, 0x52, 0xe4, 0x0b, 0xc5, 0x8e, 0x81, 0xeb, 0xc4, 0x2c, 0x55, 0x28, 0xbb, 0x04, 0xe5, 0xca,
-	0x83, 0xb5, 0x7c, 0x79, 0xf0, 0x93, 0xaa, 0x56, 0xea, 0x96, 0x8d, 0x6f, 0x00, 0x64, 0x65, 0xa7,
-	0x39, 0xae, 0xd2, 0x75, 0xa8, 0x59, 0xae, 0x63, 0x25, 0x91, 0x2a, 0x03, 0xc6, 0x1e, 0x34, 0x73,
-	0xc5, 0x2a, 0xbc, 0x4a, 0xcb, 0x75, 0xd1, 0x64, 0xf1, 0xc3, 0xd1, 0xcc, 0x86, 0xe5, 0xba, 0xcf,
-	0xe5, 0x65, 0x84, 0x6e, 0x2a, 0xd7, 0xb9, 0xca, 0x53, 0xa5, 0x10, 0x1a, 0x6a, 0x32, 0xd1, 0xf8,
-	0x00, 0xea, 0xdb, 0x89, 0x33, 0x9f, 0x48, 0x52, 0xe9, 0x2a, 0x49, 0x32, 0x3e, 0x54, 0x7b, 0xa6,
-	0x6a, 0x8a, 0x78, 0xa0, 0xea, 0x69, 0x11, 0x57, 0xef, 0x4a, 0x59, 0xae, 0x83, 0x3b, 0xa9, 0x52,
-	0x1a, 0x75, 0x36, 0xb6, 0x40, 0x7b, 0x6d, 0x85, 0x52, 0x31, 0xa0, 0x9c, 0x31, 0x60, 0x4e, 0xcd,
-	0xd2, 0xf8, 0x11, 0x40, 0x56, 0x77, 0x53, 0x82, 0xcd, 0xb3, 0xa0, 0x60, 0xdf, 0x07, 0x6d, 0x74,
-	0xea, 0xb8, 0x76, 0x28, 0xbd, 0xc2, 0xa9, 0xb3, 0x4a, 0x5d, 0x4a, 0x17, 0x77, 0xa0, 0x4a, 0xe5,
-	0xc4, 0x4a, 0xa6, 0x08, 0xd3, 0x5a, 0x22, 0x51, 0x8c, 0x0b, 0x68, 0xb3, 0xff, 0xff, 0x05, 0x5c,
-	0xa3, 0xa2, 0xde, 0x29, 0xcf, 0xe8, 0x9d, 0x1b, 0x50, 0x27, 0x8b, 0x9c, 0x9c, 0x46, 0x41, 0x57,
-	0xe8, 0xa3, 0xdf, 0x2d, 0x03, 0xf0, 0xd2, 0x7b, 0xbe, 0x2d, 0x8b, 0x81, 0x76, 0x69, 0x3a, 0xd0,
-	0x16, 0x50, 0x4d, 0x2b, 0xc5, 0xba, 0x49, 0xed, 0xcc, 0xb6, 0xa8, 0xe0, 0x9b, 0x6d, 0xcb, 0x5b,
-	0xa0, 0x93, 0x87, 0xe4, 0xfc, 0x94, 0xca, 0x14, 0xb8, 0x60, 0x86, 0xc8, 0xd7, 0x4d, 0x6b, 0xc5,
-	0xba, 0x69, 0x5a, 0x44, 0xaa, 0xf3, 0x6c, 0x5c, 0x44, 0x9a, 0x53, 0x0f, 0xe3, 0xec, 0x47, 0x24,
-	0xc3, 0x38, 0x09, 0xdd, 0x19, 0x4a, 0xa3, 0x50, 0x5d, 0xf5, 0xb5, 0x38, 0x7f, 0xe1, 0xf9, 0xc3,
-	0x91, 0xef, 0x1d, 0xbb, 0xce, 0x28, 0x56, 0x75, 0x52, 0xf0, 0xfc, 0x4d, 0x85, 0x31, 0x3e, 0x82,
-	0x56, 0xc2, 0x7f, 0x2a, 0x3b, 0xdd, 0x4f, 0x23, 0xb4, 0x52, 0x76, 0xb7, 0x19, 0x9b, 0x36, 0xca,
-	0xbd, 0x52, 0x12, 0xa3, 0x19, 0xff, 0x51, 0x49, 0x06, 0xab, 0xea, 0xc8, 0xeb, 0x79, 0x58, 0x0c,
-	0xba, 0xcb, 0x5f, 0x28, 0xe8, 0xfe, 0x16, 0xe8, 0x36, 0xc5, 0x91, 0xce, 0x79, 0x62, 0x01, 0x96,
-	0xa7, 0x63, 0x46, 0x15, 0x69, 0x3a, 0xe7, 0xd2, 0xcc, 0x3a, 0xbf, 0xe1, 0x1e, 0x52, 0x6e, 0xd7,
-	0xe6, 0x71, 0xbb, 0xfe, 0x1b, 0x72, 0xfb, 0x1d, 0x68, 0x79, 0xbe, 0x37, 0xf4, 0x26, 0xae, 0x6b,
-	0x1d, 0xb9, 0x52, 0xb1, 0xbb, 0xe9, 0xf9, 0xde, 0x9e, 0x42, 0xa1, 0xdb, 0x9a, 0xef, 0xc2, 0x8f,
-	0xba, 0x49, 0xfd, 0x16, 0x73, 0xfd, 0xe8, 0xe9, 0xaf, 0x40, 0xd7, 0x3f, 0xfa, 0x91, 0x1c, 0xc5,
-	0xc4, 0xb1, 0x21, 0xbd, 0x66, 0xf6, 0x59, 0x3b, 0x8c, 0x47, 0x16, 0xed, 0xe1, 0xbb, 0x9e, 0xba,
-	0xe6, 0xf6, 0xcc, 0x35, 0x7f, 0x08, 0x7a, 0xca, 0xa5, 0x5c, 0xcc, 0xaa, 0x43, 0x6d, 0x67, 0x6f,
-	0xab, 0xff, 0xfd, 0x6e, 0x09, 0x6d, 0x8d, 0xd9, 0x7f, 0xd5, 0x37, 0x0f, 0xfb, 0xdd, 0x32, 0xda,
-	0x81, 0xad, 0xfe, 0x6e, 0x7f, 0xd0, 0xef, 0x56, 0xd8, 0x8f, 0xa0, 0x22, 0x85, 0xeb, 0x8c, 0x9c,
-	0xd8, 0x38, 0x04, 0xc8, 0x02, 0x71, 0xd4, 0xd9, 0xd9, 0xe6, 0x54, 0x26, 0x30, 0x4e, 0xb6, 0xb5,
-	0x92, 0x3e, 0xc8, 0xf2, 0x55, 0xe1, 0x3e, 0xd3, 0x8d, 0x35, 0xd0, 0x5f, 0x58, 0xc1, 0xc7, 0x5c,
-	0xce, 0xbb, 0x07, 0x1d, 0x72, 0x67, 0x93, 0x40, 0x81, 0x95, 0x65, 0xcb, 0x6c, 0xa7, 0x58, 0xd4,
-	0xbd, 0xc6, 0xdf, 0x96, 0xe0, 0xfa, 0x0b, 0xff, 0x5c, 0xa6, 0xee, 0xe3, 0x81, 0x75, 0xe9, 0xfa,
-	0x96, 0xfd, 0x06, 0x31, 0xc4, 0x48, 0xc7, 0x9f, 0x50, 0x79, 0x2d, 0x29, 0x46, 0x9a, 0x3a, 0x63,
-	0x9e, 0xa9, 0xaf, 0x28, 0x64, 0x14, 0x13, 0xb1, 0xc2, 0xfa, 0x07, 0x61, 0x24, 0xe5, 0x22, 0xd5,
-	0x6a, 0x21, 0x52, 0x9d, 0xeb, 0x4f, 0xd6, 0xae, 0xf0, 0x27, 0xf3, 0x21, 0x6c, 0xbd, 0x10, 0xc2,
-	0x1a, 0x9b, 0xa0, 0x0f, 0x2e, 0x28, 0xc1, 0x3b, 0x89, 0x0a, 0x0e, 0x44, 0xe9, 0x35, 0x0e, 0x44,
-	0x79, 0xca, 0x81, 0xf8, 0x97, 0x12, 0x34, 0x73, 0x3e, 0xb3, 0x78, 0x07, 0xaa, 0xf1, 0x85, 0x57,
-	0xfc, 0x3c, 0x21, 0x59, 0xc4, 0x24, 0xd2, 0x4c, 0xe8, 0x5c, 0x9e, 0x09, 0x9d, 0xc5, 0x2e, 0x2c,
-	0xb2, 0x5a, 0x4e, 0xce, 0x97, 0xe4, 0x7a, 0xee, 0x4e, 0xf9, 0xe8, 0x9c, 0x04, 0x4f, 0x4e, 0xab,
-	0x12, 0x18, 0x9d, 0x93, 0x02, 0x72, 0x79, 0x1d, 0xae, 0xcd, 0xe9, 0xf6, 0x65, 0xca, 0x21, 0xc6,
-	0x6d, 0x68, 0x0f, 0x2e, 0xbc, 0x81, 0x33, 0x96, 0x51, 0x6c, 0x8d, 0x03, 0x72, 0xc0, 0x94, 0x59,
-	0xad, 0x9a, 0xe5, 0x38, 0x32, 0xde, 0x85, 0xd6, 0x81, 0x94, 0xa1, 0x29, 0xa3, 0xc0, 0xf7, 0x22,
-	0x99, 0x4b, 0x3e, 0xb3, 0x0d, 0x57, 0x90, 0xf1, 0x3b, 0xa0, 0x9b, 0xd6, 0x71, 0xbc, 0x61, 0xc5,
-	0xa3, 0xd3, 0x2f, 0x93, 0xcd, 0x78, 0x17, 0x1a, 0x01, 0x0b, 0x9c, 0x8a, 0xa4, 0x5a, 0x64, 0xcb,
-	0x95, 0x10, 0x9a, 0x09, 0xd1, 0xf8, 0x6d, 0xb8, 0x76, 0x38, 0x39, 0x8a, 0x46, 0xa1, 0x43, 0xe1,
-	0x6d, 0x62, 0xe7, 0x96, 0x41, 0x0b, 0x42, 0x79, 0xec, 0x5c, 0xc8, 0x44, 0xbc, 0x53, 0x58, 0xdc,
-	0x87, 0xc6, 0x18, 0xb7, 0x23, 0xb3, 0x87, 0x93, 0x85, 0x5f, 0x2f, 0x90, 0x62, 0x26, 0x1d, 0x8c,
-	0x6f, 0xc3, 0xf5, 0xe2, 0xf4, 0xea, 0xb8, 0x77, 0xa1, 0x72, 0x76, 0x1e, 0xa9, 0x53, 0x2c, 0x15,
-	0xc2, 0x37, 0xfa, 0x82, 0x00, 0xa9, 0xc6, 0x9f, 0x97, 0xa0, 0x82, 0xe1, 0x66, 0xee, 0xf3, 0xa8,
-	0x2a, 0x7f, 0x1e, 0x75, 0x33, 0x9f, 0x07, 0x66, 0xe7, 0x3f, 0xcb, 0xf7, 0xbe, 0x05, 0xfa, 0xb1,
-	0x1f, 0xfe, 0xc4, 0x0a, 0x6d, 0x69, 0x2b, 0xeb, 0x97, 0x21, 0x50, 0x33, 0x1e, 0x4d, 0xc6, 0x81,
-	0x52, 0xad, 0xd4, 0x16, 0xf7, 0x94, 0xfd, 0x64, 0x87, 0x7c, 0x09, 0x99, 0xba, 0x37, 0x19, 0xaf,
-	0xba, 0xd2, 0x8a, 0x48, 0xd1, 0xb3, 0x49, 0x35, 0x1e, 0x80, 0x9e, 0xa2, 0x50, 0x39, 0xed, 0x1d,
-	0x0e, 0x77, 0xb6, 0x38, 0xb7, 0x86, 0xae, 0x6b, 0x09, 0x15, 0xd3, 0xe0, 0xfb, 0x7b, 0xc3, 0xc1,
-	0x61, 0xb7, 0x6c, 0xfc, 0x10, 0x9a, 0x89, 0x78, 0xee, 0xd8, 0x54, 0x48, 0xa2, 0xf7, 0xb1, 0x63,
-	0x17, 0x9e, 0xcb, 0x0e, 0xc5, 0x16, 0xd2, 0xb3, 0x77, 0x12, 0xb9, 0x66, 0xa0, 0x78, 0x42, 0x55,
-	0x95, 0x4a, 0x4e, 0x68, 0xf4, 0x61, 0xc9, 0xa4, 0x84, 0x38, 0x1a, 0xbd, 0xe4, 0xca, 0x6e, 0x40,
-	0xdd, 0xf3, 0x6d, 0x99, 0x2e, 0xa0, 0x20, 0x5c, 0x59, 0xb9, 0x28, 0x4a, 0x9d, 0x24, 0xa0, 0x21,
-	0x61, 0x09, 0x35, 0x94, 0x2a, 0x98, 0xaa, 0x69, 0x0a, 0xc9, 0xda, 0xd2, 0x54, 0xb2, 0x16, 0x17,
-	0x51, 0x15, 0x57, 0xf6, 0x35, 0x92, 0x2a, 0xeb, 0x32, 0x68, 0x76, 0x14, 0xd3, 0xab, 0x51, 0x7a,
-	0x29, 0x85, 0x8d, 0x47, 0x70, 0x6d, 0x3d, 0x08, 0xdc, 0xcb, 0xa4, 0x8a, 0xa5, 0x16, 0xea, 0x65,
-	0xa5, 0xae, 0x92, 0x0a, 0x68, 0x18, 0x34, 0xb6, 0xa1, 0x95, 0x04, 0xcb, 0x2f, 0x64, 0x6c, 0x91,
-	0x42, 0x71, 0x9d, 0x42, 0x6c, 0xa8, 0x31, 0x62, 0x50, 0x4c, 0x09, 0x4f, 0x9d, 0x6f, 0x15, 0xea,
-	0x4a, 0x5b, 0x09, 0xa8, 0x8e, 0x7c, 0x9b, 0x17, 0xaa, 0x99, 0xd4, 0x46, 0xa9, 0x1a, 0x47, 0x27,
-	0x89, 0xb7, 0x39, 0x8e, 0x4e, 0x8c, 0xbf, 0x2f, 0x43, 0x7b, 0x83, 0x92, 0x1c, 0xc9, 0x1e, 0x73,
-	0x3a, 0xb5, 0x54, 0xd0, 0xa9, 0x79, 0x35, 0x59, 0x2e, 0x66, 0xfa, 0xf2, 0x1b, 0xaa, 0x14, 0x5d,
-	0xc4, 0xaf, 0x42, 0x63, 0xe2, 0x39, 0x17, 0x89, 0x8a, 0xd6, 0xcd, 0x3a, 0x82, 0x83, 0x48, 0xdc,
-	0x81, 0x26, 0xaa, 0x71, 0xc7, 0xe3, 0xd4, 0x19, 0xe7, 0xbf, 0xf2, 0xa8, 0xa9, 0x04, 0x59, 0xfd,
-	0xf5, 0x09, 0xb2, 0xc6, 0x1b, 0x13, 0x64, 0xda, 0x9b, 0x12, 0x64, 0xfa, 0x74, 0x82, 0xac, 0xe8,
-	0xde, 0xc2, 0x8c, 0x7b, 0xfb, 0x36, 0x00, 0x7f, 0x16, 0x72, 0x3c, 0x71, 0x5d, 0xe5, 0x33, 0xe8,
-	0x84, 0xd9, 0x9e, 0xb8, 0xae, 0xb1, 0x0b, 0x9d, 0x84, 0xb5, 0x4a, 0x05, 0x7c, 0x04, 0x8b, 0x2a,
-	0x3b, 0x2e, 0x43, 0x95, 0xf3, 0x61, 0x23, 0x40, 0xef, 0x8f, 0x13, 0xd8, 0x8a, 0x62, 0x76, 0xec,
-	0x3c, 0x18, 0x19, 0xbf, 0x2c, 0x41, 0xbb, 0xd0, 0x43, 0x3c, 0xc9, 0x72, 0xed, 0x25, 0x7a, 0xc5,
-	0xbd, 0x99, 0x59, 0x5e, 0x9f, 0x6f, 0x2f, 0x4f, 0xe5, 0xdb, 0x8d, 0x87, 0x69, 0x16, 0x5d, 0xe5,
-	0xce, 0x17, 0xd2, 0xdc, 0x39, 0xa5, 0x9b, 0xd7, 0x07, 0x03, 0xb3, 0x5b, 0x16, 0x75, 0x28, 0xef,
-	0x1d, 0x76, 0x2b, 0xc6, 0x1f, 0x97, 0xa1, 0xdd, 0xbf, 0x08, 0xe8, 0x13, 0xa9, 0x37, 0xc6, 0x0a,
-	0x39, 0xb9, 0x2a, 0x17, 0xe4, 0x2a, 0x27, 0x21, 0x15, 0x55, 0x3c, 0x64, 0x09, 0xc1, 0xe8, 0x81,
-	0xd3, 0x75, 0x4a, 0x72, 0x18, 0xfa, 0xbf, 0x20, 0x39, 0x05, 0x8d, 0x02, 0xd3, 0xe5, 0x9f, 0x5d,
-	0xe8, 0x24, 0x6c, 0x53, 0x82, 0xf1, 0x85, 0x1e, 0x2b, 0x7f, 0x14, 0xe9, 0xa6, 0x19, 0x1e, 0x06,
-	0x8c, 0x3f, 0x2a, 0x83, 0xce, 0x72, 0x86, 0x9b, 0x7f, 0x5f, 0xe9, 0xf5, 0x52, 0x56, 0x69, 0x48,
-	0x89, 0xab, 0xcf, 0xe5, 0x65, 0xa6, 0xdb, 0xe7, 0x56, 0xe7, 0x54, 0x1e, 0x88, 0x63, 0x7d, 0xca,
-	0x03, 0xdd, 0x04, 0x9d, 0xbd, 0x9e, 0x89, 0xca, 0x61, 0x57, 0x4d, 0x76, 0x83, 0x5e, 0x3a, 0x64,
-	0x59, 0x62, 0x19, 0x8e, 0xd5, 0x1d, 0x50, 0xbb, 0x18, 0x37, 0xb5, 0x13, 0x4f, 0xbe, 0xc0, 0x91,
-	0xc6, 0x34, 0x47, 0x4e, 0xa1, 0xa1, 0xf6, 0x86, 0x6e, 0xef, 0xcb, 0xbd, 0xe7, 0x7b, 0xfb, 0xdf,
-	0xdb, 0x2b, 0x48, 0x5f, 0xea, 0x18, 0x97, 0xf3, 0x8e, 0x71, 0x05, 0xf1, 0x9b, 0xfb, 0x2f, 0xf7,
-	0x06, 0xdd, 0xaa, 0x68, 0x83, 0x4e, 0xcd, 0xa1, 0xd9, 0x7f, 0xd5, 0xad, 0x51, 0x1a, 0x65, 0xf3,
-	0xe3, 0xfe, 0x8b, 0xf5, 0x6e, 0x3d, 0xad, 0xfb, 0x34, 0x8c, 0x3f, 0x2c, 0xc1, 0x12, 0x33, 0x24,
-	0x9f, 0x11, 0x11, 0x50, 0x9d, 0x38, 0x76, 0xe2, 0xac, 0x50, 0xfb, 0x7f, 0x39, 0x4b, 0x72, 0x13,
-	0xf4, 0x89, 0x93, 0x54, 0x5a, 0x39, 0x51, 0xa2, 0x4d, 0x1c, 0x55, 0x60, 0xfd, 0x8b, 0x32, 0x2c,
-	0xb3, 0x3f, 0xfe, 0x2c, 0xb4, 0x82, 0xd3, 0xef, 0xee, 0xce, 0x44, 0xe4, 0x57, 0x39, 0xa2, 0xf7,
-	0xa0, 0x43, 0x1f, 0x7d, 0xff, 0xd8, 0x1d, 0xaa, 0xa8, 0x91, 0x6f, 0xb7, 0xad, 0xb0, 0x3c, 0x91,
-	0x78, 0x0a, 0x2d, 0xfe, 0x38, 0x9c, 0x12, 0xc0, 0x85, 0x2a, 0x61, 0x21, 0x1a, 0x68, 0x72, 0x2f,
-	0xae, 0x69, 0x3e, 0x49, 0x07, 0x65, 0xc1, 0xfb, 0x6c, 0x21, 0x50, 0x0d, 0x19, 0x50, 0x39, 0xf0,
-	0x2e, 0xb4, 0x5d, 0x6b, 0x7c, 0x64, 0x5b, 0x43, 0xf6, 0x87, 0x94, 0xa0, 0xb4, 0x18, 0x79, 0x48,
-	0x38, 0xf1, 0x84, 0xf2, 0x19, 0x75, 0x12, 0xd8, 0x77, 0x70, 0xb6, 0xab, 0x8f, 0xae, 0xca, 0xb4,
-	0xc6, 0x5b, 0x54, 0x40, 0xcd, 0x6e, 0x98, 0x0b, 0x63, 0x9b, 0xe6, 0xce, 0xc1, 0xa0, 0x5b, 0x32,
-	0x1e, 0xc1, 0xcd, 0xb9, 0x53, 0xa8, 0xc7, 0x96, 0xcb, 0x75, 0xb2, 0x8c, 0x1b, 0xff, 0x50, 0x02,
-	0x6d, 0x63, 0xe2, 0x9e, 0x91, 0xe9, 0x7d, 0x1b, 0x40, 0xda, 0x27, 0x52, 0x7d, 0xb7, 0x5d, 0x22,
-	0x95, 0xa4, 0x23, 0x86, 0xbf, 0xdc, 0xfe, 0x08, 0x80, 0x39, 0x3b, 0xe4, 0x2f, 0xe0, 0xd3, 0x5a,
-	0x61, 0x32, 0x81, 0xe2, 0xe0, 0x0b, 0x2b, 0x50, 0xb5, 0xc2, 0x28, 0x81, 0xb3, 0x1a, 0x6a, 0xe5,
-	0x35, 0x35, 0xd4, 0xe5, 0x3d, 0xe8, 0x14, 0xa7, 0x98, 0x93, 0x26, 0x7b, 0xb7, 0xf8, 0x9d, 0xca,
-	0xec, 0xcd, 0xe5, 0x1c, 0xf3, 0x4f, 0x60, 0x71, 0x2a, 0x83, 0xfd, 0x3a, 0x3d, 0x5d, 0x78, 0xa8,
-	0xe5, 0xe9, 0x87, 0xfa, 0x01, 0x2c, 0x0d, 0xac, 0xe8, 0x4c, 0x05, 0x2b, 0x99, 0xcb, 0x10, 0x5b,
-	0xd1, 0xd9, 0x30, 0x65, 0x6a, 0x1d, 0xc1, 0x1d, 0xdb, 0x78, 0x02, 0x22, 0xdf, 0x5b, 0xf1, 0x1f,
-	0x23, 0x54, 0xec, 0x3e, 0x96, 0xb1, 0x95, 0xf8, 0x36, 0x88, 0x40, 0xe6, 0xad, 0xfd, 0x55, 0x09,
-	0xaa, 0xe8, 0xdd, 0x8b, 0x87, 0xa0, 0x7f, 0x2c, 0xad, 0x30, 0x3e, 0x92, 0x56, 0x2c, 0x0a, 0x9e,
-	0xfc, 0x32, 0xf1, 0x2d, 0xfb, 0xf6, 0xc5, 0x58, 0x78, 0x5c, 0x12, 0xab, 0xfc, 0xcd, 0x6d, 0xf2,
-	0x2d, 0x71, 0x3b, 0x89, 0x12, 0x28, 0x8a, 0x58, 0x2e, 0x8c, 0x37, 0x16, 0x56, 0xa8, 0xff, 0x27,
-	0xbe, 0xe3, 0x6d, 0xf2, 0x97, 0x9e, 0x62, 0x3a, 0xaa, 0x98, 0x1e, 0x21, 0x1e, 0x42, 0x7d, 0x27,
-	0xc2, 0xf0, 0x65, 0xb6, 0x2b, 0x31, 0x3f, 0x1f, 0xd9, 0x18, 0x0b, 0x6b, 0x3f, 0xab, 0x41, 0xf5,
-	0x87, 0x32, 0xf4, 0xc5, 0x07, 0xd0, 0x50, 0x5f, 0x0a, 0x89, 0xdc, 0x17, 0x41, 0xcb, 0x94, 0x66,
-	0x99, 0xfa, 0x84, 0x88, 0x56, 0xe9, 0xf2, 0xfd, 0x65, 0x75, 0x1b, 0x91, 0x7d, 0xc8, 0x34, 0xb3,
-	0xa9, 0x0f, 0xa1, 0x7b, 0x18, 0x87, 0xd2, 0x1a, 0xe7, 0xba, 0x17, 0x59, 0x35, 0xaf, 0x08, 0x44,
-	0xfc, 0x7a, 0x00, 0x75, 0x8e, 0x11, 0xa7, 0x06, 0x4c, 0x57, 0x78, 0xa8, 0xf3, 0x7b, 0xd0, 0x3c,
-	0x3c, 0xf5, 0x27, 0xae, 0x7d, 0x28, 0xc3, 0x73, 0x29, 0x72, 0xdf, 0x1c, 0x2e, 0xe7, 0xda, 0xc6,
-	0x82, 0x78, 0x0f, 0x74, 0x8e, 0x00, 0xd0, 0xff, 0x6f, 0xa8, 0xa0, 0x82, 0xe7, 0xcc, 0x45, 0x06,
-	0xc6, 0x82, 0x58, 0x01, 0xc8, 0x45, 0x8a, 0xaf, 0xeb, 0xf9, 0x14, 0xda, 0x9b, 0xa4, 0x4c, 0xf7,
-	0xc3, 0xf5, 0x23, 0x3f, 0x8c, 0xc5, 0xf4, 0x47, 0x86, 0xcb, 0xd3, 0x08, 0x63, 0x41, 0x3c, 0x06,
-	0x6d, 0x10, 0x5e, 0x72, 0xff, 0x25, 0x15, 0x60, 0x67, 0xeb, 0xcd, 0x39, 0xa4, 0xf8, 0x46, 0xfa,
-	0x48, 0x52, 0xc7, 0x7f, 0x5e, 0xed, 0x87, 0xcf, 0xcb, 0x02, 0x6d, 0x2c, 0x88, 0x27, 0x00, 0x59,
-	0x54, 0x22, 0xbe, 0xc2, 0x75, 0xa8, 0xa9, 0x28, 0x65, 0x76, 0x48, 0x16, 0x81, 0xf0, 0x90, 0x99,
-	0x88, 0x64, 0x6a, 0xc8, 0x37, 0xa1, 0x95, 0x8f, 0x26, 0x04, 0x95, 0x4f, 0xe6, 0xc4, 0x17, 0xc5,
-	0x61, 0x6b, 0xff, 0x56, 0x83, 0xfa, 0xf7, 0xfc, 0xf0, 0x4c, 0x86, 0xe2, 0x3e, 0xd4, 0xa9, 0xa2,
-	0xa8, 0x1e, 0x46, 0x5a, 0x5d, 0x9c, 0xc7, 0xbb, 0xaf, 0x83, 0x4e, 0xd7, 0x8c, 0x2f, 0x97, 0x85,
-	0x8f, 0xfe, 0x14, 0xc3, 0x93, 0x73, 0x52, 0x92, 0x24, 0xb5, 0xc3, 0xa2, 0x97, 0xd6, 0xee, 0x0b,
-	0x15, 0xbf, 0x65, 0xba, 0xd2, 0xe7, 0xaf, 0x0e, 0xf1, 0xb1, 0x3d, 0x2e, 0xa1, 0x5b, 0x72, 0xc8,
-	0x97, 0x87, 0x9d, 0xb2, 0x8f, 0xfe, 0xf9, 0x2d, 0x67, 0x5f, 0xd9, 0x1b, 0x0b, 0xe2, 0x11, 0xd4,
-	0x95, 0x95, 0x5a, 0xca, 0xb4, 0x5a, 0x72, 0xc2, 0x6e, 0x1e, 0xa5, 0x06, 0x3c, 0x81, 0x3a, 0x5b,
-	0x74, 0x1e, 0x50, 0x08, 0x67, 0x96, 0x45, 0x1e, 0x95, 0x3c, 0x4f, 0xf1, 0x00, 0x1a, 0xaa, 0x5e,
-	0x28, 0xe6, 0x14, 0x0f, 0x67, 0x6e, 0xac, 0xce, 0xee, 0x1a, 0xcf, 0x5f, 0xf0, 0x78, 0x79, 0xfe,
-	0xa2, 0x37, 0xc7, 0xef, 0xd8, 0x94, 0x23, 0xe9, 0xe4, 0x72, 0x61, 0x22, 0xe1, 0xc8, 0x1c, 0x65,
-	0xf4, 0x21, 0xb4, 0x0b, 0x79, 0x33, 0xd1, 0x4b, 0xc4, 0x62, 0x3a, 0x95, 0x36, 0xa3, 0x02, 0xbe,
-	0x0d, 0xba, 0xca, 0x36, 0x1c, 0x29, 0xc1, 0x98, 0x93, 0xdb, 0x58, 0x9e, 0x4d, 0x37, 0xd0, 0xbb,
-	0xfe, 0x3e, 0x5c, 0x9b, 0x63, 0x28, 0xc5, 0xad, 0xd7, 0x1b, 0xe1, 0xe5, 0xdb, 0x57, 0xd2, 0x53,
-	0x06, 0xfc, 0x66, 0xcf, 0xe9, 0x3b, 0x00, 0x99, 0xbd, 0xe0, 0xb7, 0x31, 0x63, 0x6d, 0x96, 0x6f,
-	0x4c, 0xa3, 0x93, 0x45, 0x37, 0x7a, 0x7f, 0xfd, 0xd9, 0xad, 0xd2, 0xaf, 0x3e, 0xbb, 0x55, 0xfa,
-	0xe7, 0xcf, 0x6e, 0x95, 0x7e, 0xf9, 0xeb, 0x5b, 0x0b, 0xbf, 0xfa, 0xf5, 0xad, 0x85, 0xbf, 0xfb,
-	0xf5, 0xad, 0x85, 0xa3, 0x3a, 0xfd, 0x83, 0xed, 0xe9, 0x7f, 0x07, 0x00, 0x00, 0xff, 0xff, 0x9e,
-	0xab, 0x7a, 0xb4, 0x37, 0x37, 0x00, 0x00,
+	// 5676 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x7b, 0x4b, 0x70, 0x1c, 0x47,
+	0x72, 0x28, 0x7a, 0xfe, 0x9d, 0xf3, 0xc1, 0xa0, 0x48, 0x51, 0xa3, 0x91, 0x44, 0x42, 0xcd, 0xa5,
+	0x04, 0x89, 0x22, 0x48, 0x82, 0xda, 0xd8, 0x95, 0x36, 0xf6, 0xc5, 0xc3, 0x67, 0x40, 0x41, 0x04,
+	0x01, 0x6c, 0x63, 0xc8, 0xfd, 0x44, 0xbc, 0x37, 0xd1, 0x98, 0x2e, 0x00, 0xbd, 0xec, 0xe9, 0xee,
+	0xed, 0xee, 0xc1, 0x02, 0x7b, 0xdb, 0x78, 0x11, 0xbb, 0xf1, 0x6e, 0x7b, 0xf4, 0xc9, 0x07, 0xdf,
+	0x1c, 0x3e, 0x7b, 0xfd, 0x09, 0xfb, 0xe6, 0x83, 0xc3, 0x17, 0xef, 0xd1, 0x0e, 0xdb, 0x0a, 0x87,
+	0xd6, 0xe1, 0x83, 0x22, 0x7c, 0xf0, 0xdd, 0x07, 0x47, 0x66, 0x56, 0xff, 0x06, 0x03, 0x52, 0x5a,
+	0x87, 0x0f, 0x3e, 0x4d, 0x65, 0x66, 0x55, 0x75, 0x55, 0x56, 0x56, 0x7e, 0x6b, 0xa0, 0x11, 0x1c,
+	0xad, 0x06, 0xa1, 0x1f, 0xfb, 0xa2, 0x14, 0x1c, 0xf5, 0x75, 0x2b, 0x70, 0x18, 0xec, 0x7f, 0x70,
+	0xe2, 0xc4, 0xa7, 0xd3, 0xa3, 0xd5, 0xb1, 0x3f, 0xb9, 0x6f, 0x9f, 0x84, 0x56, 0x70, 0x7a, 0xcf,
+	0xf1, 0xef, 0x1f, 0x59, 0xf6, 0x89, 0x0c, 0xef, 0x9f, 0x3d, 0xba, 0x1f, 0x1c, 0xdd, 0x4f, 0x86,
+	0xf6, 0xef, 0xe5, 0xfa, 0x9e, 0xf8, 0x27, 0xfe, 0x7d, 0x42, 0x1f, 0x4d, 0x8f, 0x09, 0x22, 0x80,
+	0x5a, 0xdc, 0xdd, 0xf8, 0x5f, 0x50, 0xd9, 0x75, 0xa2, 0x58, 0xdc, 0x80, 0xda, 0x91, 0x13, 0x4f,
+	0xac, 0xa0, 0x57, 0x5a, 0xd6, 0x56, 0x5a, 0xa6, 0x82, 0xc4, 0x4d, 0x80, 0xc8, 0x0f, 0x63, 0x69,
+	0x3f, 0x73, 0xec, 0xa8, 0x57, 0x5e, 0x2e, 0xaf, 0xd4, 0xcc, 0x1c, 0xc6, 0x78, 0x0a, 0xfa, 0xd0,
+	0x8a, 0x5e, 0x3c, 0xb7, 0xdc, 0xa9, 0x14, 0x5d, 0x28, 0x9f, 0x59, 0x6e, 0x4f, 0xa3, 0x19, 0xb0,
+	0x29, 0x56, 0xa1, 0x71, 0x66, 0xb9, 0xa3, 0xf8, 0x22, 0x90, 0x34, 0x71, 0x67, 0xed, 0xda, 0x6a,
+	0x70, 0xb4, 0x7a, 0xe0, 0x47, 0xb1, 0xe3, 0x9d, 0xac, 0x3e, 0xb7, 0xdc, 0xe1, 0x45, 0x20, 0xcd,
+	0xfa, 0x19, 0x37, 0x8c, 0x7d, 0x68, 0x1e, 0x86, 0xe3, 0xed, 0xa9, 0x37, 0x8e, 0x1d, 0xdf, 0x13,
+	0x02, 0x2a, 0x9e, 0x35, 0x91, 0x34, 0xa3, 0x6e, 0x52, 0x1b, 0x71, 0x56, 0x78, 0xc2, 0x6b, 0xd1,
+	0x4d, 0x6a, 0x8b, 0x1e, 0xd4, 0x9d, 0x68, 0xd3, 0x9f, 0x7a, 0x71, 0xaf, 0xb2, 0xac, 0xad, 0x34,
+	0xcc, 0x04, 0x34, 0xfe, 0xa4, 0x0c, 0xd5, 0xef, 0x4d, 0x65, 0x78, 0x41, 0xe3, 0xe2, 0x38, 0x4c,
+	0xe6, 0xc2, 0xb6, 0xb8, 0x0e, 0x55, 0xd7, 0xf2, 0x4e, 0xa2, 0x5e, 0x89, 0x26, 0x63, 0x40, 0xbc,
+	0x09, 0xba, 0x75, 0x1c, 0xcb, 0x70, 0x34, 0x75, 0xec, 0x5e, 0x79, 0x59, 0x5b, 0xa9, 0x99, 0x0d,
+	0x42, 0x3c, 0x73, 0x6c, 0xf1, 0x06, 0x34, 0x6c, 0x7f, 0x34, 0xce, 0x7f, 0xcb, 0xf6, 0xe9, 0x5b,
+	0xe2, 0x36, 0x34, 0xa6, 0x8e, 0x3d, 0x72, 0x9d, 0x28, 0xee, 0x55, 0x97, 0xb5, 0x95, 0xe6, 0x5a,
+	0x03, 0x37, 0x8b, 0xfc, 0x35, 0xeb, 0x53, 0xc7, 0xc6, 0x86, 0xf8, 0x00, 0x1a, 0x51, 0x38, 0x1e,
+	0x1d, 0x4f, 0xbd, 0x71, 0xaf, 0x46, 0x9d, 0x16, 0xb1, 0x53, 0x6e, 0xd7, 0x66, 0x3d, 0x62, 0x00,
+	0xb7, 0x15, 0xca, 0x33, 0x19, 0x46, 0xb2, 0x57, 0xe7, 0x4f, 0x29, 0x50, 0x3c, 0x80, 0xe6, 0xb1,
+	0x35, 0x96, 0xf1, 0x28, 0xb0, 0x42, 0x6b, 0xd2, 0x6b, 0x64, 0x13, 0x6d, 0x23, 0xfa, 0x00, 0xb1,
+	0x91, 0x09, 0xc7, 0x29, 0x20, 0x1e, 0x41, 0x9b, 0xa0, 0x68, 0x74, 0xec, 0xb8, 0xb1, 0x0c, 0x7b,
+	0x3a, 0x8d, 0xe9, 0xd0, 0x18, 0xc2, 0x0c, 0x43, 0x29, 0xcd, 0x16, 0x77, 0x62, 0x8c, 0x78, 0x1b,
+	0x40, 0x9e, 0x07, 0x96, 0x67, 0x8f, 0x2c, 0xd7, 0xed, 0x01, 0xad, 0x41, 0x67, 0xcc, 0xba, 0xeb,
+	0x8a, 0xd7, 0x71, 0x7d, 0x96, 0x3d, 0x8a, 0xa3, 0x5e, 0x7b, 0x59, 0x5b, 0xa9, 0x98, 0x35, 0x04,
+	0x87, 0x11, 0xf2, 0x75, 0x6c, 0x8d, 0x4f, 0x65, 0xaf, 0xb3, 0xac, 0xad, 0x54, 0x4d, 0x06, 0x10,
+	0x7b, 0xec, 0x84, 0x51, 0xdc, 0x5b, 0x64, 0x2c, 0x01, 0x28, 0x79, 0xfe, 0xf1, 0x71, 0x24, 0xe3,
+	0x5e, 0x97, 0xd0, 0x0a, 0x32, 0xd6, 0x40, 0x27, 0xa9, 0x22, 0xae, 0xdd, 0x81, 0xda, 0x19, 0x02,
+	0x51, 0x4f, 0x5b, 0x2e, 0xaf, 0x34, 0xd7, 0xda, 0xb8, 0xec, 0x54, 0xf0, 0x4c, 0x45, 0x34, 0x6e,
+	0x42, 0x63, 0xd7, 0xf2, 0x4e, 0x68, 0x88, 0x80, 0x0a, 0x1e, 0x27, 0x0d, 0xd0, 0x4d, 0x6a, 0x1b,
+	0xbf, 0x57, 0x82, 0x9a, 0x29, 0xa3, 0xa9, 0x1b, 0x8b, 0xf7, 0x00, 0xf0, 0xb0, 0x26, 0x56, 0x1c,
+	0x3a, 0xe7, 0x6a, 0xd6, 0xec, 0xb8, 0xf4, 0xa9, 0x63, 0x3f, 0x25, 0x92, 0x78, 0x00, 0x2d, 0x9a,
+	0x3d, 0xe9, 0x5a, 0xca, 0x16, 0x90, 0xae, 0xcf, 0x6c, 0x52, 0x17, 0x35, 0xe2, 0x06, 0xd4, 0x48,
+	0x3e, 0x58, 0x46, 0xdb, 0xa6, 0x82, 0xc4, 0x1d, 0xe8, 0x38, 0x5e, 0x8c, 0xe7, 0x37, 0x8e, 0x47,
+	0xb6, 0x8c, 0x12, 0x01, 0x6a, 0xa7, 0xd8, 0x2d, 0x19, 0xc5, 0xe2, 0x21, 0xf0, 0x21, 0x24, 0x1f,
+	0xac, 0x2e, 0x97, 0xd3, 0x83, 0xa2, 0xc3, 0xe1, 0x2f, 0x52, 0x1f, 0xf5, 0xc5, 0x7b, 0xd0, 0xc4,
+	0xfd, 0x25, 0x23, 0x6a, 0x34, 0xa2, 0x45, 0xbb, 0x51, 0xec, 0x30, 0x01, 0x3b, 0xa8, 0xee, 0xc8,
+	0x1a, 0x14, 0x52, 0x16, 0x2a, 0x6a, 0x1b, 0x03, 0xa8, 0xee, 0x87, 0xb6, 0x0c, 0xe7, 0xde, 0x13,
+	0x01, 0x15, 0x5b, 0x46, 0x63, 0xba, 0xc2, 0x0d, 0x93, 0xda, 0xd9, 0xdd, 0x29, 0xe7, 0xee, 0x8e,
+	0xf1, 0xfb, 0x1a, 0x34, 0x0f, 0xfd, 0x30, 0x7e, 0x2a, 0xa3, 0xc8, 0x3a, 0x91, 0xe2, 0x16, 0x54,
+	0x7d, 0x9c, 0x56, 0x71, 0x58, 0xc7, 0x35, 0xd1, 0x77, 0x4c, 0xc6, 0xcf, 0x9c, 0x43, 0xe9, 0xea,
+	0x73, 0x40, 0x99, 0xa2, 0x5b, 0x57, 0x56, 0x32, 0x85, 0x40, 0x4e, 0x7a, 0x2a, 0x79, 0xe9, 0xb9,
+	0x52, 0x34, 0x8d, 0x6f, 0x02, 0xe0, 0xfa, 0xbe, 0xa6, 0x14, 0x18, 0xbf, 0xd4, 0xa0, 0x69, 0x5a,
+	0xc7, 0xf1, 0xa6, 0xef, 0xc5, 0xf2, 0x3c, 0x16, 0x1d, 0x28, 0x39, 0x36, 0xf1, 0xa8, 0x66, 0x96,
+	0x1c, 0x1b, 0x57, 0x77, 0x12, 0xfa, 0x53, 0x56, 0x9f, 0x6d, 0x93, 0x01, 0xe2, 0xa5, 0x6d, 0x87,
+	0xbd, 0xb2, 0xe2, 0xa5, 0x6d, 0x87, 0xe2, 0x16, 0x34, 0x23, 0xcf, 0x0a, 0xa2, 0x53, 0x3f, 0xc6,
+	0xd5, 0x55, 0x68, 0x75, 0x90, 0xa0, 0x86, 0x11, 0x5e, 0x3a, 0x27, 0x1a, 0xb9, 0xd2, 0x0a, 0x3d,
+	0x19, 0x92, 0x22, 0x69, 0x98, 0xba, 0x13, 0xed, 0x32, 0xc2, 0xf8, 0x65, 0x19, 0x6a, 0x4f, 0xe5,
+	0xe4, 0x48, 0x86, 0x97, 0x16, 0xf1, 0x00, 0x1a, 0xf4, 0xdd, 0x91, 0x63, 0xf3, 0x3a, 0x36, 0x5e,
+	0xfb, 0xf2, 0xf3, 0x5b, 0x4b, 0x84, 0xdb, 0xb1, 0x3f, 0xf4, 0x27, 0x4e, 0x2c, 0x27, 0x41, 0x7c,
+	0x61, 0xd6, 0x15, 0x6a, 0xee, 0x02, 0x6f, 0x40, 0xcd, 0x95, 0x16, 0x9e, 0x19, 0x8b, 0xa7, 0x82,
+	0xc4, 0x3d, 0xa8, 0x5b, 0x93, 0x91, 0x2d, 0x2d, 0x9b, 0x17, 0xb5, 0x71, 0xfd, 0xcb, 0xcf, 0x6f,
+	0x75, 0xad, 0xc9, 0x96, 0xb4, 0xf2, 0x73, 0xd7, 0x18, 0x23, 0x3e, 0x46, 0x99, 0x8c, 0xe2, 0xd1,
+	0x34, 0xb0, 0xad, 0x58, 0x92, 0xae, 0xab, 0x6c, 0xf4, 0xbe, 0xfc, 0xfc, 0xd6, 0x75, 0x44, 0x3f,
+	0x23, 0x6c, 0x6e, 0x18, 0x64, 0x58, 0xd4, 0x7b, 0xc9, 0xf6, 0x95, 0xde, 0x53, 0xa0, 0xd8, 0x81,
+	0xa5, 0xb1, 0x3b, 0x8d, 0x50, 0x39, 0x3b, 0xde, 0xb1, 0x3f, 0xf2, 0x3d, 0xf7, 0x82, 0x0e, 0xb8,
+	0xb1, 0xf1, 0xf6, 0x97, 0x9f, 0xdf, 0x7a, 0x43, 0x11, 0x77, 0xbc, 0x63, 0x7f, 0xdf, 0x73, 0x2f,
+	0x72, 0xf3, 0x2f, 0xce, 0x90, 0xc4, 0xff, 0x86, 0xce, 0xb1, 0x1f, 0x8e, 0xe5, 0x28, 0x65, 0x59,
+	0x87, 0xe6, 0xe9, 0x7f, 0xf9, 0xf9, 0xad, 0x1b, 0x44, 0x79, 0x7c, 0x89, 0x6f, 0xad, 0x3c, 0xde,
+	0xf8, 0xa7, 0x12, 0x54, 0xa9, 0x2d, 0x1e, 0x40, 0x7d, 0x42, 0x47, 0x92, 0xe8, 0xa7, 0x1b, 0x28,
+	0x43, 0x44, 0x5b, 0xe5, 0xb3, 0x8a, 0x06, 0x5e, 0x1c, 0x5e, 0x98, 0x49, 0x37, 0x1c, 0x11, 0x5b,
+	0x47, 0xae, 0x8c, 0xa3, 0x5e, 0x69, 0x76, 0xc4, 0x90, 0x09, 0x6a, 0x84, 0xea, 0x36, 0x2b, 0x37,
+	0xe5, 0x4b, 0x72, 0xd3, 0x87, 0xc6, 0xf8, 0x54, 0x8e, 0x5f, 0x44, 0xd3, 0x89, 0x92, 0xaa, 0x14,
+	0x16, 0xb7, 0xa1, 0x4d, 0xed, 0xc0, 0x77, 0x3c, 0x1a, 0x5e, 0xa5, 0x0e, 0xad, 0x0c, 0x39, 0x8c,
+	0xfa, 0xdb, 0xd0, 0xca, 0x2f, 0x16, 0xcd, 0xf9, 0x0b, 0x79, 0x41, 0xf2, 0x55, 0x31, 0xb1, 0x29,
+	0x96, 0xa1, 0x4a, 0x8a, 0x8e, 0xa4, 0xab, 0xb9, 0x06, 0xb8, 0x66, 0x1e, 0x62, 0x32, 0xe1, 0x93,
+	0xd2, 0xb7, 0x35, 0x9c, 0x27, 0xbf, 0x85, 0xfc, 0x3c, 0xfa, 0xd5, 0xf3, 0xf0, 0x90, 0xdc, 0x3c,
+	0x86, 0x0f, 0xf5, 0x5d, 0x67, 0x2c, 0xbd, 0x88, 0x8c, 0xfe, 0x34, 0x92, 0xa9, 0x52, 0xc2, 0x36,
+	0xee, 0x77, 0x62, 0x9d, 0xef, 0xf9, 0xb6, 0x8c, 0x68, 0x9e, 0x8a, 0x99, 0xc2, 0x48, 0x93, 0xe7,
+	0x81, 0x13, 0x5e, 0x0c, 0x99, 0x53, 0x65, 0x33, 0x85, 0x51, 0xba, 0xa4, 0x87, 0x1f, 0xb3, 0x13,
+	0x03, 0xae, 0x40, 0xe3, 0x17, 0x15, 0x68, 0xfd, 0x48, 0x86, 0xfe, 0x41, 0xe8, 0x07, 0x7e, 0x64,
+	0xb9, 0x62, 0xbd, 0xc8, 0x73, 0x3e, 0xdb, 0x65, 0x5c, 0x6d, 0xbe, 0xdb, 0xea, 0x61, 0x7a, 0x08,
+	0x7c, 0x66, 0xf9, 0x53, 0x31, 0xa0, 0xc6, 0x67, 0x3e, 0x87, 0x67, 0x8a, 0x82, 0x7d, 0xf8, 0x94,
+	0x7b, 0xe5, 0xac, 0x8f, 0xe2, 0x87, 0xa2, 0xe0, 0xad, 0x9c, 0x58, 0xe7, 0xcf, 0x76, 0xb6, 0xd4,
+	0xd9, 0x2a, 0x48, 0x71, 0x61, 0x78, 0xee, 0x0d, 0x93, 0x43, 0x4d, 0x61, 0xdc, 0x29, 0x72, 0x24,
+	0xda, 0xd9, 0xea, 0xb5, 0x88, 0x94, 0x80, 0xe2, 0x2d, 0xd0, 0x27, 0xd6, 0x39, 0x2a, 0xb4, 0x1d,
+	0x9b, 0xaf, 0xa6, 0x99, 0x21, 0xc4, 0x3b, 0x50, 0x8e, 0xcf, 0xbd, 0x5e, 0x5d, 0x79, 0x15, 0xe8,
+	0x88, 0x0e, 0xcf, 0x3d, 0xa5, 0xfa, 0x4c, 0xa4, 0xe1, 0x99, 0x8e, 0x1d, 0x9b, 0x9c, 0x08, 0xdd,
+	0xc4, 0xa6, 0xb8, 0x03, 0x75, 0x97, 0x4f, 0x8b, 0x1c, 0x85, 0xe6, 0x5a, 0x93, 0xf5, 0x28, 0xa1,
+	0xcc, 0x84, 0x26, 0x3e, 0x84, 0x46, 0xc2, 0x9d, 0x5e, 0x93, 0xfa, 0x75, 0x13, 0x7e, 0x26, 0x6c,
+	0x34, 0xd3, 0x1e, 0xe2, 0x01, 0xe8, 0xb6, 0x74, 0x65, 0x2c, 0x47, 0x1e, 0x2b, 0xf2, 0x26, 0x3b,
+	0x90, 0x5b, 0x84, 0xdc, 0x8b, 0x4c, 0xf9, 0x93, 0xa9, 0x8c, 0x62, 0xb3, 0x61, 0x2b, 0x44, 0xff,
+	0xbb, 0xb0, 0x38, 0x73, 0x1c, 0x79, 0xf9, 0x6b, 0xb3, 0xfc, 0x5d, 0xcf, 0xcb, 0x5f, 0x25, 0x27,
+	0x73, 0x9f, 0x55, 0x1a, 0x8d, 0xae, 0x6e, 0xfc, 0x7b, 0x19, 0x16, 0xd5, 0x55, 0x38, 0x75, 0x82,
+	0xc3, 0x58, 0x29, 0x25, 0x32, 0x39, 0x4a, 0x0a, 0x2b, 0x66, 0x02, 0x8a, 0x6f, 0x41, 0x8d, 0x74,
+	0x48, 0x72, 0x95, 0x6f, 0x65, 0x47, 0x9c, 0x0e, 0xe7, 0xab, 0xad, 0xe4, 0x43, 0x75, 0x17, 0x1f,
+	0x41, 0xf5, 0x67, 0x32, 0xf4, 0xd9, 0x84, 0x36, 0xd7, 0x6e, 0xce, 0x1b, 0x87, 0x8c, 0x51, 0xc3,
+	0xb8, 0xf3, 0x7f, 0x55, 0x12, 0xe0, 0xeb, 0x48, 0xc2, 0x37, 0xd0, 0x8c, 0x4e, 0xfc, 0x33, 0x69,
+	0xf7, 0xea, 0xcb, 0xe5, 0x44, 0x34, 0x95, 0xf8, 0x26, 0xa4, 0x44, 0x18, 0x1a, 0x73, 0x85, 0x41,
+	0xbf, 0x5a, 0x18, 0xfa, 0x5b, 0xd0, 0xcc, 0xf1, 0x65, 0xce, 0x41, 0xdd, 0x2a, 0x2a, 0x0a, 0x3d,
+	0x55, 0x92, 0x79, 0x7d, 0xb3, 0x05, 0x90, 0x71, 0xe9, 0x77, 0xd5, 0x5a, 0xc6, 0xcf, 0x35, 0x58,
+	0xdc, 0xf4, 0x3d, 0x4f, 0x92, 0x13, 0xce, 0x67, 0x9e, 0x5d, 0x5e, 0xed, 0xca, 0xcb, 0xfb, 0x3e,
+	0x54, 0x23, 0xec, 0xdc, 0x2b, 0x65, 0xe2, 0x39, 0x73, 0x88, 0x26, 0xf7, 0x40, 0x15, 0x3e, 0xb1,
+	0xce, 0x47, 0x81, 0xf4, 0x6c, 0xc7, 0x3b, 0x49, 0x54, 0xf8, 0xc4, 0x3a, 0x3f, 0x60, 0x8c, 0xf1,
+	0xa7, 0x25, 0x80, 0x4f, 0xa5, 0xe5, 0xc6, 0xa7, 0x68, 0xa6, 0xf0, 0x44, 0x1d, 0x2f, 0x8a, 0x2d,
+	0x6f, 0x9c, 0x84, 0x40, 0x29, 0x8c, 0x27, 0x8a, 0xd6, 0x5a, 0x46, 0xac, 0xfc, 0x74, 0x33, 0x01,
+	0x51, 0x3e, 0xf0, 0x73, 0xd3, 0x48, 0x59, 0x75, 0x05, 0x65, 0x2e, 0x4a, 0x85, 0xd0, 0x0c, 0xe0,
+	0x3c, 0x18, 0x52, 0x38, 0xbe, 0x47, 0x42, 0xa3, 0x9b, 0x09, 0x88, 0xf3, 0x4c, 0x83, 0xd8, 0x99,
+	0xb0, 0xed, 0x2e, 0x9b, 0x0a, 0xc2, 0x55, 0xa1, 0xad, 0x1e, 0x8c, 0x4f, 0x7d, 0x52, 0x11, 0x65,
+	0x33, 0x85, 0x71, 0x36, 0xdf, 0x3b, 0xf1, 0x71, 0x77, 0x0d, 0x72, 0x0b, 0x13, 0x90, 0xf7, 0x62,
+	0xcb, 0x73, 0x24, 0xe9, 0x44, 0x4a, 0x61, 0xe4, 0x8b, 0x94, 0xa3, 0x63, 0x69, 0xc5, 0xd3, 0x50,
+	0x46, 0x3d, 0x20, 0x32, 0x48, 0xb9, 0xad, 0x30, 0xe2, 0x1d, 0x68, 0x21, 0xe3, 0xac, 0x28, 0x72,
+	0x4e, 0x3c, 0x69, 0x93, 0xe2, 0xa8, 0x98, 0xc8, 0xcc, 0x75, 0x85, 0x32, 0xfe, 0xb2, 0x04, 0x35,
+	0x56, 0x99, 0x05, 0x37, 0x48, 0xfb, 0x4a, 0x6e, 0xd0, 0x5b, 0xa0, 0x07, 0xa1, 0xb4, 0x9d, 0x71,
+	0x72, 0x8e, 0xba, 0x99, 0x21, 0x28, 0x6e, 0x41, 0xbb, 0x4f, 0xfc, 0x6c, 0x98, 0x0c, 0x08, 0x03,
+	0xda, 0xbe, 0x37, 0xb2, 0x9d, 0xe8, 0xc5, 0xe8, 0xe8, 0x22, 0x96, 0x91, 0xe2, 0x45, 0xd3, 0xf7,
+	0xb6, 0x9c, 0xe8, 0xc5, 0x06, 0xa2, 0x90, 0x85, 0x7c, 0x47, 0xe8, 0x6e, 0x34, 0x4c, 0x05, 0x89,
+	0x47, 0xa0, 0x93, 0x77, 0x4a, 0xee, 0x8b, 0x4e, 0x6e, 0xc7, 0x8d, 0x2f, 0x3f, 0xbf, 0x25, 0x10,
+	0x39, 0xe3, 0xb7, 0x34, 0x12, 0x1c, 0xfa, 0x5f, 0x38, 0x18, 0x0d, 0x11, 0xdd, 0x61, 0xf6, 0xbf,
+	0x10, 0x35, 0x8c, 0xf2, 0xfe, 0x17, 0x63, 0xc4, 0x3d, 0x10, 0x53, 0x6f, 0xec, 0x4f, 0x02, 0x14,
+	0x0a, 0x69, 0xab, 0x45, 0x36, 0x69, 0x91, 0x4b, 0x79, 0x0a, 0x2d, 0xd5, 0xf8, 0xc7, 0x12, 0xb4,
+	0xb6, 0x9c, 0x50, 0x8e, 0x63, 0x69, 0x0f, 0xec, 0x13, 0x89, 0x6b, 0x97, 0x5e, 0xec, 0xc4, 0x17,
+	0xca, 0xc1, 0x54, 0x50, 0x1a, 0x1f, 0x94, 0x8a, 0x71, 0x34, 0xdf, 0xb0, 0x32, 0x85, 0xfe, 0x0c,
+	0x88, 0x35, 0x00, 0x6a, 0x70, 0xf8, 0x5f, 0xb9, 0x3a, 0xfc, 0xd7, 0xa9, 0x1b, 0x36, 0x31, 0xbc,
+	0xe6, 0x31, 0x0e, 0x7b, 0x99, 0x35, 0xca, 0x0d, 0x4c, 0x25, 0xfb, 0xaa, 0x14, 0xd0, 0xd5, 0xf9,
+	0xc3, 0xd8, 0x16, 0xb7, 0xa1, 0xe4, 0x07, 0xbd, 0x46, 0x36, 0x75, 0x7e, 0x0b, 0xab, 0xfb, 0x81,
+	0x59, 0xf2, 0x03, 0xbc, 0xc5, 0x1c, 0xd5, 0x92, 0xe0, 0xe1, 0x2d, 0x46, 0x8b, 0x46, 0xb1, 0x94,
+	0xa9, 0x28, 0xc2, 0x80, 0x96, 0xe5, 0xba, 0xfe, 0x4f, 0xa5, 0x7d, 0x10, 0x4a, 0x3b, 0x91, 0xc1,
+	0x02, 0x0e, 0xa5, 0x04, 0x33, 0x10, 0x51, 0x60, 0x8d, 0xa5, 0x12, 0xc1, 0x0c, 0x61, 0xdc, 0x80,
+	0xd2, 0x7e, 0x20, 0xea, 0x50, 0x3e, 0x1c, 0x0c, 0xbb, 0x0b, 0xd8, 0xd8, 0x1a, 0xec, 0x76, 0xd1,
+	0xa2, 0xd4, 0xba, 0x75, 0xe3, 0x8b, 0x12, 0xe8, 0x4f, 0xa7, 0xb1, 0x85, 0xba, 0x25, 0xc2, 0x5d,
+	0x16, 0x25, 0x34, 0x13, 0xc5, 0x37, 0xa0, 0x11, 0xc5, 0x56, 0x48, 0xfe, 0x06, 0x5b, 0xa7, 0x3a,
+	0xc1, 0xc3, 0x48, 0xbc, 0x0b, 0x55, 0x69, 0x9f, 0xc8, 0xc4, 0x5c, 0x74, 0x67, 0xf7, 0x6b, 0x32,
+	0x59, 0xac, 0x40, 0x2d, 0x1a, 0x9f, 0xca, 0x89, 0xd5, 0xab, 0x64, 0x1d, 0x0f, 0x09, 0xc3, 0x0e,
+	0xb6, 0xa9, 0xe8, 0xe2, 0x1b, 0x50, 0xc5, 0xb3, 0x89, 0x7a, 0xb5, 0x2c, 0xc6, 0xc4, 0x63, 0x50,
+	0xdd, 0x98, 0x88, 0x82, 0x67, 0x87, 0x7e, 0x30, 0xf2, 0x03, 0xe2, 0x7d, 0x67, 0xed, 0x3a, 0xe9,
+	0xb8, 0x64, 0x37, 0xab, 0x5b, 0xa1, 0x1f, 0xec, 0x07, 0x66, 0xcd, 0xa6, 0x5f, 0x8c, 0x5f, 0xa8,
+	0x3b, 0x4b, 0x04, 0x1b, 0x05, 0x1d, 0x31, 0x9c, 0x24, 0x5a, 0x81, 0xc6, 0x44, 0xc6, 0x96, 0x6d,
+	0xc5, 0x96, 0xb2, 0x0d, 0x14, 0xa8, 0x3e, 0x55, 0x38, 0x33, 0xa5, 0x1a, 0xf7, 0xa1, 0xc6, 0x53,
+	0x8b, 0x06, 0x54, 0xf6, 0xf6, 0xf7, 0x06, 0xcc, 0xd6, 0xf5, 0xdd, 0xdd, 0xae, 0x86, 0xa8, 0xad,
+	0xf5, 0xe1, 0x7a, 0xb7, 0x84, 0xad, 0xe1, 0x0f, 0x0f, 0x06, 0xdd, 0xb2, 0xf1, 0x37, 0x1a, 0x34,
+	0x92, 0x79, 0xc4, 0x27, 0x00, 0x78, 0x85, 0x47, 0xa7, 0x8e, 0x97, 0xba, 0x6e, 0x6f, 0xe6, 0xbf,
+	0xb4, 0x8a, 0xa7, 0xfa, 0x29, 0x52, 0xd9, 0xbc, 0xea, 0x41, 0x02, 0xf7, 0x0f, 0xa1, 0x53, 0x24,
+	0xce, 0xf1, 0x61, 0xef, 0xe6, 0xad, 0x4a, 0x67, 0xed, 0xb5, 0xc2, 0xd4, 0x38, 0x92, 0x44, 0x3b,
+	0x67, 0x60, 0xee, 0x41, 0x23, 0x41, 0x8b, 0x26, 0xd4, 0xb7, 0x06, 0xdb, 0xeb, 0xcf, 0x76, 0x51,
+	0x54, 0x00, 0x6a, 0x87, 0x3b, 0x7b, 0x8f, 0x77, 0x07, 0xbc, 0xad, 0xdd, 0x9d, 0xc3, 0x61, 0xb7,
+	0x64, 0xfc, 0x5a, 0x83, 0x46, 0xe2, 0xc9, 0x88, 0xf7, 0xd1, 0xf9, 0x20, 0xf7, 0xab, 0xa7, 0x65,
+	0xb9, 0x9e, 0x5c, 0x40, 0x6a, 0x26, 0x74, 0xbc, 0x8b, 0xa4, 0x58, 0x13, 0xdf, 0x86, 0x80, 0x7c,
+	0x3c, 0x5c, 0x2e, 0xa4, 0x6a, 0x30, 0xb4, 0xf7, 0x3d, 0xa9, 0x5c, 0x61, 0x6a, 0x93, 0x0c, 0x3a,
+	0xde, 0x58, 0x66, 0x81, 0x42, 0x9d, 0xe0, 0xe1, 0x65, 0x4d, 0x5c, 0xbb, 0xac, 0x89, 0x63, 0x76,
+	0xa2, 0xd3, 0xb5, 0xa7, 0x0b, 0xd2, 0xf2, 0x0b, 0xba, 0x14, 0x91, 0x94, 0x2e, 0x47, 0x24, 0x99,
+	0x6d, 0xad, 0xbe, 0xca, 0xb6, 0x1a, 0x7f, 0x58, 0x87, 0x8e, 0x29, 0xa3, 0xd8, 0x0f, 0xa5, 0x72,
+	0x0a, 0x5f, 0x76, 0xcb, 0xde, 0x06, 0x08, 0xb9, 0x73, 0xf6, 0x69, 0x5d, 0x61, 0x38, 0x94, 0x72,
+	0xfd, 0x31, 0x89, 0xb7, 0x32, 0xa2, 0x29, 0x8c, 0xd9, 0xc1, 0x23, 0x6b, 0xfc, 0x82, 0xa7, 0x65,
+	0x53, 0xda, 0x60, 0x04, 0xcf, 0x6b, 0x8d, 0xc7, 0x32, 0x8a, 0x46, 0x28, 0x2d, 0x6c, 0x50, 0x75,
+	0xc6, 0x3c, 0x91, 0x17, 0x48, 0x8e, 0xe4, 0x38, 0x94, 0x31, 0x91, 0x6b, 0x4c, 0x66, 0x0c, 0x92,
+	0x6f, 0x43, 0x3b, 0x92, 0x11, 0x1a, 0xdf, 0x51, 0xec, 0xbf, 0x90, 0x9e, 0x52, 0x75, 0x2d, 0x85,
+	0x1c, 0x22, 0x0e, 0xb5, 0x90, 0xe5, 0xf9, 0xde, 0xc5, 0xc4, 0x9f, 0x46, 0xca, 0xac, 0x64, 0x08,
+	0xb1, 0x0a, 0xd7, 0xa4, 0x37, 0x0e, 0x2f, 0x02, 0x5c, 0x2b, 0x7e, 0x05, 0xd3, 0x7d, 0x52, 0xf9,
+	0xe9, 0x4b, 0x19, 0xe9, 0x89, 0xbc, 0xd8, 0x76, 0x5c, 0x89, 0x2b, 0x3a, 0xb3, 0xa6, 0x6e, 0x3c,
+	0xa2, 0x34, 0x00, 0xf0, 0x8a, 0x08, 0xb3, 0x8e, 0xb9, 0x80, 0x0f, 0x60, 0x89, 0xc9, 0xa1, 0xef,
+	0x4a, 0xc7, 0xe6, 0xc9, 0x9a, 0xd4, 0x6b, 0x91, 0x08, 0x26, 0xe1, 0x69, 0xaa, 0x55, 0xb8, 0xc6,
+	0x7d, 0x79, 0x43, 0x49, 0xef, 0x16, 0x7f, 0x9a, 0x48, 0x87, 0x8a, 0x52, 0xfc, 0x74, 0x60, 0xc5,
+	0xa7, 0xbd, 0x76, 0xee, 0xd3, 0x07, 0x56, 0x7c, 0x8a, 0x4e, 0x01, 0x93, 0x8f, 0x1d, 0xe9, 0x72,
+	0x70, 0xae, 0x9b, 0x3c, 0x62, 0x1b, 0x31, 0x28, 0x8a, 0xaa, 0x83, 0x1f, 0x4e, 0x2c, 0xce, 0x2a,
+	0xea, 0x26, 0x0f, 0xda, 0x26, 0x14, 0x7e, 0x42, 0x9d, 0x95, 0x37, 0x9d, 0x50, 0x7e, 0xb1, 0x62,
+	0xaa, 0xd3, 0xdb, 0x9b, 0x4e, 0xc4, 0xfb, 0xd0, 0x75, 0xbc, 0x71, 0x28, 0x27, 0xd2, 0x8b, 0x2d,
+	0x77, 0x74, 0x1c, 0xfa, 0x93, 0xde, 0x12, 0x75, 0x5a, 0xcc, 0xe1, 0xb7, 0x43, 0x7f, 0xa2, 0x92,
+	0x32, 0x81, 0x15, 0xc6, 0x8e, 0xe5, 0xf6, 0x44, 0x92, 0x94, 0x39, 0x60, 0x04, 0x05, 0xe7, 0xa4,
+	0x52, 0xd9, 0xa4, 0x5f, 0x23, 0x3a, 0x30, 0x8a, 0x8c, 0x37, 0x09, 0x1c, 0x89, 0x25, 0x8a, 0xcd,
+	0x75, 0xde, 0xac, 0xc2, 0xec, 0xd8, 0xc2, 0x82, 0xd7, 0x12, 0x83, 0x8c, 0xe7, 0xe6, 0x9f, 0xc9,
+	0x30, 0x74, 0x30, 0xb0, 0x7d, 0x8d, 0xf4, 0xd6, 0x87, 0x74, 0xdb, 0x0b, 0xd2, 0xbd, 0xba, 0x99,
+	0xf5, 0xdf, 0x4f, 0xba, 0xb3, 0x22, 0xbb, 0x3e, 0x9e, 0x43, 0xa2, 0x25, 0x5a, 0x93, 0xc0, 0x95,
+	0xa3, 0x10, 0x6f, 0xd4, 0x8d, 0x65, 0x6d, 0x45, 0x33, 0x81, 0x51, 0x66, 0xea, 0x9d, 0xa2, 0x96,
+	0x27, 0xad, 0xde, 0x7b, 0x9d, 0x19, 0x3e, 0xb1, 0x82, 0x7d, 0xc6, 0xf4, 0x1f, 0xc3, 0x1b, 0x57,
+	0x7e, 0xf4, 0x55, 0x41, 0x96, 0x9e, 0xd7, 0x84, 0xff, 0x51, 0x86, 0x46, 0x1a, 0x63, 0xdf, 0x05,
+	0x7d, 0x92, 0x98, 0x12, 0xe5, 0x43, 0xb7, 0x0b, 0xf6, 0xc5, 0xcc, 0xe8, 0xe2, 0x6d, 0x28, 0xbd,
+	0x38, 0x53, 0x66, 0xad, 0xbd, 0xca, 0x15, 0x90, 0xe0, 0xe8, 0xd1, 0xea, 0x93, 0xe7, 0x66, 0xe9,
+	0xc5, 0xd9, 0xd7, 0xd0, 0x17, 0xe2, 0x3d, 0x58, 0x1c, 0xbb, 0xd2, 0xf2, 0x46, 0x99, 0xe3, 0xc7,
+	0xf7, 0xb1, 0x43, 0xe8, 0x83, 0x04, 0x2b, 0xee, 0x40, 0xd5, 0x96, 0x6e, 0x6c, 0xe5, 0x93, 0xec,
+	0xfb, 0xa1, 0x35, 0x76, 0xe5, 0x16, 0xa2, 0x4d, 0xa6, 0xa2, 0x59, 0x4b, 0xe3, 0xda, 0x9c, 0x59,
+	0x9b, 0x13, 0xd3, 0xa6, 0xfa, 0x10, 0xf2, 0xfa, 0xf0, 0x2e, 0x2c, 0xc9, 0xf3, 0x80, 0x6c, 0xf9,
+	0x28, 0x4d, 0xe3, 0xb0, 0x93, 0xd1, 0x4d, 0x08, 0x9b, 0x0a, 0x2f, 0x3e, 0x44, 0x6d, 0x4e, 0xd2,
+	0x40, 0xd7, 0xab, 0xb9, 0x26, 0x2e, 0x0b, 0x88, 0x99, 0x74, 0x11, 0xef, 0x83, 0x3e, 0xb6, 0xc7,
+	0x23, 0xe6, 0x4c, 0x3b, 0x5b, 0xdb, 0xe6, 0xd6, 0x26, 0xb3, 0xa4, 0x31, 0xb6, 0xc7, 0xd4, 0x2a,
+	0xc6, 0xdb, 0x9d, 0xaf, 0x10, 0x6f, 0x27, 0xe7, 0xbe, 0x98, 0x85, 0x5b, 0x79, 0x0f, 0xa6, 0x5b,
+	0xf0, 0x60, 0x3e, 0xab, 0x34, 0xea, 0xdd, 0x86, 0x71, 0x1b, 0x1a, 0xc9, 0xa7, 0xd1, 0x2e, 0x45,
+	0xd2, 0x53, 0xd9, 0x15, 0xb2, 0x4b, 0x08, 0x0e, 0x23, 0x63, 0x0c, 0xe5, 0x27, 0xcf, 0x0f, 0xc9,
+	0x3c, 0xa1, 0xa7, 0x50, 0x25, 0xc7, 0x92, 0xda, 0xa9, 0xc9, 0x2a, 0xe5, 0x4c, 0xd6, 0x4d, 0xb6,
+	0xf6, 0x74, 0x64, 0x49, 0x4a, 0x3a, 0x87, 0x41, 0xa6, 0xb3, 0xa7, 0x53, 0x21, 0x12, 0x03, 0xc6,
+	0xbf, 0x96, 0xa1, 0xae, 0x9c, 0x51, 0xdc, 0xc8, 0x34, 0xcd, 0xa6, 0x62, 0xb3, 0x28, 0xc0, 0xa9,
+	0x57, 0x9b, 0x2f, 0x69, 0x95, 0x5f, 0x5d, 0xd2, 0x12, 0x9f, 0x40, 0x2b, 0x60, 0x5a, 0xde, 0x0f,
+	0x7e, 0x3d, 0x3f, 0x46, 0xfd, 0xd2, 0xb8, 0x66, 0x90, 0x01, 0xc8, 0x4a, 0xca, 0xeb, 0xc7, 0xd6,
+	0x89, 0xe2, 0x40, 0x1d, 0xe1, 0xa1, 0x75, 0xf2, 0x95, 0x9c, 0xda, 0x0e, 0x79, 0xc7, 0x2d, 0xba,
+	0x92, 0xe8, 0x08, 0xe7, 0x4f, 0xa6, 0x5d, 0xf4, 0x2d, 0xdf, 0x04, 0x7d, 0xec, 0x4f, 0x26, 0x0e,
+	0xd1, 0x3a, 0x2a, 0x7b, 0x48, 0x88, 0x61, 0x64, 0xfc, 0x42, 0x83, 0xba, 0xda, 0xd7, 0x25, 0xcf,
+	0x65, 0x63, 0x67, 0x6f, 0xdd, 0xfc, 0x61, 0x57, 0x43, 0xcf, 0x6c, 0x67, 0x6f, 0xd8, 0x2d, 0x09,
+	0x1d, 0xaa, 0xdb, 0xbb, 0xfb, 0xeb, 0xc3, 0x6e, 0x19, 0xbd, 0x99, 0x8d, 0xfd, 0xfd, 0xdd, 0x6e,
+	0x45, 0xb4, 0xa0, 0xb1, 0xb5, 0x3e, 0x1c, 0x0c, 0x77, 0x9e, 0x0e, 0xba, 0x55, 0xec, 0xfb, 0x78,
+	0xb0, 0xdf, 0xad, 0x61, 0xe3, 0xd9, 0xce, 0x56, 0xb7, 0x8e, 0xf4, 0x83, 0xf5, 0xc3, 0xc3, 0xef,
+	0xef, 0x9b, 0x5b, 0xdd, 0x06, 0x79, 0x44, 0x43, 0x73, 0x67, 0xef, 0x71, 0x57, 0xc7, 0xf6, 0xfe,
+	0xc6, 0x67, 0x83, 0xcd, 0x61, 0x17, 0x8c, 0x87, 0xd0, 0xcc, 0xf1, 0x0a, 0x47, 0x9b, 0x83, 0xed,
+	0xee, 0x02, 0x7e, 0xf2, 0xf9, 0xfa, 0xee, 0x33, 0x74, 0xa0, 0x3a, 0x00, 0xd4, 0x1c, 0xed, 0xae,
+	0xef, 0x3d, 0xee, 0x96, 0x94, 0xfb, 0xfd, 0xff, 0xb5, 0x74, 0x24, 0x15, 0x87, 0xde, 0x83, 0x86,
+	0xe2, 0x73, 0x92, 0xb4, 0x69, 0xe6, 0x0e, 0xc4, 0x4c, 0x89, 0x45, 0xbe, 0x94, 0x8b, 0x7c, 0xa1,
+	0x48, 0x3b, 0x70, 0x9d, 0x98, 0xa5, 0xaa, 0x62, 0x2a, 0x28, 0x57, 0x4c, 0xad, 0xe6, 0x8b, 0xa9,
+	0x9f, 0x55, 0x1a, 0x5a, 0xb7, 0x64, 0x7c, 0x04, 0x90, 0x15, 0xe9, 0xe6, 0x38, 0x96, 0xd7, 0xa1,
+	0x6a, 0xb9, 0x8e, 0x95, 0xc4, 0xf5, 0x0c, 0x18, 0x7b, 0xd0, 0xcc, 0x46, 0x51, 0x04, 0x61, 0xb9,
+	0x2e, 0x1a, 0x78, 0xbe, 0x38, 0x0d, 0xb3, 0x6e, 0xb9, 0xee, 0x13, 0x79, 0x11, 0xa1, 0x53, 0xcf,
+	0x55, 0xc1, 0xd2, 0x4c, 0xe1, 0x88, 0x86, 0x9a, 0x4c, 0x34, 0x3e, 0x84, 0xda, 0x76, 0x12, 0xfa,
+	0x24, 0x92, 0xa4, 0x5d, 0x25, 0x49, 0xc6, 0xc7, 0x00, 0x59, 0xed, 0x49, 0xdc, 0x55, 0xd5, 0xc7,
+	0x88, 0x6b, 0x9d, 0x5a, 0x96, 0x19, 0xe2, 0x4e, 0xaa, 0xf0, 0x48, 0x9d, 0x8d, 0x2d, 0x68, 0xbc,
+	0xb4, 0x9e, 0xab, 0x18, 0x50, 0xca, 0x18, 0x30, 0xa7, 0xc2, 0x6b, 0xfc, 0x18, 0x20, 0xab, 0x52,
+	0x2a, 0xc1, 0xe6, 0x59, 0x50, 0xb0, 0x3f, 0xc0, 0xd4, 0xb7, 0xe3, 0xda, 0xa1, 0xf4, 0x0a, 0xbb,
+	0x4e, 0x47, 0x98, 0x29, 0x5d, 0x2c, 0x43, 0x85, 0x8a, 0xaf, 0xe5, 0x4c, 0x11, 0x26, 0xeb, 0x33,
+	0x89, 0x62, 0x9c, 0x43, 0x9b, 0xa3, 0xa5, 0xaf, 0xe0, 0x48, 0x16, 0xf5, 0x4e, 0xe9, 0x92, 0xde,
+	0xb9, 0x01, 0x35, 0xf2, 0x5f, 0x92, 0xdd, 0x28, 0xe8, 0x0a, 0x7d, 0xf4, 0xff, 0x4a, 0x00, 0xfc,
+	0x69, 0x4c, 0x63, 0x17, 0xd3, 0x12, 0xda, 0x6c, 0x5a, 0x42, 0x40, 0x25, 0xad, 0xab, 0xeb, 0x26,
+	0xb5, 0x33, 0xdb, 0xa2, 0x52, 0x15, 0x04, 0xe0, 0x3c, 0xe4, 0x4f, 0x3a, 0x3f, 0x93, 0xa1, 0xfa,
+	0x60, 0x86, 0xc8, 0x57, 0x99, 0xab, 0xc5, 0x2a, 0x73, 0x5a, 0x72, 0xab, 0xf1, 0x6c, 0x04, 0xcc,
+	0xab, 0x1e, 0x72, 0xae, 0x28, 0x92, 0x61, 0x9c, 0x24, 0x3a, 0x18, 0x4a, 0x63, 0x76, 0x5d, 0xf5,
+	0xb5, 0x38, 0xdb, 0xe3, 0x61, 0x05, 0xdd, 0x3b, 0x76, 0x9d, 0x71, 0xac, 0xaa, 0xca, 0xe0, 0xf9,
+	0x9b, 0x0a, 0x63, 0x7c, 0x02, 0xad, 0x84, 0xff, 0x54, 0xa4, 0xfb, 0x20, 0x8d, 0x67, 0xb5, 0xec,
+	0x6c, 0x33, 0x36, 0x6d, 0x94, 0x7a, 0x5a, 0x12, 0xd1, 0x1a, 0xff, 0x56, 0x49, 0x06, 0xab, 0x5a,
+	0xd2, 0xcb, 0x79, 0x58, 0x4c, 0x51, 0x94, 0xbe, 0x52, 0x8a, 0xe2, 0xdb, 0xa0, 0xdb, 0x14, 0x75,
+	0x3b, 0x67, 0x89, 0x05, 0xe8, 0xcf, 0x46, 0xd8, 0x2a, 0x2e, 0x77, 0xce, 0xa4, 0x99, 0x75, 0x7e,
+	0xc5, 0x39, 0xa4, 0xdc, 0xae, 0xce, 0xe3, 0x76, 0xed, 0x77, 0xe4, 0xf6, 0x3b, 0xd0, 0xf2, 0x7c,
+	0x6f, 0xe4, 0x4d, 0x5d, 0x17, 0xb3, 0x63, 0x8a, 0xdd, 0x4d, 0xcf, 0xf7, 0xf6, 0x14, 0x0a, 0x9d,
+	0xfc, 0x7c, 0x17, 0xbe, 0xd4, 0x4d, 0xea, 0xb7, 0x98, 0xeb, 0x47, 0x57, 0x7f, 0x05, 0xba, 0xfe,
+	0xd1, 0x8f, 0xb1, 0x80, 0x8d, 0x1c, 0x1b, 0xd1, 0x6d, 0x66, 0x0f, 0xbf, 0xc3, 0x78, 0x64, 0xd1,
+	0x1e, 0xde, 0xeb, 0x99, 0x63, 0x6e, 0xcf, 0x1e, 0xb3, 0xd8, 0x80, 0x36, 0x89, 0xe7, 0x28, 0x94,
+	0x47, 0x53, 0x47, 0xb9, 0xf8, 0x9d, 0xb5, 0xb7, 0x2f, 0xf1, 0x72, 0x07, 0x7b, 0x99, 0xdc, 0xc9,
+	0x6c, 0x39, 0x39, 0xc8, 0xf8, 0x18, 0xf4, 0x94, 0xd3, 0xb9, 0x2c, 0x81, 0x0e, 0xd5, 0x9d, 0xbd,
+	0xad, 0xc1, 0x0f, 0xba, 0x1a, 0xda, 0x2b, 0x73, 0xf0, 0x7c, 0x60, 0x1e, 0x0e, 0xba, 0x25, 0xb4,
+	0x25, 0x5b, 0x83, 0xdd, 0xc1, 0x10, 0x93, 0x05, 0x1f, 0x41, 0x2b, 0x3f, 0xb1, 0x58, 0x84, 0xe6,
+	0xb3, 0xbd, 0xc3, 0x83, 0xc1, 0xe6, 0xce, 0xf6, 0xce, 0x60, 0x8b, 0x27, 0x19, 0xac, 0x3f, 0x1e,
+	0x98, 0x2a, 0x2a, 0x5f, 0xff, 0xd1, 0x0f, 0xc9, 0x9c, 0xd4, 0xbb, 0x0d, 0x2a, 0x26, 0xb9, 0xce,
+	0xd8, 0x89, 0x8d, 0x43, 0x80, 0x2c, 0x61, 0x82, 0xd6, 0x22, 0x63, 0x8b, 0xca, 0xd8, 0xc6, 0x09,
+	0x43, 0x56, 0x52, 0x55, 0x50, 0xba, 0x2a, 0x2d, 0xc3, 0x74, 0xc3, 0x07, 0xfd, 0xa9, 0x15, 0x7c,
+	0xca, 0x65, 0xd7, 0x3b, 0xd0, 0xa1, 0xb0, 0x23, 0x09, 0xe8, 0x58, 0x4d, 0xb7, 0xcc, 0x76, 0x8a,
+	0x25, 0xad, 0x7f, 0x87, 0xca, 0x99, 0x13, 0x2b, 0x1e, 0x25, 0xe9, 0x5c, 0xae, 0x44, 0xb7, 0x19,
+	0xfb, 0x9c, 0x91, 0x2c, 0x64, 0xb6, 0x1c, 0xab, 0xb0, 0x96, 0x01, 0xe3, 0x6f, 0x35, 0xb8, 0xfe,
+	0xd4, 0x3f, 0x93, 0xa9, 0xd7, 0x7b, 0x60, 0x5d, 0xb8, 0xbe, 0x65, 0xbf, 0xe2, 0xf6, 0x60, 0x38,
+	0xeb, 0x4f, 0xa9, 0x86, 0x9a, 0x54, 0x9c, 0x4d, 0x9d, 0x31, 0x8f, 0xd5, 0x53, 0x19, 0x0c, 0x78,
+	0x4e, 0xd4, 0x33, 0x9a, 0xb6, 0x59, 0x47, 0x18, 0x49, 0xb9, 0x74, 0x44, 0xa5, 0x90, 0x8e, 0x98,
+	0xeb, 0x06, 0x57, 0xaf, 0x70, 0x83, 0xf3, 0x79, 0x8a, 0x5a, 0x21, 0x4f, 0x61, 0x6c, 0x82, 0x3e,
+	0x3c, 0xa7, 0x2c, 0xfe, 0x34, 0x2a, 0xf8, 0x3d, 0xda, 0x4b, 0xfc, 0x9e, 0xd2, 0x8c, 0xdf, 0xf3,
+	0x2f, 0x1a, 0x34, 0x73, 0xae, 0xbe, 0x78, 0x07, 0x2a, 0xf1, 0xb9, 0x57, 0x7c, 0x83, 0x92, 0x7c,
+	0xc4, 0x24, 0xd2, 0xa5, 0xfc, 0x48, 0xe9, 0x52, 0x7e, 0x44, 0xec, 0xc2, 0x22, 0x5b, 0x93, 0x64,
+	0x7f, 0x49, 0x42, 0xef, 0xf6, 0x4c, 0x68, 0xc1, 0x95, 0x8e, 0x64, 0xb7, 0x2a, 0xb8, 0xeb, 0x9c,
+	0x14, 0x90, 0xfd, 0x75, 0xb8, 0x36, 0xa7, 0xdb, 0xd7, 0xa9, 0x79, 0x19, 0xb7, 0xa0, 0x8d, 0x55,
+	0x22, 0x67, 0x22, 0xa3, 0xd8, 0x9a, 0x04, 0xe4, 0x37, 0x2a, 0x6f, 0xa0, 0x62, 0x96, 0xe2, 0xc8,
+	0x78, 0x17, 0x5a, 0x07, 0x52, 0x86, 0xa6, 0x8c, 0x02, 0x1f, 0x6b, 0x78, 0x59, 0x85, 0x81, 0x5d,
+	0x0f, 0x05, 0x19, 0xff, 0x17, 0x74, 0x4c, 0x49, 0x6d, 0x58, 0xf1, 0xf8, 0xf4, 0xeb, 0xa4, 0xac,
+	0xde, 0x85, 0x7a, 0xc0, 0x02, 0xa7, 0x02, 0xc0, 0x16, 0xb9, 0x20, 0x4a, 0x08, 0xcd, 0x84, 0x68,
+	0xfc, 0x1f, 0xb8, 0x76, 0x38, 0x3d, 0x8a, 0xc6, 0xa1, 0x43, 0x11, 0x69, 0x62, 0x9e, 0xfb, 0xd0,
+	0x08, 0x42, 0x79, 0xec, 0x9c, 0xcb, 0xe4, 0x6e, 0xa4, 0xb0, 0xf8, 0x00, 0x0b, 0x5f, 0xf1, 0xf8,
+	0x54, 0x66, 0xb7, 0x2e, 0x8b, 0x1a, 0x9f, 0x22, 0xc5, 0x4c, 0x3a, 0x18, 0xdf, 0x81, 0xeb, 0xc5,
+	0xe9, 0xd5, 0x76, 0x6f, 0x43, 0xf9, 0xc5, 0x59, 0xa4, 0x76, 0xb1, 0x54, 0x88, 0x3a, 0xe9, 0x99,
+	0x08, 0x52, 0x8d, 0x3f, 0xd7, 0xa0, 0x8c, 0x39, 0x85, 0xdc, 0x1b, 0xb8, 0x0a, 0xbf, 0x81, 0x7b,
+	0x33, 0x9f, 0xec, 0xe7, 0x98, 0x25, 0x4b, 0xea, 0xbf, 0x05, 0xfa, 0xb1, 0x1f, 0xfe, 0xd4, 0x0a,
+	0x6d, 0x69, 0x2b, 0xa3, 0x9d, 0x21, 0x50, 0xa1, 0x1f, 0x4d, 0x27, 0x81, 0xb2, 0x08, 0xd4, 0x16,
+	0x77, 0x94, 0xd9, 0xe7, 0x38, 0x62, 0x09, 0x99, 0xba, 0x37, 0x9d, 0xac, 0xba, 0xd2, 0x8a, 0xc8,
+	0x3e, 0xb1, 0x27, 0x60, 0xdc, 0x05, 0x3d, 0x45, 0xa1, 0x2a, 0xdb, 0x3b, 0x1c, 0xed, 0x6c, 0x75,
+	0x17, 0x12, 0x8f, 0x5b, 0x43, 0x5d, 0x38, 0xfc, 0xc1, 0xde, 0x68, 0x78, 0xd8, 0x2d, 0x19, 0x3f,
+	0x82, 0x66, 0x22, 0x9e, 0x3b, 0x36, 0x55, 0x0b, 0xe9, 0x7e, 0xec, 0xd8, 0x85, 0xeb, 0xb2, 0x43,
+	0x21, 0x91, 0xf4, 0xec, 0x9d, 0x44, 0xae, 0x19, 0x28, 0xee, 0x50, 0x95, 0x1e, 0x93, 0x1d, 0x1a,
+	0x03, 0x58, 0x32, 0xa9, 0xea, 0x81, 0xb6, 0x3a, 0x39, 0xb2, 0x1b, 0x50, 0xf3, 0x7c, 0x5b, 0xa6,
+	0x1f, 0x50, 0x10, 0x7e, 0x59, 0x79, 0x56, 0x4a, 0x9d, 0x24, 0xa0, 0x21, 0x61, 0x09, 0x35, 0x94,
+	0xaa, 0x8a, 0xab, 0x69, 0x0a, 0x19, 0x79, 0x6d, 0x26, 0x23, 0x8f, 0x1f, 0x51, 0x65, 0x75, 0x76,
+	0x91, 0x14, 0x84, 0xf2, 0x62, 0x47, 0x31, 0xdd, 0x1a, 0xa5, 0x97, 0x52, 0xd8, 0xb8, 0x0f, 0xd7,
+	0xd6, 0x83, 0xc0, 0xbd, 0x48, 0x4a, 0x95, 0xea, 0x43, 0xbd, 0xac, 0x9e, 0xa9, 0xa9, 0x38, 0x8c,
+	0x41, 0x63, 0x1b, 0x5a, 0x49, 0x8c, 0x8f, 0xd9, 0x5f, 0x52, 0x28, 0xae, 0x53, 0x08, 0x69, 0x1b,
+	0x8c, 0x18, 0x16, 0xf3, 0xfe, 0x33, 0xfb, 0x5b, 0x85, 0x9a, 0xd2, 0x56, 0x02, 0x2a, 0xa8, 0x95,
+	0x69, 0x70, 0xd5, 0xa4, 0x36, 0x4a, 0xd5, 0x24, 0x3a, 0x49, 0x9c, 0xe4, 0x49, 0x74, 0x62, 0xfc,
+	0x7d, 0x09, 0xda, 0x1b, 0x94, 0xc9, 0x4a, 0xd6, 0x98, 0xd3, 0xa9, 0x5a, 0x41, 0xa7, 0xe6, 0xd5,
+	0x64, 0xa9, 0x98, 0xce, 0xcd, 0x2f, 0xa8, 0x5c, 0xf4, 0x6c, 0x5f, 0x87, 0xfa, 0xd4, 0x73, 0xce,
+	0x13, 0x15, 0xad, 0x9b, 0x35, 0x04, 0x87, 0x91, 0x58, 0x86, 0x26, 0xaa, 0x71, 0xc7, 0xe3, 0xfc,
+	0x28, 0x27, 0x39, 0xf3, 0xa8, 0x99, 0x2c, 0x68, 0xed, 0xe5, 0x59, 0xd0, 0xfa, 0x2b, 0xb3, 0xa0,
+	0x8d, 0x57, 0x65, 0x41, 0xf5, 0xd9, 0x2c, 0x68, 0xd1, 0x2b, 0x87, 0x4b, 0x5e, 0xf9, 0xdb, 0x00,
+	0xfc, 0xf6, 0xe7, 0x78, 0xea, 0xba, 0xbd, 0x66, 0x7a, 0xed, 0xc6, 0x72, 0x7b, 0xea, 0xba, 0xc6,
+	0x29, 0x74, 0x12, 0xd6, 0x2a, 0x15, 0xf0, 0x09, 0x2c, 0xaa, 0x12, 0x88, 0x0c, 0x55, 0xaa, 0x8a,
+	0x8d, 0x00, 0xdd, 0x3f, 0xae, 0x52, 0x28, 0x8a, 0xd9, 0xb1, 0xf3, 0x60, 0xf1, 0x5d, 0x0e, 0x1f,
+	0x60, 0x0a, 0x1b, 0xbf, 0xd2, 0xa0, 0x5d, 0x18, 0x2d, 0x1e, 0x66, 0xc5, 0x16, 0x8d, 0x6e, 0x78,
+	0xef, 0xd2, 0x17, 0x5e, 0x5e, 0x70, 0x29, 0xcd, 0x14, 0x5c, 0x8c, 0x7b, 0x69, 0x19, 0x45, 0x15,
+	0x4f, 0x16, 0xd2, 0xe2, 0x09, 0x79, 0x36, 0xeb, 0xc3, 0xa1, 0xd9, 0x2d, 0x89, 0x1a, 0x94, 0xf6,
+	0x0e, 0xbb, 0x65, 0xe3, 0x8f, 0x4b, 0xd0, 0x1e, 0x9c, 0x07, 0xf4, 0x46, 0xee, 0x95, 0xe1, 0x4f,
+	0x4e, 0xe6, 0x4a, 0x05, 0x99, 0xcb, 0x49, 0x4f, 0x59, 0x55, 0x8f, 0x59, 0x7a, 0x30, 0x20, 0xe2,
+	0x7c, 0xad, 0x92, 0x2a, 0x86, 0xfe, 0x27, 0x48, 0x55, 0x41, 0xdb, 0xc0, 0x6c, 0xfd, 0x6f, 0x17,
+	0x3a, 0x09, 0xdb, 0x94, 0xd0, 0x7c, 0xa5, 0x8b, 0xcc, 0xaf, 0x62, 0xdd, 0x34, 0x69, 0xc5, 0x80,
+	0xf1, 0x47, 0x25, 0xd0, 0x59, 0x06, 0x71, 0xf1, 0xef, 0x2b, 0x9d, 0xaf, 0x65, 0xa5, 0xa6, 0x94,
+	0xb8, 0xfa, 0x44, 0x5e, 0x64, 0x7a, 0x7f, 0x6e, 0x79, 0x56, 0xa5, 0xb6, 0x38, 0x7d, 0x81, 0x4d,
+	0xd4, 0x52, 0xec, 0x11, 0x4d, 0x55, 0x11, 0xa3, 0x62, 0xb2, 0x8b, 0x84, 0x4f, 0x9c, 0x31, 0xb0,
+	0x94, 0xe1, 0x44, 0x9d, 0x01, 0xb5, 0x8b, 0xa1, 0x60, 0x3b, 0x09, 0x4e, 0x0a, 0x1c, 0xa9, 0xcf,
+	0x72, 0xe4, 0x14, 0xea, 0x6a, 0x6d, 0xe8, 0x85, 0x3f, 0xdb, 0x7b, 0xb2, 0xb7, 0xff, 0xfd, 0xbd,
+	0x82, 0xf4, 0xa5, 0x7e, 0x7a, 0x29, 0xef, 0xa7, 0x97, 0x11, 0xbf, 0xb9, 0xff, 0x6c, 0x6f, 0xd8,
+	0xad, 0x88, 0x36, 0xe8, 0xd4, 0x1c, 0x99, 0x83, 0xe7, 0xdd, 0x2a, 0x65, 0x86, 0x36, 0x3f, 0x1d,
+	0x3c, 0x5d, 0xef, 0xd6, 0xd2, 0xc2, 0x5f, 0xdd, 0xf8, 0x03, 0x0d, 0x96, 0x98, 0x21, 0xf9, 0x24,
+	0x0f, 0x3e, 0x1a, 0x73, 0x6c, 0xbe, 0xa9, 0x15, 0x93, 0xda, 0xff, 0xcd, 0x89, 0x9f, 0x37, 0x01,
+	0x9f, 0x8c, 0xaa, 0x52, 0x3b, 0xe7, 0x7e, 0xf0, 0x49, 0x38, 0x57, 0xd8, 0xff, 0xa2, 0x04, 0x7d,
+	0x76, 0xf4, 0x1f, 0xe3, 0x13, 0xfe, 0xef, 0xed, 0x5e, 0x4a, 0x32, 0x5c, 0xe5, 0xa4, 0xde, 0x81,
+	0x0e, 0xbd, 0xfa, 0xff, 0x89, 0x3b, 0x52, 0x81, 0x30, 0x9f, 0x6e, 0x5b, 0x61, 0x79, 0x22, 0xf1,
+	0x08, 0x5a, 0xfc, 0xef, 0x00, 0xca, 0x69, 0x17, 0xca, 0xc4, 0x85, 0x30, 0xa3, 0xc9, 0xbd, 0xb8,
+	0xa8, 0xfd, 0x30, 0x1d, 0x94, 0xe5, 0x23, 0x2e, 0x57, 0x82, 0xd5, 0x10, 0xc4, 0x44, 0x78, 0x95,
+	0x5c, 0x6b, 0x72, 0x64, 0x5b, 0x23, 0xf6, 0x95, 0x94, 0xa0, 0xb4, 0x18, 0x79, 0x48, 0x38, 0xf1,
+	0x90, 0x52, 0x34, 0x35, 0x12, 0xd8, 0x77, 0x70, 0xb6, 0xab, 0xb7, 0xae, 0xea, 0xf4, 0xc6, 0x5b,
+	0x54, 0x41, 0xcf, 0x4e, 0x98, 0x2b, 0xa3, 0x9b, 0xe6, 0xce, 0xc1, 0xb0, 0xab, 0x19, 0xf7, 0xe1,
+	0xcd, 0xb9, 0x53, 0xa8, 0xcb, 0x96, 0x4b, 0xdf, 0xb2, 0x8c, 0x1b, 0xff, 0xa0, 0x41, 0x63, 0x63,
+	0xea, 0xbe, 0x20, 0xb3, 0x8c, 0x2f, 0xd9, 0xed, 0x13, 0xa9, 0x1e, 0xee, 0x6b, 0xa4, 0x92, 0x74,
+	0xc4, 0xf0, 0xd3, 0xfd, 0x4f, 0x40, 0x15, 0x6b, 0x46, 0xfc, 0x17, 0x88, 0xb4, 0x58, 0x9c, 0x4c,
+	0xa0, 0x38, 0xf8, 0xd4, 0x0a, 0x54, 0xb1, 0x38, 0x4a, 0xe0, 0xac, 0x88, 0x5e, 0x7e, 0x49, 0x11,
+	0xbd, 0xbf, 0x07, 0x9d, 0xe2, 0x14, 0x73, 0x32, 0x7f, 0xef, 0x16, 0x1f, 0x2a, 0x5d, 0x3e, 0xb9,
+	0x9c, 0xd3, 0xfe, 0x19, 0x2c, 0xce, 0x24, 0xe5, 0x5f, 0xa6, 0xa7, 0x0b, 0x17, 0xb5, 0x34, 0x7b,
+	0x51, 0x3f, 0x84, 0x25, 0x7c, 0x4b, 0xaf, 0x02, 0x99, 0xcc, 0x9d, 0x88, 0xad, 0xe8, 0xc5, 0x28,
+	0x65, 0x6a, 0x0d, 0xc1, 0x1d, 0xdb, 0x78, 0x08, 0x22, 0xdf, 0x5b, 0xf1, 0x1f, 0x43, 0x5f, 0xec,
+	0x8e, 0xd5, 0x7b, 0x35, 0xa0, 0x81, 0x08, 0x64, 0x9e, 0xf1, 0x2d, 0xb8, 0xa1, 0x8a, 0x13, 0x07,
+	0xa1, 0x7f, 0x82, 0x05, 0xa4, 0xe4, 0x2b, 0xc5, 0xba, 0x98, 0x36, 0x53, 0x17, 0x33, 0xfe, 0x4c,
+	0x83, 0xc5, 0x99, 0x91, 0x38, 0x84, 0x2e, 0xd8, 0x08, 0x4d, 0x4d, 0xe2, 0xf5, 0x11, 0xc6, 0x94,
+	0x96, 0x8d, 0x85, 0x1d, 0x26, 0x07, 0xa1, 0x3f, 0xa6, 0x07, 0x2e, 0x6a, 0xc3, 0x1d, 0x42, 0x1f,
+	0x24, 0x58, 0x54, 0x0f, 0x54, 0x09, 0xe3, 0xcb, 0x4d, 0x6d, 0xdc, 0x34, 0xea, 0xdf, 0xa4, 0xb4,
+	0xdb, 0xc6, 0x90, 0xdc, 0x95, 0x5c, 0xd8, 0x95, 0xae, 0x15, 0xe0, 0x43, 0x1a, 0x8f, 0xaf, 0x36,
+	0xca, 0x0f, 0x63, 0xf6, 0xb2, 0x2a, 0x7a, 0x2d, 0x2b, 0x49, 0xac, 0xfd, 0x95, 0x06, 0x15, 0x0c,
+	0x77, 0xc4, 0x3d, 0xd0, 0x3f, 0x95, 0x56, 0x18, 0x1f, 0x49, 0x2b, 0x16, 0x85, 0xd0, 0xa6, 0x4f,
+	0xc2, 0x92, 0xbd, 0xf8, 0x32, 0x16, 0x1e, 0x68, 0x62, 0x95, 0x5f, 0x9a, 0x27, 0x2f, 0xe8, 0xdb,
+	0x49, 0xd8, 0x44, 0x61, 0x55, 0xbf, 0x30, 0xde, 0x58, 0x58, 0xa1, 0xfe, 0x9f, 0xf9, 0x8e, 0xb7,
+	0xc9, 0xef, 0x9b, 0xc5, 0x6c, 0x98, 0x35, 0x3b, 0x42, 0xdc, 0x83, 0xda, 0x4e, 0x74, 0x20, 0xe7,
+	0x75, 0x25, 0x89, 0xcb, 0x87, 0x7a, 0xc6, 0xc2, 0xda, 0xcf, 0xab, 0x50, 0xc1, 0x7a, 0x3e, 0x16,
+	0x9d, 0xd4, 0xfb, 0x38, 0x91, 0x7b, 0x07, 0xd7, 0xa7, 0x74, 0xd9, 0xcc, 0xc3, 0x39, 0xfa, 0x4a,
+	0x97, 0x85, 0x36, 0xab, 0xbf, 0x89, 0xec, 0xf9, 0xde, 0xa5, 0x45, 0x7d, 0x0c, 0xdd, 0xc3, 0x38,
+	0x94, 0xd6, 0x24, 0xd7, 0xbd, 0xc8, 0xaa, 0x79, 0xc5, 0x3c, 0xe2, 0xd7, 0x5d, 0xa8, 0x71, 0xd0,
+	0x3c, 0x33, 0x60, 0xb6, 0x52, 0x47, 0x9d, 0xdf, 0x83, 0xe6, 0xe1, 0xa9, 0x3f, 0x75, 0xed, 0x43,
+	0x19, 0x9e, 0x49, 0x91, 0x7b, 0x69, 0xdb, 0xcf, 0xb5, 0x8d, 0x05, 0xf1, 0x1e, 0xe8, 0x1c, 0x12,
+	0x61, 0x40, 0x54, 0x57, 0x51, 0x16, 0xcf, 0x99, 0x0b, 0x95, 0x8c, 0x05, 0xb1, 0x02, 0x90, 0x0b,
+	0x9d, 0x5f, 0xd6, 0xf3, 0x11, 0xb4, 0x37, 0xc9, 0x82, 0xec, 0x87, 0xeb, 0x47, 0x7e, 0x18, 0x8b,
+	0xd9, 0xa7, 0xb5, 0xfd, 0x59, 0x84, 0xb1, 0x80, 0x8f, 0xd9, 0x86, 0xe1, 0x05, 0xf7, 0x5f, 0x52,
+	0x19, 0x87, 0xec, 0x7b, 0x73, 0x36, 0x29, 0x3e, 0x4a, 0x35, 0x43, 0x1a, 0x09, 0xcd, 0xab, 0xe1,
+	0xf1, 0x7e, 0xf9, 0x16, 0x1b, 0x0b, 0xe2, 0x21, 0x40, 0x16, 0xa6, 0x89, 0xd7, 0xb8, 0x9e, 0x38,
+	0x13, 0xb6, 0x5d, 0x1e, 0x92, 0x85, 0x64, 0x3c, 0xe4, 0x52, 0x88, 0x36, 0x33, 0xe4, 0x9b, 0xd0,
+	0xca, 0x87, 0x57, 0x82, 0xca, 0x60, 0x73, 0x02, 0xae, 0xe2, 0xb0, 0xb5, 0x5f, 0xd7, 0xa0, 0xf6,
+	0x7d, 0x3f, 0x7c, 0x21, 0xf1, 0x45, 0x42, 0x8d, 0x2a, 0xc3, 0xea, 0x62, 0xa4, 0x55, 0xe2, 0x79,
+	0xbc, 0xfb, 0x06, 0xe8, 0x74, 0xcc, 0xa8, 0xae, 0x58, 0xf8, 0xe8, 0xaf, 0x60, 0x3c, 0x39, 0x27,
+	0x97, 0x49, 0x52, 0x3b, 0x2c, 0x7a, 0xe9, 0x8b, 0x95, 0x42, 0xe5, 0xb6, 0x4f, 0x47, 0xfa, 0xe4,
+	0xf9, 0x21, 0x5e, 0xb6, 0x07, 0x1a, 0xfa, 0x62, 0x87, 0x7c, 0x78, 0xd8, 0x29, 0xfb, 0xab, 0x4b,
+	0xbf, 0x93, 0x20, 0xd2, 0x99, 0xef, 0x43, 0x4d, 0x99, 0xe6, 0xa5, 0x4c, 0x95, 0x27, 0x3b, 0xec,
+	0xe6, 0x51, 0x6a, 0xc0, 0x43, 0xa8, 0xb1, 0x1b, 0xc3, 0x03, 0x0a, 0xf1, 0x5d, 0x5f, 0xe4, 0x51,
+	0xc9, 0xf5, 0x14, 0x77, 0xa1, 0xae, 0x14, 0xa4, 0x98, 0x53, 0x04, 0xbe, 0x74, 0x62, 0x35, 0xf6,
+	0x51, 0x79, 0xfe, 0x82, 0x9b, 0xdf, 0x17, 0x79, 0x54, 0x3a, 0xff, 0x3d, 0xe8, 0x9a, 0x72, 0x2c,
+	0x9d, 0x5c, 0x72, 0x50, 0x24, 0x1c, 0x99, 0xa3, 0x8c, 0x3e, 0x86, 0x76, 0x21, 0x91, 0x28, 0x7a,
+	0x89, 0x58, 0xcc, 0xe6, 0x16, 0x67, 0x07, 0x8b, 0xef, 0x80, 0xae, 0xd2, 0x2f, 0x47, 0x4a, 0x30,
+	0xe6, 0x24, 0x7b, 0xfa, 0x97, 0xf3, 0x2f, 0x74, 0xaf, 0x7f, 0x00, 0xd7, 0xe6, 0x78, 0x07, 0xe2,
+	0xe6, 0xcb, 0x3d, 0x8f, 0xfe, 0xad, 0x2b, 0xe9, 0x29, 0x03, 0x7e, 0xb7, 0xeb, 0xf4, 0x5d, 0x80,
+	0xcc, 0x48, 0xf2, 0xdd, 0xb8, 0x64, 0x62, 0xfb, 0x37, 0x66, 0xd1, 0xe9, 0x47, 0xb7, 0x2f, 0x9b,
+	0xbd, 0x7e, 0xee, 0x74, 0x67, 0xac, 0x68, 0xff, 0xda, 0x1c, 0x1a, 0xb2, 0x65, 0xa3, 0xf7, 0xd7,
+	0x5f, 0xdc, 0xd4, 0x7e, 0xf3, 0xc5, 0x4d, 0xed, 0x9f, 0xbf, 0xb8, 0xa9, 0xfd, 0xea, 0xb7, 0x37,
+	0x17, 0x7e, 0xf3, 0xdb, 0x9b, 0x0b, 0x7f, 0xf7, 0xdb, 0x9b, 0x0b, 0x47, 0x35, 0xfa, 0xff, 0xe7,
+	0xa3, 0xff, 0x1c, 0x00, 0x4a, 0x57, 0x87, 0x14, 0x75, 0x3a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -6719,6 +6989,7 @@ type WorkerClient interface {
 	UpdateGraphQLSchema(ctx context.Context, in *UpdateGraphQLSchemaRequest, opts ...grpc.CallOption) (*UpdateGraphQLSchemaResponse, error)
 	DeleteNamespace(ctx context.Context, in *DeleteNsRequest, opts ...grpc.CallOption) (*Status, error)
 	TaskStatus(ctx context.Context, in *TaskStatusRequest, opts ...grpc.CallOption) (*TaskStatusResponse, error)
+	RestoreProgress(ctx context.Context, in *RestoreProgressRequest, opts ...grpc.CallOption) (Worker_RestoreProgressClient, error)
 }
 
 type workerClient struct {
@@ -6925,6 +7196,38 @@ func (c *workerClient) TaskStatus(ctx context.Context, in *TaskStatusRequest, op
 	return out, nil
 }
 
+func (c *workerClient) RestoreProgress(ctx context.Context, in *RestoreProgressRequest, opts ...grpc.CallOption) (Worker_RestoreProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Worker_serviceDesc.Streams[3], "/pb.Worker/RestoreProgress", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workerRestoreProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Worker_RestoreProgressClient interface {
+	Recv() (*RestoreProgress, error)
+	grpc.ClientStream
+}
+
+type workerRestoreProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *workerRestoreProgressClient) Recv() (*RestoreProgress, error) {
+	m := new(RestoreProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // WorkerServer is the server API for Worker service.
 type WorkerServer interface {
 	// Data serving RPCs.
@@ -6942,6 +7245,7 @@ type WorkerServer interface {
 	UpdateGraphQLSchema(context.Context, *UpdateGraphQLSchemaRequest) (*UpdateGraphQLSchemaResponse, error)
 	DeleteNamespace(context.Context, *DeleteNsRequest) (*Status, error)
 	TaskStatus(context.Context, *TaskStatusRequest) (*TaskStatusResponse, error)
+	RestoreProgress(*RestoreProgressRequest, Worker_RestoreProgressServer) error
 }
 
 // UnimplementedWorkerServer can be embedded to have forward compatible implementations.
@@ -6990,6 +7294,9 @@ func (*UnimplementedWorkerServer) DeleteNamespace(ctx context.Context, req *Dele
 func (*UnimplementedWorkerServer) TaskStatus(ctx context.Context, req *TaskStatusRequest) (*TaskStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TaskStatus not implemented")
 }
+func (*UnimplementedWorkerServer) RestoreProgress(req *RestoreProgressRequest, srv Worker_RestoreProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method RestoreProgress not implemented")
+}
 
 func RegisterWorkerServer(s *grpc.Server, srv WorkerServer) {
 	s.RegisterService(&_Worker_serviceDesc, srv)
@@ -7266,6 +7573,27 @@ func _Worker_TaskStatus_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Worker_RestoreProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RestoreProgressRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkerServer).RestoreProgress(m, &workerRestoreProgressServer{stream})
+}
+
+type Worker_RestoreProgressServer interface {
+	Send(*RestoreProgress) error
+	grpc.ServerStream
+}
+
+type workerRestoreProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *workerRestoreProgressServer) Send(m *RestoreProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Worker_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.Worker",
 	HandlerType: (*WorkerServer)(nil),
@@ -7332,6 +7660,11 @@ var _Worker_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Worker_Subscribe_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "RestoreProgress",
+			Handler:       _Worker_RestoreProgress_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pb.proto",
 }
@@ -9093,6 +9426,63 @@ func (m *RestoreRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.MapOptions) > 0 {
+		i -= len(m.MapOptions)
+		copy(dAtA[i:], m.MapOptions)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.MapOptions)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xba
+	}
+	if m.SampleRate != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.SampleRate))))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb1
+	}
+	if len(m.CompressionOverrides) > 0 {
+		for k := range m.CompressionOverrides {
+			v := m.CompressionOverrides[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintPb(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i = encodeVarintPb(dAtA, i, uint64(k))
+			i--
+			dAtA[i] = 0x8
+			i = encodeVarintPb(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xaa
+		}
+	}
+	if len(m.RequestId) > 0 {
+		i -= len(m.RequestId)
+		copy(dAtA[i:], m.RequestId)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.RequestId)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa2
+	}
+	if m.SchemaOnly {
+		i--
+		if m.SchemaOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x98
+	}
 	if m.IsPartial {
 		i--
 		if m.IsPartial {
@@ -10099,6 +10489,11 @@ func (m *SchemaUpdate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.IndexRebuild != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.IndexRebuild))
+		i--
+		dAtA[i] = 0x70
+	}
 	if m.NoConflict {
 		i--
 		if m.NoConflict {
@@ -10269,6 +10664,18 @@ func (m *MapHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Codec) > 0 {
+		i -= len(m.Codec)
+		copy(dAtA[i:], m.Codec)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Codec)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.FormatVersion != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.FormatVersion))
+		i--
+		dAtA[i] = 0x10
+	}
 	if len(m.PartitionKeys) > 0 {
 		for iNdEx := len(m.PartitionKeys) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.PartitionKeys[iNdEx])
@@ -11022,6 +11429,13 @@ func (m *BackupResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Checksum) > 0 {
+		i -= len(m.Checksum)
+		copy(dAtA[i:], m.Checksum)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Checksum)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.DropOperations) > 0 {
 		for iNdEx := len(m.DropOperations) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -11613,34 +12027,122 @@ func (m *TaskStatusResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintPb(dAtA []byte, offset int, v uint64) int {
-	offset -= sovPb(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *RestoreProgressRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *List) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *RestoreProgressRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RestoreProgressRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Bitmap)
-	if l > 0 {
-		n += 1 + l + sovPb(uint64(l))
-	}
-	if len(m.SortedUids) > 0 {
-		n += 1 + sovPb(uint64(len(m.SortedUids)*8)) + len(m.SortedUids)*8
+	if len(m.RequestId) > 0 {
+		i -= len(m.RequestId)
+		copy(dAtA[i:], m.RequestId)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.RequestId)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *TaskValue) Size() (n int) {
+func (m *RestoreProgress) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RestoreProgress) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RestoreProgress) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Done {
+		i--
+		if m.Done {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.ElapsedNs != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.ElapsedNs))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.FileId != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.FileId))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Rate != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.Rate))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.BytesProcessed != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.BytesProcessed))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.BytesRead != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.BytesRead))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintPb(dAtA []byte, offset int, v uint64) int {
+	offset -= sovPb(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *List) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Bitmap)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
+	if len(m.SortedUids) > 0 {
+		n += 1 + sovPb(uint64(len(m.SortedUids)*8)) + len(m.SortedUids)*8
+	}
+	return n
+}
+
+func (m *TaskValue) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -12467,6 +12969,28 @@ func (m *RestoreRequest) Size() (n int) {
 	if m.IsPartial {
 		n += 3
 	}
+	if m.SchemaOnly {
+		n += 3
+	}
+	l = len(m.RequestId)
+	if l > 0 {
+		n += 2 + l + sovPb(uint64(l))
+	}
+	if len(m.CompressionOverrides) > 0 {
+		for k, v := range m.CompressionOverrides {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + sovPb(uint64(k)) + 1 + len(v) + sovPb(uint64(len(v)))
+			n += mapEntrySize + 2 + sovPb(uint64(mapEntrySize))
+		}
+	}
+	if m.SampleRate != 0 {
+		n += 10
+	}
+	l = len(m.MapOptions)
+	if l > 0 {
+		n += 2 + l + sovPb(uint64(l))
+	}
 	return n
 }
 
@@ -12886,6 +13410,9 @@ func (m *SchemaUpdate) Size() (n int) {
 	if m.NoConflict {
 		n += 2
 	}
+	if m.IndexRebuild != 0 {
+		n += 1 + sovPb(uint64(m.IndexRebuild))
+	}
 	return n
 }
 
@@ -12920,6 +13447,13 @@ func (m *MapHeader) Size() (n int) {
 			n += 1 + l + sovPb(uint64(l))
 		}
 	}
+	if m.FormatVersion != 0 {
+		n += 1 + sovPb(uint64(m.FormatVersion))
+	}
+	l = len(m.Codec)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
 	return n
 }
 
@@ -13253,6 +13787,10 @@ func (m *BackupResponse) Size() (n int) {
 			n += 1 + l + sovPb(uint64(l))
 		}
 	}
+	l = len(m.Checksum)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
 	return n
 }
 
@@ -13523,6 +14061,46 @@ func (m *TaskStatusResponse) Size() (n int) {
 	return n
 }
 
+func (m *RestoreProgressRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.RequestId)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
+	return n
+}
+
+func (m *RestoreProgress) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BytesRead != 0 {
+		n += 1 + sovPb(uint64(m.BytesRead))
+	}
+	if m.BytesProcessed != 0 {
+		n += 1 + sovPb(uint64(m.BytesProcessed))
+	}
+	if m.Rate != 0 {
+		n += 1 + sovPb(uint64(m.Rate))
+	}
+	if m.FileId != 0 {
+		n += 1 + sovPb(uint64(m.FileId))
+	}
+	if m.ElapsedNs != 0 {
+		n += 1 + sovPb(uint64(m.ElapsedNs))
+	}
+	if m.Done {
+		n += 2
+	}
+	return n
+}
+
 func sovPb(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -19276,61 +19854,11 @@ func (m *RestoreRequest) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.IsPartial = bool(v != 0)
-		default:
-			iNdEx = preIndex
-			skippy, err := skipPb(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthPb
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Proposal) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowPb
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Proposal: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Proposal: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Mutations", wireType)
+		case 19:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SchemaOnly", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPb
@@ -19340,33 +19868,17 @@ func (m *Proposal) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthPb
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthPb
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Mutations == nil {
-				m.Mutations = &Mutations{}
-			}
-			if err := m.Mutations.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 4:
+			m.SchemaOnly = bool(v != 0)
+		case 20:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Kv", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPb
@@ -19376,29 +19888,27 @@ func (m *Proposal) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthPb
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthPb
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Kv = append(m.Kv, &pb.KV{})
-			if err := m.Kv[len(m.Kv)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.RequestId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 21:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CompressionOverrides", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -19425,36 +19935,312 @@ func (m *Proposal) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.State == nil {
-				m.State = &MembershipState{}
-			}
-			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CleanPredicate", wireType)
+			if m.CompressionOverrides == nil {
+				m.CompressionOverrides = make(map[uint32]string)
 			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPb
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
+			var mapkey uint32
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPb
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthPb
-			}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPb
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapkey |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPb
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthPb
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthPb
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipPb(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthPb
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.CompressionOverrides[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 22:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SampleRate", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.SampleRate = float64(math.Float64frombits(v))
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MapOptions", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MapOptions = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Proposal) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Proposal: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Proposal: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mutations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Mutations == nil {
+				m.Mutations = &Mutations{}
+			}
+			if err := m.Mutations.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kv", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Kv = append(m.Kv, &pb.KV{})
+			if err := m.Kv[len(m.Kv)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.State == nil {
+				m.State = &MembershipState{}
+			}
+			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CleanPredicate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
 			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthPb
@@ -21970,6 +22756,25 @@ func (m *SchemaUpdate) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.NoConflict = bool(v != 0)
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexRebuild", wireType)
+			}
+			m.IndexRebuild = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IndexRebuild |= SchemaUpdate_IndexRebuild(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -22168,6 +22973,57 @@ func (m *MapHeader) Unmarshal(dAtA []byte) error {
 			m.PartitionKeys = append(m.PartitionKeys, make([]byte, postIndex-iNdEx))
 			copy(m.PartitionKeys[len(m.PartitionKeys)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FormatVersion", wireType)
+			}
+			m.FormatVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FormatVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Codec", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Codec = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -24314,6 +25170,38 @@ func (m *BackupResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Checksum = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -26120,6 +27008,253 @@ func (m *TaskStatusResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *RestoreProgressRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RestoreProgressRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RestoreProgressRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequestId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RestoreProgress) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RestoreProgress: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RestoreProgress: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesRead", wireType)
+			}
+			m.BytesRead = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BytesRead |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesProcessed", wireType)
+			}
+			m.BytesProcessed = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BytesProcessed |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rate", wireType)
+			}
+			m.Rate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rate |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FileId", wireType)
+			}
+			m.FileId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FileId |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElapsedNs", wireType)
+			}
+			m.ElapsedNs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ElapsedNs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Done", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Done = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipPb(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
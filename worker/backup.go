@@ -18,6 +18,7 @@ package worker
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
@@ -84,6 +85,19 @@ type Manifest struct {
 	DropOperations []*pb.DropOperation `json:"drop_operations"`
 	// Compression keeps track of the compression that was used for the data.
 	Compression string `json:"compression"`
+	// Checksums maps a group to the hex encoded SHA-256 checksum of the decompressed and
+	// decrypted KV stream of its backup file. It is empty for the backups which don't record it.
+	Checksums map[uint32]string `json:"checksums,omitempty"`
+	// EncryptedDataKey is the key the backup was encrypted with, encrypted with the KMS key
+	// KmsKeyId. It is only set for the backups whose key is managed by a KMS.
+	EncryptedDataKey []byte `json:"encrypted_data_key,omitempty"`
+	KmsKeyId         string `json:"kms_key_id,omitempty"`
+	// Settings are the settings of the binary which took the backup that the restore depends
+	// on. They are not recorded by the backups taken before they were added.
+	Settings *BackupSettings `json:"settings,omitempty"`
+	// Leases are the uid and namespace leases of the cluster when the backup was taken, which
+	// bound the uids and the namespaces found in the backup. See EstimateLease.
+	Leases *LeaseEstimate `json:"leases,omitempty"`
 }
 
 // ValidReadTs function returns the valid read timestamp. The backup can have
@@ -206,6 +220,7 @@ var backupLock sync.Mutex
 // BackupRes is used to represent the response and error of the Backup gRPC call together to be
 // transported via a channel.
 type BackupRes struct {
+	gid uint32
 	res *pb.BackupResponse
 	err error
 }
@@ -302,6 +317,7 @@ func ProcessBackupRequest(ctx context.Context, req *pb.BackupRequest) error {
 	defer cancel()
 
 	var dropOperations []*pb.DropOperation
+	checksums := make(map[uint32]string)
 	{ // This is the code which sends out Backup requests and waits for them to finish.
 		resCh := make(chan BackupRes, len(state.Groups))
 		for _, gid := range groups {
@@ -310,7 +326,7 @@ func ProcessBackupRequest(ctx context.Context, req *pb.BackupRequest) error {
 			br.Predicates = predMap[gid]
 			go func(req *pb.BackupRequest) {
 				res, err := BackupGroup(ctx, req)
-				resCh <- BackupRes{res: res, err: err}
+				resCh <- BackupRes{gid: req.GroupId, res: res, err: err}
 			}(br)
 		}
 
@@ -321,6 +337,9 @@ func ProcessBackupRequest(ctx context.Context, req *pb.BackupRequest) error {
 				return backupRes.err
 			}
 			dropOperations = append(dropOperations, backupRes.res.GetDropOperations()...)
+			if sum := backupRes.res.GetChecksum(); sum != "" {
+				checksums[backupRes.gid] = sum
+			}
 		}
 	}
 
@@ -332,6 +351,9 @@ func ProcessBackupRequest(ctx context.Context, req *pb.BackupRequest) error {
 		DropOperations: dropOperations,
 		Path:           dir,
 		Compression:    "snappy",
+		Checksums:      checksums,
+		Settings:       currentBackupSettings(),
+		Leases:         &LeaseEstimate{MaxUid: state.MaxUID, MaxNs: state.MaxNsID},
 	}
 	if req.SinceTs == 0 {
 		m.Type = "full"
@@ -499,6 +521,10 @@ func (pr *BackupProcessor) WriteBackup(ctx context.Context) (*pb.BackupResponse,
 	// With snappy: 7m11s 9.5GB output.
 	// With snappy + S3: 7m54s 9.5GB output.
 	cWriter := snappy.NewBufferedWriter(eWriter)
+	// The checksum recorded in the manifest is the one of the KV stream, before it is
+	// compressed and encrypted, as this is what the restore reads back.
+	h := sha256.New()
+	kvWriter := io.MultiWriter(cWriter, h)
 
 	stream := pr.DB.NewStreamAt(pr.Request.ReadTs)
 	stream.LogPrefix = "Dgraph.Backup"
@@ -570,7 +596,7 @@ func (pr *BackupProcessor) WriteBackup(ctx context.Context) (*pb.BackupResponse,
 				maxVersion = kv.Version
 			}
 		}
-		return writeKVList(list, cWriter)
+		return writeKVList(list, kvWriter)
 	}
 
 	// This is where the execution happens.
@@ -630,7 +656,7 @@ func (pr *BackupProcessor) WriteBackup(ctx context.Context) (*pb.BackupResponse,
 			kv.ExpiresAt = item.ExpiresAt()
 			list.Kv = append(list.Kv, kv)
 		}
-		return writeKVList(list, cWriter)
+		return writeKVList(list, kvWriter)
 	}
 
 	for _, prefix := range []byte{x.ByteSchema, x.ByteType} {
@@ -655,6 +681,7 @@ func (pr *BackupProcessor) WriteBackup(ctx context.Context) (*pb.BackupResponse,
 		glog.Errorf("While closing handler: %v", err)
 		return &response, err
 	}
+	response.Checksum = hex.EncodeToString(h.Sum(nil))
 	glog.Infof("Backup complete: group %d at %d", pr.Request.GroupId, pr.Request.ReadTs)
 	return &response, nil
 }
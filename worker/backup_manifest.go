@@ -103,6 +103,31 @@ func getManifestsToRestore(
 func getFilteredManifests(h x.UriHandler, manifests []*Manifest,
 	req *pb.RestoreRequest) ([]*Manifest, error) {
 
+	// validManifests are the ones for which the corresponding backup files exists.
+	var validManifests []*Manifest
+	for _, m := range manifests {
+		if hasBackupFiles(h, m) {
+			validManifests = append(validManifests, m)
+		}
+	}
+	return filterManifestChain(validManifests, req)
+}
+
+// hasBackupFiles returns true if the backup files of all the groups in the manifest exist.
+func hasBackupFiles(h x.UriHandler, m *Manifest) bool {
+	for g := range m.Groups {
+		path := filepath.Join(m.Path, backupName(m.ValidReadTs(), g))
+		if !h.FileExists(path) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterManifestChain takes the valid manifests, ordered from the oldest to the latest, and
+// returns the chain of manifests that should be considered during a restore, ordered from the
+// latest to the oldest.
+func filterManifestChain(manifests []*Manifest, req *pb.RestoreRequest) ([]*Manifest, error) {
 	// filter takes a list of manifests and returns the list of manifests
 	// that should be considered during a restore.
 	filter := func(manifests []*Manifest, backupId string) ([]*Manifest, error) {
@@ -128,22 +153,7 @@ func getFilteredManifests(h x.UriHandler, manifests []*Manifest,
 		return out, nil
 	}
 
-	// validManifests are the ones for which the corresponding backup files exists.
-	var validManifests []*Manifest
-	for _, m := range manifests {
-		missingFiles := false
-		for g := range m.Groups {
-			path := filepath.Join(m.Path, backupName(m.ValidReadTs(), g))
-			if !h.FileExists(path) {
-				missingFiles = true
-				break
-			}
-		}
-		if !missingFiles {
-			validManifests = append(validManifests, m)
-		}
-	}
-	manifests, err := filter(validManifests, req.BackupId)
+	manifests, err := filter(manifests, req.BackupId)
 	if err != nil {
 		return nil, err
 	}
@@ -158,6 +168,64 @@ func getFilteredManifests(h x.UriHandler, manifests []*Manifest,
 	return manifests, nil
 }
 
+// getManifestsFromLocations gets the manifests from all the given locations and merges them
+// into a single chain, in the same order as getManifestsToRestore returns them. It also
+// returns the handler to be used for reading the backup files of each manifest. Drop operations
+// and the predicates of the groups are taken from the merged chain, the same way as they
+// are for a single location.
+func getManifestsFromLocations(locations []string, req *pb.RestoreRequest) (
+	[]*Manifest, map[*Manifest]x.UriHandler, error) {
+
+	type manifestKey struct {
+		backupId  string
+		backupNum uint64
+	}
+	creds := getCredentialsFromRestoreRequest(req)
+	handlers := make(map[*Manifest]x.UriHandler)
+	seen := make(map[manifestKey]string)
+	var merged []*Manifest
+	for _, loc := range locations {
+		uri, err := url.Parse(loc)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "while parsing location %s", loc)
+		}
+		h, err := x.NewUriHandler(uri, creds)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "while creating handler for location %s", loc)
+		}
+		master, err := GetManifest(h, uri)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "while reading manifests at location %s", loc)
+		}
+		for _, m := range master.Manifests {
+			if !hasBackupFiles(h, m) {
+				continue
+			}
+			key := manifestKey{backupId: m.BackupId, backupNum: m.BackupNum}
+			if other, ok := seen[key]; ok {
+				return nil, nil, errors.Errorf("manifest num %d of backup %s found in both "+
+					"%s and %s", m.BackupNum, m.BackupId, other, loc)
+			}
+			seen[key] = loc
+			handlers[m] = h
+			merged = append(merged, m)
+		}
+	}
+
+	// Order the manifests chronologically, the way they are ordered in a master manifest.
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].ValidReadTs() != merged[j].ValidReadTs() {
+			return merged[i].ValidReadTs() < merged[j].ValidReadTs()
+		}
+		return merged[i].BackupNum < merged[j].BackupNum
+	})
+	manifests, err := filterManifestChain(merged, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return manifests, handlers, nil
+}
+
 // getConsolidatedManifest walks over all the backup directories and generates a master manifest.
 func getConsolidatedManifest(h x.UriHandler, uri *url.URL) (*MasterManifest, error) {
 	// If there is a master manifest already, we just return it.
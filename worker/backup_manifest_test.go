@@ -0,0 +1,90 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestGetManifestsFromLocations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-locations")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	manifest := func(id string, num, readTs uint64) *Manifest {
+		typ := "incremental"
+		if num == 1 {
+			typ = "full"
+		}
+		return &Manifest{Type: typ, BackupId: id, BackupNum: num, ReadTs: readTs,
+			Path: fmt.Sprintf("dgraph.%s.%d", id, num), Version: x.ManifestVersion,
+			Compression: "snappy", Groups: map[uint32][]string{1: {x.GalaxyAttr("name")}}}
+	}
+	// writeLocation writes the master manifest of the location, and an empty backup file for
+	// each of its manifests.
+	writeLocation := func(name string, manifests ...*Manifest) string {
+		loc := filepath.Join(dir, name)
+		for _, m := range manifests {
+			file := filepath.Join(loc, m.Path, backupName(m.ValidReadTs(), 1))
+			require.NoError(t, os.MkdirAll(filepath.Dir(file), 0750))
+			require.NoError(t, ioutil.WriteFile(file, nil, 0600))
+		}
+		b, err := json.Marshal(&MasterManifest{Manifests: manifests})
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(loc, backupManifest), b, 0600))
+		return loc
+	}
+
+	// The series b1 is split over both locations, and b0 is an older series.
+	locA := writeLocation("a", manifest("b1", 3, 30), manifest("b1", 1, 10))
+	locB := writeLocation("b", manifest("b0", 1, 5), manifest("b1", 2, 20))
+	req := &pb.RestoreRequest{Location: locA, RestoreTs: 100}
+	manifests, handlers, err := getManifestsFromLocations([]string{locA, locB}, req)
+	require.NoError(t, err)
+	var got []string
+	for _, m := range manifests {
+		got = append(got, fmt.Sprintf("%s:%d", m.BackupId, m.BackupNum))
+		// The backup file of each manifest is read from its own location.
+		require.True(t, hasBackupFiles(handlers[m], m))
+	}
+	require.Equal(t, []string{"b1:3", "b1:2", "b1:1"}, got)
+
+	// The older series is restored when asked for.
+	req.BackupId = "b0"
+	manifests, _, err = getManifestsFromLocations([]string{locA, locB}, req)
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+	require.Equal(t, "b0", manifests[0].BackupId)
+
+	// The same backup can't be found in two locations.
+	locC := writeLocation("c", manifest("b1", 2, 20))
+	req.BackupId = ""
+	_, _, err = getManifestsFromLocations([]string{locA, locB, locC}, req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprintf("manifest num 2 of backup b1 found in both %s"+
+		" and %s", locB, locC))
+}
@@ -145,6 +145,15 @@ func ProcessRestoreRequest(ctx context.Context, req *pb.RestoreRequest, wg *sync
 	if err := VerifyBackup(req, &creds, currentGroups); err != nil {
 		return errors.Wrapf(err, "failed to verify backup")
 	}
+	// The map options are only used once the restore is proposed to the groups, so they are
+	// checked upfront to fail the request instead.
+	opts, err := MapOptionsFromRequest(req)
+	if err != nil {
+		return err
+	}
+	if err := opts.validate(); err != nil {
+		return errors.Wrapf(err, "invalid restore request")
+	}
 	if err := FillRestoreCredentials(req.Location, req); err != nil {
 		return errors.Wrapf(err, "cannot fill restore proposal with the right credentials")
 	}
@@ -309,7 +318,7 @@ func handleRestoreProposal(ctx context.Context, req *pb.RestoreRequest, pidx uin
 	glog.Infof("Created temporary map directory: %s\n", mapDir)
 
 	// Map the backup.
-	mapRes, err := RunMapper(req, mapDir)
+	mapRes, err := runRequestMapper(req, mapDir)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to map the backup files")
 	}
@@ -528,7 +537,7 @@ func RunOfflineRestore(dir, location, backupId string, keyFile string,
 		}
 		defer os.RemoveAll(mapDir)
 
-		if _, err := RunMapper(req, mapDir); err != nil {
+		if _, err := RunMapper(req, mapDir, MapOptions{}); err != nil {
 			return LoadResult{Err: errors.Wrap(err, "RunRestore failed to map")}
 		}
 		pdir := filepath.Join(dir, fmt.Sprintf("p%d", gid))
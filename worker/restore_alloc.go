@@ -0,0 +1,114 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sync"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/pkg/errors"
+)
+
+// BufferAllocator creates the buffers of the mapper, see MapOptions.BufferAllocator. The
+// mapper owns every buffer it gets, and releases it exactly once with Release when it is done
+// with it, after handing it over between its goroutines or resetting it for reuse. An
+// implementation must not release or reuse a buffer it returned, so it can only track the
+// buffers through their Buffer, like with a buffer backed by a file whose removal it watches.
+type BufferAllocator interface {
+	// NewBuffer returns a buffer of capacity bytes, held in memory, which grows as needed. tag
+	// names the buffer in the allocation stats of z.
+	NewBuffer(capacity int, tag string) *z.Buffer
+	// NewBufferTmp returns a buffer of capacity bytes backed by a temporary file, for the
+	// buffers which can grow larger than the memory.
+	NewBufferTmp(capacity int) (*z.Buffer, error)
+}
+
+// zAllocator is the default BufferAllocator. Its memory buffers are allocated with z.Calloc,
+// which uses jemalloc if the binary is built with it, and the Go heap otherwise.
+type zAllocator struct{}
+
+func (zAllocator) NewBuffer(capacity int, tag string) *z.Buffer {
+	return z.NewBuffer(capacity, tag)
+}
+
+func (zAllocator) NewBufferTmp(capacity int) (*z.Buffer, error) {
+	return z.NewBufferTmp("", capacity)
+}
+
+// newBuffer returns a buffer for the entries of a map file, backed by a temporary file.
+func newBuffer(alloc BufferAllocator) (*z.Buffer, error) {
+	buf, err := alloc.NewBufferTmp(mapFileSz)
+	if err != nil {
+		return nil, errors.Wrap(err, "while allocating a map buffer")
+	}
+	return buf.WithMaxSize(2 * mapFileSz), nil
+}
+
+// bufferPool holds the buffers released by their consumers, so that they can be reset and
+// reused instead of being freed and allocated again. A buffer must only be put back once
+// nothing refers to it anymore.
+type bufferPool struct {
+	alloc func() (*z.Buffer, error)
+	// max is the max number of idle buffers kept. The others are released.
+	max int
+
+	mu     sync.Mutex
+	bufs   []*z.Buffer
+	closed bool
+}
+
+func newBufferPool(max int, alloc func() (*z.Buffer, error)) *bufferPool {
+	return &bufferPool{alloc: alloc, max: max}
+}
+
+// get returns an idle buffer, or a new one if there is none.
+func (bp *bufferPool) get() (*z.Buffer, error) {
+	bp.mu.Lock()
+	if n := len(bp.bufs); n > 0 {
+		buf := bp.bufs[n-1]
+		bp.bufs = bp.bufs[:n-1]
+		bp.mu.Unlock()
+		return buf, nil
+	}
+	bp.mu.Unlock()
+	return bp.alloc()
+}
+
+// put takes the ownership of the buffer. It is kept for reuse if there is room for it, and is
+// released otherwise.
+func (bp *bufferPool) put(buf *z.Buffer) {
+	bp.mu.Lock()
+	if !bp.closed && len(bp.bufs) < bp.max {
+		buf.Reset()
+		bp.bufs = append(bp.bufs, buf)
+		bp.mu.Unlock()
+		return
+	}
+	bp.mu.Unlock()
+	buf.Release()
+}
+
+// close releases the idle buffers. The buffers put back after that are released.
+func (bp *bufferPool) close() {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.closed = true
+	for _, buf := range bp.bufs {
+		buf.Release()
+	}
+	bp.bufs = nil
+}
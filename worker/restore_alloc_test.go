@@ -0,0 +1,123 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// fileAllocator backs all the buffers with files in dir, and counts them by tag.
+type fileAllocator struct {
+	dir string
+
+	mu   sync.Mutex
+	tags map[string]int
+}
+
+func (a *fileAllocator) NewBuffer(capacity int, tag string) *z.Buffer {
+	a.mu.Lock()
+	a.tags[tag]++
+	a.mu.Unlock()
+	buf, err := z.NewBufferTmp(a.dir, capacity)
+	x.Check(err)
+	return buf
+}
+
+func (a *fileAllocator) NewBufferTmp(capacity int) (*z.Buffer, error) {
+	a.mu.Lock()
+	a.tags["tmp"]++
+	a.mu.Unlock()
+	return z.NewBufferTmp(a.dir, capacity)
+}
+
+func TestBufferAllocator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-alloc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	bufDir, err := ioutil.TempDir(dir, "bufs")
+	require.NoError(t, err)
+
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 100; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}}
+
+	alloc := &fileAllocator{dir: bufDir, tags: make(map[string]int)}
+	opts := MapOptions{BufferAllocator: alloc, ProcessBufSize: 1 << 10, ProcessFlushSize: 1}
+	require.NoError(t, opts.validate())
+	m := newMapper(10, dir, opts, 2)
+	m.startPipeline(2)
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.NoError(t, m.stopPipeline())
+
+	files, _, err := mapFiles(dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	alloc.mu.Lock()
+	require.Greater(t, alloc.tags["Restore.Map"], 0)
+	require.Greater(t, alloc.tags["processKVList"], 0)
+	require.Greater(t, alloc.tags["tmp"], 0)
+	alloc.mu.Unlock()
+
+	// The pools are closed once the pipeline stops, so every buffer has been released, which
+	// removes its file.
+	left, err := ioutil.ReadDir(bufDir)
+	require.NoError(t, err)
+	require.Empty(t, left)
+}
+
+// tmpFailAllocator fails to allocate the buffers backed by a file.
+type tmpFailAllocator struct {
+	zAllocator
+}
+
+func (tmpFailAllocator) NewBufferTmp(capacity int) (*z.Buffer, error) {
+	return nil, errors.New("no space left on device")
+}
+
+func TestBufferAllocatorFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-alloc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var stream bytes.Buffer
+	appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", 1))
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}}
+
+	// The map buffers can't be allocated, which fails the map phase instead of crashing.
+	opts := MapOptions{BufferAllocator: tmpFailAllocator{}}
+	require.NoError(t, opts.validate())
+	m := newMapper(10, dir, opts, 2)
+	m.startPipeline(2)
+	// Map fails if the mergers have already given up, so its error is not checked.
+	_ = m.Map(bytes.NewReader(stream.Bytes()), in)
+	err = m.stopPipeline()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "while allocating a map buffer")
+	m.closer.Signal()
+}
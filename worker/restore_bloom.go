@@ -0,0 +1,85 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"github.com/dgraph-io/ristretto/z"
+)
+
+const (
+	// predBloomBitsPerKey and predBloomHashes give a false positive rate of about 1%.
+	predBloomBitsPerKey = 10
+	predBloomHashes     = 7
+)
+
+// predBloom is a bloom filter of the names of a predicateSet, see
+// MapOptions.PredicateBloomFilter. It never reports a name of the set as missing, so a miss
+// skips the exact lookup, and a hit must be confirmed by it.
+type predBloom struct {
+	bits []uint64
+	mask uint64
+}
+
+// newPredBloom returns the bloom filter of the predicates of preds.
+func newPredBloom(preds predicateSet) *predBloom {
+	// The number of bits is rounded up to a power of two, so that the hashes are reduced with
+	// a mask.
+	n := uint64(64)
+	for n < uint64(len(preds))*predBloomBitsPerKey {
+		n <<= 1
+	}
+	bf := &predBloom{bits: make([]uint64, n/64), mask: n - 1}
+	for attr := range preds {
+		bf.add(attr)
+	}
+	return bf
+}
+
+func (bf *predBloom) add(attr string) {
+	h := z.MemHashString(attr)
+	// The hashes are derived from the two halves of h, with double hashing.
+	h1, h2 := h, (h>>32)|1
+	for i := 0; i < predBloomHashes; i++ {
+		bit := h1 & bf.mask
+		bf.bits[bit/64] |= 1 << (bit % 64)
+		h1 += h2
+	}
+}
+
+// mayContain returns false if attr is not in the set, and true if it might be.
+func (bf *predBloom) mayContain(attr string) bool {
+	h := z.MemHashString(attr)
+	h1, h2 := h, (h>>32)|1
+	for i := 0; i < predBloomHashes; i++ {
+		bit := h1 & bf.mask
+		if bf.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+		h1 += h2
+	}
+	return true
+}
+
+// hasPred returns whether attr is in the preds of the input, checking its bloom filter first
+// if it is set.
+func (in *loadBackupInput) hasPred(attr string) bool {
+	if in.predBloom != nil && !in.predBloom.mayContain(attr) {
+		return false
+	}
+	_, ok := in.preds[attr]
+	return ok
+}
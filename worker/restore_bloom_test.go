@@ -0,0 +1,108 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// bloomPreds returns a set of n predicates, and n other predicates which are not in it.
+func bloomPreds(n int) (predicateSet, []string) {
+	preds := make(predicateSet, n)
+	others := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		preds[x.GalaxyAttr(fmt.Sprintf("pred.%d", i))] = struct{}{}
+		others = append(others, x.GalaxyAttr(fmt.Sprintf("other.%d", i)))
+	}
+	return preds, others
+}
+
+func TestPredicateBloomFilter(t *testing.T) {
+	preds, others := bloomPreds(20000)
+	bf := newPredBloom(preds)
+	for attr := range preds {
+		require.True(t, bf.mayContain(attr), attr)
+	}
+	var hits int
+	for _, attr := range others {
+		if bf.mayContain(attr) {
+			hits++
+		}
+	}
+	require.Less(t, hits, len(others)/20)
+
+	// The bloom hits are confirmed by the exact lookup.
+	in := &loadBackupInput{preds: preds, predBloom: bf}
+	for _, attr := range others {
+		require.False(t, in.hasPred(attr))
+	}
+	in = &loadBackupInput{preds: predicateSet{}, predBloom: newPredBloom(predicateSet{})}
+	require.False(t, in.hasPred(x.GalaxyAttr("name")))
+
+	// The keys are filtered the same way with the bloom filter.
+	opts := MapOptions{PredicateBloomFilter: true}
+	require.NoError(t, opts.validate())
+	p := newProcessor(newMapper(10, "", opts, 2))
+	buf := z.NewBuffer(1<<10, "TestPredicateBloomFilter")
+	defer buf.Release()
+	in = &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}}
+	in.predBloom = newPredBloom(in.preds)
+	require.NoError(t, p.processKV(buf, in, nsEdgeKV(t, x.GalaxyNamespace, "name", 1)))
+	require.NoError(t, p.processKV(buf, in, nsEdgeKV(t, x.GalaxyNamespace, "age", 1)))
+	var keys []string
+	require.NoError(t, buf.SliceIterate(func(s []byte) error {
+		pk, err := x.Parse(mapEntry(s).Key())
+		require.NoError(t, err)
+		keys = append(keys, pk.Attr)
+		return nil
+	}))
+	require.Equal(t, []string{x.GalaxyAttr("name")}, keys)
+}
+
+// BenchmarkPredicateFilter compares the lookups of the predicates of the keys in the set of the
+// predicates to restore, with and without the bloom filter, when most of the keys are filtered
+// out.
+func BenchmarkPredicateFilter(b *testing.B) {
+	for _, n := range []int{100, 10000, 100000} {
+		preds, others := bloomPreds(n)
+		// One key in ten is kept.
+		var attrs []string
+		for i, attr := range others {
+			attrs = append(attrs, attr)
+			if i%9 == 0 {
+				attrs = append(attrs, x.GalaxyAttr(fmt.Sprintf("pred.%d", i)))
+			}
+		}
+		for _, bloom := range []bool{false, true} {
+			in := &loadBackupInput{preds: preds}
+			if bloom {
+				in.predBloom = newPredBloom(preds)
+			}
+			b.Run(fmt.Sprintf("preds=%d/bloom=%v", n, bloom), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					in.hasPred(attrs[i%len(attrs)])
+				}
+			})
+		}
+	}
+}
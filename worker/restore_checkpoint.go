@@ -0,0 +1,315 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// MapCheckpoint records the progress of the map phase, so that a failed map phase can be
+// resumed without mapping again the data which is already in the map files.
+type MapCheckpoint struct {
+	// Files holds the progress of every backup file whose mapping has started, keyed by the
+	// path of the file relative to its location.
+	Files map[string]*FileCheckpoint `json:"files"`
+	// MapFiles are the map files which were completely written.
+	MapFiles []MapFileInfo `json:"map_files"`
+	// NextFileId is the id after which the map files created on resume are numbered.
+	NextFileId uint32 `json:"next_file_id"`
+	// MaxUid and MaxNs are the max uid and namespace seen so far.
+	MaxUid uint64 `json:"max_uid"`
+	MaxNs  uint64 `json:"max_ns"`
+}
+
+// FileCheckpoint is the progress of a backup file.
+type FileCheckpoint struct {
+	// Offset is the offset right after the last frame whose data is in the map files. It is
+	// an offset in the stream after decryption and decompression, so it is always at a frame
+	// boundary.
+	Offset uint64 `json:"offset"`
+	// Done is set once all the frames of the file are in the map files.
+	Done bool `json:"done"`
+}
+
+// readCheckpoint reads the checkpoint from the file. It returns nil if the file doesn't exist.
+func readCheckpoint(file string) (*MapCheckpoint, error) {
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp MapCheckpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, errors.Wrapf(err, "while parsing checkpoint %s", file)
+	}
+	if cp.Files == nil {
+		cp.Files = make(map[string]*FileCheckpoint)
+	}
+	return &cp, nil
+}
+
+// writeCheckpoint replaces the checkpoint in the file.
+func writeCheckpoint(file string, cp *MapCheckpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(file, b)
+}
+
+// writeFileAtomic replaces the contents of the file. The contents are written to a temporary
+// file first, so that the file is never observed partially written.
+func writeFileAtomic(file string, b []byte) error {
+	tmp := file + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// checkpointer tracks which frames of the backup files have made it to the map files. A frame
+// is only recorded once all the map files holding its entries have been written, and only if
+// all the frames before it in its file have been recorded as well.
+type checkpointer struct {
+	file string
+
+	mu    sync.Mutex
+	cp    *MapCheckpoint
+	dirty bool
+}
+
+func newCheckpointer(file string, cp *MapCheckpoint) *checkpointer {
+	if cp == nil {
+		cp = &MapCheckpoint{Files: make(map[string]*FileCheckpoint)}
+	}
+	return &checkpointer{file: file, cp: cp}
+}
+
+// resumeCheckpoint reads the checkpoint of CheckpointFile, and restores the state of the mapper
+// from it if a previous run failed. It returns true if the map phase is resumed.
+func (m *mapper) resumeCheckpoint(mapDir string) (bool, error) {
+	cp, err := readCheckpoint(m.opts.CheckpointFile)
+	if err != nil {
+		return false, errors.Wrap(err, "while reading the map checkpoint")
+	}
+	m.ckpt = newCheckpointer(m.opts.CheckpointFile, cp)
+	if cp == nil {
+		return false, nil
+	}
+	if m.mapStore == nil {
+		if err := removeUncheckpointedMapFiles(mapDir, cp); err != nil {
+			return false, errors.Wrap(err, "while removing the map files of the failed run")
+		}
+	}
+	m.written = cp.MapFiles
+	m.nextId = cp.NextFileId
+	m.maxUid, m.maxNs = cp.MaxUid, cp.MaxNs
+	glog.Infof("%sResuming the map phase from checkpoint: %s with %d map files",
+		m.logPrefix, m.opts.CheckpointFile, len(cp.MapFiles))
+	return true, nil
+}
+
+// resumeFrom returns the progress recorded for the backup file.
+func (c *checkpointer) resumeFrom(file string) FileCheckpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if fc, ok := c.cp.Files[file]; ok {
+		return *fc
+	}
+	return FileCheckpoint{}
+}
+
+// track starts tracking the frames of the backup file, which are read from the offset.
+func (c *checkpointer) track(file string, offset uint64) *fileTracker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.cp.Files[file]; !ok {
+		c.cp.Files[file] = &FileCheckpoint{Offset: offset}
+	}
+	return &fileTracker{c: c, file: file}
+}
+
+// markDirty makes the next save write the checkpoint out.
+func (c *checkpointer) markDirty() {
+	c.mu.Lock()
+	c.dirty = true
+	c.mu.Unlock()
+}
+
+// save writes the checkpoint out if it changed since the last time.
+func (c *checkpointer) save(m *mapper) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	c.cp.MapFiles = m.writtenFiles()
+	c.cp.NextFileId = atomic.LoadUint32(&m.nextId)
+	c.cp.MaxUid = atomic.LoadUint64(&m.maxUid)
+	c.cp.MaxNs = atomic.LoadUint64(&m.maxNs)
+	if err := writeCheckpoint(c.file, c.cp); err != nil {
+		return errors.Wrapf(err, "while writing checkpoint %s", c.file)
+	}
+	c.dirty = false
+	return nil
+}
+
+// fileTracker tracks the frames of a backup file.
+type fileTracker struct {
+	c    *checkpointer
+	file string
+	// pending are the batches of frames being mapped, in the order of their offsets. They
+	// are guarded by c.mu.
+	pending []*frameAck
+}
+
+// newAck starts tracking the batch of frames which ends at the offset. last is set for the
+// last batch of the file.
+func (ft *fileTracker) newAck(end uint64, last bool) *frameAck {
+	if ft == nil {
+		return nil
+	}
+	ft.c.mu.Lock()
+	defer ft.c.mu.Unlock()
+	a := &frameAck{ft: ft, end: end, last: last, refs: 1}
+	ft.pending = append(ft.pending, a)
+	return a
+}
+
+// frameAck is a batch of frames of a backup file. It is held by the request which maps the
+// frames and by every buffer which holds some of their entries, until the map file holding
+// the entries is written. The batch is done once all of them have released it.
+type frameAck struct {
+	ft   *fileTracker
+	end  uint64
+	last bool
+	// refs and failed are guarded by ft.c.mu. failed is set once the batch is nacked.
+	refs   int
+	failed bool
+}
+
+func (a *frameAck) hold() {
+	if a == nil {
+		return
+	}
+	a.ft.c.mu.Lock()
+	a.refs++
+	a.ft.c.mu.Unlock()
+}
+
+func (a *frameAck) release() {
+	if a == nil {
+		return
+	}
+	ft := a.ft
+	ft.c.mu.Lock()
+	defer ft.c.mu.Unlock()
+	a.refs--
+	if a.refs > 0 {
+		return
+	}
+	// Advance the offset of the file past all the batches which are done, in order.
+	fc := ft.c.cp.Files[ft.file]
+	for len(ft.pending) > 0 && ft.pending[0].refs == 0 && !ft.pending[0].failed {
+		done := ft.pending[0]
+		ft.pending = ft.pending[1:]
+		fc.Offset = done.end
+		fc.Done = done.last
+		ft.c.dirty = true
+	}
+}
+
+// nack releases the batch without it being done, as some of its entries were not written. The
+// offset of the file never advances past it, so its frames are mapped again on resume.
+func (a *frameAck) nack() {
+	if a == nil {
+		return
+	}
+	a.ft.c.mu.Lock()
+	defer a.ft.c.mu.Unlock()
+	a.failed = true
+	a.refs--
+}
+
+// releaseAcks releases all the acks.
+func releaseAcks(acks []*frameAck) {
+	for _, a := range acks {
+		a.release()
+	}
+}
+
+// nackAcks nacks all the acks.
+func nackAcks(acks []*frameAck) {
+	for _, a := range acks {
+		a.nack()
+	}
+}
+
+// removeUncheckpointedMapFiles removes the map files in the local map directory which are not
+// recorded in the checkpoint. They were written by the failed run after the checkpoint was
+// saved, or were left incomplete by it. Their entries are mapped again on resume.
+func removeUncheckpointedMapFiles(mapDir string, cp *MapCheckpoint) error {
+	if _, err := os.Stat(mapDir); os.IsNotExist(err) {
+		return nil
+	}
+	files, _, err := mapFiles(mapDir)
+	if err != nil {
+		return err
+	}
+	keep := make(map[string]struct{}, len(cp.MapFiles))
+	for _, info := range cp.MapFiles {
+		keep[filepath.Clean(info.Name)] = struct{}{}
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		rel, err := filepath.Rel(mapDir, file)
+		if err != nil {
+			return err
+		}
+		if _, ok := keep[rel]; ok {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+		glog.Infof("Removed map file: %s which is not in the checkpoint", file)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestMapResumeFromOffset(t *testing.T) {
+	// The backup has a frame per predicate. The first two are already mapped.
+	var stream bytes.Buffer
+	var offset uint64
+	preds := make(predicateSet)
+	for i := 0; i < 5; i++ {
+		attr := fmt.Sprintf("p%d", i)
+		preds[x.GalaxyAttr(attr)] = struct{}{}
+		appendKVList(t, &stream, edgeKV(t, pb.BackupKey_DATA, attr, 1, 2))
+		if i == 1 {
+			offset = uint64(stream.Len())
+		}
+	}
+
+	readers := map[string]func() io.Reader{
+		"seek": func() io.Reader { return bytes.NewReader(stream.Bytes()) },
+		// The reader hides the Seek method of bytes.Reader, so the frames are skipped.
+		"skip": func() io.Reader { return struct{ io.Reader }{bytes.NewReader(stream.Bytes())} },
+	}
+	for name, reader := range readers {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "restore-map")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			ckptFile := filepath.Join(dir, "checkpoint.json")
+			m := newMapper(10, filepath.Join(dir, "map"), MapOptions{CheckpointFile: ckptFile}, 2)
+			m.ckpt = newCheckpointer(ckptFile, nil)
+			m.startPipeline(2)
+			defer m.closer.Signal()
+			in := &loadBackupInput{
+				preds:       preds,
+				startOffset: offset,
+				tracker:     m.ckpt.track("backup", offset),
+			}
+			require.NoError(t, m.Map(reader(), in))
+			require.NoError(t, m.stopPipeline())
+			require.NoError(t, m.ckpt.save(m))
+
+			require.Equal(t, map[string]struct{}{
+				x.GalaxyAttr("p2"): {},
+				x.GalaxyAttr("p3"): {},
+				x.GalaxyAttr("p4"): {},
+			}, m.seenPreds)
+
+			cp, err := readCheckpoint(ckptFile)
+			require.NoError(t, err)
+			require.Equal(t, &FileCheckpoint{Offset: uint64(stream.Len()), Done: true},
+				cp.Files["backup"])
+			require.Len(t, cp.MapFiles, 1)
+			require.Equal(t, uint32(1), cp.NextFileId)
+		})
+	}
+}
+
+func TestSkipFrames(t *testing.T) {
+	var stream bytes.Buffer
+	appendKVList(t, &stream, edgeKV(t, pb.BackupKey_DATA, "name", 1, 2))
+	end := uint64(stream.Len())
+	appendKVList(t, &stream, edgeKV(t, pb.BackupKey_DATA, "name", 2, 3))
+
+	require.NoError(t, skipFrames(bufio.NewReader(bytes.NewReader(stream.Bytes())), end))
+	err := skipFrames(bufio.NewReader(bytes.NewReader(stream.Bytes())), end+1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not at a frame boundary")
+}
+
+func TestFrameAcksInOrder(t *testing.T) {
+	c := newCheckpointer("", nil)
+	ft := c.track("backup", 0)
+	first := ft.newAck(10, false)
+	second := ft.newAck(20, true)
+
+	// The second batch is done first, but the offset can't skip past the first one.
+	second.release()
+	require.Equal(t, FileCheckpoint{}, c.resumeFrom("backup"))
+	first.hold()
+	first.release()
+	require.Equal(t, FileCheckpoint{}, c.resumeFrom("backup"))
+	first.release()
+	require.Equal(t, FileCheckpoint{Offset: 20, Done: true}, c.resumeFrom("backup"))
+}
+
+func TestNackedFrameAck(t *testing.T) {
+	c := newCheckpointer("", nil)
+	ft := c.track("backup", 0)
+	first := ft.newAck(10, false)
+	second := ft.newAck(20, true)
+
+	// The entries of the first batch were not written, so the offset stays before it.
+	first.hold()
+	first.nack()
+	first.release()
+	second.release()
+	require.Equal(t, FileCheckpoint{}, c.resumeFrom("backup"))
+}
+
+func TestWriteNowNacksOnError(t *testing.T) {
+	f, err := ioutil.TempFile("", "restore-map")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	defer os.Remove(f.Name())
+
+	// The map files can't be created under a regular file.
+	m := newMapper(10, filepath.Join(f.Name(), "map"), MapOptions{}, 2)
+	c := newCheckpointer("", nil)
+	ack := c.track("backup", 0).newAck(10, true)
+	buf := z.NewBuffer(1<<10, "TestWriteNowNacksOnError")
+	me := buf.SliceAllocate(2 + 3 + 1)
+	binary.BigEndian.PutUint16(me, 3)
+	copy(me[2:], "key")
+
+	m.writers <- struct{}{}
+	require.Error(t, m.writeNow(buf, 1, []*frameAck{ack}))
+	require.True(t, ack.failed)
+	require.Zero(t, ack.refs)
+	require.Equal(t, FileCheckpoint{}, c.resumeFrom("backup"))
+	// The slot of the writer is given back.
+	require.Zero(t, len(m.writers))
+}
+
+func TestRemoveUncheckpointedMapFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"000001.map", "m2/000002.map", "m2/000003.map"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0750))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+	cp := &MapCheckpoint{MapFiles: []MapFileInfo{{Name: "000001.map"}, {Name: "m2/000002.map"}}}
+	require.NoError(t, removeUncheckpointedMapFiles(dir, cp))
+	files, _, err := mapFiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "000001.map"),
+		filepath.Join(dir, "m2", "000002.map")}, files)
+	require.NoError(t, removeUncheckpointedMapFiles(filepath.Join(dir, "missing"), cp))
+}
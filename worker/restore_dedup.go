@@ -0,0 +1,59 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"sync/atomic"
+
+	"github.com/dgraph-io/badger/v3/y"
+	"github.com/golang/glog"
+)
+
+// latestEntries wraps the iteration over the sorted entries of a buffer, to only pass on the
+// latest version of every key, for DedupLatest. The entries are sorted by y.CompareKeys, so the
+// first entry of a key is its latest version. The parts of a multi-part list have keys of their
+// own, so they are never deduped against each other or against their main list. The versions
+// of a key in different map files are still picked by the reduce phase.
+func (m *mapper) latestEntries(iterate func(func([]byte) error) error) func(
+	func([]byte) error) error {
+	return func(fn func([]byte) error) error {
+		var last []byte
+		var stale uint64
+		err := iterate(func(slice []byte) error {
+			key := y.ParseKey(mapEntry(slice).Key())
+			if last != nil && bytes.Equal(last, key) {
+				stale++
+				return nil
+			}
+			last = key
+			return fn(slice)
+		})
+		atomic.AddUint64(&m.staleEntries, stale)
+		return err
+	}
+}
+
+// staleEntryCount returns the number of entries dropped by DedupLatest.
+func (m *mapper) staleEntryCount() uint64 {
+	n := atomic.LoadUint64(&m.staleEntries)
+	if n > 0 {
+		glog.Infof("%sDropped %d entries which were not the latest version of their key",
+			m.logPrefix, n)
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// checkEncryption verifies that the supplied encryption key is consistent with the encryption
+// declared by the manifests that are going to be mapped. Manifests with an empty Type were
+// written by older versions which did not record the encryption, so they are not checked. The
+// key of a manifest is its data key decrypted with the KMS if it has one, and rawKey otherwise.
+func checkEncryption(manifests []*Manifest, req *pb.RestoreRequest, rawKey x.Sensitive,
+	dataKeys map[*Manifest]x.Sensitive) error {
+	for _, manifest := range manifests {
+		if manifest.BackupNum < req.IncrementalFrom {
+			break
+		}
+		encKey := rawKey
+		if key, ok := dataKeys[manifest]; ok {
+			encKey = key
+		}
+		if manifest.Type == "" || manifest.Encrypted {
+			// The key is used to decrypt the backups of the older versions as well, as
+			// they did not record whether they were encrypted.
+			if err := checkEncKeyLength(encKey); err != nil {
+				return errors.Wrapf(err, "for manifest num: %d, path: %s",
+					manifest.BackupNum, manifest.Path)
+			}
+		}
+		if manifest.Type == "" {
+			continue
+		}
+		switch {
+		case manifest.Encrypted && len(encKey) == 0:
+			return errors.Errorf("backup is encrypted but no key provided. Manifest num: %d,"+
+				" path: %s", manifest.BackupNum, manifest.Path)
+		case !manifest.Encrypted && len(encKey) > 0:
+			glog.Warningf("An encryption key was provided but the backup with manifest num: %d,"+
+				" path: %s is not encrypted. Ignoring the key for this backup.",
+				manifest.BackupNum, manifest.Path)
+		}
+	}
+	return nil
+}
+
+// checkEncKeyLength checks that the encryption key, if any, can be used to decrypt the backups.
+// The backups are encrypted with AES, in the mode picked by the length of the key, so the key
+// must be 16, 24 or 32 bytes long. The manifests don't record which one was used, so a key of a
+// valid length which is not the one of the backup is only caught while reading the backup.
+func checkEncKeyLength(encKey x.Sensitive) error {
+	switch len(encKey) {
+	case 0, 16, 24, 32:
+		return nil
+	default:
+		return errors.Errorf("invalid encryption key: expected a 16, 24 or 32-byte key for"+
+			" AES-128, AES-192 or AES-256, got %d bytes", len(encKey))
+	}
+}
+
+// manifestEncKey returns the key the backups of the manifest are decrypted with. It is the data
+// key of the manifest decrypted with the KMS if it has one, and rawKey otherwise.
+func manifestEncKey(manifest *Manifest, rawKey x.Sensitive,
+	dataKeys map[*Manifest]x.Sensitive) x.Sensitive {
+	if manifest.Type != "" && !manifest.Encrypted {
+		// The manifest says that this backup is not encrypted. Do not try to decrypt it
+		// with the supplied key, that would only end up garbling the stream.
+		return nil
+	}
+	if key, ok := dataKeys[manifest]; ok {
+		return key
+	}
+	return rawKey
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// faultPoint is a point of the restore pipeline where a failure can be injected.
+type faultPoint int
+
+const (
+	// faultRead is hit on every read from a backup file.
+	faultRead faultPoint = iota
+	// faultProcess is hit for every KV processed by processKV.
+	faultProcess
+	// faultMerge is hit for every processed buffer received by mergeAndSend.
+	faultMerge
+	// faultWrite is hit for every map file written by writeToDisk.
+	faultWrite
+	numFaultPoints
+)
+
+var faultPointNames = [numFaultPoints]string{"read", "process", "merge", "write"}
+
+// errInjected is the error returned at a fault point once it fails.
+var errInjected = errors.New("injected failure")
+
+// faultInjector makes the fault points fail after a given number of operations. Once a point
+// fails, it keeps failing.
+type faultInjector struct {
+	// after is the number of operations allowed to succeed at each point. A negative value
+	// never fails.
+	after [numFaultPoints]int64
+	ops   [numFaultPoints]int64
+}
+
+// newFaultInjector returns an injector which doesn't fail at any point.
+func newFaultInjector() *faultInjector {
+	fi := &faultInjector{}
+	for i := range fi.after {
+		fi.after[i] = -1
+	}
+	return fi
+}
+
+// failAfter makes the point fail after n operations.
+func (fi *faultInjector) failAfter(point faultPoint, n int64) *faultInjector {
+	fi.after[point] = n
+	return fi
+}
+
+// restoreFaults is the injector used by the restore pipeline. It is only set by tests, and is
+// nil otherwise, in which case all the fault points are no-ops.
+var restoreFaults *faultInjector
+
+// injectFault returns errInjected if the point should fail.
+func injectFault(point faultPoint) error {
+	fi := restoreFaults
+	if fi == nil || fi.after[point] < 0 {
+		return nil
+	}
+	if atomic.AddInt64(&fi.ops[point], 1) > fi.after[point] {
+		return errors.Wrapf(errInjected, "at %s", faultPointNames[point])
+	}
+	return nil
+}
+
+// faultReader injects the read failures into the reads of a backup file.
+type faultReader struct {
+	io.ReadCloser
+}
+
+func (fr *faultReader) Read(p []byte) (int, error) {
+	if err := injectFault(faultRead); err != nil {
+		return 0, err
+	}
+	return fr.ReadCloser.Read(p)
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bufio"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// maxFramePeek is the max number of bytes peeked at the beginning of a frame to find the
+// namespace of its first key.
+const maxFramePeek = 4 << 10
+
+// isPriorityFrame returns true if the first key of the frame of size sz, which is about to be
+// read from br, belongs to one of the priority namespaces. The frame is peeked, not read.
+func (m *mapper) isPriorityFrame(br *bufio.Reader, sz uint64) bool {
+	n := maxFramePeek
+	if sz < uint64(n) {
+		n = int(sz)
+	}
+	// Peek returns what it could read along with the error, which is left to the read of
+	// the frame.
+	b, _ := br.Peek(n)
+	ns, ok := frameNamespace(b)
+	if !ok {
+		return false
+	}
+	_, prio := m.priorityNs[ns]
+	return prio
+}
+
+// frameNamespace returns the namespace of the first key of the marshalled KVList, of which b
+// holds the beginning. It returns false if the key is not found in b.
+func frameNamespace(b []byte) (uint64, bool) {
+	key, ok := frameKey(b)
+	if !ok {
+		return 0, false
+	}
+	return key.Namespace, true
+}
+
+// frameKey returns the first key of the marshalled KVList, of which b holds the beginning. It
+// returns false if the key is not found in b.
+func frameKey(b []byte) (*pb.BackupKey, bool) {
+	// The KVList starts with the tag of its first KV, which is field 1 and length delimited,
+	// followed by the size of the KV.
+	next := func() (uint64, bool) {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return 0, false
+		}
+		b = b[n:]
+		return v, true
+	}
+	if tag, ok := next(); !ok || tag != 1<<3|2 {
+		return nil, false
+	}
+	if _, ok := next(); !ok {
+		return nil, false
+	}
+	// The key is the first field of the KV.
+	if tag, ok := next(); !ok || tag != 1<<3|2 {
+		return nil, false
+	}
+	sz, ok := next()
+	if !ok || sz > uint64(len(b)) {
+		return nil, false
+	}
+	var key pb.BackupKey
+	if err := key.Unmarshal(b[:sz]); err != nil {
+		return nil, false
+	}
+	return &key, true
+}
+
+// skipFrames reads and discards the frames up to the offset, which must be at a frame
+// boundary.
+func skipFrames(r *bufio.Reader, offset uint64) error {
+	var read uint64
+	for read < offset {
+		var sz uint64
+		if err := binary.Read(r, binary.LittleEndian, &sz); err != nil {
+			return err
+		}
+		if _, err := r.Discard(int(sz)); err != nil {
+			return err
+		}
+		read += 8 + sz
+	}
+	if read != offset {
+		return errors.Errorf("offset: %d is not at a frame boundary", offset)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// mapHeartbeat is the contents of the heartbeat file, see MapOptions.HeartbeatFile.
+type mapHeartbeat struct {
+	Time           time.Time `json:"time"`
+	ElapsedMs      int64     `json:"elapsed_ms"`
+	BytesRead      uint64    `json:"bytes_read"`
+	BytesProcessed uint64    `json:"bytes_processed"`
+	FileId         uint32    `json:"file_id"`
+	Done           bool      `json:"done"`
+}
+
+// writeHeartbeat replaces the heartbeat file with the progress, if MapOptions.HeartbeatFile is
+// set. A failure is only logged, as the heartbeat must never abort the restore.
+func (m *mapper) writeHeartbeat(p *MapProgress) {
+	file := m.opts.HeartbeatFile
+	if file == "" {
+		return
+	}
+	b, err := json.Marshal(&mapHeartbeat{
+		Time:           time.Now().UTC(),
+		ElapsedMs:      p.Elapsed.Milliseconds(),
+		BytesRead:      p.BytesRead,
+		BytesProcessed: p.BytesProcessed,
+		FileId:         p.FileId,
+		Done:           p.Final,
+	})
+	if err == nil {
+		if filepath.IsAbs(file) {
+			err = writeFileAtomic(file, b)
+		} else {
+			err = m.writeMapDirFile(file, b)
+		}
+	}
+	if err != nil {
+		glog.Warningf("%sUnable to write the heartbeat file: %s. Err: %v", m.logPrefix, file,
+			err)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteHeartbeat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-heartbeat")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	read := func(file string) mapHeartbeat {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		var hb mapHeartbeat
+		require.NoError(t, json.Unmarshal(b, &hb))
+		return hb
+	}
+
+	// A file name is written to the map directory, with the prefix of the request id.
+	m := newMapper(10, dir, MapOptions{HeartbeatFile: "heartbeat.json"}, 2)
+	m.setRequestId("req")
+	before := time.Now()
+	m.writeHeartbeat(&MapProgress{Elapsed: 2 * time.Second, BytesRead: 100,
+		BytesProcessed: 80, FileId: 3})
+	hb := read(filepath.Join(dir, "req-heartbeat.json"))
+	require.False(t, hb.Time.Before(before.Add(-time.Second)))
+	require.Equal(t, mapHeartbeat{Time: hb.Time, ElapsedMs: 2000, BytesRead: 100,
+		BytesProcessed: 80, FileId: 3}, hb)
+
+	m.writeHeartbeat(&MapProgress{BytesRead: 200, Final: true})
+	hb = read(filepath.Join(dir, "req-heartbeat.json"))
+	require.Equal(t, uint64(200), hb.BytesRead)
+	require.True(t, hb.Done)
+
+	// An absolute path is written as it is.
+	file := filepath.Join(dir, "watchdog", "heartbeat.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0750))
+	m = newMapper(10, dir, MapOptions{HeartbeatFile: file}, 2)
+	m.writeHeartbeat(&MapProgress{BytesRead: 1})
+	require.Equal(t, uint64(1), read(file).BytesRead)
+
+	// A failure to write the heartbeat is only logged.
+	m = newMapper(10, dir, MapOptions{HeartbeatFile: filepath.Join(dir, "missing", "hb")}, 2)
+	m.writeHeartbeat(&MapProgress{BytesRead: 1})
+	_, err = os.Stat(filepath.Join(dir, "missing"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// BackupInventory is a catalog of what a restore of a backup would contain.
+type BackupInventory struct {
+	Manifests  []InventoryManifest  `json:"manifests"`
+	Namespaces []uint64             `json:"namespaces"`
+	Predicates []InventoryPredicate `json:"predicates"`
+	Types      []string             `json:"types"`
+}
+
+// InventoryManifest summarizes a manifest in the chain of backups being restored.
+type InventoryManifest struct {
+	BackupNum      uint64   `json:"backup_num"`
+	Type           string   `json:"type"`
+	ReadTs         uint64   `json:"read_ts"`
+	Path           string   `json:"path"`
+	Groups         []uint32 `json:"groups"`
+	DropOperations int      `json:"drop_operations"`
+}
+
+// InventoryPredicate describes a predicate found in the backup. NumKVs is the number of
+// key-values for the predicate as stored in the backup, which approximates its size.
+type InventoryPredicate struct {
+	Namespace uint64 `json:"namespace"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	NumKVs    uint64 `json:"num_kvs"`
+}
+
+// inventory accumulates the counts collected by the processors in the inventory mode.
+type inventory struct {
+	sync.Mutex
+	manifests  []InventoryManifest
+	namespaces map[uint64]struct{}
+	counts     map[string]uint64
+	predTypes  map[string]string
+	types      map[string]struct{}
+}
+
+func newInventory() *inventory {
+	return &inventory{
+		namespaces: make(map[uint64]struct{}),
+		counts:     make(map[string]uint64),
+		predTypes:  make(map[string]string),
+		types:      make(map[string]struct{}),
+	}
+}
+
+func (inv *inventory) addManifest(m *Manifest) {
+	im := InventoryManifest{
+		BackupNum:      m.BackupNum,
+		Type:           m.Type,
+		ReadTs:         m.ValidReadTs(),
+		Path:           m.Path,
+		DropOperations: len(m.DropOperations),
+	}
+	for gid := range m.Groups {
+		im.Groups = append(im.Groups, gid)
+	}
+	sort.Slice(im.Groups, func(i, j int) bool { return im.Groups[i] < im.Groups[j] })
+
+	inv.Lock()
+	defer inv.Unlock()
+	inv.manifests = append(inv.manifests, im)
+}
+
+// merge adds the counts collected by a processor to the inventory.
+func (inv *inventory) merge(other *inventory) {
+	inv.Lock()
+	defer inv.Unlock()
+	for ns := range other.namespaces {
+		inv.namespaces[ns] = struct{}{}
+	}
+	for attr, cnt := range other.counts {
+		inv.counts[attr] += cnt
+	}
+	for attr, typ := range other.predTypes {
+		inv.predTypes[attr] = typ
+	}
+	for typ := range other.types {
+		inv.types[typ] = struct{}{}
+	}
+}
+
+func (inv *inventory) recordSchema(attr string, update *pb.SchemaUpdate) {
+	typ := types.TypeID(update.ValueType).Name()
+	if update.List {
+		typ = "[" + typ + "]"
+	}
+	inv.predTypes[attr] = typ
+}
+
+// countPostingList counts a posting list of the namespaced predicate in the namespace.
+func (inv *inventory) countPostingList(ns uint64, attr string) {
+	inv.namespaces[ns] = struct{}{}
+	inv.counts[attr]++
+}
+
+// countSchema records the marshalled schema or type update of the key in the namespace.
+func (inv *inventory) countSchema(ns uint64, pk x.ParsedKey, val []byte) error {
+	inv.namespaces[ns] = struct{}{}
+	if pk.IsType() {
+		inv.types[pk.Attr] = struct{}{}
+		return nil
+	}
+	var update pb.SchemaUpdate
+	if err := update.Unmarshal(val); err != nil {
+		return errors.Wrapf(err, "while reading schema of %s", pk.Attr)
+	}
+	inv.recordSchema(pk.Attr, &update)
+	return nil
+}
+
+func (inv *inventory) report() *BackupInventory {
+	inv.Lock()
+	defer inv.Unlock()
+
+	res := &BackupInventory{Manifests: inv.manifests}
+	for ns := range inv.namespaces {
+		res.Namespaces = append(res.Namespaces, ns)
+	}
+	sort.Slice(res.Namespaces, func(i, j int) bool {
+		return res.Namespaces[i] < res.Namespaces[j]
+	})
+
+	// A predicate can have a schema without having any data and vice versa.
+	attrs := make(map[string]struct{})
+	for attr := range inv.counts {
+		attrs[attr] = struct{}{}
+	}
+	for attr := range inv.predTypes {
+		attrs[attr] = struct{}{}
+	}
+	for attr := range attrs {
+		ns, name := x.ParseNamespaceAttr(attr)
+		res.Predicates = append(res.Predicates, InventoryPredicate{
+			Namespace: ns,
+			Name:      name,
+			Type:      inv.predTypes[attr],
+			NumKVs:    inv.counts[attr],
+		})
+	}
+	sort.Slice(res.Predicates, func(i, j int) bool {
+		pi, pj := res.Predicates[i], res.Predicates[j]
+		if pi.Namespace != pj.Namespace {
+			return pi.Namespace < pj.Namespace
+		}
+		return pi.Name < pj.Name
+	})
+
+	for typ := range inv.types {
+		res.Types = append(res.Types, typ)
+	}
+	sort.Strings(res.Types)
+	return res
+}
+
+// Inventory reads the backups which would be restored by the given request and returns a
+// catalog of their contents, without writing any map files. The backups are read and filtered
+// the same way as the map phase does, but the posting lists are only counted, not decoded.
+func Inventory(req *pb.RestoreRequest) (*BackupInventory, error) {
+	inv := newInventory()
+	if _, err := RunMapper(req, "", MapOptions{inventory: inv}); err != nil {
+		return nil, errors.Wrap(err, "while taking inventory of the backup")
+	}
+	return inv.report(), nil
+}
+
+// LeaseEstimate holds the leases a restore needs to bump to.
+type LeaseEstimate struct {
+	MaxUid uint64 `json:"max_uid"`
+	MaxNs  uint64 `json:"max_ns"`
+}
+
+// EstimateLease returns the max uid and the max namespace that the restore of the given request
+// would bump the leases to. They are read from the leases recorded in the manifests, so no
+// backup file is read. The leases are the ones of the cluster when the backups were taken,
+// which bound the uids and the namespaces found in the backups. The estimate can be above what
+// the restore actually bumps the leases to, if the restore skips some of the data. It fails
+// if a manifest was written before the leases were recorded.
+func EstimateLease(req *pb.RestoreRequest) (*LeaseEstimate, error) {
+	uri, err := url.Parse(req.Location)
+	if err != nil {
+		return nil, err
+	}
+	h, err := x.NewUriHandler(uri, getCredentialsFromRestoreRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := getRestoreManifests(h, uri, req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot retrieve manifests")
+	}
+	if len(manifests) == 0 {
+		return nil, errors.Errorf("no backup found at location: %s", req.Location)
+	}
+
+	est := &LeaseEstimate{}
+	for _, m := range manifests {
+		if m.Leases == nil {
+			return nil, errors.Errorf("the manifest of backup: %d doesn't record the leases",
+				m.BackupNum)
+		}
+		est.MaxUid = x.Max(est.MaxUid, m.Leases.MaxUid)
+		est.MaxNs = x.Max(est.MaxNs, m.Leases.MaxNs)
+	}
+	return est, nil
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestInventory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-inventory")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	name, age, name2 := x.GalaxyAttr("name"), x.GalaxyAttr("age"), x.NamespaceAttr(2, "name")
+	manifest := func(num uint64, drops ...*pb.DropOperation) *Manifest {
+		typ := "incremental"
+		if num == 1 {
+			typ = "full"
+		}
+		return &Manifest{Type: typ, BackupNum: num, ReadTs: num * 10,
+			Path: filepath.Join("dgraph", typ), Compression: "snappy", Version: 2105,
+			Groups:         map[uint32][]string{1: {name, age, name2}},
+			DropOperations: drops}
+	}
+	// The latest backup drops age and the data of namespace 2, which only applies to the older
+	// backup. nick is in the backup file, but not in the manifest, so it is filtered out.
+	fixtures := []backupFixture{
+		{
+			manifest: manifest(2,
+				&pb.DropOperation{DropOp: pb.DropOperation_ATTR, DropValue: age},
+				&pb.DropOperation{DropOp: pb.DropOperation_DATA, DropValue: "2"}),
+			kvs: []*bpb.KV{
+				schemaKV(t, x.GalaxyNamespace, "name"),
+				nsEdgeKV(t, x.GalaxyNamespace, "name", 3),
+				nsEdgeKV(t, x.GalaxyNamespace, "name", 4),
+				nsEdgeKV(t, x.GalaxyNamespace, "age", 3),
+				nsEdgeKV(t, 2, "name", 3),
+			},
+		},
+		{
+			manifest: manifest(1),
+			kvs: []*bpb.KV{
+				nsEdgeKV(t, x.GalaxyNamespace, "name", 1),
+				nsEdgeKV(t, x.GalaxyNamespace, "age", 1),
+				nsEdgeKV(t, x.GalaxyNamespace, "nick", 1),
+				nsEdgeKV(t, 2, "name", 1),
+				nsEdgeKV(t, 2, "name", 2),
+			},
+		},
+	}
+	backupDir := filepath.Join(dir, "backup")
+	manifests := writeBackupFixtures(t, backupDir, fixtures)
+	defer func(get func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error)) {
+		getRestoreManifests = get
+	}(getRestoreManifests)
+	getRestoreManifests = func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error) {
+		return manifests, nil
+	}
+
+	inv, err := Inventory(&pb.RestoreRequest{Location: backupDir, RestoreTs: 100, GroupId: 1})
+	require.NoError(t, err)
+	require.Equal(t, []InventoryManifest{
+		{BackupNum: 2, Type: "incremental", ReadTs: 20, Path: filepath.Join("dgraph", "incremental"),
+			Groups: []uint32{1}, DropOperations: 2},
+		{BackupNum: 1, Type: "full", ReadTs: 10, Path: filepath.Join("dgraph", "full"),
+			Groups: []uint32{1}},
+	}, inv.Manifests)
+	require.Equal(t, []uint64{x.GalaxyNamespace, 2}, inv.Namespaces)
+	require.Equal(t, []InventoryPredicate{
+		{Namespace: x.GalaxyNamespace, Name: "age", NumKVs: 1},
+		{Namespace: x.GalaxyNamespace, Name: "name", Type: "default", NumKVs: 3},
+		{Namespace: 2, Name: "name", NumKVs: 1},
+	}, inv.Predicates)
+	require.Empty(t, inv.Types)
+}
+
+func TestEstimateLease(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-lease")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The backup files are not read, so the location only holds the manifests.
+	var manifests []*Manifest
+	defer func(get func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error)) {
+		getRestoreManifests = get
+	}(getRestoreManifests)
+	getRestoreManifests = func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error) {
+		return manifests, nil
+	}
+	req := &pb.RestoreRequest{Location: dir, RestoreTs: 100, GroupId: 1}
+
+	manifests = []*Manifest{
+		{BackupNum: 2, Leases: &LeaseEstimate{MaxUid: 5000, MaxNs: 3}},
+		{BackupNum: 1, Leases: &LeaseEstimate{MaxUid: 1000, MaxNs: 4}},
+	}
+	est, err := EstimateLease(req)
+	require.NoError(t, err)
+	require.Equal(t, &LeaseEstimate{MaxUid: 5000, MaxNs: 4}, est)
+
+	manifests = append(manifests, &Manifest{BackupNum: 3})
+	_, err = EstimateLease(req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "backup: 3 doesn't record the leases")
+
+	manifests = nil
+	_, err = EstimateLease(req)
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"context"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// KmsProvider decrypts the data keys of the backups with a key management service, like AWS KMS
+// or GCP Cloud KMS, see MapOptions.KmsProvider.
+type KmsProvider interface {
+	// Decrypt returns the data key encrypted with the KMS key, which is an ARN for AWS KMS and
+	// a resource name for GCP Cloud KMS.
+	Decrypt(ctx context.Context, keyId string, encryptedKey []byte) (x.Sensitive, error)
+}
+
+// KmsProviderFunc adapts a function to a KmsProvider, to plug in the client of a KMS.
+type KmsProviderFunc func(ctx context.Context, keyId string, encryptedKey []byte) (
+	x.Sensitive, error)
+
+func (f KmsProviderFunc) Decrypt(ctx context.Context, keyId string,
+	encryptedKey []byte) (x.Sensitive, error) {
+	return f(ctx, keyId, encryptedKey)
+}
+
+// kmsDataKeys decrypts the data keys recorded in the manifests to restore with the provider,
+// and returns them by manifest. The KMS is called once per distinct data key, as the manifests
+// of a series usually share it. keyId overrides the KMS keys recorded in the manifests if it is
+// set. The manifests without a data key are left out, so they are decrypted with the raw key
+// of the encryption config, if any.
+func kmsDataKeys(ctx context.Context, provider KmsProvider, keyId string,
+	manifests []*Manifest, incrementalFrom uint64) (map[*Manifest]x.Sensitive, error) {
+	if provider == nil {
+		return nil, nil
+	}
+	dataKeys := make(map[*Manifest]x.Sensitive)
+	decrypted := make(map[string]x.Sensitive)
+	for _, manifest := range manifests {
+		if manifest.BackupNum < incrementalFrom {
+			break
+		}
+		if len(manifest.EncryptedDataKey) == 0 {
+			continue
+		}
+		id := keyId
+		if id == "" {
+			id = manifest.KmsKeyId
+		}
+		if id == "" {
+			return nil, errors.Errorf("no KMS key to decrypt the data key of manifest num: %d,"+
+				" path: %s", manifest.BackupNum, manifest.Path)
+		}
+		cacheKey := id + "\x00" + string(manifest.EncryptedDataKey)
+		key, ok := decrypted[cacheKey]
+		if !ok {
+			var err error
+			if key, err = provider.Decrypt(ctx, id, manifest.EncryptedDataKey); err != nil {
+				return nil, errors.Wrapf(err, "while decrypting the data key of manifest num: %d,"+
+					" path: %s with KMS key: %s", manifest.BackupNum, manifest.Path, id)
+			}
+			glog.Infof("Decrypted the data key of manifest num: %d with KMS key: %s",
+				manifest.BackupNum, id)
+			decrypted[cacheKey] = key
+		}
+		dataKeys[manifest] = key
+	}
+	return dataKeys, nil
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestKmsDataKeys(t *testing.T) {
+	// The data key is the encrypted one repeated to make an AES-256 key.
+	var calls []string
+	provider := KmsProviderFunc(func(_ context.Context, keyId string,
+		encryptedKey []byte) (x.Sensitive, error) {
+		calls = append(calls, keyId+":"+string(encryptedKey))
+		if keyId == "bad" {
+			return nil, errors.New("access denied")
+		}
+		return bytes.Repeat(encryptedKey, 32/len(encryptedKey)), nil
+	})
+	manifest := func(num uint64, keyId, encryptedKey string) *Manifest {
+		m := &Manifest{Type: "incremental", BackupNum: num, Encrypted: true, KmsKeyId: keyId,
+			Path: fmt.Sprintf("dgraph.%d", num)}
+		if encryptedKey != "" {
+			m.EncryptedDataKey = []byte(encryptedKey)
+		}
+		return m
+	}
+	manifests := []*Manifest{
+		manifest(5, "arn:key", "abcd"),
+		manifest(4, "arn:key", "abcd"),
+		manifest(3, "", ""),
+		manifest(2, "arn:key", "efgh"),
+		manifest(1, "arn:key", "ijkl"),
+	}
+
+	keys, err := kmsDataKeys(context.Background(), provider, "", manifests, 2)
+	require.NoError(t, err)
+	// The KMS is called once per data key, and not for the manifests before IncrementalFrom.
+	require.Equal(t, []string{"arn:key:abcd", "arn:key:efgh"}, calls)
+	require.Len(t, keys, 3)
+	require.Equal(t, x.Sensitive(bytes.Repeat([]byte("abcd"), 8)), keys[manifests[0]])
+	require.Equal(t, keys[manifests[0]], keys[manifests[1]])
+	require.Equal(t, x.Sensitive(bytes.Repeat([]byte("efgh"), 8)), keys[manifests[3]])
+
+	// The manifest without a data key falls back to the raw key.
+	req := &pb.RestoreRequest{IncrementalFrom: 2}
+	require.Error(t, checkEncryption(manifests, req, nil, keys))
+	require.NoError(t, checkEncryption(manifests, req, make(x.Sensitive, 16), keys))
+
+	calls = nil
+	keys, err = kmsDataKeys(context.Background(), provider, "projects/p/keys/k", manifests, 0)
+	require.NoError(t, err)
+	require.Len(t, keys, 4)
+	require.Equal(t, []string{"projects/p/keys/k:abcd", "projects/p/keys/k:efgh",
+		"projects/p/keys/k:ijkl"}, calls)
+
+	_, err = kmsDataKeys(context.Background(), provider, "bad", manifests, 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "manifest num: 5")
+	require.Contains(t, err.Error(), "access denied")
+	_, err = kmsDataKeys(context.Background(), provider, "",
+		[]*Manifest{manifest(1, "", "abcd")}, 0)
+	require.Error(t, err)
+
+	keys, err = kmsDataKeys(context.Background(), nil, "", manifests, 0)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
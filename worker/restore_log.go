@@ -0,0 +1,135 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/glog"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// The events logged by the mapper, along with their fields. All the events have the
+// request_id field if the restore request has an id.
+const (
+	// MapEventProgress is logged every second with the fields elapsed (time.Duration),
+	// req_ch and write_ch (int, the number of queued requests and buffers), bytes_read,
+	// bytes_processed and rate (uint64, bytes processed per second), file_id (uint32, the
+	// number of map files created), writers (int, the number of busy writers) and jemalloc
+	// (uint64, bytes allocated).
+	MapEventProgress = "progress"
+	// MapEventDone is logged when the map phase is done, with the field elapsed.
+	MapEventDone = "done"
+	// MapEventFileCreated is logged for every map file written, with the fields file (string)
+	// and size (uint64).
+	MapEventFileCreated = "file_created"
+	// MapEventManifestDone is logged once all the backups of a manifest are mapped, with the
+	// field backup_num (uint64).
+	MapEventManifestDone = "manifest_done"
+)
+
+// MapLogField is a field of a structured log event.
+type MapLogField struct {
+	Key   string
+	Value interface{}
+}
+
+// MapLogger logs the events of the map phase as key-value fields instead of formatted
+// strings, so that they can be indexed by a log aggregation system.
+type MapLogger interface {
+	Log(event string, fields []MapLogField)
+}
+
+// glogMapLogger is the default MapLogger. It logs the events as text using glog.
+type glogMapLogger struct{}
+
+func (glogMapLogger) Log(event string, fields []MapLogField) {
+	f := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		f[field.Key] = field.Value
+	}
+	var prefix string
+	if id, ok := f["request_id"]; ok {
+		prefix = fmt.Sprintf("[%s] ", id)
+	}
+
+	switch event {
+	case MapEventProgress:
+		glog.Infof("%sRestore MAP %s len(reqCh): %d len(writeCh): %d read: %s. output: %s."+
+			" rate: %s/sec. nextFileId: %d writers: %d jemalloc: %s.\n",
+			prefix, x.FixedDuration(f["elapsed"].(time.Duration)), f["req_ch"],
+			f["write_ch"], humanize.IBytes(f["bytes_read"].(uint64)),
+			humanize.IBytes(f["bytes_processed"].(uint64)),
+			humanize.IBytes(f["rate"].(uint64)), f["file_id"],
+			f["writers"],
+			humanize.IBytes(f["jemalloc"].(uint64)))
+	case MapEventDone:
+		glog.Infof("%sRestore MAP Done in %s.\n", prefix,
+			x.FixedDuration(f["elapsed"].(time.Duration)))
+	case MapEventFileCreated:
+		glog.Infof("%sCreated new backup map file: %s of size: %s\n",
+			prefix, f["file"], humanize.IBytes(f["size"].(uint64)))
+	case MapEventManifestDone:
+		glog.Infof("%s[MAP] Processed manifest num: %d\n", prefix, f["backup_num"])
+	default:
+		var sb strings.Builder
+		for _, field := range fields {
+			fmt.Fprintf(&sb, " %s: %v", field.Key, field.Value)
+		}
+		glog.Infof("%sRestore MAP %s%s", prefix, event, sb.String())
+	}
+}
+
+// log logs the event with the logger of the mapper, adding the request id to the fields.
+func (m *mapper) log(event string, fields ...MapLogField) {
+	if m.reqId != "" {
+		fields = append(fields, MapLogField{"request_id", m.reqId})
+	}
+	m.opts.Logger.Log(event, fields)
+}
+
+// maxRequestIdLen is the max length of the request id used in the map file names.
+const maxRequestIdLen = 64
+
+// sanitizeRequestId makes the request id safe to use in a file name. Any character other than
+// an ASCII letter, a digit, '-' or '_' is replaced with '_', and the id is truncated to
+// maxRequestIdLen characters.
+func sanitizeRequestId(id string) string {
+	if len(id) > maxRequestIdLen {
+		id = id[:maxRequestIdLen]
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, id)
+}
+
+// setRequestId tags the map files and the log messages of the mapper with the request id.
+func (m *mapper) setRequestId(id string) {
+	m.reqId = sanitizeRequestId(id)
+	if m.reqId != "" {
+		m.logPrefix = fmt.Sprintf("[%s] ", m.reqId)
+	}
+}
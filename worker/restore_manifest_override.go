@@ -0,0 +1,127 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// manifestOverride is the contents of the file of MapOptions.ManifestOverride. It has the
+// layout of the master manifest, the manifests being ordered from the oldest to the latest.
+type manifestOverride struct {
+	Manifests []*overrideManifest `json:"manifests"`
+}
+
+// overrideManifest is a manifest of a manifest override. FileSizes optionally holds the size
+// in bytes of the backup file of each group, which is checked before anything is mapped.
+type overrideManifest struct {
+	Manifest
+	FileSizes map[uint32]int64 `json:"file_sizes,omitempty"`
+}
+
+// getOverrideManifests reads the manifest override file at path and returns the manifests to
+// restore, in the same order as getManifestsToRestore. Unlike the manifests of the location,
+// a manifest of the override must have all its backup files, with the sizes it records, as
+// it describes the backups which are known to be there.
+func getOverrideManifests(h x.UriHandler, path string, req *pb.RestoreRequest) (
+	[]*Manifest, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading the manifest override")
+	}
+	var override manifestOverride
+	if err := json.Unmarshal(b, &override); err != nil {
+		return nil, errors.Wrapf(err, "while parsing the manifest override: %s", path)
+	}
+	if len(override.Manifests) == 0 {
+		return nil, errors.Errorf("manifest override: %s has no manifest", path)
+	}
+	manifests := make([]*Manifest, 0, len(override.Manifests))
+	for _, om := range override.Manifests {
+		if err := checkOverrideManifest(h, om); err != nil {
+			return nil, errors.Wrapf(err, "invalid manifest num: %d, path: %s in the manifest"+
+				" override", om.BackupNum, om.Path)
+		}
+		manifests = append(manifests, &om.Manifest)
+	}
+	glog.Warningf("Restoring with the manifest override: %s, the manifests of the location"+
+		" are not read", path)
+	return filterManifestChain(manifests, req)
+}
+
+// checkOverrideManifest checks the fields of the manifest, and that its backup files are
+// present with the expected sizes.
+func checkOverrideManifest(h x.UriHandler, om *overrideManifest) error {
+	if om.Type != "full" && om.Type != "incremental" {
+		return errors.Errorf("type: %q must be full or incremental", om.Type)
+	}
+	if om.ValidReadTs() == 0 {
+		return errors.New("read_ts must be set")
+	}
+	if om.Path == "" {
+		return errors.New("path must be set")
+	}
+	switch om.Compression {
+	case "", "snappy", "gzip":
+	default:
+		return errors.Errorf("compression: %q is not supported", om.Compression)
+	}
+	for gid := range om.FileSizes {
+		if _, ok := om.Groups[gid]; !ok {
+			return errors.Errorf("file_sizes has group: %d, which is not in groups", gid)
+		}
+	}
+	for gid := range om.Groups {
+		path := filepath.Join(om.Path, backupName(om.ValidReadTs(), gid))
+		if !h.FileExists(path) {
+			return errors.Errorf("backup file: %s of group: %d is missing", path, gid)
+		}
+		want, ok := om.FileSizes[gid]
+		if !ok {
+			continue
+		}
+		size, err := backupFileSize(h, path)
+		if err != nil {
+			return errors.Wrapf(err, "while reading backup file: %s", path)
+		}
+		if size != want {
+			return errors.Errorf("backup file: %s of group: %d has %d bytes, expected %d",
+				path, gid, size, want)
+		}
+	}
+	return nil
+}
+
+// backupFileSize returns the size of the file by reading it, as the handlers don't expose the
+// sizes of the files.
+func backupFileSize(h x.UriHandler, path string) (int64, error) {
+	rc, err := h.Stream(path)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	return io.Copy(ioutil.Discard, rc)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestManifestOverride(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-override")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	backupDir := filepath.Join(dir, "backup")
+
+	// The location has a backup file, and a corrupt manifest.
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 100; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	var comp bytes.Buffer
+	w := snappy.NewBufferedWriter(&comp)
+	_, err = w.Write(stream.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	file := filepath.Join(backupDir, "full", backupName(5, 1))
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0750))
+	require.NoError(t, ioutil.WriteFile(file, comp.Bytes(), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(backupDir, backupManifest),
+		[]byte("{corrupt"), 0600))
+
+	writeOverride := func(size int64) string {
+		om := &overrideManifest{
+			Manifest: Manifest{Type: "full", BackupNum: 1, ReadTs: 5, Path: "full",
+				Version: 2105, Compression: "snappy",
+				Groups: map[uint32][]string{1: {x.GalaxyAttr("name")}}},
+			FileSizes: map[uint32]int64{1: size},
+		}
+		b, err := json.Marshal(&manifestOverride{Manifests: []*overrideManifest{om}})
+		require.NoError(t, err)
+		path := filepath.Join(dir, "override.json")
+		require.NoError(t, ioutil.WriteFile(path, b, 0600))
+		return path
+	}
+	req := &pb.RestoreRequest{Location: backupDir, RestoreTs: 10, GroupId: 1}
+
+	mapDir := filepath.Join(dir, "map")
+	_, err = RunMapper(req, mapDir, MapOptions{})
+	require.Error(t, err)
+
+	opts := MapOptions{ManifestOverride: writeOverride(int64(comp.Len()))}
+	_, err = RunMapper(req, mapDir, opts)
+	require.NoError(t, err)
+	files, _, err := mapFiles(mapDir)
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	// The override is checked against the backup files before anything is mapped.
+	mapDir = filepath.Join(dir, "map-size")
+	opts = MapOptions{ManifestOverride: writeOverride(int64(comp.Len()) + 1)}
+	_, err = RunMapper(req, mapDir, opts)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected")
+	files, _, _ = mapFiles(mapDir)
+	require.Empty(t, files)
+
+	require.NoError(t, os.Remove(file))
+	opts = MapOptions{ManifestOverride: writeOverride(int64(comp.Len()))}
+	_, err = RunMapper(req, filepath.Join(dir, "map-missing"), opts)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is missing")
+
+	opts = MapOptions{ManifestOverride: opts.ManifestOverride, ExtraLocations: []string{dir}}
+	require.Error(t, opts.validate())
+}
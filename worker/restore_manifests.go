@@ -0,0 +1,100 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// checkGroupExists returns an error listing the groups of the manifests if none of them has a
+// backup of the group gid, as nothing would be mapped for it. Nothing is checked if there are
+// no manifests.
+func checkGroupExists(manifests []*Manifest, gid uint32) error {
+	available := make(map[uint32]struct{})
+	for _, m := range manifests {
+		if _, ok := m.Groups[gid]; ok {
+			return nil
+		}
+		for g := range m.Groups {
+			available[g] = struct{}{}
+		}
+	}
+	if len(manifests) == 0 {
+		return nil
+	}
+	groups := make([]uint32, 0, len(available))
+	for g := range available {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i] < groups[j] })
+	return errors.Errorf("group %d is not in any of the %d manifests to restore. The available"+
+		" groups are: %v", gid, len(manifests), groups)
+}
+
+// manifestWindow returns the versions of the changes made between the backups of the manifests
+// from and to, as the window (min, max]. max is the read timestamp of the manifest to, and min is
+// the one of the manifest before from, or zero if from is the first one. The manifests are
+// ordered from the latest to the oldest.
+func manifestWindow(manifests []*Manifest, from, to uint64) (uint64, uint64, error) {
+	var min, max uint64
+	var foundFrom, foundTo bool
+	for i, m := range manifests {
+		if m.BackupNum == to {
+			max, foundTo = m.ValidReadTs(), true
+		}
+		if m.BackupNum == from {
+			foundFrom = true
+			if i+1 < len(manifests) {
+				min = manifests[i+1].ValidReadTs()
+			}
+		}
+	}
+	if !foundFrom || !foundTo {
+		return 0, 0, errors.Errorf("the manifests to restore don't include the window of"+
+			" manifests %d to %d", from, to)
+	}
+	if min >= max {
+		return 0, 0, errors.Errorf("the window of manifests %d to %d has no changes: read"+
+			" timestamps (%d, %d]", from, to, min, max)
+	}
+	return min, max, nil
+}
+
+// describeEmptyManifest explains why the manifest has no data to map. It returns true if the
+// manifest looks suspicious. An incremental backup without any group is legitimate, as there
+// were no writes since the previous backup. A manifest without a read timestamp, or a full
+// backup without any group, points to a corrupt or an incomplete backup.
+func describeEmptyManifest(m *Manifest) (string, bool) {
+	if m.ValidReadTs() == 0 {
+		return "has no read timestamp. Its data is skipped", true
+	}
+	var drops []string
+	for _, op := range m.DropOperations {
+		drops = append(drops, op.DropOp.String())
+	}
+	if m.Type == "full" {
+		return fmt.Sprintf("is a full backup without any group. Drop operations: %v", drops),
+			true
+	}
+	if len(drops) > 0 {
+		return fmt.Sprintf("has no data, only the drop operations: %v", drops), false
+	}
+	return "has no data. There were no writes since the previous backup", false
+}
@@ -19,17 +19,19 @@ package worker
 import (
 	"bufio"
 	"bytes"
-	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"net/url"
-	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -37,86 +39,61 @@ import (
 	bpb "github.com/dgraph-io/badger/v3/pb"
 	"github.com/dgraph-io/badger/v3/y"
 	"github.com/dgraph-io/dgraph/ee"
-	"github.com/dgraph-io/dgraph/ee/enc"
-	"github.com/dgraph-io/dgraph/posting"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/x"
 	"github.com/dgraph-io/ristretto/z"
 	"github.com/dustin/go-humanize"
 	"github.com/golang/glog"
-	"github.com/golang/snappy"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 )
 
-type backupReader struct {
-	toClose []io.Closer
-	r       io.Reader
-	err     error
-	once    sync.Once
-}
-
-func readerFrom(h x.UriHandler, file string) *backupReader {
-	br := &backupReader{}
-	reader, err := h.Stream(file)
-	br.setErr(err)
-	br.toClose = append(br.toClose, reader)
-	br.r = reader
-	return br
-}
-func (br *backupReader) Read(p []byte) (n int, err error) {
-	return br.r.Read(p)
-}
-func (br *backupReader) Close() (rerr error) {
-	br.once.Do(func() {
-		// Close in reverse order.
-		for i := len(br.toClose) - 1; i >= 0; i-- {
-			if err := br.toClose[i].Close(); err != nil {
-				rerr = err
-			}
-		}
-	})
-	return rerr
-}
-func (br *backupReader) setErr(err error) {
-	if br.err == nil {
-		br.err = err
-	}
-}
-func (br *backupReader) WithEncryption(encKey x.Sensitive) *backupReader {
-	if len(encKey) == 0 {
-		return br
-	}
-	r, err := enc.GetReader(encKey, br.r)
-	br.setErr(err)
-	br.r = r
-	return br
-}
-func (br *backupReader) WithCompression(comp string) *backupReader {
-	switch comp {
-	case "snappy":
-		br.r = snappy.NewReader(br.r)
-	case "gzip", "":
-		r, err := gzip.NewReader(br.r)
-		br.setErr(err)
-		br.r = r
-		br.toClose = append(br.toClose, r)
-	default:
-		br.setErr(fmt.Errorf("Unknown compression for backup: %s", comp))
-	}
-	return br
-}
-
 type loadBackupInput struct {
 	preds      predicateSet
 	dropNs     map[uint64]struct{}
 	version    int
 	keepSchema bool
+	// schemaPreds are the predicates whose schema keys are mapped even though keepSchema is
+	// not set, see MapOptions.IgnoreDropOperations.
+	schemaPreds predicateSet
+	// predBloom is the bloom filter of preds, only set with MapOptions.PredicateBloomFilter.
+	predBloom *predBloom
+	// backupNum is the number of the manifest being mapped.
+	backupNum uint64
+	// schemaOnly skips all the keys other than the schema and type keys.
+	schemaOnly bool
+	// groupId is the group whose backup file is being mapped.
+	groupId uint32
+	// checksum is the expected hex encoded SHA-256 checksum of the whole stream. It is only
+	// verified when it is set.
+	checksum string
+	// startOffset is the offset of the frame to resume the mapping from, and tracker tracks
+	// the mapped frames. tracker is only set when opts.CheckpointFile is set.
+	startOffset uint64
+	tracker     *fileTracker
+	// minVersion and maxVersion bound the versions of the posting lists mapped with the
+	// manifest window of the options, see MapOptions.WindowTo. The window is
+	// (minVersion, maxVersion]. It is not set if maxVersion is zero.
+	minVersion uint64
+	maxVersion uint64
 }
 
 type listReq struct {
 	lbuf *z.Buffer
 	in   *loadBackupInput
+	ack  *frameAck
+}
+
+// mapBuffer holds the map entries sent for writing along with the number of the manifest
+// they were read from, and the acks of the frames they were mapped from.
+type mapBuffer struct {
+	buf       *z.Buffer
+	backupNum uint64
+	acks      []*frameAck
+	// refs is the number of holders of buf, which is shared with opts.Sink if it is set. buf
+	// goes back to pool once all of them have released it.
+	refs int32
+	pool *bufferPool
 }
 
 // mapEntry stores uint16 (2 bytes), which store the length of the key, followed by the key itself.
@@ -142,43 +119,421 @@ type mapper struct {
 	closer         *z.Closer
 
 	restoreTs uint64
-
-	mapDir  string
-	reqCh   chan listReq
-	writeCh chan *z.Buffer
-	writers chan struct{}
-	szHist  *z.HistogramData
+	// reqId is the sanitized id of the restore request. It is used as the prefix of the map
+	// file names, and logPrefix is used to tag the log messages with it.
+	reqId     string
+	logPrefix string
+
+	mapDir string
+	// mapStore is the handler of mapDir if it is a remote URI. It is nil for a local mapDir.
+	mapStore x.UriHandler
+	opts     MapOptions
+	reqCh    chan listReq
+	writeCh  chan *mapBuffer
+	writers  chan struct{}
+	// prioCh holds the requests of opts.PriorityNamespaces. It is nil if none are set.
+	prioCh     chan listReq
+	priorityNs map[uint64]struct{}
+
+	// procBufs are the buffers the processors map the KVs into, and mergeBufs are the ones the
+	// mergers merge them into. The buffers are put back once their contents are merged or
+	// written out.
+	procBufs  *bufferPool
+	mergeBufs *bufferPool
+	// ckpt tracks the progress for opts.CheckpointFile. It is nil if that is not set.
+	ckpt *checkpointer
+	// groupId is the group whose backups are mapped.
+	groupId uint32
+	// unsynced are the map files written without being synced, and unsyncedDirs are their
+	// directories. They are guarded by syncMu.
+	unsynced     []string
+	unsyncedDirs map[string]struct{}
+	syncMu       sync.Mutex
+	// written are the map files completely written. They are guarded by writtenMu.
+	written   []MapFileInfo
+	writtenMu sync.Mutex
+	// szHist is the histogram of the sizes of the KV lists read by Map. It is guarded by
+	// szHistMu, since it is exported while Map is running.
+	szHist   *z.HistogramData
+	szHistMu sync.Mutex
 
 	maxUid uint64
 	maxNs  uint64
+
+	// clampedVersions is the number of versions clamped by preservedVersion.
+	clampedVersions uint64
+	// sampledKeys is the number of posting lists kept by SampleRate, out of sampleTotal.
+	sampledKeys uint64
+	sampleTotal uint64
+	// emptyRollups is the number of complete posting lists whose rollup returned no KV.
+	emptyRollups uint64
+	// staleEntries is the number of entries dropped by DedupLatest.
+	staleEntries uint64
+	// waits is the time in nanoseconds spent blocked at each stage of the pipeline. See
+	// MapWaits.
+	waits [numWaitStages]int64
+	// numProcessors is the number of goroutines started by startPipeline to process the
+	// KV lists.
+	numProcessors int
+
+	// seenPreds is the set of predicates for which at least one posting list was mapped.
+	// schemaPreds and nsMismatches are collected by checkSchemaNamespace. They are all guarded
+	// by seenMu, along with rollups.
+	seenPreds    map[string]struct{}
+	schemaPreds  map[string]struct{}
+	nsMismatches []string
+	// schemaAttrs is the set of predicates for which a schema key was mapped.
+	schemaAttrs map[string]struct{}
+	// rollups is the cost of the rollups, per predicate.
+	rollups map[string]*rollupCost
+	// namespaces is the set of namespaces of the keys mapped, only collected with
+	// opts.CollectNamespaces.
+	namespaces map[uint64]struct{}
+	seenMu     sync.Mutex
+
+	// schemaKeys are the schema and type keys already mapped with MergeAllGroups, and
+	// schemaCollisions are the ones found with different values in different groups. They are
+	// guarded by schemaMu.
+	schemaKeys       map[string]schemaSource
+	schemaCollisions []string
+	schemaMu         sync.Mutex
+
+	// strippedAttrs maps the predicates and types stripped of their namespace with
+	// StripNamespaces to the namespace they were first found in, and nsCollisions are the ones
+	// found in more than one namespace. They are guarded by stripMu.
+	strippedAttrs map[string]uint64
+	nsCollisions  []string
+	stripMu       sync.Mutex
+
+	// badKeys is the number of keys skipped because they could not be parsed. The hex dumps of
+	// the first few of them are kept in badKeySamples.
+	badKeys       uint64
+	badKeysMu     sync.Mutex
+	badKeySamples []string
+
+	// files are the map files created by the mapper. They are tracked only when
+	// opts.CleanupOnError is set. Once cleanedUp is set, no more map files can be created.
+	filesMu   sync.Mutex
+	files     []string
+	cleanedUp bool
+
+	// ctx is cancelled when a processing or a merging goroutine fails. processors holds the
+	// goroutines running processReqCh and mergers holds the ones running mergeAndSend.
+	ctx        context.Context
+	cancel     context.CancelFunc
+	processors *errgroup.Group
+	mergers    errgroup.Group
+	stopOnce   sync.Once
+	stopErr    error
+}
+
+func newMapper(restoreTs uint64, mapDir string, opts MapOptions, numGo int) *mapper {
+	numGo = atLeastOne(numGo)
+	// By default, there are half as many mergers as processors, and only half the writers
+	// should be writing at the same time. There must be at least one merger and one writer,
+	// or nothing would drain writeCh and the processors would hang.
+	half := atLeastOne(numGo / 2)
+	if opts.MergeConcurrency == 0 {
+		opts.MergeConcurrency = half
+	}
+	if opts.WriteConcurrency == 0 {
+		opts.WriteConcurrency = half
+	}
+	if opts.Logger == nil {
+		opts.Logger = glogMapLogger{}
+	}
+	if opts.BufferAllocator == nil {
+		opts.BufferAllocator = zAllocator{}
+	}
+	if opts.KeyComparator == nil {
+		opts.KeyComparator = y.CompareKeys
+	}
+	if opts.PartitionBufSize == 0 {
+		opts.PartitionBufSize = partitionBufSz
+	}
+	if opts.LargeValueThreshold == 0 {
+		opts.LargeValueThreshold = defaultLargeValueThreshold
+	}
+	procBufs := newBufferPool(numGo, func() (*z.Buffer, error) {
+		return opts.BufferAllocator.NewBuffer(opts.ProcessBufSize, "processKVList"), nil
+	})
+	mergeBufs := newBufferPool(opts.MergeConcurrency, func() (*z.Buffer, error) {
+		return newBuffer(opts.BufferAllocator)
+	})
+	var prioCh chan listReq
+	var priorityNs map[uint64]struct{}
+	if len(opts.PriorityNamespaces) > 0 {
+		prioCh = make(chan listReq, numGo+numGo/4)
+		priorityNs = make(map[uint64]struct{}, len(opts.PriorityNamespaces))
+		for _, ns := range opts.PriorityNamespaces {
+			priorityNs[ns] = struct{}{}
+		}
+	}
+	return &mapper{
+		closer: z.NewCloser(1),
+		reqCh:  make(chan listReq, numGo+numGo/4),
+		prioCh: prioCh,
+		// Let every merger have a buffer queued up while it is merging another one.
+		writeCh:     make(chan *mapBuffer, 2*opts.MergeConcurrency),
+		writers:     make(chan struct{}, opts.WriteConcurrency),
+		restoreTs:   restoreTs,
+		priorityNs:  priorityNs,
+		mapDir:      mapDir,
+		opts:        opts,
+		szHist:      z.NewHistogramData(z.HistogramBounds(10, 32)),
+		seenPreds:   make(map[string]struct{}),
+		schemaPreds: make(map[string]struct{}),
+		rollups:     make(map[string]*rollupCost),
+		schemaKeys:  make(map[string]schemaSource),
+		schemaAttrs: make(map[string]struct{}),
+		procBufs:    procBufs,
+		mergeBufs:   mergeBufs,
+	}
 }
 
-func (mw *mapper) newMapFile() (*os.File, error) {
-	fileNum := atomic.AddUint32(&mw.nextId, 1)
-	filename := filepath.Join(mw.mapDir, fmt.Sprintf("%06d.map", fileNum))
-	x.Check(os.MkdirAll(filepath.Dir(filename), 0750))
+// atLeastOne returns n, or one if n is below one. It is the floor of the number of goroutines
+// of each stage of the pipeline.
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// startPipeline starts numGo goroutines to process the requests sent to reqCh, and
+// opts.MergeConcurrency goroutines to merge the processed buffers and write them out to map
+// files.
+func (m *mapper) startPipeline(numGo int) {
+	numGo = atLeastOne(numGo)
+	var ctx context.Context
+	if m.opts.MaxMapDuration > 0 {
+		ctx, m.cancel = context.WithTimeout(m.closer.Ctx(), m.opts.MaxMapDuration)
+	} else {
+		ctx, m.cancel = context.WithCancel(m.closer.Ctx())
+	}
+	m.processors, m.ctx = errgroup.WithContext(ctx)
+	m.numProcessors = numGo
+	for i := 0; i < numGo; i++ {
+		m.processors.Go(func() error {
+			return m.processReqCh(m.ctx)
+		})
+	}
+	for i := 0; i < m.opts.MergeConcurrency; i++ {
+		m.mergers.Go(func() error {
+			err := m.mergeAndSend()
+			if err != nil {
+				// Stop the processors from waiting on writeCh, which might not be drained.
+				m.cancel()
+			}
+			glog.Infof("%smapper.mergeAndSend done with error: %v", m.logPrefix, err)
+			return err
+		})
+	}
+}
+
+// ErrMapTimeout is returned when the map phase takes longer than MapOptions.MaxMapDuration.
+var ErrMapTimeout = errors.New("map phase exceeded the max duration")
+
+// checkTimeout returns ErrMapTimeout wrapping err if the pipeline was stopped because the map
+// phase took too long. It returns err otherwise.
+func (m *mapper) checkTimeout(err error) error {
+	if err == nil || m.ctx == nil || m.ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	return errors.Wrapf(ErrMapTimeout, "after %s: %v", m.opts.MaxMapDuration, err)
+}
+
+// watchCancel signals the closer of the mapper once ctx is canceled, which stops the pipeline
+// and the progress reports. It gives up once the closer is signalled.
+func (m *mapper) watchCancel(ctx context.Context) {
+	if ctx.Done() == nil {
+		return
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			glog.Infof("%sMap phase canceled. Err: %v", m.logPrefix, ctx.Err())
+			m.closer.Signal()
+		case <-m.closer.HasBeenClosed():
+		}
+	}()
+}
+
+// checkCanceled returns ctx.Err() wrapping err if the map phase failed because ctx was
+// canceled. It returns err otherwise.
+func checkCanceled(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ErrMapTimeout) {
+		return err
+	}
+	return errors.Wrapf(ctx.Err(), "map phase canceled: %v", err)
+}
+
+// stopPipeline waits for the processing goroutines to finish and only then closes writeCh, so
+// that no processor can send to writeCh after it has been closed. It then waits for the
+// merging goroutines to finish. The buffers left unprocessed because of an error are
+// released. It is safe to call stopPipeline multiple times.
+func (m *mapper) stopPipeline() error {
+	m.stopOnce.Do(func() {
+		close(m.reqCh)
+		if m.prioCh != nil {
+			close(m.prioCh)
+		}
+		if err := m.processors.Wait(); err != nil {
+			m.stopErr = errors.Wrapf(err, "from processKVList")
+		}
+		for req := range m.reqCh {
+			req.lbuf.Release()
+		}
+		if m.prioCh != nil {
+			for req := range m.prioCh {
+				req.lbuf.Release()
+			}
+		}
+		glog.Infof("%smapper.processReqCh done", m.logPrefix)
 
-	return os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		close(m.writeCh)
+		if err := m.mergers.Wait(); err != nil && m.stopErr == nil {
+			m.stopErr = errors.Wrapf(err, "mergeAndSend returned error")
+		}
+		for mb := range m.writeCh {
+			nackAcks(mb.acks)
+			mb.release()
+		}
+		m.procBufs.close()
+		m.mergeBufs.close()
+		m.cancel()
+	})
+	return m.stopErr
 }
 
-func (m *mapper) writeToDisk(buf *z.Buffer) error {
-	defer buf.Release()
+// writeToDisk writes the sorted entries of buf to a map file, or to a map file per namespace
+// with PerNamespaceSubdirs. It takes the ownership of buf.
+func (m *mapper) writeToDisk(buf *z.Buffer, backupNum uint64) error {
+	defer m.mergeBufs.put(buf)
 	if buf.IsEmpty() {
 		return nil
 	}
+	iterate := buf.SliceIterate
+	if m.opts.DedupLatest {
+		iterate = m.latestEntries(iterate)
+	}
+	if !m.opts.PerNamespaceSubdirs {
+		return m.writeEntrySets(iterate, backupNum, "")
+	}
+	// The entries are sorted, so the ones of a namespace are next to each other, unless a
+	// custom KeyComparator orders them otherwise. Every run of entries of the same namespace
+	// is written to its own map file.
+	var run [][]byte
+	var runNs uint64
+	write := func() error {
+		if len(run) == 0 {
+			return nil
+		}
+		entries := run
+		run = run[:0]
+		return m.writeEntrySets(iterateEntries(entries), backupNum, namespaceDir(runNs))
+	}
+	err := iterate(func(slice []byte) error {
+		ns := entryNamespace(mapEntry(slice).Key())
+		if ns != runNs {
+			if err := write(); err != nil {
+				return err
+			}
+			runNs = ns
+		}
+		run = append(run, slice)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return write()
+}
+
+// writeEntrySets writes the sorted entries passed to fn by iterate to a map file. With
+// SplitLargeValues, the entries of the large values are written to a map file of their own,
+// and with RestoreTsSets, the entries of every restore timestamp are as well.
+func (m *mapper) writeEntrySets(iterate func(fn func([]byte) error) error, backupNum uint64,
+	nsDir string) error {
+	if !m.opts.SplitLargeValues && len(m.opts.RestoreTsSets) == 0 {
+		return m.writeMapFile(iterate, backupNum, mapFileSet{nsDir: nsDir})
+	}
+	// The sets are subsequences of the sorted entries, so they are sorted as well.
+	sets := make(map[mapFileSet][][]byte)
+	var order []mapFileSet
+	err := iterate(func(slice []byte) error {
+		me := mapEntry(slice)
+		set := mapFileSet{nsDir: nsDir}
+		if m.opts.SplitLargeValues {
+			set.large = len(me.Data()) > m.opts.LargeValueThreshold
+		}
+		if len(m.opts.RestoreTsSets) > 0 {
+			// The entries of the sets only differ by the version of their KV.
+			var kv bpb.KV
+			if err := kv.Unmarshal(me.Data()); err != nil {
+				return errors.Wrap(err, "while reading the version of a map entry")
+			}
+			set.restoreTs = kv.Version
+		}
+		if _, ok := sets[set]; !ok {
+			order = append(order, set)
+		}
+		sets[set] = append(sets[set], slice)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, set := range order {
+		if err := m.writeMapFile(iterateEntries(sets[set]), backupNum, set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterateEntries returns a function which passes the entries to fn, like z.Buffer.SliceIterate.
+func iterateEntries(entries [][]byte) func(fn func([]byte) error) error {
+	return func(fn func([]byte) error) error {
+		for _, slice := range entries {
+			if err := fn(slice); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// writeMapFile writes the entries passed to fn by iterate to a new map file. The entries must
+// be sorted, and iterate must pass the same entries every time it is called.
+func (m *mapper) writeMapFile(iterate func(fn func([]byte) error) error, backupNum uint64,
+	set mapFileSet) (rerr error) {
+	if err := injectFault(faultWrite); err != nil {
+		return err
+	}
+	if m.opts.MapFileFormat == mapFormatSST {
+		return m.writeSSTFile(iterate, backupNum, set)
+	}
 
-	f, err := m.newMapFile()
+	mf, err := m.newMapFile(backupNum, set)
 	if err != nil {
 		return errors.Wrap(err, "openOutputFile")
 	}
-	defer f.Close()
+	if m.opts.VerifyChecksums {
+		mf.h = sha256.New()
+	}
+	defer func() {
+		if rerr != nil {
+			mf.abort(rerr)
+		}
+	}()
 
 	// Create partition keys for the map file.
-	header := &pb.MapHeader{PartitionKeys: [][]byte{}}
+	header := &pb.MapHeader{PartitionKeys: [][]byte{}, FormatVersion: mapFormatVersion}
 	var bufSize int
-	buf.SliceIterate(func(slice []byte) error {
+	iterate(func(slice []byte) error {
 		bufSize += 4 + len(slice)
-		if bufSize < partitionBufSz {
+		if bufSize < m.opts.PartitionBufSize {
 			return nil
 		}
 		sz := len(header.PartitionKeys)
@@ -193,22 +548,37 @@ func (m *mapper) writeToDisk(buf *z.Buffer) error {
 	})
 
 	// Write the header to the map file.
+	header.PartitionKeys = encodePartitionKeys(header.PartitionKeys)
+	if m.opts.MapFileCompression == mapCodecGzip {
+		header.Codec = mapCodecGzip
+	}
 	headerBuf, err := header.Marshal()
 	x.Check(err)
 	var lenBuf [4]byte
 	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(headerBuf)))
 
-	w := snappy.NewBufferedWriter(f)
-	x.Check2(w.Write(lenBuf[:]))
-	x.Check2(w.Write(headerBuf))
-	x.Check(err)
+	// The writes to a remote map file can fail, so they are checked as well.
+	var w mapFileWriter
+	if header.Codec == mapCodecGzip {
+		if w, err = newGzipMapFileWriter(mf, m.opts.MapFileGzipLevel); err != nil {
+			return errors.Wrap(err, "while creating the gzip writer")
+		}
+	} else {
+		w = newMapFileWriter(mf, m.opts.UncompressedMapFiles)
+	}
+	if _, err := w.Write(lenBuf[:]); err != nil {
+		return errors.Wrap(err, "while writing header length")
+	}
+	if _, err := w.Write(headerBuf); err != nil {
+		return errors.Wrap(err, "while writing header")
+	}
 
 	sizeBuf := make([]byte, binary.MaxVarintLen64)
-	err = buf.SliceIterate(func(slice []byte) error {
+	err = iterate(func(slice []byte) error {
 		n := binary.PutUvarint(sizeBuf, uint64(len(slice)))
-		_, err := w.Write(sizeBuf[:n])
-		x.Check(err)
-
+		if _, err := w.Write(sizeBuf[:n]); err != nil {
+			return err
+		}
 		_, err = w.Write(slice)
 		return err
 	})
@@ -218,66 +588,164 @@ func (m *mapper) writeToDisk(buf *z.Buffer) error {
 	if err := w.Close(); err != nil {
 		return errors.Wrap(err, "writer.Close")
 	}
-	if err := f.Sync(); err != nil {
-		return errors.Wrap(err, "file.Sync")
-	}
-	if fi, err := f.Stat(); err == nil {
-		glog.Infof("Created new backup map file: %s of size: %s\n",
-			fi.Name(), humanize.IBytes(uint64(fi.Size())))
-	}
-	return f.Close()
-}
-
-func newBuffer() *z.Buffer {
-	buf, err := z.NewBufferTmp("", mapFileSz)
-	x.Check(err)
-	return buf.WithMaxSize(2 * mapFileSz)
+	return m.finishMapFile(mf, backupNum, set, len(header.PartitionKeys))
 }
 
-func (mw *mapper) writeNow(mbuf *z.Buffer) error {
+// writeNow writes out mbuf to a map file. The acks of the frames whose entries are in mbuf
+// are released once the file is written, and nacked if it can't be.
+func (mw *mapper) writeNow(mbuf *z.Buffer, backupNum uint64, acks []*frameAck) error {
 	defer func() {
 		<-mw.writers
 	}()
 
 	if mbuf.IsEmpty() {
 		mbuf.Release()
+		releaseAcks(acks)
 		return nil
 	}
+	if err := mw.waitForDiskSpace(); err != nil {
+		mbuf.Release()
+		nackAcks(acks)
+		return err
+	}
 	mbuf.SortSlice(func(ls, rs []byte) bool {
 		lme := mapEntry(ls)
 		rme := mapEntry(rs)
-		return y.CompareKeys(lme.Key(), rme.Key()) < 0
+		return mw.opts.KeyComparator(lme.Key(), rme.Key()) < 0
 	})
-	return mw.writeToDisk(mbuf)
+	if mw.opts.VerifySortOrder {
+		if err := verifySorted(mbuf, mw.opts.KeyComparator); err != nil {
+			mbuf.Release()
+			nackAcks(acks)
+			return err
+		}
+	}
+	start := time.Now()
+	if err := mw.writeToDisk(mbuf, backupNum); err != nil {
+		nackAcks(acks)
+		return err
+	}
+	mw.addWait(waitDisk, start)
+	releaseAcks(acks)
+	return nil
 }
 
+// Flush syncs the map files which were not synced when they were written, along with their
+// directories. See MapOptions.SyncMode.
 func (mw *mapper) Flush() error {
+	mw.syncMu.Lock()
+	files, dirs := mw.unsynced, mw.unsyncedDirs
+	mw.unsynced, mw.unsyncedDirs = nil, nil
+	mw.syncMu.Unlock()
+	if err := syncFiles(files); err != nil {
+		return err
+	}
+	for dir := range dirs {
+		if err := syncFile(dir); err != nil {
+			return errors.Wrapf(err, "while syncing map directory %s", dir)
+		}
+	}
 	return nil
 }
 
-func fromBackupKey(key []byte) ([]byte, uint64, error) {
-	backupKey := &pb.BackupKey{}
-	if err := backupKey.Unmarshal(key); err != nil {
-		return nil, 0, errors.Wrapf(err, "while reading backup key %s", hex.Dump(key))
+func (m *mapper) mergeAndSend() error {
+	var tick <-chan time.Time
+	if m.opts.FlushInterval > 0 {
+		ticker := time.NewTicker(m.opts.FlushInterval / 2)
+		defer ticker.Stop()
+		tick = ticker.C
 	}
-	return x.FromBackupKey(backupKey), backupKey.Namespace, nil
-}
 
-func (m *mapper) mergeAndSend(closer *z.Closer) error {
-	defer closer.Done()
+	mbuf, err := m.mergeBufs.get()
+	if err != nil {
+		return err
+	}
+	var backupNum uint64
+	var acks []*frameAck
+	// mbufSince is the time at which the data was first written to mbuf.
+	var mbufSince time.Time
+	// write writes out mbuf. The caller must have acquired a slot in m.writers.
+	write := func() error {
+		if err := m.writeNow(mbuf, backupNum, acks); err != nil {
+			return errors.Wrapf(err, "sendForWriting")
+		}
+		var err error
+		if mbuf, err = m.mergeBufs.get(); err != nil {
+			return err
+		}
+		acks = nil
+		mbufSince = time.Time{}
+		return nil
+	}
+
+	for {
+		var mb *mapBuffer
+		select {
+		case mb = <-m.writeCh:
+		case <-tick:
+			if mbufSince.IsZero() || time.Since(mbufSince) < m.opts.FlushInterval {
+				continue
+			}
+			// The buffer has been holding data for too long without reaching the size
+			// threshold. Write it out, but only if a writer is free. If all the writers are
+			// busy, the data is flowing in fast enough to fill up the buffer anyway.
+			select {
+			case m.writers <- struct{}{}:
+			default:
+				continue
+			}
+			glog.V(2).Infof("%sFlushing map buffer of size: %s held for %s", m.logPrefix,
+				humanize.IBytes(uint64(mbuf.LenNoPadding())), time.Since(mbufSince))
+			if err := write(); err != nil {
+				return err
+			}
+			continue
+		}
+		if mb == nil {
+			// writeCh has been closed.
+			break
+		}
+		if err := injectFault(faultMerge); err != nil {
+			nackAcks(mb.acks)
+			nackAcks(acks)
+			mb.release()
+			mbuf.Release()
+			return err
+		}
 
-	mbuf := newBuffer()
-	for buf := range m.writeCh {
-		atomic.AddUint64(&m.bytesProcessed, uint64(buf.LenNoPadding()))
-		mbuf.Write(buf.Bytes())
-		buf.Release()
+		if m.opts.PerManifestSubdirs && mb.backupNum != backupNum && !mbuf.IsEmpty() {
+			// The map files of different manifests go to different directories, so write out
+			// whatever we have accumulated for the previous manifest.
+			m.acquireWriter()
+			if err := write(); err != nil {
+				return err
+			}
+		}
+		backupNum = mb.backupNum
+
+		processed := atomic.AddUint64(&m.bytesProcessed, uint64(mb.buf.LenNoPadding()))
+		if limit := m.opts.MaxOutputBytes; limit > 0 && processed > uint64(limit) {
+			nackAcks(mb.acks)
+			nackAcks(acks)
+			mb.release()
+			mbuf.Release()
+			return errors.Errorf("map phase aborted, its output of %s (%d bytes) is above"+
+				" MaxOutputBytes: %s (%d bytes)", humanize.IBytes(processed), processed,
+				humanize.IBytes(uint64(limit)), limit)
+		}
+		mbuf.Write(mb.buf.Bytes())
+		mb.release()
+		acks = append(acks, mb.acks...)
+		if mbufSince.IsZero() && !mbuf.IsEmpty() {
+			mbufSince = time.Now()
+		}
 
 		var writeNow bool
 		if mbuf.LenNoPadding() >= mapFileSz {
 			writeNow = true
-			m.writers <- struct{}{}
+			m.acquireWriter()
 
-		} else if mbuf.LenNoPadding() >= mapFileSz/4 {
+		} else if mbuf.LenNoPadding() >= mapFileSz/4 && !m.opts.Deterministic {
 			// This mechanism allows us to stagger our writes. So, if can do a
 			// write, and we have accumulated a large enough buffer, then go for
 			// it.
@@ -289,254 +757,204 @@ func (m *mapper) mergeAndSend(closer *z.Closer) error {
 		}
 
 		if writeNow {
-			if err := m.writeNow(mbuf); err != nil {
-				return errors.Wrapf(err, "sendForWriting")
+			if err := write(); err != nil {
+				return err
 			}
-			mbuf = newBuffer()
 		}
 	}
-	m.writers <- struct{}{}
-	return m.writeNow(mbuf)
+	m.acquireWriter()
+	return m.writeNow(mbuf, backupNum, acks)
 }
 
-type processor struct {
-	*mapper
-	maxUid uint64
-	maxNs  uint64
-}
-
-func (p *processor) processKV(buf *z.Buffer, in *loadBackupInput, kv *bpb.KV) error {
-	toBuffer := func(kv *bpb.KV, version uint64) error {
-		key := y.KeyWithTs(kv.Key, version)
-		sz := kv.Size()
-		b := buf.SliceAllocate(2 + len(key) + sz)
-
-		binary.BigEndian.PutUint16(b[0:2], uint16(len(key)))
-		x.AssertTrue(copy(b[2:], key) == len(key))
-		_, err := kv.MarshalToSizedBuffer(b[2+len(key):])
-		return err
-	}
-	if len(kv.GetUserMeta()) != 1 {
-		return errors.Errorf(
-			"Unexpected meta: %v for key: %s", kv.UserMeta, hex.Dump(kv.Key))
-	}
-
-	restoreKey, ns, err := fromBackupKey(kv.Key)
-	if err != nil {
-		return errors.Wrap(err, "fromBackupKey")
-	}
-
-	// Filter keys using the preds set. Do not do this filtering for type keys
-	// as they are meant to be in every group and their Attr value does not
-	// match a predicate name.
-	parsedKey, err := x.Parse(restoreKey)
+func (m *mapper) processReqCh(ctx context.Context) error {
+	var list bpb.KVList
+	p := newProcessor(m)
+	buf, err := m.procBufs.get()
 	if err != nil {
-		return errors.Wrapf(err, "could not parse key %s", hex.Dump(restoreKey))
+		return err
 	}
-
-	// Update the local max uid and max namespace values.
-	p.maxUid = x.Max(p.maxUid, parsedKey.Uid)
-	p.maxNs = x.Max(p.maxNs, ns)
-
-	if !in.keepSchema && (parsedKey.IsSchema() || parsedKey.IsType()) {
+	var backupNum uint64
+	// acks are the acks of the frames mapped into buf.
+	var acks []*frameAck
+	// bufSince is the time at which the data was first written to buf.
+	var bufSince time.Time
+
+	send := func() error {
+		if err := m.sendBuffer(ctx, m.newMapBuffer(buf, backupNum, acks)); err != nil {
+			return errors.Wrapf(err, "processReqCh.SliceIterate")
+		}
+		var err error
+		if buf, err = m.procBufs.get(); err != nil {
+			return err
+		}
+		acks = nil
+		bufSince = time.Time{}
 		return nil
 	}
-	if _, ok := in.preds[parsedKey.Attr]; !parsedKey.IsType() && !ok {
-		return nil
+	// addAck makes buf hold the ack of the frames being mapped into it.
+	addAck := func(ack *frameAck) {
+		if ack != nil {
+			ack.hold()
+			acks = append(acks, ack)
+		}
 	}
 
-	switch kv.GetUserMeta()[0] {
-	case posting.BitEmptyPosting, posting.BitCompletePosting, posting.BitDeltaPosting:
-		if _, ok := in.dropNs[ns]; ok {
-			return nil
+	process := func(req listReq) error {
+		defer req.lbuf.Release()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
-		backupPl := &pb.BackupPostingList{}
-		if err := backupPl.Unmarshal(kv.Value); err != nil {
-			return errors.Wrapf(err, "while reading backup posting list")
-		}
-		pl := posting.FromBackupPostingList(backupPl)
-
-		if !posting.ShouldSplit(pl) || parsedKey.HasStartUid || len(pl.GetSplits()) > 0 {
-			// This covers two cases.
-			// 1. The list is not big enough to be split.
-			// 2. This key is storing part of a multi-part list. Write each individual
-			// part without rolling the key first. This part is here for backwards
-			// compatibility. New backups are not affected because there was a change
-			// to roll up lists into a single one.
-			newKv := posting.MarshalPostingList(pl, nil)
-			newKv.Key = restoreKey
-
-			// We are using kv.Version (from the key-value) to generate the key. But, using
-			// restoreTs to set the version of the KV. This way, when we sort the keys, we
-			// choose the latest key based on kv.Version. But, then set its version to
-			// restoreTs.
-			newKv.Version = p.restoreTs
-			if err := toBuffer(newKv, kv.Version); err != nil {
+		if m.opts.PerManifestSubdirs && req.in.backupNum != backupNum && !buf.IsEmpty() {
+			// Do not mix the entries from different manifests in the same buffer.
+			if err := send(); err != nil {
 				return err
 			}
-		} else {
-			// This is a complete list. It should be rolled up to avoid writing
-			// a list that is too big to be read back from disk.
-			// Rollup will take ownership of the Pack and will free the memory.
-			l := posting.NewList(restoreKey, pl, kv.Version)
-			kvs, err := l.Rollup(nil)
-			if err != nil {
-				// TODO: wrap errors in this file for easier debugging.
-				return err
-			}
-			for _, kv := range kvs {
-				version := kv.Version
-				kv.Version = p.restoreTs
-				if err := toBuffer(kv, version); err != nil {
-					return err
-				}
-			}
 		}
-
-	case posting.BitSchemaPosting:
-		appendNamespace := func() error {
-			// If the backup was taken on old version, we need to append the namespace to
-			// the fields of TypeUpdate.
-			var update pb.TypeUpdate
-			if err := update.Unmarshal(kv.Value); err != nil {
-				return err
-			}
-			update.TypeName = x.GalaxyAttr(update.TypeName)
-			for _, sch := range update.Fields {
-				sch.Predicate = x.GalaxyAttr(sch.Predicate)
+		backupNum = req.in.backupNum
+		defer func() {
+			if bufSince.IsZero() && !buf.IsEmpty() {
+				bufSince = time.Now()
 			}
-			kv.Value, err = update.Marshal()
-			return err
-		}
-		changeFormat := func() error {
-			// In the backup taken on 2103, we have the schemaUpdate.Predicate in format
-			// <namespace 8 bytes>|<attribute>. That had issues with JSON marshalling.
-			// So, we switched over to the format <namespace hex string>-<attribute>.
-			var err error
-			if parsedKey.IsSchema() {
-				var update pb.SchemaUpdate
-				if err := update.Unmarshal(kv.Value); err != nil {
-					return err
-				}
-				if update.Predicate, err = x.AttrFrom2103(update.Predicate); err != nil {
-					return err
-				}
-				kv.Value, err = update.Marshal()
+		}()
+		addAck(req.ack)
+		err := req.lbuf.SliceIterate(func(s []byte) error {
+			list.Reset()
+			if err := list.Unmarshal(s); err != nil {
 				return err
 			}
-			if parsedKey.IsType() {
-				var update pb.TypeUpdate
-				if err := update.Unmarshal(kv.Value); err != nil {
-					return err
-				}
-				if update.TypeName, err = x.AttrFrom2103(update.TypeName); err != nil {
+			for _, kv := range list.GetKv() {
+				if err := p.processKV(buf, req.in, kv); err != nil {
 					return err
 				}
-				for _, sch := range update.Fields {
-					if sch.Predicate, err = x.AttrFrom2103(sch.Predicate); err != nil {
+				if buf.LenNoPadding() > m.opts.ProcessFlushSize {
+					if err := send(); err != nil {
 						return err
 					}
+					addAck(req.ack)
 				}
-				kv.Value, err = update.Marshal()
-				return err
 			}
 			return nil
-		}
-		// We changed the format of predicate in 2103 and 2105. SchemaUpdate and TypeUpdate have
-		// predicate stored within them, so they also need to be updated accordingly.
-		switch in.version {
-		case 0:
-			if parsedKey.IsType() {
-				if err := appendNamespace(); err != nil {
-					glog.Errorf("Unable to (un)marshal type: %+v. Err=%v\n", parsedKey, err)
-					return nil
-				}
-			}
-		case 2103:
-			if err := changeFormat(); err != nil {
-				glog.Errorf("Unable to change format for: %+v Err=%+v", parsedKey, err)
-				return nil
-			}
-		default:
-			// for manifest versions >= 2015, do nothing.
-		}
-		// Reset the StreamId to prevent ordering issues while writing to stream writer.
-		kv.StreamId = 0
-		// Schema and type keys are not stored in an intermediate format so their
-		// value can be written as is.
-		version := kv.Version
-		kv.Version = p.restoreTs
-		kv.Key = restoreKey
-		if err := toBuffer(kv, version); err != nil {
+		})
+		if err != nil {
 			return err
 		}
-
-	default:
-		return errors.Errorf(
-			"Unexpected meta %d for key %s", kv.UserMeta[0], hex.Dump(kv.Key))
+		// The max uid and namespace are updated for every request, so that a checkpoint
+		// covers the uids of the frames recorded in it.
+		m.updateMax(p.maxUid, p.maxNs)
+		req.ack.release()
+		return nil
 	}
-	return nil
-}
 
-func (m *mapper) processReqCh(ctx context.Context) error {
-	var list bpb.KVList
-	p := &processor{mapper: m}
-	buf := z.NewBuffer(256<<20, "processKVList")
-
-	process := func(req listReq) error {
-		defer req.lbuf.Release()
-
-		if ctx.Err() != nil {
-			return ctx.Err()
+	var tick <-chan time.Time
+	if m.opts.FlushInterval > 0 {
+		ticker := time.NewTicker(m.opts.FlushInterval / 2)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	// The channels are set to nil once they are closed. prioCh is nil from the start if there
+	// are no priority namespaces.
+	reqCh, prioCh := m.reqCh, m.prioCh
+	for reqCh != nil || prioCh != nil {
+		if prioCh != nil {
+			// The requests of the priority namespaces are taken first.
+			select {
+			case req, ok := <-prioCh:
+				if !ok {
+					prioCh = nil
+					continue
+				}
+				if err := process(req); err != nil {
+					return err
+				}
+				continue
+			default:
+			}
 		}
-		return req.lbuf.SliceIterate(func(s []byte) error {
-			list.Reset()
-			if err := list.Unmarshal(s); err != nil {
+		waitStart := time.Now()
+		select {
+		case req, ok := <-prioCh:
+			m.addWait(waitReqCh, waitStart)
+			if !ok {
+				prioCh = nil
+				continue
+			}
+			if err := process(req); err != nil {
+				return err
+			}
+		case req, ok := <-reqCh:
+			m.addWait(waitReqCh, waitStart)
+			if !ok {
+				reqCh = nil
+				continue
+			}
+			if err := process(req); err != nil {
 				return err
 			}
-			for _, kv := range list.GetKv() {
-				if err := p.processKV(buf, req.in, kv); err != nil {
+		case <-tick:
+			m.addWait(waitReqCh, waitStart)
+			// Under a low ingest rate, the buffer might take a long time to fill up. Push it
+			// out once it has been holding data for longer than the flush interval.
+			if !bufSince.IsZero() && time.Since(bufSince) >= m.opts.FlushInterval {
+				if err := send(); err != nil {
 					return err
 				}
-				if buf.LenNoPadding() > 228<<20 {
-					select {
-					case m.writeCh <- buf:
-						// good.
-					case <-ctx.Done():
-						return errors.Wrapf(ctx.Err(), "processReqCh.SliceIterate")
-					}
-					buf = z.NewBuffer(256<<20, "processKVList")
-				}
 			}
-			return nil
-		})
+		}
 	}
-
-	for req := range m.reqCh {
-		if err := process(req); err != nil {
-			return err
+	if err := m.sendBuffer(ctx, m.newMapBuffer(buf, backupNum, acks)); err != nil {
+		return errors.Wrapf(err, "processReqCh")
+	}
+	if p.inv != nil {
+		m.opts.inventory.merge(p.inv)
+	}
+	m.seenMu.Lock()
+	for attr := range p.seen {
+		m.seenPreds[attr] = struct{}{}
+	}
+	for attr := range p.schemas {
+		m.schemaPreds[attr] = struct{}{}
+	}
+	for attr := range p.schemaAttrs {
+		m.schemaAttrs[attr] = struct{}{}
+	}
+	m.nsMismatches = append(m.nsMismatches, p.nsMismatches...)
+	for ns := range p.namespaces {
+		if m.namespaces == nil {
+			m.namespaces = make(map[uint64]struct{})
 		}
+		m.namespaces[ns] = struct{}{}
 	}
-	m.writeCh <- buf
+	for attr, cost := range p.rollups {
+		total, ok := m.rollups[attr]
+		if !ok {
+			total = &rollupCost{}
+			m.rollups[attr] = total
+		}
+		total.count += cost.count
+		total.time += cost.time
+	}
+	m.seenMu.Unlock()
+	return nil
+}
 
-	// Update the global maxUid and maxNs. We need CAS here because mapping is
-	// being carried out concurrently.
+// updateMax updates the global maxUid and maxNs. We need CAS here because mapping is being
+// carried out concurrently.
+func (m *mapper) updateMax(maxUid, maxNs uint64) {
 	for {
 		oldMaxUid := atomic.LoadUint64(&m.maxUid)
-		newMaxUid := x.Max(oldMaxUid, p.maxUid)
+		newMaxUid := x.Max(oldMaxUid, maxUid)
 		if swapped := atomic.CompareAndSwapUint64(&m.maxUid, oldMaxUid, newMaxUid); swapped {
 			break
 		}
 	}
 	for {
 		oldMaxNs := atomic.LoadUint64(&m.maxNs)
-		newMaxNs := x.Max(oldMaxNs, p.maxNs)
+		newMaxNs := x.Max(oldMaxNs, maxNs)
 		if swapped := atomic.CompareAndSwapUint64(&m.maxNs, oldMaxNs, newMaxNs); swapped {
 			break
 		}
 	}
-
-	return nil
 }
 
 func (m *mapper) Progress() {
@@ -545,28 +963,91 @@ func (m *mapper) Progress() {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 
+	// readRateHist tracks the bytes read per second, to tell a uniformly slow restore apart
+	// from one which stalls from time to time.
+	readRateHist := z.NewHistogramData(z.HistogramBounds(10, 36))
+	var lastRead uint64
+	lastTick := time.Now()
+
+	stats := m.openStatsFile()
+	defer func() {
+		stats.close()
+	}()
+
 	start := time.Now()
-	update := func() {
+	update := func(final bool) {
 		read := atomic.LoadUint64(&m.bytesRead)
 		proc := atomic.LoadUint64(&m.bytesProcessed)
 		since := time.Since(start)
 		rate := uint64(float64(proc) / since.Seconds())
-		glog.Infof("Restore MAP %s len(reqCh): %d len(writeCh): %d read: %s. output: %s."+
-			" rate: %s/sec. nextFileId: %d writers: %d jemalloc: %s.\n",
-			x.FixedDuration(since), len(m.reqCh),
-			len(m.writeCh), humanize.IBytes(read), humanize.IBytes(proc),
-			humanize.IBytes(rate), atomic.LoadUint32(&m.nextId),
-			len(m.writers),
-			humanize.IBytes(uint64(z.NumAllocBytes())))
+
+		if stats != nil {
+			err := stats.write(&mapStatsRow{
+				Time:           time.Now().UTC(),
+				BytesRead:      read,
+				BytesProcessed: proc,
+				Rate:           rate,
+				Writers:        len(m.writers),
+				AllocBytes:     uint64(z.NumAllocBytes()),
+			})
+			if err != nil {
+				glog.Warningf("%sUnable to write the stats file: %s. No more stats are written."+
+					" Err: %v", m.logPrefix, stats.name, err)
+				stats.close()
+				stats = nil
+			}
+		}
+
+		if interval := time.Since(lastTick); interval > 0 {
+			readRateHist.Update(int64(float64(read-lastRead) / interval.Seconds()))
+		}
+		lastRead, lastTick = read, time.Now()
+		if m.opts.OnProgress != nil || m.opts.HeartbeatFile != "" {
+			progress := &MapProgress{
+				Elapsed:        since,
+				BytesRead:      read,
+				BytesProcessed: proc,
+				Rate:           rate,
+				FileId:         atomic.LoadUint32(&m.nextId),
+				Final:          final,
+				ReadRateHist:   readRateHist,
+				InputSizeHist:  m.InputSizeHist(),
+				Waits:          m.pipelineWaits(),
+			}
+			if m.opts.OnProgress != nil {
+				m.opts.OnProgress(progress)
+			}
+			m.writeHeartbeat(progress)
+		}
+
+		m.log(MapEventProgress,
+			MapLogField{"elapsed", since},
+			MapLogField{"req_ch", len(m.reqCh)},
+			MapLogField{"write_ch", len(m.writeCh)},
+			MapLogField{"bytes_read", read},
+			MapLogField{"bytes_processed", proc},
+			MapLogField{"rate", rate},
+			MapLogField{"file_id", atomic.LoadUint32(&m.nextId)},
+			MapLogField{"writers", len(m.writers)},
+			MapLogField{"waits", m.pipelineWaits()},
+			MapLogField{"jemalloc", uint64(z.NumAllocBytes())})
 	}
 	for {
 		select {
 		case <-m.closer.HasBeenClosed():
-			update()
-			glog.Infof("Restore MAP Done in %s.\n", x.FixedDuration(time.Since(start)))
+			update(true)
+			glog.Infof("%sHistogram of map read rates (bytes/sec):\n%s\n",
+				m.logPrefix, readRateHist)
+			m.logWaits(time.Since(start))
+			m.log(MapEventDone, MapLogField{"elapsed", time.Since(start)})
 			return
 		case <-ticker.C:
-			update()
+			update(false)
+			if m.ckpt != nil {
+				if err := m.ckpt.save(m); err != nil {
+					glog.Errorf("%sUnable to save the map checkpoint. Err: %v", m.logPrefix, err)
+				}
+			}
 		}
 	}
 }
@@ -581,32 +1062,131 @@ const bufSoftLimit = bufSz - 2<<20
 // Otherwise, the original value is used.
 // TODO(DGRAPH-1234): Check whether restoreTs can be removed.
 func (m *mapper) Map(r io.Reader, in *loadBackupInput) error {
+	// offset is the offset of the next frame, and skip is the offset up to which the frames
+	// have to be skipped.
+	offset := in.startOffset
+	var skip uint64
+	checksum := in.checksum
+	if offset > 0 {
+		// The checksum covers the whole stream, so it can't be verified on resume.
+		checksum = ""
+		skip = offset
+		if s, ok := r.(io.Seeker); ok {
+			if _, err := s.Seek(int64(offset), io.SeekStart); err != nil {
+				glog.Infof("%sUnable to seek the backup of group: %d in manifest num: %d."+
+					" Skipping the frames up to offset: %d instead. Err: %v", m.logPrefix,
+					in.groupId, in.backupNum, offset, err)
+			} else {
+				skip = 0
+			}
+		}
+	}
+	// The hash is only computed if there is a checksum to compare it with.
+	var h hash.Hash
+	if checksum != "" {
+		h = sha256.New()
+		r = io.TeeReader(r, h)
+	}
 	br := bufio.NewReaderSize(r, 16<<10)
-	zbuf := z.NewBuffer(bufSz, "Restore.Map")
+	if skip > 0 {
+		// The stream can't seek, so it is read from the start, skipping the frames which
+		// are already mapped.
+		if err := skipFrames(br, skip); err != nil {
+			return errors.Wrapf(err, "while skipping to offset: %d", skip)
+		}
+	}
+	zbuf := m.opts.BufferAllocator.NewBuffer(bufSz, "Restore.Map")
+	// prio is set if the frames in zbuf belong to the priority namespaces.
+	var prio bool
+	// spool holds the frames by predicate with PredicateOrder. They are only sent once the
+	// whole file is read.
+	var spool *predicateSpool
+	if m.opts.predicateOrder != nil {
+		spool = newPredicateSpool(m.opts.BufferAllocator, len(m.opts.predicateOrder))
+		defer spool.release()
+	}
 
 	for {
 		var sz uint64
 		err := binary.Read(br, binary.LittleEndian, &sz)
 		if err == io.EOF {
+			if h != nil {
+				if got := hex.EncodeToString(h.Sum(nil)); got != checksum {
+					zbuf.Release()
+					return errors.Errorf("checksum mismatch for the backup of group: %d in"+
+						" manifest num: %d. Expected: %s, got: %s",
+						in.groupId, in.backupNum, checksum, got)
+				}
+			}
 			break
 		} else if err != nil {
 			return err
 		}
 
+		m.szHistMu.Lock()
 		m.szHist.Update(int64(sz))
+		m.szHistMu.Unlock()
+		if spool != nil {
+			if err := spool.add(br, sz, m.frameRank(br, sz)); err != nil {
+				return err
+			}
+			offset += 8 + sz
+			continue
+		}
+		if m.priorityNs != nil {
+			// The frames of the priority namespaces are not batched with the other ones, so
+			// that they can be queued separately.
+			if framePrio := m.isPriorityFrame(br, sz); framePrio != prio {
+				if zbuf.LenNoPadding() > 0 {
+					atomic.AddUint64(&m.bytesRead, uint64(zbuf.LenNoPadding()))
+					req := listReq{zbuf, in, in.tracker.newAck(offset, false)}
+					if err := m.sendReq(req, prio); err != nil {
+						return err
+					}
+					zbuf = m.opts.BufferAllocator.NewBuffer(bufSz, "Restore.Map")
+				}
+				prio = framePrio
+			}
+		}
 		buf := zbuf.SliceAllocate(int(sz))
 		if _, err = io.ReadFull(br, buf); err != nil {
 			return err
 		}
+		offset += 8 + sz
 
 		if zbuf.LenNoPadding() > bufSoftLimit {
 			atomic.AddUint64(&m.bytesRead, uint64(zbuf.LenNoPadding()))
-			m.reqCh <- listReq{zbuf, in}
-			zbuf = z.NewBuffer(bufSz, "Restore.Map")
+			if err := m.sendReq(listReq{zbuf, in, in.tracker.newAck(offset, false)},
+				prio); err != nil {
+				return err
+			}
+			zbuf = m.opts.BufferAllocator.NewBuffer(bufSz, "Restore.Map")
 		}
 	}
-	m.reqCh <- listReq{zbuf, in}
-	return nil
+	if spool != nil {
+		zbuf.Release()
+		return m.sendSpool(spool, in, offset)
+	}
+	return m.sendReq(listReq{zbuf, in, in.tracker.newAck(offset, true)}, prio)
+}
+
+// sendReq sends the request for processing. It gives up if the processing has failed.
+func (m *mapper) sendReq(req listReq, prio bool) error {
+	if err := m.ctx.Err(); err != nil {
+		req.lbuf.Release()
+		return errors.Wrap(err, "while sending request for processing")
+	}
+	reqCh := m.reqCh
+	if prio {
+		reqCh = m.prioCh
+	}
+	select {
+	case reqCh <- req:
+		return nil
+	case <-m.ctx.Done():
+		req.lbuf.Release()
+		return errors.Wrap(m.ctx.Err(), "while sending request for processing")
+	}
 }
 
 type mapResult struct {
@@ -621,11 +1201,261 @@ type mapResult struct {
 	shouldDropAll bool
 	dropAttr      map[string]struct{}
 	dropNs        map[uint64]struct{}
+
+	// badKeys is the number of keys skipped because of SkipBadKeys, and badKeySamples holds
+	// the error and the hex dump for some of them.
+	badKeys       uint64
+	badKeySamples []string
+
+	// inputSizeHist is the histogram of the sizes of all the KV lists read by the mapper.
+	inputSizeHist *HistogramSnapshot
+
+	// schemaNsMismatches are the inconsistencies between the namespaces of the schema and of
+	// the data found with CheckSchemaNamespaces.
+	schemaNsMismatches []string
+
+	// emptyPreds are the predicates of the mapped backups for which no posting list was
+	// mapped. They either have a schema but no data, or their data is missing from the backup.
+	emptyPreds []string
+
+	// rollupCosts are the predicates with the largest total rollup time, most expensive first.
+	rollupCosts []predRollupCost
+
+	// namespaces are the distinct namespaces of the keys mapped, in increasing order. They are
+	// only collected with CollectNamespaces.
+	namespaces []uint64
+
+	// verifyStore is the result of VerifyStore.
+	verifyStore *VerifyStoreResult
+
+	// dataWithoutSchema are the predicates which have data but no schema key, and
+	// schemaWithoutData are the ones which have a schema key but no data.
+	dataWithoutSchema []string
+	schemaWithoutData []string
+
+	// schemaCollisions are the schema and type keys found with different values in the
+	// backups of different groups with MergeAllGroups.
+	schemaCollisions []string
+
+	// nsCollisions are the predicates and types found in several namespaces, which were merged
+	// into the galaxy namespace with MergeNamespaceCollisions.
+	nsCollisions []string
+
+	// sampledKeys is the number of posting lists kept with SampleRate, out of sampleTotal.
+	sampledKeys uint64
+	sampleTotal uint64
+
+	// emptyRollups is the number of complete posting lists whose rollup returned no KV. They
+	// are written as empty posting lists with KeepEmptyRollups, and skipped otherwise.
+	emptyRollups uint64
+	// staleEntries is the number of entries dropped by MapOptions.DedupLatest.
+	staleEntries uint64
+
+	// unreadableFiles are the backup files skipped with SkipUnreadableFiles, along with the
+	// error they failed with.
+	unreadableFiles []string
+
+	// fileSizes is the distribution of the sizes of the map files written.
+	fileSizes mapFileSizes
+}
+
+// MapProgress is a snapshot of the progress of the map phase.
+type MapProgress struct {
+	Elapsed        time.Duration
+	BytesRead      uint64
+	BytesProcessed uint64
+	// Rate is the number of bytes processed per second since the start of the map phase.
+	Rate uint64
+	// FileId is the id of the last map file created.
+	FileId uint32
+	// Final is set on the last update, once the map phase is over.
+	Final bool
+	// ReadRateHist is the histogram of the bytes read per second, sampled every second. It is
+	// owned by the mapper and must not be used after the callback returns.
+	ReadRateHist *z.HistogramData
+	// InputSizeHist is the histogram of the sizes of the KV lists read so far.
+	InputSizeHist *HistogramSnapshot
+	// Waits is the time spent blocked at each stage of the pipeline so far.
+	Waits MapWaits
+}
+
+// The hooks below are replaced by tests, so that the manifest loop of RunMapper can be driven
+// with crafted manifests and drop operations, without a store.
+var (
+	// getRestoreManifests returns the manifests to restore from the location, ordered from
+	// the latest to the oldest.
+	getRestoreManifests = getManifestsToRestore
+	// banNamespace bans the namespace in the store, for the drop namespace operations.
+	banNamespace = func(ns uint64) error { return pstore.BanNamespace(ns) }
+)
+
+// mapGoroutines returns the number of goroutines processing the KV lists.
+func mapGoroutines(opts MapOptions) int {
+	if opts.Deterministic {
+		// A single goroutine maps the KV lists in the order they are read.
+		return 1
+	}
+	numGo := int(float64(runtime.NumCPU()) * 0.75)
+	if numGo < 2 {
+		numGo = 2
+	}
+	return numGo
+}
+
+// StreamMapInput describes the stream of KV lists mapped by RunStreamMapper.
+type StreamMapInput struct {
+	// Preds are the predicates to map, in the format returned by x.NamespaceAttr. The keys of
+	// the other predicates are skipped. The type keys are always mapped.
+	Preds []string
+	// Version is the version of the backup format the stream was written with.
+	Version int
+	// RestoreTs is the timestamp the entries are restored at.
+	RestoreTs uint64
+	// KeepSchema maps the schema and type keys. They should only be mapped from the latest
+	// backup.
+	KeepSchema bool
+	// GroupId is the group the stream belongs to. It is only recorded in the manifest of the
+	// map files.
+	GroupId uint32
+	// RequestId tags the map files and the log messages, like the id of a restore request.
+	RequestId string
+}
+
+// RunStreamMapper maps a stream of KV lists, framed the way they are in a backup file, to map
+// files in mapDir. The stream must be already decrypted and decompressed. Unlike RunMapper, it
+// doesn't read any manifest, so the drop operations of the backups are not applied. This
+// allows feeding the mapper from a pipe, e.g. from an external decryption tool.
+// CheckpointFile is not supported, since a stream can't be resumed.
+func RunStreamMapper(r io.Reader, mapDir string, in StreamMapInput, opts MapOptions) (
+	_ *mapResult, rerr error) {
+	if in.RestoreTs == 0 {
+		return nil, errors.New("StreamMapInput must have a valid RestoreTs")
+	}
+	if opts.CheckpointFile != "" {
+		return nil, errors.New("CheckpointFile is not supported when mapping a stream")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid map options")
+	}
+	// A map directory which can't be written is reported before any backup is read.
+	if !isRemoteMapDir(mapDir) {
+		if err := checkMapDirWritable(mapDir); err != nil {
+			return nil, err
+		}
+	}
+
+	numGo := mapGoroutines(opts)
+	mapper := newMapper(in.RestoreTs, mapDir, opts, numGo)
+	mapper.setRequestId(in.RequestId)
+	mapper.groupId = in.GroupId
+	if isRemoteMapDir(mapDir) {
+		if opts.CleanupOnError {
+			return nil, errors.Errorf("CleanupOnError is not supported for remote map dir: %s",
+				mapDir)
+		}
+		mapUri, err := url.Parse(mapDir)
+		if err != nil {
+			return nil, err
+		}
+		if mapper.mapStore, err = x.NewUriHandler(mapUri, nil); err != nil {
+			return nil, errors.Wrapf(err, "while creating handler for map dir")
+		}
+	}
+	defer func() {
+		if rerr != nil && opts.CleanupOnError {
+			glog.Infof("%sMap phase failed. Removing the map files. Err: %v",
+				mapper.logPrefix, rerr)
+			mapper.removeMapFiles()
+		}
+	}()
+
+	mapper.startPipeline(numGo)
+	defer func() {
+		rerr = mapper.checkTimeout(rerr)
+		if rerr != nil {
+			mapper.cancel()
+		}
+		mapper.stopPipeline()
+	}()
+
+	go mapper.Progress()
+	defer mapper.closer.SignalAndWait()
+
+	preds := make(predicateSet, len(in.Preds))
+	for _, attr := range in.Preds {
+		preds[attr] = struct{}{}
+	}
+	lin := &loadBackupInput{
+		preds:      preds,
+		dropNs:     make(map[uint64]struct{}),
+		version:    in.Version,
+		keepSchema: in.KeepSchema,
+		schemaOnly: opts.SchemaOnly,
+		groupId:    in.GroupId,
+	}
+	if err := mapper.Map(r, lin); err != nil {
+		return nil, errors.Wrap(err, "mapper.Map")
+	}
+	if err := mapper.stopPipeline(); err != nil {
+		return nil, err
+	}
+	if err := mapper.Flush(); err != nil {
+		return nil, errors.Wrap(err, "failed to flush the mapper")
+	}
+	if opts.WriteMapManifest {
+		if err := mapper.writeMapManifest(); err != nil {
+			return nil, errors.Wrap(err, "while writing the manifest of the map files")
+		}
+	}
+	mapRes := &mapResult{
+		maxUid:        mapper.maxUid,
+		maxNs:         mapper.maxNs,
+		dropAttr:      make(map[string]struct{}),
+		dropNs:        make(map[uint64]struct{}),
+		badKeys:       mapper.badKeys,
+		badKeySamples: mapper.badKeySamples,
+		inputSizeHist: mapper.InputSizeHist(),
+		rollupCosts:   topRollupCosts(mapper.rollups, maxRollupCostPreds),
+		fileSizes:     mapper.reportFileSizes(),
+	}
+	if n := atomic.LoadUint64(&mapper.clampedVersions); n > 0 {
+		glog.Warningf("%sClamped %d versions which the VersionOffset: %d moved out of [1, %d)",
+			mapper.logPrefix, n, mapper.opts.VersionOffset, mapper.restoreTs)
+	}
+	mapRes.sampledKeys, mapRes.sampleTotal = mapper.sampleCounts()
+	mapRes.emptyRollups = mapper.emptyRollupCount()
+	mapRes.staleEntries = mapper.staleEntryCount()
+	switch n := mapRes.emptyRollups; {
+	case n > 0 && mapper.opts.KeepEmptyRollups:
+		glog.Warningf("%sWrote empty posting lists for %d keys whose rollup returned no KV",
+			mapper.logPrefix, n)
+	case n > 0:
+		glog.Warningf("%sSkipped %d keys whose rollup returned no KV", mapper.logPrefix, n)
+	}
+	mapRes.namespaces = mapper.sortedNamespaces()
+	if mapRes.badKeys > 0 {
+		glog.Warningf("%sSkipped %d keys which could not be parsed. Samples:\n%s",
+			mapper.logPrefix, mapRes.badKeys, strings.Join(mapRes.badKeySamples, "\n"))
+	}
+	return mapRes, nil
 }
 
 // 1. RunMapper creates a mapper object
 // 2. mapper.Map() ->
-func RunMapper(req *pb.RestoreRequest, mapDir string) (*mapResult, error) {
+func RunMapper(req *pb.RestoreRequest, mapDir string, opts MapOptions) (*mapResult, error) {
+	return RunMapperContext(context.Background(), req, mapDir, opts)
+}
+
+// RunMapperContext works like RunMapper, but stops the map phase once ctx is canceled, like
+// when an admin aborts the restore. All the goroutines of the mapper are stopped, and the map
+// files and the checkpoint are handled as for any other failure. The error returned then
+// wraps ctx.Err(), so errors.Is tells it apart from a genuine failure.
+func RunMapperContext(ctx context.Context, req *pb.RestoreRequest, mapDir string,
+	opts MapOptions) (_ *mapResult, rerr error) {
+	start := time.Now()
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(err, "map phase canceled before it started")
+	}
 	uri, err := url.Parse(req.Location)
 	if err != nil {
 		return nil, err
@@ -633,6 +1463,28 @@ func RunMapper(req *pb.RestoreRequest, mapDir string) (*mapResult, error) {
 	if req.RestoreTs == 0 {
 		return nil, errors.New("RestoreRequest must have a valid restoreTs")
 	}
+	for pred, ts := range opts.PredicateRestoreTs {
+		if ts > req.RestoreTs {
+			return nil, errors.Errorf("PredicateRestoreTs: %d of predicate: %s is above"+
+				" restoreTs: %d", ts, pred, req.RestoreTs)
+		}
+	}
+	for _, ts := range opts.RestoreTsSets {
+		if ts > req.RestoreTs {
+			return nil, errors.Errorf("RestoreTsSets: timestamp: %d is above restoreTs: %d",
+				ts, req.RestoreTs)
+		}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid map options")
+	}
+	// A map directory which can't be written is reported before any backup is read. The
+	// inventory doesn't write map files.
+	if !isRemoteMapDir(mapDir) && opts.inventory == nil {
+		if err := checkMapDirWritable(mapDir); err != nil {
+			return nil, err
+		}
+	}
 
 	creds := getCredentialsFromRestoreRequest(req)
 	h, err := x.NewUriHandler(uri, creds)
@@ -640,11 +1492,36 @@ func RunMapper(req *pb.RestoreRequest, mapDir string) (*mapResult, error) {
 		return nil, err
 	}
 
-	manifests, err := getManifestsToRestore(h, uri, req)
+	var manifests []*Manifest
+	// handlers is only set when the backups are spread over multiple locations.
+	var handlers map[*Manifest]x.UriHandler
+	if len(opts.ExtraLocations) > 0 {
+		locations := append([]string{req.Location}, opts.ExtraLocations...)
+		manifests, handlers, err = getManifestsFromLocations(locations, req)
+	} else if opts.ManifestOverride != "" {
+		manifests, err = getOverrideManifests(h, opts.ManifestOverride, req)
+	} else {
+		manifests, err = getRestoreManifests(h, uri, req)
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "cannot retrieve manifests")
 	}
 	glog.Infof("Got %d backups to restore ", len(manifests))
+	if opts.ManifestVerifier != nil {
+		if err := verifyManifestSignatures(opts.ManifestVerifier, manifests, h,
+			handlers); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkBackupSettings(manifests, req.IncrementalFrom,
+		opts.StrictBackupSettings); err != nil {
+		return nil, err
+	}
+	if req.GroupId != 0 && !opts.MergeAllGroups {
+		if err := checkGroupExists(manifests, req.GroupId); err != nil {
+			return nil, err
+		}
+	}
 
 	cfg, err := getEncConfig(req)
 	if err != nil {
@@ -654,47 +1531,69 @@ func RunMapper(req *pb.RestoreRequest, mapDir string) (*mapResult, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	numGo := int(float64(runtime.NumCPU()) * 0.75)
-	if numGo < 2 {
-		numGo = 2
+	// The data keys managed by a KMS are only decrypted once for the whole restore.
+	dataKeys, err := kmsDataKeys(ctx, opts.KmsProvider, opts.KmsKeyId, manifests,
+		req.IncrementalFrom)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEncryption(manifests, req, keys.EncKey, dataKeys); err != nil {
+		return nil, err
 	}
+
+	numGo := mapGoroutines(opts)
 	glog.Infof("Setting numGo = %d\n", numGo)
-	mapper := &mapper{
-		closer:  z.NewCloser(1),
-		reqCh:   make(chan listReq, numGo+numGo/4),
-		writeCh: make(chan *z.Buffer, numGo),
-		// Only half the writers should be writing at the same time.
-		writers:   make(chan struct{}, numGo/2),
-		restoreTs: req.RestoreTs,
-		mapDir:    mapDir,
-		szHist:    z.NewHistogramData(z.HistogramBounds(10, 32)),
-	}
-
-	g, ctx := errgroup.WithContext(mapper.closer.Ctx())
-	for i := 0; i < numGo; i++ {
-		g.Go(func() error {
-			return mapper.processReqCh(ctx)
-		})
+	mapper := newMapper(req.RestoreTs, mapDir, opts, numGo)
+	mapper.setRequestId(req.RequestId)
+	mapper.groupId = req.GroupId
+	if isRemoteMapDir(mapDir) {
+		if err := mapper.openMapStore(mapDir, creds); err != nil {
+			return nil, err
+		}
+	}
+	glog.Infof("Setting merge concurrency = %d, write concurrency = %d\n",
+		mapper.opts.MergeConcurrency, mapper.opts.WriteConcurrency)
+	var resumed bool
+	if opts.CheckpointFile != "" {
+		if resumed, err = mapper.resumeCheckpoint(mapDir); err != nil {
+			return nil, err
+		}
+	}
+	// The map files of a resumed run have already been checked against the checkpoint.
+	if !resumed && mapper.mapStore == nil {
+		if err := checkMapDir(mapDir, opts); err != nil {
+			return nil, err
+		}
 	}
+	// This is deferred first, so that it runs after the goroutines have been signalled to stop.
+	defer func() {
+		if rerr != nil && opts.CleanupOnError {
+			glog.Infof("%sMap phase failed. Removing the map files. Err: %v",
+				mapper.logPrefix, rerr)
+			mapper.removeMapFiles()
+		}
+	}()
+	// The checkpoint is saved once the pipeline has stopped, so that it records all the map
+	// files written.
+	defer func() {
+		if mapper.ckpt == nil {
+			return
+		}
+		if err := mapper.ckpt.save(mapper); err != nil && rerr == nil {
+			rerr = err
+		}
+	}()
 
-	wCloser := z.NewCloser(numGo / 2)
-	defer wCloser.Signal()
-	go func() {
-		<-wCloser.HasBeenClosed()
-		close(mapper.writeCh)
+	mapper.startPipeline(numGo)
+	mapper.watchCancel(ctx)
+	defer func() {
+		rerr = checkCanceled(ctx, mapper.checkTimeout(rerr))
+		if rerr != nil {
+			mapper.cancel()
+		}
+		// On success, the pipeline has already been stopped. This is a no-op then.
+		mapper.stopPipeline()
 	}()
-	for i := 0; i < numGo/2; i++ {
-		go func() {
-			err := mapper.mergeAndSend(wCloser)
-			if err != nil {
-				g.Go(func() error {
-					return errors.Wrapf(err, "mergeAndSend returned error")
-				})
-			}
-			glog.Infof("mapper.mergeAndSend done with error: %v", err)
-		}()
-	}
 
 	go mapper.Progress()
 	defer func() {
@@ -702,71 +1601,228 @@ func RunMapper(req *pb.RestoreRequest, mapDir string) (*mapResult, error) {
 		mapper.closer.SignalAndWait()
 	}()
 
+	if opts.IgnoreDropOperations {
+		glog.Warningf("%sIgnoreDropOperations is set. The drop operations of the manifests are"+
+			" ignored, so the data dropped after the backups were taken is restored, and no"+
+			" namespace is banned", mapper.logPrefix)
+	}
 	dropAll := false
 	dropAttr := make(map[string]struct{})
 	dropNs := make(map[uint64]struct{})
+	// expectedPreds are the predicates which could have data in the mapped backups.
+	expectedPreds := make(map[string]struct{})
 	var maxBannedNs uint64
+	// latest is the latest manifest with data. The predicates and the schema are taken from it.
+	var latest *Manifest
+	// schemaMapped are the predicates of the manifests already mapped. It is only used with
+	// IgnoreDropOperations, to map the schema of the predicates missing from the latest manifest.
+	schemaMapped := make(predicateSet)
+
+	var windowMin, windowMax uint64
+	if opts.WindowTo > 0 {
+		if windowMin, windowMax, err = manifestWindow(manifests, opts.WindowFrom,
+			opts.WindowTo); err != nil {
+			return nil, err
+		}
+		glog.Infof("%sOnly mapping the changes of manifests %d to %d, with versions in (%d, %d]",
+			mapper.logPrefix, opts.WindowFrom, opts.WindowTo, windowMin, windowMax)
+	}
 
+	// mapped are the manifests whose drop operations and groups were processed.
+	var mapped []*Manifest
+	// unreadable are the backup files skipped with SkipUnreadableFiles.
+	var unreadable []string
 	// manifests are ordered as: latest..full
-	for i, manifest := range manifests {
+	for _, manifest := range manifests {
 
 		// We only need to consider the incremental backups.
 		if manifest.BackupNum < req.IncrementalFrom {
 			break
 		}
+		if opts.WindowTo > 0 {
+			// The manifests after the window are skipped along with their drop operations,
+			// which happened after the window.
+			if manifest.BackupNum > opts.WindowTo {
+				continue
+			}
+			if manifest.BackupNum < opts.WindowFrom {
+				break
+			}
+		}
 
 		// A dropAll or DropData operation is encountered. No need to restore previous backups.
 		if dropAll {
 			break
 		}
+		mapped = append(mapped, manifest)
+		// The manifests without data have no group to map, but their drop operations must still
+		// be applied to the older manifests.
+		groups := manifest.Groups
 		if manifest.ValidReadTs() == 0 || len(manifest.Groups) == 0 {
-			continue
+			msg, suspicious := describeEmptyManifest(manifest)
+			if suspicious {
+				glog.Warningf("%sManifest num: %d in %s %s", mapper.logPrefix,
+					manifest.BackupNum, manifest.Path, msg)
+			} else {
+				glog.Infof("%sManifest num: %d in %s %s", mapper.logPrefix,
+					manifest.BackupNum, manifest.Path, msg)
+			}
+			groups = nil
+		} else {
+			if opts.MergeAllGroups {
+				if conflicts := findGroupConflicts(manifest); len(conflicts) > 0 {
+					return nil, errors.Errorf(
+						"cannot merge the groups of manifest num: %d in %s: %s",
+						manifest.BackupNum, manifest.Path, strings.Join(conflicts, "; "))
+				}
+			}
+			if latest == nil {
+				latest = manifest
+			}
+			if opts.inventory != nil {
+				opts.inventory.addManifest(manifest)
+			}
 		}
-		for gid := range manifest.Groups {
-			if gid != req.GroupId {
+		mh := h
+		if handlers != nil {
+			mh = handlers[manifest]
+		}
+		for gid := range groups {
+			if gid != req.GroupId && !opts.MergeAllGroups {
 				// LoadBackup will try to call the backup function for every group.
 				// Exit here if the group is not the one indicated by the request.
 				continue
 			}
+			if opts.SchemaOnly && manifest != latest {
+				// Only the schema of the latest backup is mapped, so there is nothing to
+				// read from the older backups. Their drop operations are still processed.
+				continue
+			}
 
 			// Only restore the predicates that were assigned to this group at the time
 			// of the last backup.
 			file := filepath.Join(manifest.Path, backupName(manifest.ValidReadTs(), gid))
-			br := readerFrom(h, file).WithEncryption(keys.EncKey).WithCompression(manifest.Compression)
-			if br.err != nil {
-				return nil, errors.Wrap(br.err, "newBackupReader")
-			}
-			defer br.Close()
-
-			// Only map the predicates which haven't been dropped yet.
-			predSet := manifests[0].getPredsInGroup(gid)
-			for p := range predSet {
-				if _, ok := dropAttr[p]; ok {
-					delete(predSet, p)
+			var tracker *fileTracker
+			var startOffset uint64
+			if mapper.ckpt != nil {
+				fc := mapper.ckpt.resumeFrom(file)
+				if fc.Done {
+					glog.Infof("%sSkipping backup file: %s which is already mapped",
+						mapper.logPrefix, file)
+					continue
 				}
+				if fc.Offset > 0 {
+					glog.Infof("%sResuming backup file: %s from offset: %d",
+						mapper.logPrefix, file, fc.Offset)
+				}
+				startOffset = fc.Offset
+				tracker = mapper.ckpt.track(file, fc.Offset)
 			}
-			localDropNs := make(map[uint64]struct{})
-			for ns := range dropNs {
-				localDropNs[ns] = struct{}{}
-			}
-			in := &loadBackupInput{
-				preds:   predSet,
-				dropNs:  localDropNs,
-				version: manifest.Version,
-				// Only map the schema keys corresponding to the latest backup.
-				keepSchema: i == 0,
+			encKey := manifestEncKey(manifest, keys.EncKey, dataKeys)
+			comp := manifest.Compression
+			if c, ok := opts.CompressionOverrides[gid]; ok {
+				comp = c
 			}
 
-			// This would stream the backups from the source, and map them in
-			// Dgraph compatible format on disk.
-			if err := mapper.Map(br, in); err != nil {
-				return nil, errors.Wrap(err, "mapper.Map")
+			// The reader is closed as soon as the file is mapped, rather than once all the
+			// backups are mapped.
+			mapFile := func() error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				br := readerFrom(mh, file).WithEncryption(encKey)
+				defer br.Close()
+				if opts.SniffCompression {
+					br = br.WithSniffedCompression(comp)
+				} else {
+					br = br.WithCompression(comp)
+				}
+				if br.err != nil {
+					if !opts.SkipUnreadableFiles {
+						return errors.Wrap(br.err, "newBackupReader")
+					}
+					glog.Errorf("%sSkipping unreadable backup file: %s of group: %d. Err: %v",
+						mapper.logPrefix, file, gid, br.err)
+					unreadable = append(unreadable, fmt.Sprintf("%s: %v", file, br.err))
+					return nil
+				}
+
+				// Only map the predicates which haven't been dropped yet.
+				predSet := latest.getPredsInGroup(gid)
+				if opts.IgnoreDropOperations {
+					// The predicates dropped since this backup are missing from the latest
+					// one.
+					if predSet == nil {
+						predSet = make(predicateSet)
+					}
+					for p := range manifest.getPredsInGroup(gid) {
+						predSet[p] = struct{}{}
+					}
+				}
+				for p := range predSet {
+					if _, ok := dropAttr[p]; ok {
+						delete(predSet, p)
+						continue
+					}
+					expectedPreds[p] = struct{}{}
+				}
+				localDropNs := make(map[uint64]struct{})
+				for ns := range dropNs {
+					localDropNs[ns] = struct{}{}
+				}
+				in := &loadBackupInput{
+					preds:   predSet,
+					dropNs:  localDropNs,
+					version: manifest.Version,
+					// Only map the schema keys corresponding to the latest backup.
+					keepSchema:  manifest == latest,
+					backupNum:   manifest.BackupNum,
+					schemaOnly:  opts.SchemaOnly,
+					groupId:     gid,
+					startOffset: startOffset,
+					tracker:     tracker,
+					minVersion:  windowMin,
+					maxVersion:  windowMax,
+				}
+				if opts.IgnoreDropOperations && manifest != latest {
+					// The schema of a dropped predicate is only found in the backups taken
+					// before the drop. It is taken from the latest of them, as all the
+					// schema keys are written at the same version.
+					in.schemaPreds = make(predicateSet)
+					for p := range manifest.getPredsInGroup(gid) {
+						if _, ok := schemaMapped[p]; !ok {
+							in.schemaPreds[p] = struct{}{}
+						}
+					}
+				}
+				if opts.VerifyChecksums {
+					in.checksum = manifest.Checksums[gid]
+				}
+				if opts.PredicateBloomFilter {
+					in.predBloom = newPredBloom(predSet)
+				}
+				// This would stream the backups from the source, and map them in
+				// Dgraph compatible format on disk.
+				if err := mapper.Map(br, in); err != nil {
+					return errors.Wrap(err, "mapper.Map")
+				}
+				return errors.Wrap(br.Close(), "br.Close")
+			}
+			if err := mapFile(); err != nil {
+				return nil, err
 			}
-			if err := br.Close(); err != nil {
-				return nil, errors.Wrap(err, "br.Close")
+		}
+		for _, preds := range groups {
+			for _, p := range preds {
+				schemaMapped[p] = struct{}{}
 			}
 		}
 		for _, op := range manifest.DropOperations {
+			if opts.IgnoreDropOperations {
+				glog.Warningf("%sIgnoring drop operation: %s of manifest num: %d in %s",
+					mapper.logPrefix, op, manifest.BackupNum, manifest.Path)
+				continue
+			}
 			switch op.DropOp {
 			case pb.DropOperation_ALL:
 				dropAll = true
@@ -789,33 +1845,145 @@ func RunMapper(req *pb.RestoreRequest, mapDir string) (*mapResult, error) {
 				if err != nil {
 					return nil, errors.Wrapf(err, "Map phase failed to parse namespace")
 				}
-				if err := pstore.BanNamespace(ns); err != nil {
-					return nil, errors.Wrapf(err, "Map phase failed to ban namespace: %d", ns)
+				// The inventory must not have any side effects on the store.
+				if opts.inventory == nil {
+					if err := banNamespace(ns); err != nil {
+						return nil, errors.Wrapf(err, "Map phase failed to ban namespace: %d", ns)
+					}
 				}
 				maxBannedNs = x.Max(maxBannedNs, ns)
 			}
 		}
-		glog.Infof("[MAP] Processed manifest num: %d\n", manifest.BackupNum)
+		mapper.log(MapEventManifestDone, MapLogField{"backup_num", manifest.BackupNum})
 	} // done with all the manifests.
 
-	glog.Infof("Histogram of map input sizes:\n%s\n", mapper.szHist)
-	close(mapper.reqCh)
-	if err := g.Wait(); err != nil {
-		return nil, errors.Wrapf(err, "from processKVList")
+	mapper.szHistMu.Lock()
+	glog.Infof("%sHistogram of map input sizes:\n%s\n", mapper.logPrefix, mapper.szHist)
+	mapper.szHistMu.Unlock()
+	if err := mapper.stopPipeline(); err != nil {
+		return nil, err
+	}
+	// Some of the data might not have been mapped if the map phase was canceled.
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	glog.Infof("mapper.processReqCh done")
-	wCloser.SignalAndWait()
 	if err := mapper.Flush(); err != nil {
 		return nil, errors.Wrap(err, "failed to flush the mapper")
 	}
+	if opts.WriteMapManifest && opts.inventory == nil {
+		if err := mapper.writeMapManifest(); err != nil {
+			return nil, errors.Wrap(err, "while writing the manifest of the map files")
+		}
+	}
+	if opts.CheckPartitions && opts.inventory == nil && mapper.mapStore == nil {
+		pr, err := checkPartitions(mapDir, mapper.opts.KeyComparator)
+		if err != nil {
+			return nil, errors.Wrap(err, "while checking the partitions of the map files")
+		}
+		glog.Infof("%sPartitions of the map files: %s", mapper.logPrefix, pr)
+		for _, file := range pr.unsorted {
+			glog.Warningf("%sMap file %s has unsorted partition keys", mapper.logPrefix, file)
+		}
+	}
 	mapRes := &mapResult{
 		maxUid:        mapper.maxUid,
 		maxNs:         mapper.maxNs,
 		shouldDropAll: dropAll,
 		dropAttr:      dropAttr,
 		dropNs:        dropNs,
+		badKeys:       mapper.badKeys,
+		badKeySamples: mapper.badKeySamples,
+		inputSizeHist: mapper.InputSizeHist(),
+	}
+	if len(unreadable) > 0 {
+		mapRes.unreadableFiles = unreadable
+		glog.Warningf("%sSkipped %d unreadable backup files. Their data is missing from the"+
+			" restore: %v", mapper.logPrefix, len(unreadable), unreadable)
+	}
+	if opts.CollectNamespaces && !resumed {
+		mapRes.namespaces = mapper.sortedNamespaces()
+		glog.Infof("%sNamespaces of the backups: %v", mapper.logPrefix, mapRes.namespaces)
+	} else if opts.CollectNamespaces {
+		glog.Warningf("%sThe map phase was resumed, so the namespaces are not collected",
+			mapper.logPrefix)
+	}
+	// The data mapped before a resume is not seen, so the predicates can't be reported then.
+	if !opts.SchemaOnly && !resumed {
+		mapRes.emptyPreds = findEmptyPreds(expectedPreds, mapper.seenPreds, dropNs)
+	}
+	if !opts.SchemaOnly && !resumed {
+		if err := mapper.reportPredicateMismatches(mapRes, dropNs); err != nil {
+			return nil, err
+		}
+	} else if opts.FailOnPredicateMismatch {
+		glog.Warningf("%sThe map phase was resumed, so the predicates are not checked for"+
+			" mismatches", mapper.logPrefix)
+	}
+	if opts.CheckSchemaNamespaces && !opts.SchemaOnly {
+		mapRes.schemaNsMismatches = findSchemaNsMismatches(mapper.nsMismatches,
+			mapper.seenPreds, mapper.schemaPreds)
+		for _, msg := range mapRes.schemaNsMismatches {
+			glog.Warningf("%sSchema namespace mismatch: %s", mapper.logPrefix, msg)
+		}
+	}
+	if opts.VerifyStore && opts.inventory == nil {
+		res, err := verifyStore(mapDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "while verifying the map files")
+		}
+		glog.Infof("%sVerified the map files by restoring %d keys into a throwaway store",
+			mapper.logPrefix, res.StoredKeys)
+		mapRes.verifyStore = res
+	}
+	if len(mapper.nsCollisions) > 0 {
+		mapRes.nsCollisions = append([]string{}, mapper.nsCollisions...)
+		sort.Strings(mapRes.nsCollisions)
+		for _, msg := range mapRes.nsCollisions {
+			glog.Warningf("%sMerged the %s into the galaxy namespace", mapper.logPrefix, msg)
+		}
+	}
+	if len(mapper.schemaCollisions) > 0 {
+		mapRes.schemaCollisions = append([]string{}, mapper.schemaCollisions...)
+		sort.Strings(mapRes.schemaCollisions)
+		for _, msg := range mapRes.schemaCollisions {
+			glog.Warningf("%sSchema collision: %s", mapper.logPrefix, msg)
+		}
+	}
+	mapRes.fileSizes = mapper.reportFileSizes()
+	mapRes.rollupCosts = topRollupCosts(mapper.rollups, maxRollupCostPreds)
+	if len(mapRes.rollupCosts) > 0 {
+		glog.Infof("%sPredicates with the most expensive rollups: %v", mapper.logPrefix,
+			mapRes.rollupCosts)
+	}
+	if len(mapRes.emptyPreds) > 0 {
+		glog.Infof("%sNo data was mapped for %d predicates: %v", mapper.logPrefix,
+			len(mapRes.emptyPreds), mapRes.emptyPreds)
+	}
+	if n := atomic.LoadUint64(&mapper.clampedVersions); n > 0 {
+		glog.Warningf("%sClamped %d versions which the VersionOffset: %d moved out of [1, %d)",
+			mapper.logPrefix, n, mapper.opts.VersionOffset, mapper.restoreTs)
+	}
+	mapRes.sampledKeys, mapRes.sampleTotal = mapper.sampleCounts()
+	mapRes.emptyRollups = mapper.emptyRollupCount()
+	mapRes.staleEntries = mapper.staleEntryCount()
+	switch n := mapRes.emptyRollups; {
+	case n > 0 && mapper.opts.KeepEmptyRollups:
+		glog.Warningf("%sWrote empty posting lists for %d keys whose rollup returned no KV",
+			mapper.logPrefix, n)
+	case n > 0:
+		glog.Warningf("%sSkipped %d keys whose rollup returned no KV", mapper.logPrefix, n)
+	}
+	if mapRes.badKeys > 0 {
+		glog.Warningf("%sSkipped %d keys which could not be parsed. Samples:\n%s",
+			mapper.logPrefix, mapRes.badKeys, strings.Join(mapRes.badKeySamples, "\n"))
 	}
 	// update the maxNsId considering banned namespaces.
 	mapRes.maxNs = x.Max(mapRes.maxNs, maxBannedNs)
+	if opts.WriteSummary && opts.inventory == nil {
+		summary := mapper.newMapSummary(req, mapped, mapRes, numGo, time.Since(start))
+		if err := mapper.writeMapSummary(summary); err != nil {
+			return nil, errors.Wrap(err, "while writing the summary of the map phase")
+		}
+	}
 	return mapRes, nil
 }
@@ -0,0 +1,567 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// mapFile is a map file being written, either to the local disk or through the handler of a
+// remote map directory.
+type mapFile struct {
+	name string
+	// rel is the path of the file relative to the map directory.
+	rel string
+	w   io.WriteCloser
+	// f is only set for the local files.
+	f *os.File
+	// size is the number of bytes written.
+	size int64
+	// h is the hash of the bytes written. It is only set if opts.VerifyChecksums is set.
+	h hash.Hash
+	// closed is set once the file has been closed by finish or abort.
+	closed bool
+}
+
+func (mf *mapFile) Write(p []byte) (int, error) {
+	n, err := mf.w.Write(p)
+	mf.size += int64(n)
+	if mf.h != nil {
+		mf.h.Write(p[:n])
+	}
+	return n, err
+}
+
+// finish closes the map file once it has been completely written. A local file is synced first
+// if sync is set, while a remote file is only created once its upload completes.
+func (mf *mapFile) finish(sync bool) error {
+	mf.closed = true
+	if mm, ok := mf.w.(*mmapWriter); ok {
+		// The file must be truncated to its final size before it is synced.
+		if err := mm.unmap(); err != nil {
+			mm.Close()
+			return err
+		}
+	}
+	if mf.f != nil && sync {
+		if err := mf.f.Sync(); err != nil {
+			mf.f.Close()
+			return errors.Wrap(err, "file.Sync")
+		}
+	}
+	return mf.w.Close()
+}
+
+// abort closes the map file after a failure. A remote file is discarded. A local file is left
+// as it is, and is removed if opts.CleanupOnError is set.
+func (mf *mapFile) abort(err error) {
+	if mf.closed {
+		return
+	}
+	mf.closed = true
+	if a, ok := mf.w.(x.FileAborter); ok {
+		if err := a.Abort(err); err != nil {
+			glog.Errorf("Unable to abort writing map file: %s. Err: %v", mf.name, err)
+		}
+		return
+	}
+	mf.w.Close()
+}
+
+// checkMapDir handles the map files found in the local map directory before the map phase. They
+// were left by a previous run, and the reduce phase would read them along with the new ones.
+// They are removed with CleanMapDir, and fail the map phase with FailIfMapDirNonEmpty. They
+// are only reported otherwise.
+func checkMapDir(mapDir string, opts MapOptions) error {
+	if _, err := os.Stat(mapDir); os.IsNotExist(err) {
+		return nil
+	}
+	files, _, err := mapFiles(mapDir)
+	if err != nil {
+		return errors.Wrapf(err, "while listing the map files in %s", mapDir)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	sort.Strings(files)
+	switch {
+	case opts.FailIfMapDirNonEmpty:
+		return errors.Errorf("map directory %s already holds %d map files, like %s. Remove"+
+			" them or use CleanMapDir", mapDir, len(files), files[0])
+	case opts.CleanMapDir:
+		for _, file := range files {
+			if err := os.Remove(file); err != nil {
+				return errors.Wrapf(err, "while removing map file %s", file)
+			}
+		}
+		glog.Infof("Removed %d map files left by a previous run in %s", len(files), mapDir)
+	default:
+		glog.Warningf("Map directory %s already holds %d map files, like %s. They will be"+
+			" reduced along with the new ones", mapDir, len(files), files[0])
+	}
+	return nil
+}
+
+// checkMapDirWritable creates the local map directory if needed, and checks that map files can
+// be created in it, by creating and removing a file.
+func checkMapDirWritable(mapDir string) error {
+	if err := os.MkdirAll(mapDir, 0750); err != nil {
+		return errors.Wrapf(err, "map directory %s can't be created", mapDir)
+	}
+	f, err := ioutil.TempFile(mapDir, ".probe-")
+	if err != nil {
+		return errors.Wrapf(err, "map directory %s is not writable", mapDir)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "map directory %s is not writable", mapDir)
+	}
+	return errors.Wrapf(os.Remove(f.Name()), "while removing %s", f.Name())
+}
+
+// isRemoteMapDir returns true if mapDir is a URI handled by a x.UriHandler, like
+// s3://bucket/path, rather than a local directory.
+func isRemoteMapDir(mapDir string) bool {
+	return isRemoteLocation(mapDir)
+}
+
+// isRemoteLocation returns true if the location is a URI of an object storage rather than a
+// local path.
+func isRemoteLocation(location string) bool {
+	uri, err := url.Parse(location)
+	// A single letter scheme is a Windows drive.
+	return err == nil && len(uri.Scheme) > 1 && uri.Scheme != "file"
+}
+
+// openMapStore sets up the handler of the remote map dir, where the map files are uploaded.
+// The options which need the map files on the local disk are rejected.
+func (m *mapper) openMapStore(mapDir string, creds *x.MinioCredentials) error {
+	if m.opts.CleanupOnError {
+		return errors.Errorf("CleanupOnError is not supported for remote map dir: %s", mapDir)
+	}
+	if m.opts.VerifyStore {
+		return errors.Errorf("VerifyStore is not supported for remote map dir: %s", mapDir)
+	}
+	if m.opts.CleanMapDir || m.opts.FailIfMapDirNonEmpty {
+		return errors.Errorf("CleanMapDir and FailIfMapDirNonEmpty are not supported"+
+			" for remote map dir: %s", mapDir)
+	}
+	mapUri, err := url.Parse(mapDir)
+	if err != nil {
+		return err
+	}
+	if m.mapStore, err = x.NewUriHandler(mapUri, creds); err != nil {
+		return errors.Wrapf(err, "while creating handler for map dir")
+	}
+	return nil
+}
+
+// mapFileSet is the set of map files an entry is written to.
+type mapFileSet struct {
+	// nsDir is the subdirectory of the namespace of the entries with PerNamespaceSubdirs, and
+	// is empty otherwise.
+	nsDir string
+	// large is set for the large values with SplitLargeValues.
+	large bool
+	// restoreTs is the version of the entries with RestoreTsSets, and is zero otherwise.
+	restoreTs uint64
+}
+
+// newMapFile creates the next map file of the set.
+func (mw *mapper) newMapFile(backupNum uint64, set mapFileSet) (*mapFile, error) {
+	fileNum := atomic.AddUint32(&mw.nextId, 1)
+	var dir string
+	if set.restoreTs > 0 {
+		dir = restoreTsDir(set.restoreTs)
+	}
+	if set.large {
+		dir = path.Join(dir, largeValueDir)
+	}
+	if mw.opts.PerManifestSubdirs {
+		dir = path.Join(dir, fmt.Sprintf("m%d", backupNum))
+	}
+	dir = path.Join(dir, set.nsDir)
+	name := fmt.Sprintf("%06d.map", fileNum)
+	if mw.opts.MapFileFormat == mapFormatSST {
+		name = fmt.Sprintf("%06d%s", fileNum, sstFileExt)
+	}
+	if mw.reqId != "" {
+		name = mw.reqId + "-" + name
+	}
+
+	if mw.mapStore != nil {
+		// The object storage has no directories, so the name is relative to the map dir.
+		name = path.Join(dir, name)
+		w, err := mw.mapStore.CreateFile(name)
+		if err != nil {
+			return nil, err
+		}
+		return &mapFile{name: mw.mapStore.JoinPath(name), rel: name, w: w}, nil
+	}
+
+	filename := filepath.Join(mw.mapDir, dir, name)
+	if err := os.MkdirAll(filepath.Dir(filename), 0750); err != nil {
+		return nil, err
+	}
+	open := func() (*mapFile, error) {
+		flag := os.O_WRONLY
+		if mw.opts.MmapMapFiles {
+			// A writable mapping needs the file to be readable as well.
+			flag = os.O_RDWR
+		}
+		f, err := os.OpenFile(filename, flag|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return nil, err
+		}
+		mf := &mapFile{name: filename, rel: filepath.Join(dir, name), w: f, f: f}
+		if mw.opts.MmapMapFiles {
+			if mf.w, err = newMmapWriter(f, mmapInitialSize); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+		return mf, nil
+	}
+
+	if !mw.opts.CleanupOnError {
+		return open()
+	}
+	mw.filesMu.Lock()
+	defer mw.filesMu.Unlock()
+	if mw.cleanedUp {
+		return nil, errors.Errorf("cannot create map file %s after cleanup", filename)
+	}
+	mf, err := open()
+	if err == nil {
+		mw.files = append(mw.files, filename)
+	}
+	return mf, err
+}
+
+// MapFileInfo describes a map file written by the mapper.
+type MapFileInfo struct {
+	// Name is the path of the file relative to the map directory.
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// Checksum is the hex encoded SHA-256 checksum of the file. It is only set if
+	// MapOptions.VerifyChecksums is set.
+	Checksum      string `json:"checksum,omitempty"`
+	PartitionKeys int    `json:"partition_keys"`
+	// BackupNum is the number of the manifest whose entries are in the file. It is only set
+	// with MapOptions.PerManifestSubdirs, as the files hold the entries of multiple manifests
+	// otherwise.
+	BackupNum uint64 `json:"backup_num,omitempty"`
+	GroupId   uint32 `json:"group_id"`
+	// LargeValues is set if the file holds the entries of the large values, see
+	// MapOptions.SplitLargeValues.
+	LargeValues bool `json:"large_values,omitempty"`
+	// RestoreTs is the version of the entries of the file with MapOptions.RestoreTsSets.
+	RestoreTs uint64 `json:"restore_ts,omitempty"`
+}
+
+// addWrittenFile records a map file which was completely written.
+func (mw *mapper) addWrittenFile(info MapFileInfo) {
+	mw.writtenMu.Lock()
+	mw.written = append(mw.written, info)
+	mw.writtenMu.Unlock()
+	if mw.ckpt != nil {
+		mw.ckpt.markDirty()
+	}
+}
+
+// writtenFiles returns a copy of the map files written so far.
+func (mw *mapper) writtenFiles() []MapFileInfo {
+	mw.writtenMu.Lock()
+	defer mw.writtenMu.Unlock()
+	return append([]MapFileInfo{}, mw.written...)
+}
+
+// mapManifestName is the name of the manifest of the map files written in the map directory.
+const mapManifestName = "map_manifest.json"
+
+// mapManifest lists the map files written by the map phase.
+type mapManifest struct {
+	Files []MapFileInfo `json:"files"`
+}
+
+// writeMapManifest writes the manifest of the map files written so far to the map directory.
+func (mw *mapper) writeMapManifest() error {
+	b, err := json.Marshal(&mapManifest{Files: mw.writtenFiles()})
+	if err != nil {
+		return err
+	}
+	return mw.writeMapDirFile(mapManifestName, b)
+}
+
+// writeMapDirFile atomically writes b to the file of the map directory with the given name,
+// prefixed with the id of the request.
+func (mw *mapper) writeMapDirFile(name string, b []byte) error {
+	if mw.reqId != "" {
+		name = mw.reqId + "-" + name
+	}
+	if mw.mapStore == nil {
+		if err := os.MkdirAll(mw.mapDir, 0750); err != nil {
+			return err
+		}
+		return writeFileAtomic(filepath.Join(mw.mapDir, name), b)
+	}
+	// The object is only created once the upload completes, so it is never observed partially
+	// written.
+	w, err := mw.mapStore.CreateFile(name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		if a, ok := w.(x.FileAborter); ok {
+			a.Abort(err)
+		} else {
+			w.Close()
+		}
+		return err
+	}
+	return w.Close()
+}
+
+// removeMapFiles removes the map files created by the mapper, and prevents it from creating
+// any more of them.
+func (mw *mapper) removeMapFiles() {
+	mw.filesMu.Lock()
+	defer mw.filesMu.Unlock()
+
+	mw.cleanedUp = true
+	for _, file := range mw.files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			glog.Errorf("%sUnable to remove map file: %s. Err: %v", mw.logPrefix, file, err)
+			continue
+		}
+		glog.Infof("%sRemoved map file: %s", mw.logPrefix, file)
+	}
+	mw.files = nil
+}
+
+// namespaceDir is the subdirectory of the map files of the namespace with PerNamespaceSubdirs.
+func namespaceDir(ns uint64) string {
+	return fmt.Sprintf("ns%d", ns)
+}
+
+// entryNamespace returns the namespace of the key of a map entry.
+func entryNamespace(key []byte) uint64 {
+	if len(key) < 9 {
+		return x.GalaxyNamespace
+	}
+	return binary.BigEndian.Uint64(key[1:9])
+}
+
+// finishMapFile closes the map file once it has been completely written, and records it in the
+// files written by the mapper.
+func (m *mapper) finishMapFile(mf *mapFile, backupNum uint64, set mapFileSet,
+	partitionKeys int) error {
+	deferSync := m.opts.SyncMode == SyncBatch || m.opts.SyncMode == SyncNone
+	if err := mf.finish(!deferSync); err != nil {
+		return errors.Wrapf(err, "while finishing map file %s", mf.name)
+	}
+	if deferSync && mf.f != nil {
+		if err := syncFiles(m.addUnsynced(mf.name)); err != nil {
+			return errors.Wrap(err, "while syncing a batch of map files")
+		}
+	}
+	info := MapFileInfo{
+		Name:          mf.rel,
+		Size:          mf.size,
+		PartitionKeys: partitionKeys,
+		GroupId:       m.groupId,
+	}
+	if m.opts.PerManifestSubdirs {
+		info.BackupNum = backupNum
+	}
+	info.LargeValues = set.large
+	info.RestoreTs = set.restoreTs
+	if mf.h != nil {
+		info.Checksum = hex.EncodeToString(mf.h.Sum(nil))
+	}
+	m.addWrittenFile(info)
+	m.log(MapEventFileCreated, MapLogField{"file", filepath.Base(mf.name)},
+		MapLogField{"size", uint64(mf.size)})
+	return nil
+}
+
+// waitForDiskSpace blocks until the free space on the file system of mapDir is at least
+// opts.DiskWatermarkBytes. It gives up after opts.DiskWatermarkTimeout. If the free space can't
+// be determined, it doesn't block.
+func (mw *mapper) waitForDiskSpace() error {
+	if mw.opts.DiskWatermarkBytes == 0 || mw.mapStore != nil {
+		return nil
+	}
+	var ticker *time.Ticker
+	var start time.Time
+	for {
+		free, err := x.FreeDiskSpace(mw.mapDir)
+		if err != nil {
+			glog.V(2).Infof("%sUnable to get the free disk space of %s. Err: %v",
+				mw.logPrefix, mw.mapDir, err)
+			return nil
+		}
+		if free >= mw.opts.DiskWatermarkBytes {
+			if ticker != nil {
+				ticker.Stop()
+				glog.Infof("%sResuming map file writes after %s. Free disk space: %s",
+					mw.logPrefix, time.Since(start).Round(time.Second), humanize.IBytes(free))
+			}
+			return nil
+		}
+		if ticker == nil {
+			ticker = time.NewTicker(time.Second)
+			start = time.Now()
+			glog.Warningf("%sPausing map file writes. Free disk space: %s is below the"+
+				" watermark: %s", mw.logPrefix, humanize.IBytes(free),
+				humanize.IBytes(mw.opts.DiskWatermarkBytes))
+		}
+		if time.Since(start) >= mw.opts.DiskWatermarkTimeout {
+			ticker.Stop()
+			return errors.Errorf("free disk space: %s stayed below the watermark: %s for %s",
+				humanize.IBytes(free), humanize.IBytes(mw.opts.DiskWatermarkBytes),
+				mw.opts.DiskWatermarkTimeout)
+		}
+		select {
+		case <-ticker.C:
+		case <-mw.ctx.Done():
+			ticker.Stop()
+			return errors.Wrap(mw.ctx.Err(), "while waiting for disk space")
+		}
+	}
+}
+
+// addUnsynced records a map file written without being synced. With SyncBatch, it returns the
+// files to sync once there are SyncBatchSize of them.
+func (mw *mapper) addUnsynced(file string) []string {
+	mw.syncMu.Lock()
+	defer mw.syncMu.Unlock()
+	if mw.unsyncedDirs == nil {
+		mw.unsyncedDirs = make(map[string]struct{})
+	}
+	// The directory entries of the map files, and of their subdirectories, are synced too.
+	mw.unsyncedDirs[filepath.Dir(file)] = struct{}{}
+	mw.unsyncedDirs[mw.mapDir] = struct{}{}
+	mw.unsynced = append(mw.unsynced, file)
+	if mw.opts.SyncMode != SyncBatch || len(mw.unsynced) < mw.opts.SyncBatchSize {
+		return nil
+	}
+	files := mw.unsynced
+	mw.unsynced = nil
+	return files
+}
+
+// syncFiles syncs the files to disk.
+func syncFiles(files []string) error {
+	for _, file := range files {
+		if err := syncFile(file); err != nil {
+			return errors.Wrapf(err, "while syncing map file %s", file)
+		}
+	}
+	return nil
+}
+
+// syncFile syncs the file or the directory to disk.
+func syncFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// mapFileSizes is the distribution of the sizes of the map files written by the mapper.
+type mapFileSizes struct {
+	count                   int
+	total, min, median, max int64
+	// skew is the size of the largest map file over the median size. It is 1 if all the map
+	// files have the same size. A large skew means that a few map files hold most of the data,
+	// which leaves the reduce phase unbalanced.
+	skew float64
+}
+
+func (s mapFileSizes) String() string {
+	return fmt.Sprintf("%d map files of %s, min: %s, median: %s, max: %s, skew: %.2f",
+		s.count, humanize.IBytes(uint64(s.total)), humanize.IBytes(uint64(s.min)),
+		humanize.IBytes(uint64(s.median)), humanize.IBytes(uint64(s.max)), s.skew)
+}
+
+// mapFileSkewWarning is the skew of the sizes of the map files above which it is logged as a
+// warning.
+const mapFileSkewWarning = 8
+
+// newMapFileSizes returns the distribution of the sizes of the map files.
+func newMapFileSizes(files []MapFileInfo) mapFileSizes {
+	if len(files) == 0 {
+		return mapFileSizes{}
+	}
+	sizes := make([]int64, 0, len(files))
+	var total int64
+	for _, f := range files {
+		sizes = append(sizes, f.Size)
+		total += f.Size
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+	n := len(sizes)
+	median := sizes[n/2]
+	if n%2 == 0 {
+		median = (sizes[n/2-1] + sizes[n/2]) / 2
+	}
+	s := mapFileSizes{count: n, total: total, min: sizes[0], median: median, max: sizes[n-1]}
+	if median > 0 {
+		s.skew = float64(s.max) / float64(median)
+	}
+	return s
+}
+
+// reportFileSizes logs the distribution of the sizes of the map files written, from the sizes
+// recorded as they were written, and returns it.
+func (m *mapper) reportFileSizes() mapFileSizes {
+	sizes := newMapFileSizes(m.writtenFiles())
+	if sizes.count == 0 {
+		return sizes
+	}
+	if sizes.skew > mapFileSkewWarning {
+		glog.Warningf("%sThe sizes of the map files are skewed: %s. The reduce phase may be"+
+			" unbalanced, consider tuning the merge and write concurrency", m.logPrefix, sizes)
+	} else {
+		glog.Infof("%sWrote %s", m.logPrefix, sizes)
+	}
+	return sizes
+}
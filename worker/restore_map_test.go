@@ -0,0 +1,2834 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	bpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/dgraph-io/badger/v3/y"
+	"github.com/dgraph-io/dgo/v210/protos/api"
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/codec"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// schemaKV returns the KV for the schema of the given predicate, as it is stored in a backup.
+func schemaKV(t *testing.T, ns uint64, attr string) *bpb.KV {
+	key, err := (&pb.BackupKey{Type: pb.BackupKey_SCHEMA, Attr: attr, Namespace: ns}).Marshal()
+	require.NoError(t, err)
+	val, err := (&pb.SchemaUpdate{Predicate: x.NamespaceAttr(ns, attr)}).Marshal()
+	require.NoError(t, err)
+	return &bpb.KV{
+		Key:      key,
+		Value:    val,
+		UserMeta: []byte{posting.BitSchemaPosting},
+		Version:  1,
+	}
+}
+
+// typeKV returns the KV for the definition of the given type, as it is stored in a backup.
+func typeKV(t *testing.T, ns uint64, name string) *bpb.KV {
+	key, err := (&pb.BackupKey{Type: pb.BackupKey_TYPE, Attr: name, Namespace: ns}).Marshal()
+	require.NoError(t, err)
+	val, err := (&pb.TypeUpdate{TypeName: x.NamespaceAttr(ns, name)}).Marshal()
+	require.NoError(t, err)
+	return &bpb.KV{
+		Key:      key,
+		Value:    val,
+		UserMeta: []byte{posting.BitSchemaPosting},
+		Version:  1,
+	}
+}
+
+// appendKVList appends the list to the stream in the format read by mapper.Map.
+func appendKVList(t *testing.T, stream *bytes.Buffer, kvs ...*bpb.KV) {
+	data, err := (&bpb.KVList{Kv: kvs}).Marshal()
+	require.NoError(t, err)
+	require.NoError(t, binary.Write(stream, binary.LittleEndian, uint64(len(data))))
+	stream.Write(data)
+}
+
+func TestMapperStopOnMergeFailure(t *testing.T) {
+	f, err := ioutil.TempFile("", "restore-map")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	defer os.Remove(f.Name())
+
+	var stream bytes.Buffer
+	for i := 0; i < 2000; i++ {
+		appendKVList(t, &stream, schemaKV(t, x.GalaxyNamespace, "name"))
+	}
+	in := &loadBackupInput{
+		preds:      predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		keepSchema: true,
+	}
+
+	for i := 0; i < 20; i++ {
+		// The map files can't be created under a regular file, so every write fails. Send each
+		// KV for merging on its own and flush the merge buffers all the time, so that the
+		// merging fails while the processors are still sending.
+		opts := MapOptions{
+			ProcessBufSize:   1 << 10,
+			ProcessFlushSize: 1,
+			FlushInterval:    time.Millisecond,
+		}
+		require.NoError(t, opts.validate())
+		m := newMapper(10, filepath.Join(f.Name(), "map"), opts, 4)
+		m.startPipeline(4)
+		// Map fails if the processors have already given up, so its error is not checked.
+		_ = m.Map(bytes.NewReader(stream.Bytes()), in)
+		require.Error(t, m.stopPipeline())
+		// Stopping again must not panic on the closed channels.
+		require.Error(t, m.stopPipeline())
+		m.closer.Signal()
+	}
+}
+
+func TestSanitizeRequestId(t *testing.T) {
+	require.Equal(t, "", sanitizeRequestId(""))
+	require.Equal(t, "restore-1_a", sanitizeRequestId("restore-1_a"))
+	require.Equal(t, "______etc_passwd", sanitizeRequestId("../../etc/passwd"))
+	require.Equal(t, "a_b_c", sanitizeRequestId("a b%c"))
+	require.Len(t, sanitizeRequestId(strings.Repeat("x", 100)), maxRequestIdLen)
+}
+
+func TestMapFileName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	m := newMapper(10, dir, MapOptions{}, 2)
+	m.setRequestId("tenant/1")
+	mf, err := m.newMapFile(1, mapFileSet{})
+	require.NoError(t, err)
+	require.NoError(t, mf.finish(true))
+	require.Equal(t, filepath.Join(dir, "tenant_1-000001.map"), mf.name)
+}
+
+func TestSniffCompression(t *testing.T) {
+	data := bytes.Repeat([]byte("backup data "), 100)
+	var gz, sn bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, err := gw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	sw := snappy.NewBufferedWriter(&sn)
+	_, err = sw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, sw.Close())
+
+	tests := []struct {
+		name     string
+		file     []byte
+		declared string
+		// err is set if the file can't be read back.
+		err bool
+	}{
+		{"gzip declared gzip", gz.Bytes(), "gzip", false},
+		{"gzip declared snappy", gz.Bytes(), "snappy", false},
+		{"snappy declared snappy", sn.Bytes(), "snappy", false},
+		{"snappy declared gzip", sn.Bytes(), "gzip", false},
+		{"snappy declared default", sn.Bytes(), "", false},
+		// Nothing is detected, so the declared compression is used and fails.
+		{"plain declared snappy", data, "snappy", true},
+		{"plain declared gzip", data, "gzip", true},
+		{"truncated gzip header", gz.Bytes()[:1], "snappy", true},
+		{"truncated snappy header", sn.Bytes()[:4], "gzip", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := &backupReader{file: tt.name, r: bytes.NewReader(tt.file)}
+			br = br.WithSniffedCompression(tt.declared)
+			defer br.Close()
+			err := br.err
+			var got []byte
+			if err == nil {
+				got, err = ioutil.ReadAll(br)
+			}
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, data, got)
+		})
+	}
+}
+
+func TestMapVerifyChecksum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var stream bytes.Buffer
+	appendKVList(t, &stream, schemaKV(t, x.GalaxyNamespace, "name"))
+	sum := sha256.Sum256(stream.Bytes())
+
+	m := newMapper(10, dir, MapOptions{}, 2)
+	m.startPipeline(2)
+	defer m.closer.Signal()
+	in := &loadBackupInput{
+		preds:      predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		keepSchema: true,
+		groupId:    1,
+		checksum:   hex.EncodeToString(sum[:]),
+	}
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+
+	in.checksum = strings.Repeat("0", 64)
+	err = m.Map(bytes.NewReader(stream.Bytes()), in)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checksum mismatch for the backup of group: 1")
+	require.NoError(t, m.stopPipeline())
+}
+
+func TestBackupChecksum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	attr := x.GalaxyAttr("backup_checksum")
+	val, err := (&pb.SchemaUpdate{Predicate: attr, ValueType: pb.Posting_STRING}).Marshal()
+	require.NoError(t, err)
+	txn := pstore.NewTransactionAt(math.MaxUint64, true)
+	require.NoError(t, txn.Set(x.SchemaKey(attr), val))
+	require.NoError(t, txn.CommitAt(1, nil))
+
+	req := &pb.BackupRequest{
+		ReadTs:      10,
+		GroupId:     1,
+		UnixTs:      "checksum",
+		Destination: "file://" + dir,
+		Predicates:  []string{attr},
+	}
+	bp := NewBackupProcessor(pstore, req)
+	defer bp.Close()
+	res, err := bp.WriteBackup(context.Background())
+	require.NoError(t, err)
+	require.Len(t, res.Checksum, 64)
+
+	// The checksum is the one of the stream read back by the restore.
+	f, err := os.Open(filepath.Join(dir, fmt.Sprintf(backupPathFmt, req.UnixTs),
+		backupName(req.ReadTs, req.GroupId)))
+	require.NoError(t, err)
+	defer f.Close()
+	stream, err := ioutil.ReadAll(snappy.NewReader(f))
+	require.NoError(t, err)
+	sum := sha256.Sum256(stream)
+	require.Equal(t, hex.EncodeToString(sum[:]), res.Checksum)
+
+	mapDir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(mapDir)
+	m := newMapper(10, mapDir, MapOptions{}, 2)
+	m.startPipeline(2)
+	defer m.closer.Signal()
+	in := &loadBackupInput{
+		preds:      predicateSet{attr: struct{}{}},
+		keepSchema: true,
+		groupId:    1,
+		checksum:   res.Checksum,
+	}
+	require.NoError(t, m.Map(bytes.NewReader(stream), in))
+	require.NoError(t, m.stopPipeline())
+}
+
+func TestInputSizeHist(t *testing.T) {
+	m := newMapper(10, "", MapOptions{}, 2)
+	m.szHist.Update(100)
+	m.szHist.Update(5000)
+
+	hist := m.InputSizeHist()
+	require.Equal(t, int64(2), hist.Count)
+	require.Equal(t, int64(100), hist.Min)
+	require.Equal(t, int64(5000), hist.Max)
+	require.Equal(t, len(hist.Bounds)+1, len(hist.Counts))
+	var total int64
+	for _, cnt := range hist.Counts {
+		total += cnt
+	}
+	require.Equal(t, int64(2), total)
+
+	// The snapshot must not change with the histogram.
+	m.szHist.Update(100)
+	require.Equal(t, int64(2), hist.Count)
+}
+
+func TestWaitForDiskSpace(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("free disk space is only supported on Linux")
+	}
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := MapOptions{DiskWatermarkBytes: 1}
+	require.NoError(t, opts.validate())
+	m := newMapper(10, dir, opts, 2)
+	m.ctx = context.Background()
+	require.NoError(t, m.waitForDiskSpace())
+
+	m.opts.DiskWatermarkBytes = math.MaxUint64
+	m.opts.DiskWatermarkTimeout = 10 * time.Millisecond
+	err = m.waitForDiskSpace()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "stayed below the watermark")
+}
+
+func TestMapperConcurrency(t *testing.T) {
+	m := newMapper(10, "", MapOptions{}, 8)
+	require.Equal(t, 4, m.opts.MergeConcurrency)
+	require.Equal(t, 4, cap(m.writers))
+	require.Equal(t, 8, cap(m.writeCh))
+
+	m = newMapper(10, "", MapOptions{MergeConcurrency: 1, WriteConcurrency: 6}, 8)
+	require.Equal(t, 1, m.opts.MergeConcurrency)
+	require.Equal(t, 6, cap(m.writers))
+	require.Equal(t, 2, cap(m.writeCh))
+
+	opts := MapOptions{MergeConcurrency: -1}
+	require.Error(t, opts.validate())
+}
+
+func TestMapperMinimalConcurrency(t *testing.T) {
+	for _, numGo := range []int{0, 1} {
+		dir, err := ioutil.TempDir("", "restore-map")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		m := newMapper(10, dir, MapOptions{}, numGo)
+		require.Equal(t, 1, m.opts.MergeConcurrency)
+		require.Equal(t, 1, m.opts.WriteConcurrency)
+		require.Equal(t, 1, cap(m.writers))
+
+		var stream bytes.Buffer
+		for uid := uint64(1); uid <= 100; uid++ {
+			appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+		}
+		// The pipeline has one processor, one merger and one writer, which must be enough to
+		// map the whole stream without hanging.
+		m.startPipeline(numGo)
+		in := &loadBackupInput{
+			preds:   predicateSet{x.GalaxyAttr("name"): struct{}{}},
+			groupId: 1,
+		}
+		require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+		require.NoError(t, m.stopPipeline())
+		files, _, err := mapFiles(dir)
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+	}
+}
+
+// writeMapHeader writes a map file with the given partition keys and no entries.
+func writeMapHeader(t *testing.T, filename string, keys ...string) {
+	header := &pb.MapHeader{FormatVersion: mapFormatVersion}
+	for _, k := range keys {
+		header.PartitionKeys = append(header.PartitionKeys, y.KeyWithTs([]byte(k), 1))
+	}
+	header.PartitionKeys = encodePartitionKeys(header.PartitionKeys)
+	data, err := header.Marshal()
+	require.NoError(t, err)
+
+	f, err := os.Create(filename)
+	require.NoError(t, err)
+	w := snappy.NewBufferedWriter(f)
+	require.NoError(t, binary.Write(w, binary.BigEndian, uint32(len(data))))
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+}
+
+func TestCheckPartitions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeMapHeader(t, filepath.Join(dir, "000001.map"), "k1", "k3")
+	writeMapHeader(t, filepath.Join(dir, "000002.map"), "k2", "k4")
+	writeMapHeader(t, filepath.Join(dir, "000003.map"), "k5")
+	writeMapHeader(t, filepath.Join(dir, "000004.map"))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "m2"), 0750))
+	writeMapHeader(t, filepath.Join(dir, "m2", "000005.map"), "k4", "k2")
+
+	pr, err := checkPartitions(dir, y.CompareKeys)
+	require.NoError(t, err)
+	require.Equal(t, 5, pr.files)
+	require.Equal(t, 1, pr.unpartitioned)
+	require.Equal(t, []string{filepath.Join(dir, "m2", "000005.map")}, pr.unsorted)
+	require.Equal(t, 2, pr.maxOverlap)
+	require.Equal(t, y.KeyWithTs([]byte("k2"), 1), pr.overlapKey)
+}
+
+func TestReduceOpenFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, _, err = newMapIterator(filepath.Join(dir, "000001.map"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "while opening map file")
+
+	writeMapHeader(t, filepath.Join(dir, "000001.map"), "k1")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "000002.map"), []byte("bad"), 0600))
+	r := &reducer{mapDir: dir}
+	require.Error(t, r.Reduce())
+	require.Empty(t, r.mapItrs)
+}
+
+type reduceWriterFunc func(buf *z.Buffer) error
+
+func (f reduceWriterFunc) Write(buf *z.Buffer) error {
+	return f(buf)
+}
+
+func TestReduceCorruptMapFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeMapFile := func(name string, data []byte) {
+		f, err := os.Create(filepath.Join(dir, name))
+		require.NoError(t, err)
+		w := snappy.NewBufferedWriter(f)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.NoError(t, f.Close())
+	}
+	key := func(k string) string {
+		return string(y.KeyWithTs([]byte(k), 1))
+	}
+	writeMapFile("000001.map", mapFileContents(t, key("a"), key("b")))
+	// The last entry of the second file is truncated.
+	data := mapFileContents(t, key("c"), key("d"))
+	writeMapFile("000002.map", data[:len(data)-3])
+
+	r := &reducer{
+		mapDir:   dir,
+		bufferCh: make(chan *z.Buffer, 10),
+		w: reduceWriterFunc(func(*z.Buffer) error {
+			return nil
+		}),
+	}
+	done := make(chan error)
+	go func() {
+		done <- r.Reduce()
+	}()
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "has a truncated entry")
+	case <-time.After(10 * time.Second):
+		t.Fatal("Reduce hung on a corrupt map file")
+	}
+
+	// The buffers are no longer sent, and the map files have been closed.
+	_, ok := <-r.bufferCh
+	require.False(t, ok)
+	require.Len(t, r.mapItrs, 2)
+	for _, itr := range r.mapItrs {
+		require.True(t, errors.Is(itr.fd.Close(), os.ErrClosed))
+	}
+}
+
+func TestInferSchemaType(t *testing.T) {
+	untyped, err := (&pb.SchemaUpdate{Predicate: "age"}).Marshal()
+	require.NoError(t, err)
+	typed, err := (&pb.SchemaUpdate{Predicate: "age", ValueType: pb.Posting_STRING}).Marshal()
+	require.NoError(t, err)
+
+	check := func(val []byte, force bool, expected pb.Posting_ValType) {
+		out, err := inferSchemaType(val, types.IntID, force)
+		require.NoError(t, err)
+		var update pb.SchemaUpdate
+		require.NoError(t, update.Unmarshal(out))
+		require.Equal(t, expected, update.ValueType)
+		require.Equal(t, "age", update.Predicate)
+	}
+	check(untyped, false, pb.Posting_INT)
+	check(typed, false, pb.Posting_STRING)
+	check(typed, true, pb.Posting_INT)
+
+	opts := MapOptions{TypeInference: map[string]string{"age": "int"}}
+	require.NoError(t, opts.validate())
+	require.Equal(t, types.IntID, opts.inferredTypes["age"])
+	opts = MapOptions{TypeInference: map[string]string{"friend": "uid"}}
+	require.Error(t, opts.validate())
+}
+
+func TestDowngradeSchema(t *testing.T) {
+	marshal := func(m interface{ Marshal() ([]byte, error) }) []byte {
+		b, err := m.Marshal()
+		require.NoError(t, err)
+		return b
+	}
+	parse := func(key []byte) x.ParsedKey {
+		pk, err := x.Parse(key)
+		require.NoError(t, err)
+		return pk
+	}
+	ns2 := string(x.NamespaceToBytes(2))
+	galaxy := string(x.NamespaceToBytes(x.GalaxyNamespace))
+
+	schema := func(pred string) []byte {
+		return marshal(&pb.SchemaUpdate{Predicate: pred, ValueType: pb.Posting_STRING,
+			Directive: pb.SchemaUpdate_INDEX, Tokenizer: []string{"exact"}})
+	}
+	typ := func(name string, preds ...string) []byte {
+		update := &pb.TypeUpdate{TypeName: name}
+		for _, pred := range preds {
+			update.Fields = append(update.Fields, &pb.SchemaUpdate{Predicate: pred})
+		}
+		return marshal(update)
+	}
+	name2, person2 := x.NamespaceAttr(2, "name"), x.NamespaceAttr(2, "Person")
+	name0, person0 := x.GalaxyAttr("name"), x.GalaxyAttr("Person")
+
+	tests := []struct {
+		name     string
+		key      []byte
+		val      []byte
+		target   int
+		expected []byte
+	}{
+		{"schema to 2103", x.SchemaKey(name2), schema(name2), schemaFormat2103,
+			schema(ns2 + "name")},
+		{"type to 2103", x.TypeKey(person2), typ(person2, name2), schemaFormat2103,
+			typ(ns2+"Person", ns2+"name")},
+		{"schema to 2011", x.SchemaKey(name0), schema(name0), schemaFormat2011,
+			schema("name")},
+		{"type to 2011", x.TypeKey(person0), typ(person0, name0), schemaFormat2011,
+			typ("Person", "name")},
+		{"galaxy schema to 2103", x.SchemaKey(name0), schema(name0), schemaFormat2103,
+			schema(galaxy + "name")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := downgradeSchema(tt.val, parse(tt.key), tt.target)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, out)
+		})
+	}
+
+	// The older format has no namespaces.
+	_, err := downgradeSchema(schema(name2), parse(x.SchemaKey(name2)), schemaFormat2011)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot convert name in namespace 0x2 to format 2011")
+	_, err = downgradeSchema(typ(person0, name2), parse(x.TypeKey(person0)), schemaFormat2011)
+	require.Error(t, err)
+	_, err = downgradeSchema(schema(name0), parse(x.SchemaKey(name0)), 2105)
+	require.Error(t, err)
+}
+
+func TestIndexRebuildHints(t *testing.T) {
+	in := &loadBackupInput{
+		preds: predicateSet{
+			x.GalaxyAttr("name"):       struct{}{},
+			x.NamespaceAttr(2, "name"): struct{}{},
+			x.GalaxyAttr("age"):        struct{}{},
+			x.GalaxyAttr("friend"):     struct{}{},
+		},
+		keepSchema: true,
+		version:    2105,
+	}
+	opts := MapOptions{IndexRebuildHints: map[string]string{"name": "lazy", "age": "Eager"}}
+	require.NoError(t, opts.validate())
+	p := newProcessor(newMapper(10, "", opts, 2))
+	buf := z.NewBuffer(1<<10, "TestIndexRebuildHints")
+	defer buf.Release()
+	for _, kv := range []*bpb.KV{schemaKV(t, x.GalaxyNamespace, "name"), schemaKV(t, 2, "name"),
+		schemaKV(t, x.GalaxyNamespace, "age"), schemaKV(t, x.GalaxyNamespace, "friend")} {
+		require.NoError(t, p.processKV(buf, in, kv))
+	}
+
+	hints := make(map[string]pb.SchemaUpdate_IndexRebuild)
+	require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+		var kv bpb.KV
+		require.NoError(t, kv.Unmarshal(mapEntry(slice).Data()))
+		var update pb.SchemaUpdate
+		require.NoError(t, update.Unmarshal(kv.Value))
+		hints[update.Predicate] = update.IndexRebuild
+		// The hint survives another round trip.
+		b, err := update.Marshal()
+		require.NoError(t, err)
+		require.Equal(t, kv.Value, b)
+		return nil
+	}))
+	require.Equal(t, map[string]pb.SchemaUpdate_IndexRebuild{
+		x.GalaxyAttr("name"):       pb.SchemaUpdate_LAZY,
+		x.NamespaceAttr(2, "name"): pb.SchemaUpdate_LAZY,
+		x.GalaxyAttr("age"):        pb.SchemaUpdate_EAGER,
+		x.GalaxyAttr("friend"):     pb.SchemaUpdate_UNSPECIFIED,
+	}, hints)
+
+	opts = MapOptions{IndexRebuildHints: map[string]string{"name": "later"}}
+	require.Error(t, opts.validate())
+}
+
+func TestIncludeExcludeTypes(t *testing.T) {
+	in := &loadBackupInput{
+		preds:      predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		keepSchema: true,
+		version:    2105,
+	}
+	// mapped returns the schema and type keys mapped with the options, in the order of the
+	// KVs.
+	mapped := func(opts MapOptions) []string {
+		require.NoError(t, opts.validate())
+		p := newProcessor(newMapper(10, "", opts, 2))
+		buf := z.NewBuffer(1<<10, "TestIncludeExcludeTypes")
+		defer buf.Release()
+		for _, kv := range []*bpb.KV{schemaKV(t, x.GalaxyNamespace, "name"),
+			typeKV(t, x.GalaxyNamespace, "Person"), typeKV(t, 2, "Person"),
+			typeKV(t, x.GalaxyNamespace, "Film"), typeKV(t, x.GalaxyNamespace, "Secret")} {
+			require.NoError(t, p.processKV(buf, in, kv))
+		}
+		var keys []string
+		require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+			pk, err := x.Parse(y.ParseKey(mapEntry(slice).Key()))
+			require.NoError(t, err)
+			keys = append(keys, fmt.Sprintf("%v:%s", pk.IsType(), pk.Attr))
+			return nil
+		}))
+		return keys
+	}
+	typ := func(ns uint64, name string) string {
+		return "true:" + x.NamespaceAttr(ns, name)
+	}
+	schema := "false:" + x.GalaxyAttr("name")
+
+	require.Equal(t, []string{schema, typ(x.GalaxyNamespace, "Person"), typ(2, "Person"),
+		typ(x.GalaxyNamespace, "Film"), typ(x.GalaxyNamespace, "Secret")},
+		mapped(MapOptions{}))
+	// The types are filtered in every namespace, and the schema of the predicates is kept.
+	require.Equal(t, []string{schema, typ(x.GalaxyNamespace, "Person"), typ(2, "Person")},
+		mapped(MapOptions{IncludeTypes: []string{"Person"}}))
+	require.Equal(t, []string{schema, typ(x.GalaxyNamespace, "Person"), typ(2, "Person"),
+		typ(x.GalaxyNamespace, "Film")},
+		mapped(MapOptions{ExcludeTypes: []string{"Secret"}}))
+
+	opts := MapOptions{IncludeTypes: []string{"Person"}, ExcludeTypes: []string{"Person"}}
+	require.Error(t, opts.validate())
+	opts = MapOptions{IncludeTypes: []string{""}}
+	require.Error(t, opts.validate())
+}
+
+func TestMapperInjectedFaults(t *testing.T) {
+	defer func() { restoreFaults = nil }()
+
+	var stream bytes.Buffer
+	for i := 0; i < 100; i++ {
+		appendKVList(t, &stream, schemaKV(t, x.GalaxyNamespace, "name"))
+	}
+	in := &loadBackupInput{
+		preds:      predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		keepSchema: true,
+	}
+
+	for _, point := range []faultPoint{faultProcess, faultMerge, faultWrite} {
+		t.Run(faultPointNames[point], func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "restore-map")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			restoreFaults = newFaultInjector().failAfter(point, 0)
+			opts := MapOptions{ProcessBufSize: 1 << 10, ProcessFlushSize: 1}
+			require.NoError(t, opts.validate())
+			m := newMapper(10, dir, opts, 4)
+			m.startPipeline(4)
+			defer m.closer.Signal()
+			// Map fails if the processors have already given up, so its error is not checked.
+			_ = m.Map(bytes.NewReader(stream.Bytes()), in)
+			require.ErrorIs(t, m.stopPipeline(), errInjected)
+		})
+	}
+
+	t.Run("read", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "restore-map")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "backup"), stream.Bytes(), 0600))
+
+		restoreFaults = newFaultInjector().failAfter(faultRead, 1)
+		br := readerFrom(x.NewFileHandler(&url.URL{Path: dir}), "backup")
+		require.NoError(t, br.err)
+		defer br.Close()
+		_, err = ioutil.ReadAll(br)
+		require.ErrorIs(t, err, errInjected)
+	})
+}
+
+func TestMaxOutputBytes(t *testing.T) {
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 1000; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}}
+
+	for _, limit := range []int64{0, 1 << 10, 1 << 30} {
+		dir, err := ioutil.TempDir("", "restore-map")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		opts := MapOptions{ProcessBufSize: 1 << 10, ProcessFlushSize: 1, MaxOutputBytes: limit}
+		require.NoError(t, opts.validate())
+		m := newMapper(10, dir, opts, 2)
+		m.startPipeline(2)
+		// Map fails if the pipeline has already given up, so its error is not checked.
+		_ = m.Map(bytes.NewReader(stream.Bytes()), in)
+		err = m.stopPipeline()
+		if limit == 1<<10 {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "MaxOutputBytes: 1.0 KiB (1024 bytes)")
+			m.closer.Signal()
+			continue
+		}
+		require.NoError(t, err)
+	}
+
+	opts := MapOptions{MaxOutputBytes: -1}
+	require.Error(t, opts.validate())
+}
+
+func TestCollectNamespaces(t *testing.T) {
+	var stream bytes.Buffer
+	in := &loadBackupInput{preds: predicateSet{}}
+	for _, ns := range []uint64{7, x.GalaxyNamespace, 3, 7, 1 << 40} {
+		appendKVList(t, &stream, nsEdgeKV(t, ns, "name", 1))
+		in.preds[x.NamespaceAttr(ns, "name")] = struct{}{}
+	}
+
+	for _, collect := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "restore-map")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		opts := MapOptions{CollectNamespaces: collect}
+		require.NoError(t, opts.validate())
+		m := newMapper(10, dir, opts, 2)
+		m.startPipeline(4)
+		require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+		require.NoError(t, m.stopPipeline())
+		require.Equal(t, uint64(1<<40), m.maxNs)
+		if collect {
+			require.Equal(t, []uint64{x.GalaxyNamespace, 3, 7, 1 << 40}, m.sortedNamespaces())
+		} else {
+			require.Nil(t, m.sortedNamespaces())
+		}
+	}
+}
+
+// edgeKV returns the KV for a posting list with the given uids, as it is stored in a backup.
+func edgeKV(t *testing.T, typ pb.BackupKey_KeyType, attr string, uid uint64,
+	uids ...uint64) *bpb.KV {
+	key, err := (&pb.BackupKey{Type: typ, Attr: attr, Uid: uid,
+		Namespace: x.GalaxyNamespace}).Marshal()
+	require.NoError(t, err)
+	val, err := (&pb.BackupPostingList{Uids: uids}).Marshal()
+	require.NoError(t, err)
+	return &bpb.KV{
+		Key:      key,
+		Value:    val,
+		UserMeta: []byte{posting.BitCompletePosting},
+		Version:  1,
+	}
+}
+
+func TestSkipReverseEdges(t *testing.T) {
+	in := &loadBackupInput{
+		preds: predicateSet{x.GalaxyAttr("friend"): struct{}{}},
+	}
+	forward := edgeKV(t, pb.BackupKey_DATA, "friend", 1, 2)
+	reverse := edgeKV(t, pb.BackupKey_REVERSE, "friend", 2, 1)
+
+	for _, skip := range []bool{false, true} {
+		p := newProcessor(newMapper(10, "", MapOptions{SkipReverseEdges: skip}, 2))
+		buf := z.NewBuffer(1<<10, "TestSkipReverseEdges")
+		require.NoError(t, p.processKV(buf, in, forward))
+		withForward := buf.LenNoPadding()
+		require.Greater(t, withForward, 0)
+		require.NoError(t, p.processKV(buf, in, reverse))
+		require.Equal(t, skip, buf.LenNoPadding() == withForward)
+		buf.Release()
+	}
+}
+
+func TestSkipBadKeys(t *testing.T) {
+	name := x.GalaxyAttr("name")
+	bad := nsEdgeKV(t, x.GalaxyNamespace, "name", 2)
+	bad.Key = []byte{0xff, 0xff, 0xff}
+	fixtures := []backupFixture{{
+		manifest: &Manifest{Type: "full", BackupNum: 1, ReadTs: 10, Path: "dgraph.1",
+			Compression: "snappy", Version: 2105, Groups: map[uint32][]string{1: {name}}},
+		kvs: []*bpb.KV{
+			nsEdgeKV(t, x.GalaxyNamespace, "name", 1),
+			bad,
+			nsEdgeKV(t, x.GalaxyNamespace, "name", 3),
+		},
+	}}
+
+	// The bad key is counted and sampled, and the keys after it are still mapped.
+	out := runDropChain(t, fixtures, 0, MapOptions{SkipBadKeys: true})
+	require.Equal(t, uint64(1), out.res.badKeys)
+	require.Len(t, out.res.badKeySamples, 1)
+	require.Contains(t, out.res.badKeySamples[0], hex.Dump(bad.Key))
+	require.Equal(t, map[string]struct{}{name + ":1": {}, name + ":3": {}}, out.mapped)
+}
+
+func TestMaxAllowedUid(t *testing.T) {
+	in := &loadBackupInput{
+		preds: predicateSet{
+			x.GalaxyAttr("name"):       struct{}{},
+			x.NamespaceAttr(2, "name"): struct{}{},
+		},
+		dropNs: map[uint64]struct{}{2: {}},
+	}
+	p := newProcessor(newMapper(10, "", MapOptions{MaxAllowedUid: 10}, 2))
+	buf := z.NewBuffer(1<<10, "TestMaxAllowedUid")
+	defer buf.Release()
+	require.NoError(t, p.processKV(buf, in, edgeKV(t, pb.BackupKey_DATA, "name", 10, 1)))
+
+	// The keys which are not restored are not checked.
+	require.NoError(t, p.processKV(buf, in, edgeKV(t, pb.BackupKey_DATA, "age", 11, 1)))
+	require.NoError(t, p.processKV(buf, in, nsEdgeKV(t, 2, "name", 11)))
+
+	err := p.processKV(buf, in, edgeKV(t, pb.BackupKey_DATA, "name", 11, 1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exceeds the max allowed uid")
+}
+
+type testMapLogger struct {
+	sync.Mutex
+	events map[string][]MapLogField
+}
+
+func (l *testMapLogger) Log(event string, fields []MapLogField) {
+	l.Lock()
+	defer l.Unlock()
+	l.events[event] = fields
+}
+
+func TestMapLogger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logger := &testMapLogger{events: make(map[string][]MapLogField)}
+	m := newMapper(10, dir, MapOptions{Logger: logger}, 2)
+	m.setRequestId("job-1")
+	buf := z.NewBuffer(1<<10, "TestMapLogger")
+	me := buf.SliceAllocate(2 + 3 + 1)
+	binary.BigEndian.PutUint16(me, 3)
+	copy(me[2:], "key")
+	require.NoError(t, m.writeToDisk(buf, 1))
+
+	fields := logger.events[MapEventFileCreated]
+	require.Contains(t, fields, MapLogField{"file", "job-1-000001.map"})
+	require.Contains(t, fields, MapLogField{"request_id", "job-1"})
+}
+
+func TestFindEmptyPreds(t *testing.T) {
+	set := func(attrs ...string) map[string]struct{} {
+		s := make(map[string]struct{})
+		for _, attr := range attrs {
+			s[attr] = struct{}{}
+		}
+		return s
+	}
+	expected := set(x.GalaxyAttr("name"), x.GalaxyAttr("age"), x.GalaxyAttr("email"),
+		x.NamespaceAttr(2, "name"))
+	seen := set(x.GalaxyAttr("name"))
+	dropNs := map[uint64]struct{}{2: {}}
+
+	require.Equal(t, []string{x.GalaxyAttr("age"), x.GalaxyAttr("email")},
+		findEmptyPreds(expected, seen, dropNs))
+	require.Empty(t, findEmptyPreds(expected, expected, nil))
+}
+
+func TestWriteMapFileToHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.True(t, isRemoteMapDir("s3://bucket/map"))
+	require.False(t, isRemoteMapDir("file:///tmp/map"))
+	require.False(t, isRemoteMapDir("/tmp/map"))
+
+	// Write the map file through a handler, the way it is written to an object storage.
+	m := newMapper(10, "", MapOptions{PerManifestSubdirs: true}, 2)
+	m.mapStore = x.NewFileHandler(&url.URL{Path: dir})
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "m3"), 0750))
+	buf := z.NewBuffer(1<<10, "TestWriteMapFileToHandler")
+	me := buf.SliceAllocate(2 + 3 + 1)
+	binary.BigEndian.PutUint16(me, 3)
+	copy(me[2:], "key")
+	require.NoError(t, m.writeToDisk(buf, 3))
+
+	header, itr, err := newMapIterator(filepath.Join(dir, "m3", "000001.map"))
+	require.NoError(t, err)
+	defer itr.Close()
+	require.Equal(t, mapFormatVersion, header.FormatVersion)
+	cbuf := z.NewBuffer(1<<10, "TestWriteMapFileToHandler")
+	defer cbuf.Release()
+	require.NoError(t, itr.Next(cbuf, nil))
+	var keys []string
+	require.NoError(t, cbuf.SliceIterate(func(slice []byte) error {
+		keys = append(keys, string(mapEntry(slice).Key()))
+		return nil
+	}))
+	require.Equal(t, []string{"key"}, keys)
+}
+
+func TestPartitionBufSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// partitions writes 100 entries of about 100 bytes, and returns the number of partition
+	// keys of the map file.
+	partitions := func(opts MapOptions, file string) int {
+		m := newMapper(10, filepath.Join(dir, file), opts, 2)
+		buf := z.NewBuffer(1<<20, "TestPartitionBufSize")
+		for i := 0; i < 100; i++ {
+			key := y.KeyWithTs([]byte(fmt.Sprintf("key%04d", i)), 1)
+			me := buf.SliceAllocate(2 + len(key) + 90)
+			binary.BigEndian.PutUint16(me, uint16(len(key)))
+			copy(me[2:], key)
+		}
+		require.NoError(t, m.writeToDisk(buf, 1))
+		header, itr, err := newMapIterator(filepath.Join(dir, file, "000001.map"))
+		require.NoError(t, err)
+		require.NoError(t, itr.Close())
+		return len(header.PartitionKeys)
+	}
+	require.Zero(t, partitions(MapOptions{}, "default"))
+	// A partition key every ~1 KiB.
+	n := partitions(MapOptions{PartitionBufSize: 1 << 10}, "small")
+	require.GreaterOrEqual(t, n, 9)
+	require.LessOrEqual(t, n, 11)
+
+	opts := MapOptions{}
+	require.NoError(t, opts.validate())
+	require.Equal(t, partitionBufSz, opts.PartitionBufSize)
+	require.Error(t, (&MapOptions{PartitionBufSize: -1}).validate())
+}
+
+func TestPerNamespaceSubdirs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var stream bytes.Buffer
+	in := &loadBackupInput{preds: predicateSet{}, groupId: 1, backupNum: 3}
+	for _, ns := range []uint64{x.GalaxyNamespace, 2, 5} {
+		in.preds[x.NamespaceAttr(ns, "name")] = struct{}{}
+		for uid := uint64(1); uid <= 10; uid++ {
+			appendKVList(t, &stream, nsEdgeKV(t, ns, "name", uid))
+		}
+	}
+	m := newMapper(10, dir, MapOptions{PerManifestSubdirs: true, PerNamespaceSubdirs: true}, 2)
+	m.startPipeline(2)
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.NoError(t, m.stopPipeline())
+
+	sets, err := namespaceMapFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, sets, 3)
+	for ns, files := range sets {
+		var uids []uint64
+		for _, file := range files {
+			require.Equal(t, filepath.Join(dir, "m3", namespaceDir(ns)), filepath.Dir(file))
+			_, itr, err := newMapIterator(file)
+			require.NoError(t, err)
+			cbuf := z.NewBuffer(1<<10, "TestPerNamespaceSubdirs")
+			require.NoError(t, itr.Next(cbuf, nil))
+			require.NoError(t, cbuf.SliceIterate(func(me []byte) error {
+				pk, err := x.Parse(y.ParseKey(mapEntry(me).Key()))
+				require.NoError(t, err)
+				require.Equal(t, x.NamespaceAttr(ns, "name"), pk.Attr)
+				uids = append(uids, pk.Uid)
+				return nil
+			}))
+			cbuf.Release()
+			require.NoError(t, itr.Close())
+		}
+		require.Len(t, uids, 10)
+	}
+
+	// The map files written without the option can't be grouped by namespace.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "000100.map"), nil, 0600))
+	_, err = namespaceMapFiles(dir)
+	require.Error(t, err)
+}
+
+func TestSplitLargeValues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The keys of long are long enough to make their KVs cross the threshold.
+	long := strings.Repeat("long", 50)
+	var stream bytes.Buffer
+	in := &loadBackupInput{
+		preds: predicateSet{
+			x.GalaxyAttr("name"): struct{}{},
+			x.GalaxyAttr(long):   struct{}{},
+		},
+		groupId:   1,
+		backupNum: 3,
+	}
+	for uid := uint64(1); uid <= 10; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid),
+			nsEdgeKV(t, x.GalaxyNamespace, long, uid))
+	}
+	opts := MapOptions{PerManifestSubdirs: true, SplitLargeValues: true,
+		LargeValueThreshold: 150}
+	m := newMapper(10, dir, opts, 2)
+	m.startPipeline(2)
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.NoError(t, m.stopPipeline())
+
+	regular, large, err := largeValueMapFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, regular, 1)
+	require.Len(t, large, 1)
+	require.Equal(t, filepath.Join(dir, largeValueDir, "m3"), filepath.Dir(large[0]))
+	attrs := func(file string) map[string]int {
+		counts := make(map[string]int)
+		_, itr, err := newMapIterator(file)
+		require.NoError(t, err)
+		cbuf := z.NewBuffer(1<<10, "TestSplitLargeValues")
+		require.NoError(t, itr.Next(cbuf, nil))
+		require.NoError(t, cbuf.SliceIterate(func(me []byte) error {
+			pk, err := x.Parse(y.ParseKey(mapEntry(me).Key()))
+			require.NoError(t, err)
+			counts[pk.Attr]++
+			return nil
+		}))
+		cbuf.Release()
+		require.NoError(t, itr.Close())
+		return counts
+	}
+	require.Equal(t, map[string]int{x.GalaxyAttr("name"): 10}, attrs(regular[0]))
+	require.Equal(t, map[string]int{x.GalaxyAttr(long): 10}, attrs(large[0]))
+	for _, info := range m.writtenFiles() {
+		require.Equal(t, strings.HasPrefix(info.Name, largeValueDir+"/"), info.LargeValues)
+	}
+
+	opts = MapOptions{LargeValueThreshold: -1}
+	require.Error(t, opts.validate())
+}
+
+func TestRestoreTsSets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var stream bytes.Buffer
+	in := &loadBackupInput{
+		preds:      predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		keepSchema: true,
+		groupId:    1,
+	}
+	appendKVList(t, &stream, schemaKV(t, x.GalaxyNamespace, "name"))
+	for uid := uint64(1); uid <= 10; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	m := newMapper(10, dir, MapOptions{RestoreTsSets: []uint64{5, 8}}, 2)
+	m.startPipeline(2)
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.NoError(t, m.stopPipeline())
+
+	sets, err := restoreTsMapFiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, map[uint64]string{
+		5: filepath.Join(dir, "ts5"),
+		8: filepath.Join(dir, "ts8"),
+	}, sets)
+	for ts, setDir := range sets {
+		files, _, err := mapFiles(setDir)
+		require.NoError(t, err)
+		var keys int
+		for _, file := range files {
+			_, itr, err := newMapIterator(file)
+			require.NoError(t, err)
+			cbuf := z.NewBuffer(1<<10, "TestRestoreTsSets")
+			require.NoError(t, itr.Next(cbuf, nil))
+			require.NoError(t, cbuf.SliceIterate(func(me []byte) error {
+				var kv bpb.KV
+				require.NoError(t, kv.Unmarshal(mapEntry(me).Data()))
+				require.Equal(t, ts, kv.Version)
+				keys++
+				return nil
+			}))
+			cbuf.Release()
+			require.NoError(t, itr.Close())
+		}
+		// The schema key is written to every set as well.
+		require.Equal(t, 11, keys)
+	}
+	for _, info := range m.writtenFiles() {
+		require.Equal(t, restoreTsDir(info.RestoreTs), strings.Split(info.Name, "/")[0])
+	}
+
+	for _, opts := range []MapOptions{
+		{RestoreTsSets: []uint64{0}},
+		{RestoreTsSets: []uint64{5, 5}},
+		{RestoreTsSets: []uint64{5}, PreserveVersions: true},
+	} {
+		require.Error(t, opts.validate())
+	}
+}
+
+func TestCheckSchemaNamespace(t *testing.T) {
+	p := newProcessor(newMapper(10, "", MapOptions{}, 2))
+	check := func(keyAttr, pred string) {
+		val, err := (&pb.SchemaUpdate{Predicate: pred}).Marshal()
+		require.NoError(t, err)
+		parsedKey, err := x.Parse(x.SchemaKey(keyAttr))
+		require.NoError(t, err)
+		require.NoError(t, p.checkSchemaNamespace(parsedKey, val))
+	}
+	check(x.GalaxyAttr("name"), x.GalaxyAttr("name"))
+	check(x.NamespaceAttr(2, "age"), x.GalaxyAttr("age"))
+	check(x.NamespaceAttr(2, "email"), "email")
+
+	data := map[string]struct{}{
+		x.GalaxyAttr("name"):      {},
+		x.NamespaceAttr(2, "age"): {},
+	}
+	require.Equal(t, []string{
+		"data of age in namespace 0x2 has no schema in its namespace",
+		"schema key of age in namespace 0x2 holds age in namespace 0x0",
+		`schema key of email in namespace 0x2 holds "email" without namespace`,
+	}, findSchemaNsMismatches(p.nsMismatches, data, p.schemas))
+}
+
+func TestTopRollupCosts(t *testing.T) {
+	p := &processor{}
+	p.addRollupCost("name", 3*time.Second)
+	p.addRollupCost("age", time.Second)
+	p.addRollupCost("name", time.Second)
+	p.addRollupCost("email", 2*time.Second)
+
+	top := topRollupCosts(p.rollups, 2)
+	require.Equal(t, []predRollupCost{
+		{attr: "name", rollupCost: rollupCost{count: 2, time: 4 * time.Second}},
+		{attr: "email", rollupCost: rollupCost{count: 1, time: 2 * time.Second}},
+	}, top)
+	require.Equal(t, "name: 4s in 2 rollups", top[0].String())
+	require.Len(t, topRollupCosts(p.rollups, maxRollupCostPreds), 3)
+}
+
+func TestMapFileSizes(t *testing.T) {
+	files := func(sizes ...int64) []MapFileInfo {
+		var infos []MapFileInfo
+		for _, sz := range sizes {
+			infos = append(infos, MapFileInfo{Size: sz})
+		}
+		return infos
+	}
+	require.Equal(t, mapFileSizes{}, newMapFileSizes(nil))
+	require.Equal(t, mapFileSizes{count: 3, total: 1300, min: 100, median: 200, max: 1000,
+		skew: 5}, newMapFileSizes(files(1000, 100, 200)))
+	require.Equal(t, mapFileSizes{count: 4, total: 1000, min: 100, median: 250, max: 400,
+		skew: 1.6}, newMapFileSizes(files(400, 100, 300, 200)))
+	require.Equal(t, "2 map files of 2.0 KiB, min: 1.0 KiB, median: 1.0 KiB, max: 1.0 KiB,"+
+		" skew: 1.00", newMapFileSizes(files(1024, 1024)).String())
+}
+
+func TestWriteMapManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	m := newMapper(10, dir, MapOptions{VerifyChecksums: true}, 2)
+	m.setRequestId("job-1")
+	m.groupId = 2
+	for _, key := range []string{"a", "b"} {
+		buf := z.NewBuffer(1<<10, "TestWriteMapManifest")
+		me := buf.SliceAllocate(2 + len(key) + 1)
+		binary.BigEndian.PutUint16(me, uint16(len(key)))
+		copy(me[2:], key)
+		require.NoError(t, m.writeToDisk(buf, 1))
+	}
+	require.NoError(t, m.writeMapManifest())
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "job-1-"+mapManifestName))
+	require.NoError(t, err)
+	var manifest mapManifest
+	require.NoError(t, json.Unmarshal(b, &manifest))
+	require.Len(t, manifest.Files, 2)
+	for i, info := range manifest.Files {
+		require.Equal(t, fmt.Sprintf("job-1-%06d.map", i+1), info.Name)
+		require.Equal(t, uint32(2), info.GroupId)
+		data, err := ioutil.ReadFile(filepath.Join(dir, info.Name))
+		require.NoError(t, err)
+		sum := sha256.Sum256(data)
+		require.Equal(t, hex.EncodeToString(sum[:]), info.Checksum)
+		require.Equal(t, int64(len(data)), info.Size)
+	}
+}
+
+func TestRunStreamMapper(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var stream bytes.Buffer
+	appendKVList(t, &stream, schemaKV(t, x.GalaxyNamespace, "name"),
+		edgeKV(t, pb.BackupKey_DATA, "name", 5, 6), edgeKV(t, pb.BackupKey_DATA, "age", 7, 8))
+	in := StreamMapInput{
+		Preds:      []string{x.GalaxyAttr("name")},
+		RestoreTs:  10,
+		KeepSchema: true,
+	}
+	res, err := RunStreamMapper(&stream, dir, in, MapOptions{})
+	require.NoError(t, err)
+	// The uids of the skipped predicates don't bump the uid lease.
+	require.Equal(t, uint64(7), res.maxUid)
+	files, _, err := mapFiles(dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	_, err = RunStreamMapper(&stream, dir, StreamMapInput{}, MapOptions{})
+	require.Error(t, err)
+}
+
+func TestBufferPool(t *testing.T) {
+	var allocs int
+	bp := newBufferPool(1, func() (*z.Buffer, error) {
+		allocs++
+		return z.NewBuffer(1<<10, "TestBufferPool"), nil
+	})
+	get := func() *z.Buffer {
+		buf, err := bp.get()
+		require.NoError(t, err)
+		return buf
+	}
+	first := get()
+	first.SliceAllocate(10)
+	second := get()
+	require.Equal(t, 2, allocs)
+
+	// Only one idle buffer is kept, and it is reset.
+	bp.put(first)
+	bp.put(second)
+	buf := get()
+	require.Equal(t, first, buf)
+	require.True(t, buf.IsEmpty())
+	require.Equal(t, 2, allocs)
+
+	bp.put(buf)
+	bp.close()
+	require.Empty(t, bp.bufs)
+	bp.put(get())
+	require.Equal(t, 3, allocs)
+	require.Empty(t, bp.bufs)
+}
+
+// mapFileContents returns the decompressed contents of a map file with the given keys.
+func mapFileContents(t testing.TB, keys ...string) []byte {
+	var b bytes.Buffer
+	header, err := (&pb.MapHeader{FormatVersion: mapFormatVersion}).Marshal()
+	require.NoError(t, err)
+	require.NoError(t, binary.Write(&b, binary.BigEndian, uint32(len(header))))
+	b.Write(header)
+	for _, key := range keys {
+		me := make([]byte, 2+len(key)+4)
+		binary.BigEndian.PutUint16(me, uint16(len(key)))
+		copy(me[2:], key)
+		var sz [binary.MaxVarintLen64]byte
+		b.Write(sz[:binary.PutUvarint(sz[:], uint64(len(me)))])
+		b.Write(me)
+	}
+	return b.Bytes()
+}
+
+// readMapContents reads the entries from the decompressed contents of a map file.
+func readMapContents(data []byte) (int, error) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	if _, err := readMapHeader("test.map", reader); err != nil {
+		return 0, err
+	}
+	itr := &mapIterator{name: "test.map", reader: reader}
+	cbuf := z.NewBuffer(1<<10, "readMapContents")
+	defer cbuf.Release()
+	if err := itr.Next(cbuf, nil); err != nil {
+		return 0, err
+	}
+	var n int
+	err := cbuf.SliceIterate(func([]byte) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+func TestMapReaderCorruptEntries(t *testing.T) {
+	data := mapFileContents(t, "a", "bb", "c")
+	n, err := readMapContents(data)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	// An entry which claims to be larger than the rest of the file.
+	huge := append([]byte{}, data...)
+	last := len(huge) - (2 + 1 + 4) - 1
+	huge[last] = 0x7f
+	_, err = readMapContents(huge)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "map file test.map has a truncated entry of size: 127")
+
+	// A size which is not a valid varint.
+	bad := append(append([]byte{}, data[:last]...), bytes.Repeat([]byte{0xff}, 11)...)
+	_, err = readMapContents(bad)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid entry size at offset")
+
+	// A key which is longer than its entry.
+	longKey := append([]byte{}, data...)
+	binary.BigEndian.PutUint16(longKey[last+1:], 100)
+	_, err = readMapContents(longKey)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "with a key of size: 100")
+
+	// Truncating or corrupting the file anywhere must never panic.
+	for i := 0; i < len(data); i++ {
+		readMapContents(data[:i])
+		for _, b := range []byte{0x00, 0x7f, 0x80, 0xff} {
+			corrupt := append([]byte{}, data...)
+			corrupt[i] = b
+			readMapContents(corrupt)
+		}
+	}
+}
+
+// FuzzMapReader reads the decompressed contents of a map file. It must return an error rather
+// than panic on any input. Run it with: go test -run NONE -fuzz FuzzMapReader ./worker
+func FuzzMapReader(f *testing.F) {
+	f.Add(mapFileContents(f, "a", "bb", "c"))
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		readMapContents(data)
+	})
+}
+
+func TestVerifyStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tmpDir := x.WorkerConfig.TmpDir
+	x.WorkerConfig.TmpDir = dir
+	defer func() { x.WorkerConfig.TmpDir = tmpDir }()
+
+	var stream bytes.Buffer
+	appendKVList(t, &stream, schemaKV(t, x.GalaxyNamespace, "name"),
+		edgeKV(t, pb.BackupKey_DATA, "name", 5, 6), edgeKV(t, pb.BackupKey_DATA, "name", 7, 8))
+	in := StreamMapInput{
+		Preds:      []string{x.GalaxyAttr("name")},
+		RestoreTs:  10,
+		KeepSchema: true,
+	}
+	mapDir := filepath.Join(dir, "map")
+	_, err = RunStreamMapper(&stream, mapDir, in, MapOptions{})
+	require.NoError(t, err)
+
+	res, err := verifyStore(mapDir)
+	require.NoError(t, err)
+	require.Equal(t, &VerifyStoreResult{WrittenKeys: 3, StoredKeys: 3}, res)
+}
+
+func TestCompressionOverrides(t *testing.T) {
+	opts := MapOptions{CompressionOverrides: map[uint32]string{1: "snappy", 2: "gzip"}}
+	require.NoError(t, opts.validate())
+
+	opts = MapOptions{CompressionOverrides: map[uint32]string{3: "zstd"}}
+	err := opts.validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown compression: "zstd" for group: 3`)
+}
+
+func TestDescribeEmptyManifest(t *testing.T) {
+	msg, suspicious := describeEmptyManifest(&Manifest{Type: "incremental", ReadTs: 10})
+	require.False(t, suspicious)
+	require.Contains(t, msg, "no writes since the previous backup")
+
+	drop := &pb.DropOperation{DropOp: pb.DropOperation_ALL}
+	msg, suspicious = describeEmptyManifest(&Manifest{Type: "incremental", ReadTs: 10,
+		DropOperations: []*pb.DropOperation{drop}})
+	require.False(t, suspicious)
+	require.Contains(t, msg, "only the drop operations: [ALL]")
+
+	_, suspicious = describeEmptyManifest(&Manifest{Type: "full", ReadTs: 10})
+	require.True(t, suspicious)
+	_, suspicious = describeEmptyManifest(&Manifest{Type: "incremental"})
+	require.True(t, suspicious)
+}
+
+func TestMaxMapDuration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var stream bytes.Buffer
+	appendKVList(t, &stream, schemaKV(t, x.GalaxyNamespace, "name"))
+	in := StreamMapInput{
+		Preds:      []string{x.GalaxyAttr("name")},
+		RestoreTs:  10,
+		KeepSchema: true,
+	}
+	opts := MapOptions{MaxMapDuration: time.Nanosecond, CleanupOnError: true}
+	_, err = RunStreamMapper(&stream, dir, in, opts)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrMapTimeout))
+	files, _, err := mapFiles(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+// cancelSink cancels the map phase once it receives the first batch.
+type cancelSink struct {
+	cancel context.CancelFunc
+}
+
+func (s cancelSink) Consume(batch *MapBatch) error {
+	batch.Release()
+	s.cancel()
+	return nil
+}
+
+func TestRunMapperContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	backupDir, mapDir := filepath.Join(dir, "backup"), filepath.Join(dir, "map")
+
+	manifest := &Manifest{Type: "full", BackupNum: 1, ReadTs: 5, Path: "full", Version: 2105,
+		Compression: "snappy", Groups: map[uint32][]string{1: {x.GalaxyAttr("name")}}}
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 100; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	var comp bytes.Buffer
+	w := snappy.NewBufferedWriter(&comp)
+	_, err = w.Write(stream.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	file := filepath.Join(backupDir, manifest.Path, backupName(manifest.ValidReadTs(), 1))
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0750))
+	require.NoError(t, ioutil.WriteFile(file, comp.Bytes(), 0600))
+
+	defer func(get func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error)) {
+		getRestoreManifests = get
+	}(getRestoreManifests)
+	getRestoreManifests = func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error) {
+		return []*Manifest{manifest}, nil
+	}
+	req := &pb.RestoreRequest{Location: backupDir, RestoreTs: 10, GroupId: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = RunMapperContext(ctx, req, mapDir, MapOptions{})
+	require.True(t, errors.Is(err, context.Canceled))
+
+	before := runtime.NumGoroutine()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	opts := MapOptions{Sink: cancelSink{cancel: cancel}, CleanupOnError: true}
+	_, err = RunMapperContext(ctx, req, mapDir, opts)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+	files, _, err := mapFiles(mapDir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+	// The goroutines of the mapper wind down once it returns.
+	require.Eventually(t, func() bool {
+		return runtime.NumGoroutine() <= before
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The map phase is not affected without a cancellation.
+	_, err = RunMapperContext(context.Background(), req, mapDir, MapOptions{})
+	require.NoError(t, err)
+}
+
+func TestDeterministicMap(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	backupDir := filepath.Join(dir, "backup")
+
+	manifest := &Manifest{Type: "full", BackupNum: 1, ReadTs: 5, Path: "full", Version: 2105,
+		Compression: "snappy", Groups: map[uint32][]string{1: {x.GalaxyAttr("name"),
+			x.GalaxyAttr("age")}}}
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 2000; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid),
+			nsEdgeKV(t, x.GalaxyNamespace, "age", uid))
+	}
+	var comp bytes.Buffer
+	w := snappy.NewBufferedWriter(&comp)
+	_, err = w.Write(stream.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	file := filepath.Join(backupDir, manifest.Path, backupName(manifest.ValidReadTs(), 1))
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0750))
+	require.NoError(t, ioutil.WriteFile(file, comp.Bytes(), 0600))
+
+	defer func(get func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error)) {
+		getRestoreManifests = get
+	}(getRestoreManifests)
+	getRestoreManifests = func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error) {
+		return []*Manifest{manifest}, nil
+	}
+	req := &pb.RestoreRequest{Location: backupDir, RestoreTs: 10, GroupId: 1}
+
+	// run maps the backup and returns the contents of the map files, by their relative path.
+	run := func(name string) map[string][]byte {
+		mapDir := filepath.Join(dir, name)
+		opts := MapOptions{Deterministic: true, ProcessBufSize: 1 << 10, ProcessFlushSize: 1}
+		_, err := RunMapper(req, mapDir, opts)
+		require.NoError(t, err)
+		files, _, err := mapFiles(mapDir)
+		require.NoError(t, err)
+		out := make(map[string][]byte)
+		for _, file := range files {
+			rel, err := filepath.Rel(mapDir, file)
+			require.NoError(t, err)
+			out[rel], err = ioutil.ReadFile(file)
+			require.NoError(t, err)
+		}
+		return out
+	}
+	first := run("map-1")
+	require.NotEmpty(t, first)
+	require.Equal(t, first, run("map-2"))
+
+	for _, opts := range []MapOptions{
+		{Deterministic: true, FlushInterval: time.Second},
+		{Deterministic: true, PriorityNamespaces: []uint64{1}},
+		{Deterministic: true, MergeConcurrency: 2},
+	} {
+		require.Error(t, opts.validate())
+	}
+	require.Equal(t, 1, mapGoroutines(MapOptions{Deterministic: true}))
+}
+
+func TestMergeAllGroupsSchema(t *testing.T) {
+	m := newMapper(10, "", MapOptions{MergeAllGroups: true}, 1)
+	name := x.ParsedKey{Attr: x.GalaxyAttr("name")}
+	key := x.SchemaKey(name.Attr)
+
+	require.False(t, m.mapSchemaKeyOnce(name, key, []byte("string"), 1))
+	// The replica of the schema stored in the backup of another group is skipped.
+	require.True(t, m.mapSchemaKeyOnce(name, key, []byte("string"), 2))
+	require.Empty(t, m.schemaCollisions)
+	// A different schema is skipped as well, but reported.
+	require.True(t, m.mapSchemaKeyOnce(name, key, []byte("int"), 3))
+	require.Len(t, m.schemaCollisions, 1)
+	require.Contains(t, m.schemaCollisions[0], "differs between groups 1 and 3")
+}
+
+func TestCheckGroupExists(t *testing.T) {
+	manifests := []*Manifest{
+		{BackupNum: 2, Groups: map[uint32][]string{1: nil, 3: nil}},
+		{BackupNum: 1, Groups: map[uint32][]string{1: nil, 2: nil}},
+		{BackupNum: 3},
+	}
+	for _, gid := range []uint32{1, 2, 3} {
+		require.NoError(t, checkGroupExists(manifests, gid))
+	}
+	err := checkGroupExists(manifests, 4)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "group 4 is not in any of the 3 manifests")
+	require.Contains(t, err.Error(), "[1 2 3]")
+	require.NoError(t, checkGroupExists(nil, 4))
+}
+
+func TestFindGroupConflicts(t *testing.T) {
+	manifest := &Manifest{Groups: map[uint32][]string{
+		1: {x.GalaxyAttr("name"), x.GalaxyAttr("age")},
+		2: {x.GalaxyAttr("friend")},
+	}}
+	require.Empty(t, findGroupConflicts(manifest))
+
+	manifest.Groups[3] = []string{x.GalaxyAttr("name")}
+	require.Equal(t, []string{"name in namespace 0x0 belongs to groups [1 3]"},
+		findGroupConflicts(manifest))
+}
+
+// nsEdgeKV returns the KV for a posting list of the predicate in the namespace, as it is stored
+// in a backup.
+func nsEdgeKV(t *testing.T, ns uint64, attr string, uid uint64) *bpb.KV {
+	kv := edgeKV(t, pb.BackupKey_DATA, attr, uid, uid+100)
+	key, err := (&pb.BackupKey{Type: pb.BackupKey_DATA, Attr: attr, Uid: uid,
+		Namespace: ns}).Marshal()
+	require.NoError(t, err)
+	kv.Key = key
+	return kv
+}
+
+// backupFixture is a crafted backup of group 1, along with the KVs of its backup file.
+type backupFixture struct {
+	manifest *Manifest
+	kvs      []*bpb.KV
+	// missing leaves the backup file out, as if it was deleted from the storage.
+	missing bool
+}
+
+// dropChainResult is the outcome of running the map phase over a chain of backup fixtures.
+type dropChainResult struct {
+	res *mapResult
+	// mapped holds the <attr>:<uid> of the posting lists found in the map files.
+	mapped map[string]struct{}
+	banned []uint64
+	// summary is the summary written with MapOptions.WriteSummary.
+	summary *mapSummary
+	// schema holds the predicates of the schema keys found in the map files.
+	schema map[string]struct{}
+	// files are the names of the map files.
+	files []string
+}
+
+// writeBackupFixtures writes the backup files of the fixtures under backupDir, and returns
+// their manifests.
+func writeBackupFixtures(t *testing.T, backupDir string, fixtures []backupFixture) []*Manifest {
+	var manifests []*Manifest
+	for _, f := range fixtures {
+		manifests = append(manifests, f.manifest)
+		if len(f.manifest.Groups) == 0 || f.missing {
+			continue
+		}
+		var stream bytes.Buffer
+		appendKVList(t, &stream, f.kvs...)
+		var comp bytes.Buffer
+		w := snappy.NewBufferedWriter(&comp)
+		_, err := w.Write(stream.Bytes())
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		file := filepath.Join(backupDir, f.manifest.Path,
+			backupName(f.manifest.ValidReadTs(), 1))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0750))
+		require.NoError(t, ioutil.WriteFile(file, comp.Bytes(), 0600))
+	}
+	return manifests
+}
+
+// runDropChain runs RunMapper over the fixtures, which are ordered from the latest to the
+// oldest, as returned by getRestoreManifests.
+func runDropChain(t *testing.T, fixtures []backupFixture, incrementalFrom uint64,
+	opts MapOptions) dropChainResult {
+	req := &pb.RestoreRequest{IncrementalFrom: incrementalFrom}
+	return mapFixtures(t, fixtures, req, func(req *pb.RestoreRequest,
+		mapDir string) (*mapResult, error) {
+		return RunMapper(req, mapDir, opts)
+	})
+}
+
+// runRestoreRequest maps the fixtures the way a group maps the restore request proposed to
+// it: the request goes through the raft log, and the map options are taken from it.
+func runRestoreRequest(t *testing.T, fixtures []backupFixture,
+	req *pb.RestoreRequest) dropChainResult {
+	data, err := req.Marshal()
+	require.NoError(t, err)
+	var proposed pb.RestoreRequest
+	require.NoError(t, proposed.Unmarshal(data))
+	return mapFixtures(t, fixtures, &proposed, runRequestMapper)
+}
+
+// mapFixtures writes the fixtures to a backup location, and maps them with run. The location,
+// the restoreTs and the group of req are set to the ones of the fixtures.
+func mapFixtures(t *testing.T, fixtures []backupFixture, req *pb.RestoreRequest,
+	run func(*pb.RestoreRequest, string) (*mapResult, error)) dropChainResult {
+	dir, err := ioutil.TempDir("", "restore-drops")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	backupDir, mapDir := filepath.Join(dir, "backup"), filepath.Join(dir, "map")
+	manifests := writeBackupFixtures(t, backupDir, fixtures)
+
+	var out dropChainResult
+	defer func(get func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error),
+		ban func(uint64) error) {
+		getRestoreManifests, banNamespace = get, ban
+	}(getRestoreManifests, banNamespace)
+	getRestoreManifests = func(x.UriHandler, *url.URL, *pb.RestoreRequest) ([]*Manifest, error) {
+		return manifests, nil
+	}
+	banNamespace = func(ns uint64) error {
+		out.banned = append(out.banned, ns)
+		return nil
+	}
+
+	req.Location, req.RestoreTs, req.GroupId = backupDir, 1000, 1
+	out.res, err = run(req, mapDir)
+	require.NoError(t, err)
+	if b, err := ioutil.ReadFile(filepath.Join(mapDir, mapSummaryName)); err == nil {
+		out.summary = &mapSummary{}
+		require.NoError(t, json.Unmarshal(b, out.summary))
+	}
+
+	out.mapped, out.schema = make(map[string]struct{}), make(map[string]struct{})
+	files, _, err := mapFiles(mapDir)
+	require.NoError(t, err)
+	for _, file := range files {
+		out.files = append(out.files, filepath.Base(file))
+		_, itr, err := newMapIterator(file)
+		require.NoError(t, err)
+		cbuf := z.NewBuffer(1<<10, "runDropChain")
+		require.NoError(t, itr.Next(cbuf, nil))
+		require.NoError(t, cbuf.SliceIterate(func(me []byte) error {
+			pk, err := x.Parse(y.ParseKey(mapEntry(me).Key()))
+			require.NoError(t, err)
+			switch {
+			case pk.IsData():
+				out.mapped[fmt.Sprintf("%s:%d", pk.Attr, pk.Uid)] = struct{}{}
+			case pk.IsSchema():
+				out.schema[pk.Attr] = struct{}{}
+			}
+			return nil
+		}))
+		cbuf.Release()
+		require.NoError(t, itr.Close())
+	}
+	return out
+}
+
+func TestRunMapperDropChain(t *testing.T) {
+	name, age := x.GalaxyAttr("name"), x.GalaxyAttr("age")
+	name2 := x.NamespaceAttr(2, "name")
+	// backup returns the fixture of a backup holding a posting list for uid in name and age,
+	// in the galaxy namespace and in namespace 2.
+	backup := func(num, uid uint64, drops ...*pb.DropOperation) backupFixture {
+		typ := "incremental"
+		if num == 1 {
+			typ = "full"
+		}
+		return backupFixture{
+			manifest: &Manifest{
+				Type:           typ,
+				BackupNum:      num,
+				ReadTs:         num * 10,
+				Path:           fmt.Sprintf("dgraph.%d", num),
+				Compression:    "snappy",
+				Version:        2105,
+				Groups:         map[uint32][]string{1: {name, age, name2}},
+				DropOperations: drops,
+			},
+			kvs: []*bpb.KV{
+				nsEdgeKV(t, x.GalaxyNamespace, "name", uid),
+				nsEdgeKV(t, x.GalaxyNamespace, "age", uid),
+				nsEdgeKV(t, 2, "name", uid),
+			},
+		}
+	}
+	drop := func(op pb.DropOperation_DropOp, val string) *pb.DropOperation {
+		return &pb.DropOperation{DropOp: op, DropValue: val}
+	}
+	keys := func(keys ...string) map[string]struct{} {
+		res := make(map[string]struct{})
+		for _, k := range keys {
+			res[k] = struct{}{}
+		}
+		return res
+	}
+
+	t.Run("attr and data", func(t *testing.T) {
+		// The drops of a backup only apply to the older backups.
+		out := runDropChain(t, []backupFixture{
+			backup(3, 3),
+			backup(2, 2, drop(pb.DropOperation_ATTR, age), drop(pb.DropOperation_DATA, "2")),
+			backup(1, 1),
+		}, 0, MapOptions{})
+		require.False(t, out.res.shouldDropAll)
+		require.Equal(t, map[string]struct{}{age: {}}, out.res.dropAttr)
+		require.Equal(t, map[uint64]struct{}{2: {}}, out.res.dropNs)
+		require.Equal(t, keys(name+":1", name+":2", name+":3", age+":2", age+":3",
+			name2+":2", name2+":3"), out.mapped)
+		require.Empty(t, out.banned)
+	})
+
+	t.Run("drop all", func(t *testing.T) {
+		out := runDropChain(t, []backupFixture{
+			backup(3, 3),
+			backup(2, 2, drop(pb.DropOperation_ALL, "")),
+			backup(1, 1, drop(pb.DropOperation_NS, "2")),
+		}, 0, MapOptions{})
+		require.True(t, out.res.shouldDropAll)
+		require.Equal(t, keys(name+":2", name+":3", age+":2", age+":3", name2+":2",
+			name2+":3"), out.mapped)
+		// The backups older than the drop all are not visited at all.
+		require.Empty(t, out.banned)
+	})
+
+	t.Run("drop data of all namespaces", func(t *testing.T) {
+		out := runDropChain(t, []backupFixture{
+			backup(2, 2, drop(pb.DropOperation_DATA, "")),
+			backup(1, 1),
+		}, 0, MapOptions{})
+		require.True(t, out.res.shouldDropAll)
+		require.Empty(t, out.res.dropNs)
+		require.Equal(t, keys(name+":2", age+":2", name2+":2"), out.mapped)
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		// A dropped namespace is banned in the store, and its data is still mapped.
+		out := runDropChain(t, []backupFixture{
+			backup(2, 2, drop(pb.DropOperation_NS, "2"), drop(pb.DropOperation_NS, "0x5")),
+			backup(1, 1),
+		}, 0, MapOptions{})
+		require.False(t, out.res.shouldDropAll)
+		require.Equal(t, []uint64{2, 5}, out.banned)
+		require.Equal(t, uint64(5), out.res.maxNs)
+		require.Equal(t, keys(name+":1", name+":2", age+":1", age+":2", name2+":1",
+			name2+":2"), out.mapped)
+	})
+
+	t.Run("ignore drops", func(t *testing.T) {
+		// All the backups are mapped, including the predicates missing from the latest one.
+		latest := backup(3, 3, drop(pb.DropOperation_ATTR, age))
+		latest.manifest.Groups = map[uint32][]string{1: {name, name2}}
+		latest.kvs = latest.kvs[:1]
+		out := runDropChain(t, []backupFixture{
+			latest,
+			backup(2, 2, drop(pb.DropOperation_ALL, ""), drop(pb.DropOperation_DATA, "2")),
+			backup(1, 1, drop(pb.DropOperation_NS, "2")),
+		}, 0, MapOptions{IgnoreDropOperations: true})
+		require.False(t, out.res.shouldDropAll)
+		require.Empty(t, out.res.dropAttr)
+		require.Empty(t, out.res.dropNs)
+		require.Empty(t, out.banned)
+		require.Equal(t, keys(name+":1", name+":2", name+":3", age+":1", age+":2",
+			name2+":1", name2+":2"), out.mapped)
+	})
+
+	t.Run("manifest without data", func(t *testing.T) {
+		empty := backup(2, 2, drop(pb.DropOperation_ATTR, name))
+		empty.manifest.Groups = nil
+		out := runDropChain(t, []backupFixture{backup(3, 3), empty, backup(1, 1)}, 0,
+			MapOptions{})
+		require.Equal(t, map[string]struct{}{name: {}}, out.res.dropAttr)
+		require.Equal(t, keys(name+":3", age+":1", age+":3", name2+":1", name2+":3"),
+			out.mapped)
+	})
+
+	t.Run("incremental from", func(t *testing.T) {
+		// The backups before IncrementalFrom are not visited, so their drops are not applied.
+		out := runDropChain(t, []backupFixture{
+			backup(3, 3),
+			backup(2, 2, drop(pb.DropOperation_ALL, "")),
+			backup(1, 1),
+		}, 3, MapOptions{})
+		require.False(t, out.res.shouldDropAll)
+		require.Equal(t, keys(name+":3", age+":3", name2+":3"), out.mapped)
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		missing := backup(2, 2, drop(pb.DropOperation_ATTR, age))
+		missing.missing = true
+		out := runDropChain(t, []backupFixture{backup(3, 3), missing, backup(1, 1)}, 0,
+			MapOptions{SkipUnreadableFiles: true})
+		require.Len(t, out.res.unreadableFiles, 1)
+		require.Contains(t, out.res.unreadableFiles[0], filepath.Join("dgraph.2",
+			backupName(20, 1)))
+		// The drops of the manifest are still applied.
+		require.Equal(t, keys(name+":1", name+":3", age+":3", name2+":1", name2+":3"),
+			out.mapped)
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		out := runDropChain(t, []backupFixture{
+			backup(3, 3),
+			backup(2, 2, drop(pb.DropOperation_ATTR, age), drop(pb.DropOperation_DATA, "2")),
+			backup(1, 1),
+		}, 2, MapOptions{WriteSummary: true, MergeConcurrency: 1, WriteConcurrency: 1})
+		s := out.summary
+		require.Equal(t, x.Version(), s.Version)
+		require.Equal(t, uint32(1), s.Request.GroupId)
+		require.Equal(t, uint64(1000), s.Request.RestoreTs)
+		require.Equal(t, uint64(2), s.Request.IncrementalFrom)
+		require.False(t, s.Request.Encrypted)
+		// The backups before IncrementalFrom are not processed.
+		require.Len(t, s.Manifests, 2)
+		for i, m := range s.Manifests {
+			require.Equal(t, uint64(3-i), m.BackupNum)
+			require.Equal(t, fmt.Sprintf("dgraph.%d", 3-i), m.Path)
+			require.Equal(t, uint64(30-10*i), m.ReadTs)
+		}
+		require.Equal(t, out.res.maxUid, s.MaxUid)
+		require.Equal(t, mapSummaryDrops{Attrs: []string{age}, Namespaces: []uint64{2}}, s.Drops)
+		require.Greater(t, s.MapFiles, 0)
+		require.Greater(t, s.MapBytes, int64(0))
+		require.Equal(t, 1, s.Concurrency.Mergers)
+		require.Equal(t, 1, s.Concurrency.Writers)
+		require.Equal(t, mapGoroutines(MapOptions{}), s.Concurrency.Processors)
+	})
+
+	t.Run("manifest window", func(t *testing.T) {
+		// versioned sets the versions of the KVs of a backup to the ones committed right before
+		// its read timestamp.
+		versioned := func(f backupFixture) backupFixture {
+			for _, kv := range f.kvs {
+				kv.Version = f.manifest.ReadTs - 1
+			}
+			return f
+		}
+		b3 := versioned(backup(3, 3))
+		// An older version of a posting list, in the window of the first backup.
+		old := nsEdgeKV(t, x.GalaxyNamespace, "age", 1)
+		old.Version = 5
+		b3.kvs = append(b3.kvs, old)
+		// The drops of the backups after the window are not applied.
+		out := runDropChain(t, []backupFixture{
+			versioned(backup(4, 4, drop(pb.DropOperation_ALL, ""))),
+			b3,
+			versioned(backup(2, 2, drop(pb.DropOperation_ATTR, age))),
+			versioned(backup(1, 1)),
+		}, 0, MapOptions{WindowFrom: 2, WindowTo: 3})
+		require.False(t, out.res.shouldDropAll)
+		require.Equal(t, map[string]struct{}{age: {}}, out.res.dropAttr)
+		require.Equal(t, keys(name+":2", name+":3", age+":2", age+":3", name2+":2", name2+":3"),
+			out.mapped)
+	})
+}
+
+func TestManifestWindow(t *testing.T) {
+	var manifests []*Manifest
+	for num := uint64(4); num > 0; num-- {
+		manifests = append(manifests, &Manifest{BackupNum: num, ReadTs: num * 10})
+	}
+	for _, tc := range []struct {
+		from, to, min, max uint64
+	}{
+		{1, 4, 0, 40},
+		{1, 1, 0, 10},
+		{2, 3, 10, 30},
+		{4, 4, 30, 40},
+	} {
+		min, max, err := manifestWindow(manifests, tc.from, tc.to)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{tc.min, tc.max}, []uint64{min, max},
+			"window %d to %d", tc.from, tc.to)
+	}
+
+	_, _, err := manifestWindow(manifests, 2, 5)
+	require.Error(t, err)
+	_, _, err = manifestWindow(manifests[:2], 1, 4)
+	require.Error(t, err)
+
+	require.Error(t, (&MapOptions{WindowFrom: 3, WindowTo: 2}).validate())
+	require.Error(t, (&MapOptions{WindowTo: 2}).validate())
+	require.NoError(t, (&MapOptions{WindowFrom: 2, WindowTo: 2}).validate())
+}
+
+func TestFindPredicateMismatches(t *testing.T) {
+	set := func(attrs ...string) map[string]struct{} {
+		res := make(map[string]struct{})
+		for _, attr := range attrs {
+			res[attr] = struct{}{}
+		}
+		return res
+	}
+	data := set(x.GalaxyAttr("name"), x.GalaxyAttr("age"), x.NamespaceAttr(2, "name"))
+	schema := set(x.GalaxyAttr("name"), x.GalaxyAttr("email"), x.NamespaceAttr(2, "age"),
+		x.NamespaceAttr(3, "age"), x.NamespaceAttr(2, "dgraph.type"))
+
+	noSchema, noData := findPredicateMismatches(data, schema, map[uint64]struct{}{3: {}})
+	require.Equal(t, []string{x.GalaxyAttr("age"), x.NamespaceAttr(2, "name")}, noSchema)
+	// The reserved predicates and the predicates of the dropped namespaces are not reported.
+	require.Equal(t, []string{x.GalaxyAttr("email"), x.NamespaceAttr(2, "age")}, noData)
+
+	opts := MapOptions{FailOnPredicateMismatch: true, SchemaOnly: true}
+	require.Error(t, opts.validate())
+}
+
+func TestPartitionKeysRoundTrip(t *testing.T) {
+	var keys [][]byte
+	for i := 0; i < 100; i++ {
+		keys = append(keys, y.KeyWithTs(x.DataKey(x.GalaxyAttr("name"), uint64(i*1000)), 1))
+	}
+	// The keys are not required to be sorted, unique or non-empty.
+	keys = append(keys, keys[10], nil, []byte("a"), keys[0][:5])
+
+	encoded := encodePartitionKeys(keys)
+	var encSize, size int
+	for i := range keys {
+		size += len(keys[i])
+		encSize += len(encoded[i])
+	}
+	require.Less(t, encSize, size/2)
+	decoded, err := decodePartitionKeys("test.map", encoded)
+	require.NoError(t, err)
+	require.Equal(t, len(keys), len(decoded))
+	for i := range keys {
+		require.True(t, bytes.Equal(keys[i], decoded[i]), "key %d", i)
+	}
+
+	// The header of the map file holds the encoded keys, and the reader decodes them.
+	for _, version := range []uint32{mapFormatV1, mapFormatV2} {
+		header := &pb.MapHeader{FormatVersion: version, PartitionKeys: keys}
+		if version >= mapFormatV2 {
+			header.PartitionKeys = encoded
+		}
+		data, err := header.Marshal()
+		require.NoError(t, err)
+		var b bytes.Buffer
+		require.NoError(t, binary.Write(&b, binary.BigEndian, uint32(len(data))))
+		b.Write(data)
+		read, err := readMapHeader("test.map", bufio.NewReader(&b))
+		require.NoError(t, err)
+		require.Equal(t, len(keys), len(read.PartitionKeys))
+		for i := range keys {
+			require.True(t, bytes.Equal(keys[i], read.PartitionKeys[i]), "key %d", i)
+		}
+	}
+
+	// A key can't share more than the length of the previous key.
+	_, err = decodePartitionKeys("test.map", [][]byte{{0, 'a'}, {2, 'b'}})
+	require.Error(t, err)
+	_, err = decodePartitionKeys("test.map", [][]byte{{}})
+	require.Error(t, err)
+}
+
+func TestCheckMapFormat(t *testing.T) {
+	for _, version := range []uint32{mapFormatLegacy, mapFormatV1, mapFormatV2} {
+		require.NoError(t, checkMapFormat("test.map", &pb.MapHeader{FormatVersion: version}))
+	}
+
+	// A map file written by a newer mapper is rejected, both on its own and when it is read.
+	err := checkMapFormat("test.map", &pb.MapHeader{FormatVersion: 3})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "map file test.map has unsupported format version: 3."+
+		" Max supported version: 2")
+
+	data, err := (&pb.MapHeader{FormatVersion: 3}).Marshal()
+	require.NoError(t, err)
+	var b bytes.Buffer
+	require.NoError(t, binary.Write(&b, binary.BigEndian, uint32(len(data))))
+	b.Write(data)
+	_, err = readMapHeader("test.map", bufio.NewReader(&b))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported format version: 3")
+}
+
+func TestPriorityNamespaces(t *testing.T) {
+	list, err := (&bpb.KVList{Kv: []*bpb.KV{nsEdgeKV(t, 2, "name", 1)}}).Marshal()
+	require.NoError(t, err)
+	ns, ok := frameNamespace(list)
+	require.True(t, ok)
+	require.Equal(t, uint64(2), ns)
+	_, ok = frameNamespace(list[:10])
+	require.False(t, ok)
+
+	var stream bytes.Buffer
+	for _, ns := range []uint64{0, 0, 2, 2, 0} {
+		appendKVList(t, &stream, nsEdgeKV(t, ns, "name", 1))
+	}
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}}
+
+	// The requests are only queued, as the pipeline is not started.
+	m := newMapper(10, "", MapOptions{PriorityNamespaces: []uint64{2}}, 8)
+	m.ctx = context.Background()
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	// The frames of namespace 2 are batched separately from the ones before and after them.
+	require.Equal(t, 2, len(m.reqCh))
+	require.Equal(t, 1, len(m.prioCh))
+	close(m.reqCh)
+	close(m.prioCh)
+	for req := range m.reqCh {
+		req.lbuf.Release()
+	}
+	for req := range m.prioCh {
+		req.lbuf.Release()
+	}
+
+	// By default, all the frames are batched together.
+	m = newMapper(10, "", MapOptions{}, 8)
+	m.ctx = context.Background()
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.Nil(t, m.prioCh)
+	require.Equal(t, 1, len(m.reqCh))
+	(<-m.reqCh).lbuf.Release()
+}
+
+func TestCheckEncryptionKeyLength(t *testing.T) {
+	req := &pb.RestoreRequest{}
+	encrypted := []*Manifest{{Type: "full", BackupNum: 1, Encrypted: true}}
+	legacy := []*Manifest{{BackupNum: 1}}
+	plain := []*Manifest{{Type: "full", BackupNum: 1}}
+
+	for _, n := range []int{16, 24, 32} {
+		require.NoError(t, checkEncryption(encrypted, req, make(x.Sensitive, n), nil))
+	}
+	err := checkEncryption(encrypted, req, make(x.Sensitive, 20), nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "got 20 bytes")
+	require.Error(t, checkEncryption(legacy, req, make(x.Sensitive, 20), nil))
+	// The key is not used for the backups which are not encrypted.
+	require.NoError(t, checkEncryption(plain, req, make(x.Sensitive, 20), nil))
+}
+
+func TestCheckEncryptionMissingKey(t *testing.T) {
+	manifests := []*Manifest{
+		{Type: "incremental", BackupNum: 2, Path: "dgraph.2"},
+		{Type: "full", BackupNum: 1, Path: "dgraph.1", Encrypted: true},
+	}
+	err := checkEncryption(manifests, &pb.RestoreRequest{}, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(),
+		"backup is encrypted but no key provided. Manifest num: 1, path: dgraph.1")
+	require.NoError(t, checkEncryption(manifests, &pb.RestoreRequest{}, make(x.Sensitive, 16),
+		nil))
+	// The encrypted backup is not read if the restore starts after it.
+	require.NoError(t, checkEncryption(manifests, &pb.RestoreRequest{IncrementalFrom: 2}, nil,
+		nil))
+}
+
+func TestReadPartitionKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeMapHeader(t, filepath.Join(dir, "000001.map"), "k1", "k3")
+	writeMapHeader(t, filepath.Join(dir, "000002.map"))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "m2"), 0750))
+	writeMapHeader(t, filepath.Join(dir, "m2", "000003.map"), "k4", "k2")
+
+	// A map file of the previous format version, whose keys are not delta encoded.
+	header := &pb.MapHeader{FormatVersion: mapFormatV1, PartitionKeys: [][]byte{
+		y.KeyWithTs([]byte("k2"), 1), y.KeyWithTs([]byte("k5"), 1)}}
+	data, err := header.Marshal()
+	require.NoError(t, err)
+	var b bytes.Buffer
+	w := snappy.NewBufferedWriter(&b)
+	require.NoError(t, binary.Write(w, binary.BigEndian, uint32(len(data))))
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "000004.map"), b.Bytes(), 0600))
+
+	keys, err := ReadPartitionKeys(dir)
+	require.NoError(t, err)
+	var want [][]byte
+	for _, k := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		want = append(want, y.KeyWithTs([]byte(k), 1))
+	}
+	require.Equal(t, want, keys)
+}
+
+func TestVerifyVersions(t *testing.T) {
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("friend"): struct{}{}}}
+	p := newProcessor(newMapper(100, "", MapOptions{VerifyVersions: true}, 2))
+	buf := z.NewBuffer(1<<10, "TestVerifyVersions")
+	defer buf.Release()
+
+	kv := edgeKV(t, pb.BackupKey_DATA, "friend", 1, 2)
+	kv.Version = 10
+	require.NoError(t, p.processKV(buf, in, kv))
+	require.Greater(t, buf.LenNoPadding(), 0)
+
+	// The entry would be sorted above restoreTs, after the entries written at it.
+	kv = edgeKV(t, pb.BackupKey_DATA, "friend", 1, 2)
+	kv.Version = 150
+	err := p.processKV(buf, in, kv)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not below restoreTs 100")
+}
+
+func TestPreserveVersions(t *testing.T) {
+	in := &loadBackupInput{
+		preds:      predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		keepSchema: true,
+	}
+	// versions returns the version the entry of the KV read at version is sorted by, and the
+	// one it is written at.
+	versions := func(opts MapOptions, version uint64) (uint64, uint64) {
+		p := newProcessor(newMapper(100, "", opts, 2))
+		buf := z.NewBuffer(1<<10, "TestPreserveVersions")
+		defer buf.Release()
+		kv := schemaKV(t, x.GalaxyNamespace, "name")
+		kv.Version = version
+		require.NoError(t, p.processKV(buf, in, kv))
+		var sortTs, ts uint64
+		require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+			me := mapEntry(slice)
+			sortTs = y.ParseTs(me.Key())
+			var out bpb.KV
+			require.NoError(t, out.Unmarshal(me.Data()))
+			ts = out.Version
+			return nil
+		}))
+		return sortTs, ts
+	}
+
+	sortTs, ts := versions(MapOptions{}, 40)
+	require.Equal(t, []uint64{40, 100}, []uint64{sortTs, ts})
+	sortTs, ts = versions(MapOptions{PreserveVersions: true}, 40)
+	require.Equal(t, []uint64{40, 40}, []uint64{sortTs, ts})
+	sortTs, ts = versions(MapOptions{PreserveVersions: true, VersionOffset: 30}, 40)
+	require.Equal(t, []uint64{40, 70}, []uint64{sortTs, ts})
+	// The shifted versions are clamped to [1, restoreTs).
+	sortTs, ts = versions(MapOptions{PreserveVersions: true, VersionOffset: 80}, 40)
+	require.Equal(t, []uint64{40, 99}, []uint64{sortTs, ts})
+	sortTs, ts = versions(MapOptions{PreserveVersions: true, VersionOffset: -50}, 40)
+	require.Equal(t, []uint64{40, 1}, []uint64{sortTs, ts})
+
+	opts := MapOptions{VersionOffset: 10}
+	require.Error(t, opts.validate())
+}
+
+func TestParallelRollup(t *testing.T) {
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("friend"): struct{}{}}}
+	var uids []uint64
+	for i := uint64(1); i <= 20000; i++ {
+		uids = append(uids, i*1000)
+	}
+	kv := edgeKV(t, pb.BackupKey_DATA, "friend", 1, uids...)
+
+	// entries returns the entries the list is mapped to. The parts of a list are rolled up in
+	// no particular order, so the entries are sorted.
+	entries := func(opts MapOptions) []string {
+		require.NoError(t, opts.validate())
+		p := newProcessor(newMapper(10, "", opts, 2))
+		buf := z.NewBuffer(1<<20, "TestParallelRollup")
+		defer buf.Release()
+		require.NoError(t, p.processKV(buf, in, kv))
+		var res []string
+		require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+			me := mapEntry(slice)
+			res = append(res, string(me.Key())+string(me.Data()))
+			return nil
+		}))
+		sort.Strings(res)
+		return res
+	}
+
+	serial := entries(MapOptions{PostingSplitSize: 4 << 10})
+	// The list is split into many parts.
+	require.Greater(t, len(serial), 2)
+	parallel := entries(MapOptions{PostingSplitSize: 4 << 10, ParallelRollupThreshold: 1})
+	require.Equal(t, serial, parallel)
+}
+
+func TestStripFacets(t *testing.T) {
+	facet := func() []*api.Facet {
+		return []*api.Facet{{Key: "since", Value: []byte("2006")}}
+	}
+	key, err := (&pb.BackupKey{Type: pb.BackupKey_DATA, Attr: "friend", Uid: 1,
+		Namespace: x.GalaxyNamespace}).Marshal()
+	require.NoError(t, err)
+	val, err := (&pb.BackupPostingList{
+		Uids: []uint64{2, 3},
+		Postings: []*pb.Posting{
+			{Uid: 2, PostingType: pb.Posting_REF, Facets: facet()},
+			{Uid: math.MaxUint64, PostingType: pb.Posting_VALUE, Value: []byte("a"),
+				Facets: facet()},
+		},
+	}).Marshal()
+	require.NoError(t, err)
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("friend"): struct{}{}}}
+
+	for _, strip := range []bool{false, true} {
+		p := newProcessor(newMapper(10, "", MapOptions{StripFacets: strip}, 2))
+		buf := z.NewBuffer(1<<10, "TestStripFacets")
+		kv := &bpb.KV{Key: key, Value: val, UserMeta: []byte{posting.BitCompletePosting},
+			Version: 1}
+		require.NoError(t, p.processKV(buf, in, kv))
+		var pl pb.PostingList
+		require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+			var out bpb.KV
+			require.NoError(t, out.Unmarshal(mapEntry(slice).Data()))
+			return pl.Unmarshal(out.Value)
+		}))
+		buf.Release()
+
+		if !strip {
+			require.Len(t, pl.Postings, 2)
+			continue
+		}
+		// The uid posting was only there for its facets. The uids are all kept.
+		require.Len(t, pl.Postings, 1)
+		require.Equal(t, []byte("a"), pl.Postings[0].Value)
+		require.Empty(t, pl.Postings[0].Facets)
+		require.Equal(t, []uint64{2, 3}, codec.FromBytes(pl.Bitmap).ToArray())
+	}
+}
+
+func TestMaxPostingListBytes(t *testing.T) {
+	var uids []uint64
+	for i := uint64(1); i <= 20000; i++ {
+		uids = append(uids, i*7919)
+	}
+	kv := edgeKV(t, pb.BackupKey_DATA, "friend", 1, uids...)
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("friend"): struct{}{}}}
+	const limit = 4 << 10
+	require.Greater(t, len(kv.Value), limit)
+
+	p := newProcessor(newMapper(10, "", MapOptions{MaxPostingListBytes: limit}, 2))
+	buf := z.NewBuffer(1<<20, "TestMaxPostingListBytes")
+	defer buf.Release()
+	require.NoError(t, p.processKV(buf, in, kv))
+
+	var splits []uint64
+	parts := make(map[uint64][]uint64)
+	require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+		me := mapEntry(slice)
+		var out bpb.KV
+		require.NoError(t, out.Unmarshal(me.Data()))
+		require.Equal(t, uint64(10), out.Version)
+		var pl pb.PostingList
+		require.NoError(t, pl.Unmarshal(out.Value))
+		pk, err := x.Parse(y.ParseKey(me.Key()))
+		require.NoError(t, err)
+		if !pk.HasStartUid {
+			splits = pl.Splits
+			return nil
+		}
+		require.LessOrEqual(t, len(out.Value), limit)
+		parts[pk.StartUid] = codec.FromBytes(pl.Bitmap).ToArray()
+		return nil
+	}))
+
+	// The main list points to all the parts, which hold all the uids in order.
+	require.Greater(t, len(splits), 1)
+	require.Len(t, parts, len(splits))
+	var got []uint64
+	for _, start := range splits {
+		part, ok := parts[start]
+		require.True(t, ok, "missing part: %d", start)
+		got = append(got, part...)
+	}
+	require.Equal(t, uids, got)
+
+	// Without the option, the list is small enough to be kept whole.
+	p = newProcessor(newMapper(10, "", MapOptions{}, 2))
+	buf.Reset()
+	require.NoError(t, p.processKV(buf, in, kv))
+	var n int
+	require.NoError(t, buf.SliceIterate(func([]byte) error {
+		n++
+		return nil
+	}))
+	require.Equal(t, 1, n)
+	require.Error(t, (&MapOptions{MaxPostingListBytes: -1}).validate())
+}
+
+func TestPostingSplitSize(t *testing.T) {
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("friend"): struct{}{}}}
+	buf := z.NewBuffer(1<<20, "TestPostingSplitSize")
+	defer buf.Release()
+	// count maps the list and returns the number of KVs written for it, the main list and its
+	// parts.
+	count := func(kv *bpb.KV, opts MapOptions) int {
+		buf.Reset()
+		p := newProcessor(newMapper(10, "", opts, 2))
+		require.NoError(t, p.processKV(buf, in, kv))
+		var n int
+		require.NoError(t, buf.SliceIterate(func([]byte) error {
+			n++
+			return nil
+		}))
+		return n
+	}
+	// listSize returns the size of the list as it is checked for a split.
+	listSize := func(kv *bpb.KV) int {
+		var bl pb.BackupPostingList
+		require.NoError(t, bl.Unmarshal(kv.Value))
+		return posting.FromBackupPostingList(&bl).Size()
+	}
+
+	var uids []uint64
+	for i := uint64(1); i <= 20000; i++ {
+		uids = append(uids, i*7919)
+	}
+	small := edgeKV(t, pb.BackupKey_DATA, "friend", 1, uids...)
+	size := listSize(small)
+	require.Less(t, size, posting.MaxListSize())
+	require.Equal(t, 1, count(small, MapOptions{}))
+	// The list is split once its size reaches PostingSplitSize.
+	require.Greater(t, count(small, MapOptions{PostingSplitSize: size}), 2)
+	require.Equal(t, 1, count(small, MapOptions{PostingSplitSize: size + 1}))
+	// MaxPostingListBytes still lowers it.
+	require.Greater(t, count(small, MapOptions{PostingSplitSize: size + 1,
+		MaxPostingListBytes: size}), 2)
+
+	// Every uid is in a container of its own, which makes the list bigger than the split size
+	// of this binary.
+	uids = uids[:0]
+	for i := uint64(1); i <= 50000; i++ {
+		uids = append(uids, i<<16)
+	}
+	large := edgeKV(t, pb.BackupKey_DATA, "friend", 1, uids...)
+	size = listSize(large)
+	require.GreaterOrEqual(t, size, posting.MaxListSize())
+	require.Greater(t, count(large, MapOptions{}), 2)
+	// A larger split size keeps the list whole.
+	require.Equal(t, 1, count(large, MapOptions{PostingSplitSize: size + 1}))
+
+	require.Error(t, (&MapOptions{PostingSplitSize: -1}).validate())
+}
+
+func TestEmptyRollups(t *testing.T) {
+	var uids []uint64
+	for i := uint64(1); i <= 20000; i++ {
+		uids = append(uids, i*7919)
+	}
+	kv := edgeKV(t, pb.BackupKey_DATA, "friend", 1, uids...)
+	// The rollup of a list whose version is above any read timestamp returns no KV.
+	kv.Version = math.MaxUint64
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("friend"): struct{}{}}}
+	const limit = 4 << 10
+
+	// The key is skipped and counted by default.
+	m := newMapper(10, "", MapOptions{MaxPostingListBytes: limit}, 2)
+	buf := z.NewBuffer(1<<20, "TestEmptyRollups")
+	defer buf.Release()
+	require.NoError(t, newProcessor(m).processKV(buf, in, kv))
+	require.True(t, buf.IsEmpty())
+	require.Equal(t, uint64(1), m.emptyRollupCount())
+
+	// With KeepEmptyRollups, an empty posting list is written instead.
+	m = newMapper(10, "", MapOptions{MaxPostingListBytes: limit, KeepEmptyRollups: true}, 2)
+	require.NoError(t, newProcessor(m).processKV(buf, in, kv))
+	require.Equal(t, uint64(1), m.emptyRollupCount())
+	var n int
+	require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+		n++
+		me := mapEntry(slice)
+		var out bpb.KV
+		require.NoError(t, out.Unmarshal(me.Data()))
+		require.Equal(t, uint64(10), out.Version)
+		require.Equal(t, []byte{posting.BitEmptyPosting}, out.UserMeta)
+		require.Empty(t, out.Value)
+		pk, err := x.Parse(y.ParseKey(me.Key()))
+		require.NoError(t, err)
+		require.Equal(t, x.GalaxyAttr("friend"), pk.Attr)
+		require.Equal(t, uint64(1), pk.Uid)
+		return nil
+	}))
+	require.Equal(t, 1, n)
+}
+
+func TestPredicateRestoreTs(t *testing.T) {
+	in := &loadBackupInput{
+		preds: predicateSet{
+			x.GalaxyAttr("name"):       struct{}{},
+			x.NamespaceAttr(2, "name"): struct{}{},
+			x.GalaxyAttr("age"):        struct{}{},
+		},
+		keepSchema: true,
+		version:    2105,
+	}
+	opts := MapOptions{PredicateRestoreTs: map[string]uint64{"name": 5}}
+	require.NoError(t, opts.validate())
+	p := newProcessor(newMapper(10, "", opts, 2))
+	buf := z.NewBuffer(1<<10, "TestPredicateRestoreTs")
+	defer buf.Release()
+	for _, kv := range []*bpb.KV{nsEdgeKV(t, x.GalaxyNamespace, "name", 1),
+		nsEdgeKV(t, 2, "name", 1), nsEdgeKV(t, x.GalaxyNamespace, "age", 1),
+		schemaKV(t, x.GalaxyNamespace, "name")} {
+		require.NoError(t, p.processKV(buf, in, kv))
+	}
+	versions := make(map[string]uint64)
+	require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+		me := mapEntry(slice)
+		var kv bpb.KV
+		require.NoError(t, kv.Unmarshal(me.Data()))
+		pk, err := x.Parse(y.ParseKey(me.Key()))
+		require.NoError(t, err)
+		if pk.IsSchema() {
+			versions["schema:"+pk.Attr] = kv.Version
+		} else {
+			versions[pk.Attr] = kv.Version
+		}
+		return nil
+	}))
+	require.Equal(t, map[string]uint64{
+		x.GalaxyAttr("name"):             5,
+		x.NamespaceAttr(2, "name"):       5,
+		x.GalaxyAttr("age"):              10,
+		"schema:" + x.GalaxyAttr("name"): 5,
+	}, versions)
+
+	// The versions of the predicate in the backup must be below its override.
+	kv := nsEdgeKV(t, x.GalaxyNamespace, "name", 2)
+	kv.Version = 5
+	require.Error(t, p.processKV(buf, in, kv))
+	kv = nsEdgeKV(t, x.GalaxyNamespace, "age", 2)
+	kv.Version = 5
+	require.NoError(t, p.processKV(buf, in, kv))
+
+	require.Error(t, (&MapOptions{PredicateRestoreTs: map[string]uint64{"name": 0}}).validate())
+	require.Error(t, (&MapOptions{PredicateRestoreTs: map[string]uint64{"name": 5},
+		PreserveVersions: true}).validate())
+}
+
+func TestSampleRate(t *testing.T) {
+	in := &loadBackupInput{
+		preds:      predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		keepSchema: true,
+	}
+	// sample returns the uids of the posting lists kept with the rate, and the number of
+	// entries mapped.
+	sample := func(rate float64) (map[uint64]struct{}, int, *mapper) {
+		m := newMapper(10, "", MapOptions{SampleRate: rate}, 2)
+		p := newProcessor(m)
+		buf := z.NewBuffer(1<<20, "TestSampleRate")
+		defer buf.Release()
+		require.NoError(t, p.processKV(buf, in, schemaKV(t, x.GalaxyNamespace, "name")))
+		for uid := uint64(1); uid <= 1000; uid++ {
+			require.NoError(t, p.processKV(buf, in, nsEdgeKV(t, x.GalaxyNamespace, "name", uid)))
+		}
+		kept := make(map[uint64]struct{})
+		var n int
+		require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+			n++
+			pk, err := x.Parse(y.ParseKey(mapEntry(slice).Key()))
+			require.NoError(t, err)
+			if pk.IsData() {
+				kept[pk.Uid] = struct{}{}
+			}
+			return nil
+		}))
+		return kept, n, m
+	}
+
+	kept, n, m := sample(0.25)
+	// The schema key is always kept.
+	require.Equal(t, len(kept)+1, n)
+	require.InDelta(t, 250, len(kept), 75)
+	sampled, total := m.sampleCounts()
+	require.Equal(t, uint64(len(kept)), sampled)
+	require.Equal(t, uint64(1000), total)
+
+	// The same keys are kept in every run, and a higher rate keeps more of them.
+	again, _, _ := sample(0.25)
+	require.Equal(t, kept, again)
+	more, _, _ := sample(0.5)
+	for uid := range kept {
+		require.Contains(t, more, uid)
+	}
+	all, _, m := sample(1)
+	require.Len(t, all, 1000)
+	_, total = m.sampleCounts()
+	require.Zero(t, total)
+
+	for _, rate := range []float64{-0.1, 1.5, math.NaN()} {
+		require.Error(t, (&MapOptions{SampleRate: rate}).validate())
+	}
+}
+
+func TestStripNamespaces(t *testing.T) {
+	in := &loadBackupInput{
+		preds: predicateSet{
+			x.GalaxyAttr("name"):              struct{}{},
+			x.NamespaceAttr(2, "age"):         struct{}{},
+			x.NamespaceAttr(2, "name"):        struct{}{},
+			x.GalaxyAttr("dgraph.type"):       struct{}{},
+			x.NamespaceAttr(2, "dgraph.type"): struct{}{},
+		},
+		keepSchema: true,
+	}
+	process := func(p *processor, kvs ...*bpb.KV) ([]string, error) {
+		buf := z.NewBuffer(1<<10, "TestStripNamespaces")
+		defer buf.Release()
+		for _, kv := range kvs {
+			if err := p.processKV(buf, in, kv); err != nil {
+				return nil, err
+			}
+		}
+		var attrs []string
+		require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+			me := mapEntry(slice)
+			pk, err := x.Parse(y.ParseKey(me.Key()))
+			require.NoError(t, err)
+			attrs = append(attrs, pk.Attr)
+			if pk.IsSchema() {
+				var out bpb.KV
+				require.NoError(t, out.Unmarshal(me.Data()))
+				var update pb.SchemaUpdate
+				require.NoError(t, update.Unmarshal(out.Value))
+				require.Equal(t, pk.Attr, update.Predicate)
+			}
+			return nil
+		}))
+		return attrs, nil
+	}
+
+	opts := MapOptions{StripNamespaces: true}
+	require.NoError(t, opts.validate())
+	p := newProcessor(newMapper(10, "", opts, 2))
+	attrs, err := process(p,
+		nsEdgeKV(t, x.GalaxyNamespace, "name", 1),
+		nsEdgeKV(t, 2, "age", 2),
+		schemaKV(t, 2, "age"),
+		// The reserved predicates are in every namespace.
+		nsEdgeKV(t, x.GalaxyNamespace, "dgraph.type", 1),
+		nsEdgeKV(t, 2, "dgraph.type", 2))
+	require.NoError(t, err)
+	require.Equal(t, []string{x.GalaxyAttr("name"), x.GalaxyAttr("age"), x.GalaxyAttr("age"),
+		x.GalaxyAttr("dgraph.type"), x.GalaxyAttr("dgraph.type")}, attrs)
+
+	// The data and the schema of name in namespace 2 would clobber the ones of the galaxy.
+	for _, kv := range []*bpb.KV{nsEdgeKV(t, 2, "name", 2), schemaKV(t, 2, "name")} {
+		_, err = process(p, kv)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "predicate name of namespaces 0x0 and 0x2")
+	}
+
+	opts.MergeNamespaceCollisions = true
+	p = newProcessor(newMapper(10, "", opts, 2))
+	attrs, err = process(p, nsEdgeKV(t, x.GalaxyNamespace, "name", 1),
+		nsEdgeKV(t, 2, "name", 2), schemaKV(t, 2, "name"))
+	require.NoError(t, err)
+	require.Equal(t, []string{x.GalaxyAttr("name"), x.GalaxyAttr("name"),
+		x.GalaxyAttr("name")}, attrs)
+	require.Equal(t, []string{"predicate name of namespaces 0x0 and 0x2"}, p.nsCollisions)
+
+	require.Error(t, (&MapOptions{MergeNamespaceCollisions: true}).validate())
+}
+
+func TestKeyComparator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	reverse := func(a, b []byte) int { return y.CompareKeys(b, a) }
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 100; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	m := newMapper(10, dir, MapOptions{KeyComparator: reverse}, 2)
+	m.startPipeline(2)
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}}
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.NoError(t, m.stopPipeline())
+
+	files, _, err := mapFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	_, itr, err := newMapIterator(files[0])
+	require.NoError(t, err)
+	cbuf := z.NewBuffer(1<<10, "TestKeyComparator")
+	defer cbuf.Release()
+	require.NoError(t, itr.Next(cbuf, nil))
+	require.NoError(t, itr.Close())
+	var uids []uint64
+	require.NoError(t, cbuf.SliceIterate(func(me []byte) error {
+		pk, err := x.Parse(y.ParseKey(mapEntry(me).Key()))
+		require.NoError(t, err)
+		uids = append(uids, pk.Uid)
+		return nil
+	}))
+	require.Len(t, uids, 100)
+	for i, uid := range uids {
+		require.Equal(t, uint64(100-i), uid)
+	}
+
+	// The partition keys follow the same order.
+	pr, err := checkPartitions(dir, reverse)
+	require.NoError(t, err)
+	require.Empty(t, pr.unsorted)
+
+	opts := MapOptions{KeyComparator: reverse, VerifyStore: true}
+	require.Error(t, opts.validate())
+}
+
+func TestVerifySorted(t *testing.T) {
+	entries := func(keys ...string) *z.Buffer {
+		buf := z.NewBuffer(1<<10, "TestVerifySorted")
+		for _, k := range keys {
+			key := y.KeyWithTs([]byte(k), 1)
+			me := buf.SliceAllocate(2 + len(key) + 1)
+			binary.BigEndian.PutUint16(me, uint16(len(key)))
+			copy(me[2:], key)
+		}
+		return buf
+	}
+	reverse := func(a, b []byte) int { return y.CompareKeys(b, a) }
+
+	buf := entries("a", "b", "b", "c")
+	defer buf.Release()
+	require.NoError(t, verifySorted(buf, y.CompareKeys))
+	require.Error(t, verifySorted(buf, reverse))
+
+	unsorted := entries("a", "c", "b")
+	defer unsorted.Release()
+	err := verifySorted(unsorted, y.CompareKeys)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprintf("key %x is followed by key %x",
+		y.KeyWithTs([]byte("c"), 1), y.KeyWithTs([]byte("b"), 1)))
+}
+
+func TestDedupLatest(t *testing.T) {
+	base := x.DataKey(x.GalaxyAttr("name"), 1)
+	other := x.DataKey(x.GalaxyAttr("name"), 2)
+	part, err := x.SplitKey(base, 100)
+	require.NoError(t, err)
+	buf := z.NewBuffer(1<<10, "TestDedupLatest")
+	defer buf.Release()
+	// The entries of a key are sorted from the latest version to the oldest, and the parts of
+	// the lists after all the other data keys. The part of the list of uid 1 is only found in
+	// its older version.
+	for _, e := range []struct {
+		key     []byte
+		version uint64
+	}{{base, 3}, {base, 2}, {other, 5}, {other, 5}, {other, 1}, {part, 2}} {
+		key := y.KeyWithTs(e.key, e.version)
+		me := buf.SliceAllocate(2 + len(key) + 1)
+		binary.BigEndian.PutUint16(me, uint16(len(key)))
+		copy(me[2:], key)
+	}
+	require.NoError(t, verifySorted(buf, y.CompareKeys))
+
+	m := newMapper(10, "", MapOptions{DedupLatest: true}, 2)
+	var kept []string
+	require.NoError(t, m.latestEntries(buf.SliceIterate)(func(slice []byte) error {
+		key := mapEntry(slice).Key()
+		kept = append(kept, fmt.Sprintf("%x@%d", y.ParseKey(key), y.ParseTs(key)))
+		return nil
+	}))
+	require.Equal(t, []string{fmt.Sprintf("%x@3", base), fmt.Sprintf("%x@5", other),
+		fmt.Sprintf("%x@2", part)}, kept)
+	require.Equal(t, uint64(3), m.staleEntryCount())
+
+	for _, opts := range []MapOptions{
+		{DedupLatest: true, PreserveVersions: true},
+		{DedupLatest: true, RestoreTsSets: []uint64{5}},
+		{DedupLatest: true, KeyComparator: y.CompareKeys},
+	} {
+		require.Error(t, opts.validate())
+	}
+}
+
+func TestSyncMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := MapOptions{SyncMode: SyncBatch, SyncBatchSize: 2, PerManifestSubdirs: true}
+	require.NoError(t, opts.validate())
+	m := newMapper(10, dir, opts, 2)
+	for i := 0; i < 3; i++ {
+		buf := z.NewBuffer(1<<10, "TestSyncMode")
+		me := buf.SliceAllocate(2 + 3 + 1)
+		binary.BigEndian.PutUint16(me, 3)
+		copy(me[2:], "key")
+		require.NoError(t, m.writeToDisk(buf, 3))
+	}
+	// The first two files were synced as a batch.
+	require.Equal(t, []string{filepath.Join(dir, "m3", "000003.map")}, m.unsynced)
+	require.Equal(t, map[string]struct{}{dir: {}, filepath.Join(dir, "m3"): {}},
+		m.unsyncedDirs)
+	require.NoError(t, m.Flush())
+	require.Empty(t, m.unsynced)
+	require.Empty(t, m.unsyncedDirs)
+	require.Len(t, m.writtenFiles(), 3)
+
+	opts = MapOptions{}
+	require.NoError(t, opts.validate())
+	require.Equal(t, SyncAlways, opts.SyncMode)
+	opts = MapOptions{SyncMode: "sometimes"}
+	require.Error(t, opts.validate())
+	opts = MapOptions{SyncMode: SyncNone, CheckpointFile: "checkpoint.json"}
+	require.Error(t, opts.validate())
+}
+
+func TestCheckMapDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	strict := MapOptions{FailIfMapDirNonEmpty: true}
+	require.NoError(t, checkMapDir(filepath.Join(dir, "missing"), strict))
+	require.NoError(t, checkMapDir(dir, strict))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "m2"), 0750))
+	for _, name := range []string{"000001.map", "m2/000002.map", "checkpoint.json"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+	err = checkMapDir(dir, strict)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already holds 2 map files")
+
+	// The map files are only reported by default.
+	require.NoError(t, checkMapDir(dir, MapOptions{}))
+	files, _, err := mapFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	require.NoError(t, checkMapDir(dir, MapOptions{CleanMapDir: true}))
+	files, _, err = mapFiles(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+	_, err = os.Stat(filepath.Join(dir, "checkpoint.json"))
+	require.NoError(t, err)
+
+	opts := MapOptions{CleanMapDir: true, FailIfMapDirNonEmpty: true}
+	require.Error(t, opts.validate())
+}
+
+func TestCheckMapDirWritable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-map")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The map directory is created, and the probe is removed.
+	mapDir := filepath.Join(dir, "map")
+	require.NoError(t, checkMapDirWritable(mapDir))
+	entries, err := ioutil.ReadDir(mapDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// A directory can't be created under a file, whatever the permissions of the user.
+	file := filepath.Join(dir, "file")
+	require.NoError(t, ioutil.WriteFile(file, nil, 0600))
+	require.Error(t, checkMapDirWritable(filepath.Join(file, "map")))
+
+	// RunMapper fails before reading the manifests.
+	req := &pb.RestoreRequest{Location: filepath.Join(dir, "missing"), RestoreTs: 10}
+	_, err = RunMapper(req, filepath.Join(file, "map"), MapOptions{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't be created")
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// schemaSource is the group a schema or type key was first mapped from, and the checksum of its
+// value.
+type schemaSource struct {
+	groupId uint32
+	sum     [sha256.Size]byte
+}
+
+// mapSchemaKeyOnce records the schema or type key read from the backup of the group. It returns
+// true if the key was already mapped from the backup of another group, in which case it should
+// be skipped. If the values of the key differ, the collision is recorded and the value mapped
+// first is kept.
+func (m *mapper) mapSchemaKeyOnce(parsedKey x.ParsedKey, key, val []byte, gid uint32) bool {
+	sum := sha256.Sum256(val)
+	m.schemaMu.Lock()
+	defer m.schemaMu.Unlock()
+	prev, ok := m.schemaKeys[string(key)]
+	if !ok {
+		m.schemaKeys[string(key)] = schemaSource{groupId: gid, sum: sum}
+		return false
+	}
+	if prev.sum != sum {
+		kind := "schema"
+		if parsedKey.IsType() {
+			kind = "type"
+		}
+		m.schemaCollisions = append(m.schemaCollisions, fmt.Sprintf(
+			"%s of %s differs between groups %d and %d, keeping the one of group %d",
+			kind, describeAttr(parsedKey.Attr), prev.groupId, gid, prev.groupId))
+	}
+	return true
+}
+
+// findGroupConflicts returns the predicates which belong to more than one group in the
+// manifest, in sorted order.
+func findGroupConflicts(manifest *Manifest) []string {
+	owners := make(map[string][]uint32)
+	for gid, preds := range manifest.Groups {
+		for _, pred := range preds {
+			owners[pred] = append(owners[pred], gid)
+		}
+	}
+	var res []string
+	for pred, gids := range owners {
+		if len(gids) < 2 {
+			continue
+		}
+		sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+		res = append(res, fmt.Sprintf("%s belongs to groups %v", describeAttr(pred), gids))
+	}
+	sort.Strings(res)
+	return res
+}
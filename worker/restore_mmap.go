@@ -0,0 +1,111 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"os"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/pkg/errors"
+)
+
+// mmapInitialSize is the size the map files are first extended to with MapOptions.MmapMapFiles.
+const mmapInitialSize = 64 << 20
+
+// mmapWriter writes to a file through a writable memory mapping, see MapOptions.MmapMapFiles.
+// The file is extended ahead of the writes, by doubling its size when the mapping is full, and
+// is truncated to the bytes written once it is unmapped.
+type mmapWriter struct {
+	f    *os.File
+	data []byte
+	off  int
+	// unmapped is set once the file has been unmapped and truncated.
+	unmapped bool
+}
+
+// newMmapWriter maps f, which must be opened for reading and writing, after extending it to
+// size bytes.
+func newMmapWriter(f *os.File, size int) (*mmapWriter, error) {
+	mw := &mmapWriter{f: f}
+	if err := mw.remap(size); err != nil {
+		return nil, err
+	}
+	return mw, nil
+}
+
+// remap extends the file to size bytes, and maps it again.
+func (mw *mmapWriter) remap(size int) error {
+	if mw.data != nil {
+		if err := z.Munmap(mw.data); err != nil {
+			return errors.Wrapf(err, "while unmapping %s", mw.f.Name())
+		}
+		mw.data = nil
+	}
+	if err := mw.f.Truncate(int64(size)); err != nil {
+		return errors.Wrapf(err, "while extending %s to %d bytes", mw.f.Name(), size)
+	}
+	data, err := z.Mmap(mw.f, true, int64(size))
+	if err != nil {
+		return errors.Wrapf(err, "while mapping %s", mw.f.Name())
+	}
+	mw.data = data
+	return nil
+}
+
+func (mw *mmapWriter) Write(p []byte) (int, error) {
+	if mw.unmapped {
+		return 0, errors.Errorf("write to %s after it was unmapped", mw.f.Name())
+	}
+	if need := mw.off + len(p); need > len(mw.data) {
+		size := 2 * len(mw.data)
+		for size < need {
+			size *= 2
+		}
+		if err := mw.remap(size); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(mw.data[mw.off:], p)
+	mw.off += n
+	return n, nil
+}
+
+// unmap unmaps the file and truncates it to the bytes written, so that it can be synced. The
+// calls after the first one do nothing.
+func (mw *mmapWriter) unmap() error {
+	if mw.unmapped {
+		return nil
+	}
+	mw.unmapped = true
+	if mw.data != nil {
+		if err := z.Munmap(mw.data); err != nil {
+			return errors.Wrapf(err, "while unmapping %s", mw.f.Name())
+		}
+		mw.data = nil
+	}
+	return errors.Wrapf(mw.f.Truncate(int64(mw.off)), "while truncating %s to %d bytes",
+		mw.f.Name(), mw.off)
+}
+
+// Close unmaps and truncates the file if it hasn't been yet, and closes it.
+func (mw *mmapWriter) Close() error {
+	if err := mw.unmap(); err != nil {
+		mw.f.Close()
+		return err
+	}
+	return mw.f.Close()
+}
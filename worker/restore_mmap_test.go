@@ -0,0 +1,145 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// writeChunks writes data to w in 1 KiB writes, like the entries of a map file, and closes it.
+func writeChunks(w mapFileWriter, data []byte) error {
+	for off := 0; off < len(data); off += 1 << 10 {
+		end := off + 1<<10
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := w.Write(data[off:end]); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+func TestMmapWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-mmap")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	data := mapFileData(3*snappyMaxChunkLen + 100)
+	for _, stored := range []bool{false, true} {
+		var want bytes.Buffer
+		require.NoError(t, writeChunks(newMapFileWriter(&want, stored), data))
+
+		f, err := os.OpenFile(filepath.Join(dir, fmt.Sprintf("stored-%v", stored)),
+			os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+		require.NoError(t, err)
+		// The mapping starts small, so that it is extended several times.
+		mw, err := newMmapWriter(f, 1<<10)
+		require.NoError(t, err)
+		require.NoError(t, writeChunks(newMapFileWriter(mw, stored), data))
+		require.NoError(t, mw.Close())
+
+		// The snappy stream is the same, and the file is truncated to it.
+		got, err := ioutil.ReadFile(f.Name())
+		require.NoError(t, err)
+		require.Equal(t, want.Bytes(), got)
+		_, err = mw.Write([]byte("x"))
+		require.Error(t, err)
+	}
+}
+
+func TestMmapMapFiles(t *testing.T) {
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 1000; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}}
+
+	// run maps the stream and returns the contents of the map files, by their relative path.
+	run := func(mmap bool) map[string][]byte {
+		dir, err := ioutil.TempDir("", "restore-mmap")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		opts := MapOptions{Deterministic: true, MmapMapFiles: mmap, ProcessBufSize: 1 << 10,
+			ProcessFlushSize: 1}
+		require.NoError(t, opts.validate())
+		m := newMapper(10, dir, opts, mapGoroutines(opts))
+		m.startPipeline(mapGoroutines(opts))
+		require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+		require.NoError(t, m.stopPipeline())
+
+		files, _, err := mapFiles(dir)
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+		out := make(map[string][]byte)
+		for _, file := range files {
+			rel, err := filepath.Rel(dir, file)
+			require.NoError(t, err)
+			out[rel], err = ioutil.ReadFile(file)
+			require.NoError(t, err)
+		}
+		return out
+	}
+	require.Equal(t, run(false), run(true))
+}
+
+// BenchmarkMmapMapFileWriter compares the write throughput of a map file written with write
+// calls and through a memory mapping.
+func BenchmarkMmapMapFileWriter(b *testing.B) {
+	dir, err := ioutil.TempDir("", "restore-mmap")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := mapFileData(256 << 20)
+	for _, mmap := range []bool{false, true} {
+		b.Run(fmt.Sprintf("mmap=%v", mmap), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				f, err := os.OpenFile(filepath.Join(dir, "bench.map"),
+					os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var w io.WriteCloser = f
+				if mmap {
+					if w, err = newMmapWriter(f, mmapInitialSize); err != nil {
+						b.Fatal(err)
+					}
+				}
+				if err := writeChunks(newMapFileWriter(w, false), data); err != nil {
+					b.Fatal(err)
+				}
+				if err := w.Close(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
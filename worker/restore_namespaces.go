@@ -0,0 +1,158 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// splitNamespaceAttr is like x.ParseNamespaceAttr, but it returns false instead of panicking
+// if the attribute is not in the <namespace>-<attribute> format.
+func splitNamespaceAttr(attr string) (uint64, string, bool) {
+	splits := strings.SplitN(attr, x.NsSeparator, 2)
+	if len(splits) != 2 {
+		return 0, "", false
+	}
+	ns, err := strconv.ParseUint(splits[0], 16, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return ns, splits[1], true
+}
+
+// describeAttr returns the namespace and the name of the attribute for the diagnostics.
+func describeAttr(attr string) string {
+	ns, name, ok := splitNamespaceAttr(attr)
+	if !ok {
+		return fmt.Sprintf("%q without namespace", attr)
+	}
+	return fmt.Sprintf("%s in namespace %#x", name, ns)
+}
+
+// checkSchemaNamespace records the predicate of the schema update, after the conversions of
+// the older formats, and checks that it is the one of the schema key.
+func (p *processor) checkSchemaNamespace(parsedKey x.ParsedKey, val []byte) error {
+	var update pb.SchemaUpdate
+	if err := update.Unmarshal(val); err != nil {
+		return err
+	}
+	p.schemas[update.Predicate] = struct{}{}
+	if update.Predicate != parsedKey.Attr {
+		p.nsMismatches = append(p.nsMismatches, fmt.Sprintf("schema key of %s holds %s",
+			describeAttr(parsedKey.Attr), describeAttr(update.Predicate)))
+	}
+	return nil
+}
+
+// stripNamespace moves the key, parsed as pk, to the galaxy namespace with StripNamespaces. It
+// fails if the predicate or the type of the key was already found in another namespace, as the
+// keys of both would be restored into the same one, unless MergeNamespaceCollisions is set. The
+// reserved predicates and types are in every namespace, and are always merged.
+func (p *processor) stripNamespace(key []byte, pk x.ParsedKey) error {
+	kind := "predicate"
+	if pk.IsType() {
+		kind = "type"
+	}
+	src := kind + " " + pk.Attr
+	if _, ok := p.stripped[src]; !ok {
+		ns, name := x.ParseNamespaceAttr(pk.Attr)
+		if !x.IsReservedPredicate(pk.Attr) {
+			dest := kind + " " + name
+			p.stripMu.Lock()
+			if p.strippedAttrs == nil {
+				p.strippedAttrs = make(map[string]uint64)
+			}
+			first, found := p.strippedAttrs[dest]
+			if !found {
+				p.strippedAttrs[dest] = ns
+			}
+			if found && first != ns {
+				msg := fmt.Sprintf("%s of namespaces %#x and %#x", dest, first, ns)
+				if !p.opts.MergeNamespaceCollisions {
+					p.stripMu.Unlock()
+					return errors.Errorf("cannot strip the namespaces: the %s would be"+
+						" restored into the galaxy namespace", msg)
+				}
+				p.nsCollisions = append(p.nsCollisions, msg)
+			}
+			p.stripMu.Unlock()
+		}
+		p.stripped[src] = struct{}{}
+	}
+	binary.BigEndian.PutUint64(key[1:9], x.GalaxyNamespace)
+	return nil
+}
+
+// stripSchemaNamespace strips the namespace of the schema or type update of e with
+// StripNamespaces. It returns true if the update is skipped.
+func (p *processor) stripSchemaNamespace(in *loadBackupInput, e *backupKV) (bool, error) {
+	if _, ok := in.dropNs[e.ns]; ok {
+		// The schema of a dropped namespace would override the one of the galaxy.
+		return true, nil
+	}
+	if err := p.stripNamespace(e.key, e.pk); err != nil {
+		return false, err
+	}
+	val, err := rewriteSchemaAttrs(e.kv.Value, e.pk, func(attr string) (string, error) {
+		return x.GalaxyAttr(x.ParseAttr(attr)), nil
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "while stripping the namespace of %s",
+			describeAttr(e.pk.Attr))
+	}
+	e.kv.Value = val
+	return false, nil
+}
+
+// findSchemaNsMismatches returns the mismatches found by checkSchemaNamespace, along with the
+// predicates which have data but no schema in the same namespace, in sorted order.
+func findSchemaNsMismatches(mismatches []string, data, schemas map[string]struct{}) []string {
+	res := append([]string{}, mismatches...)
+	for attr := range data {
+		if _, ok := schemas[attr]; !ok {
+			res = append(res, fmt.Sprintf("data of %s has no schema in its namespace",
+				describeAttr(attr)))
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// sortedNamespaces returns the namespaces collected with CollectNamespaces, in increasing
+// order.
+func (m *mapper) sortedNamespaces() []uint64 {
+	m.seenMu.Lock()
+	defer m.seenMu.Unlock()
+	if len(m.namespaces) == 0 {
+		return nil
+	}
+	res := make([]uint64, 0, len(m.namespaces))
+	for ns := range m.namespaces {
+		res = append(res, ns)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
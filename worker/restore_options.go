@@ -0,0 +1,1232 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/dustin/go-humanize"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+// MapOptions holds the options which change the behaviour of the map phase. The zero value
+// maps the backup the default way. The online restore takes them from the restore request, see
+// MapOptionsFromRequest, and export_backup from its map flag. The hooks, like Logger or Sink,
+// can only be set by the callers of RunMapper.
+type MapOptions struct {
+	// PerManifestSubdirs writes the map files of each manifest into their own subdirectory
+	// named m<BackupNum> under the map directory. The reduce phase walks the map directory
+	// recursively, so it picks up the map files from all the subdirectories.
+	PerManifestSubdirs bool
+	// PerNamespaceSubdirs writes the entries of each namespace into their own map files, in a
+	// subdirectory named ns<namespace>, under the one of the manifest with
+	// PerManifestSubdirs. The buffers are split at the namespace boundaries when they are
+	// written, so every map file holds a single namespace. It lets a reduce phase sharded by
+	// namespace pick the map files of its namespaces without splitting them again.
+	PerNamespaceSubdirs bool
+	// SplitLargeValues writes the entries whose marshalled KV is larger than LargeValueThreshold
+	// into their own map files, under a subdirectory named large, so that the reduce phase can
+	// load them apart with largeValueMapFiles. LargeValueThreshold defaults to 1 MiB.
+	SplitLargeValues    bool
+	LargeValueThreshold int
+	// SkipBadKeys skips the keys which can not be parsed instead of failing the restore. The
+	// skipped keys are counted and a sample of them is reported in the map result.
+	SkipBadKeys bool
+	// SkipUnreadableFiles skips the backup files which can't be opened, like the files missing
+	// from the storage, instead of failing the restore. The data of the other files is still
+	// mapped. The skipped files are logged and reported in the map result. A file which fails
+	// once it is being read still fails the restore.
+	SkipUnreadableFiles bool
+	// MaxAllowedUid is the largest uid that a restored key is allowed to have. The restore is
+	// aborted if a key with a larger uid is found. Zero means no limit.
+	MaxAllowedUid uint64
+	// SniffCompression peeks at the beginning of each backup file and uses the compression
+	// found there when it contradicts the compression declared in the manifest. This helps
+	// with backup directories whose files were not all compressed the same way.
+	SniffCompression bool
+	// MaxMapDuration is the max time the map phase can take. Once it is exceeded, the map phase
+	// is aborted and fails with ErrMapTimeout. The map files are removed if CleanupOnError is
+	// set, and the checkpoint is saved if CheckpointFile is set, so that the map phase can be
+	// resumed. The deadline is checked between the reads from the backup files, so a read
+	// which hangs is not interrupted. Zero means no limit.
+	MaxMapDuration time.Duration
+	// MergeAllGroups maps the backup files of all the groups, instead of only the ones of the
+	// group of the request. This is used to restore a backup taken on a cluster with several
+	// groups into a cluster with a single group. The schema and type keys, which are stored
+	// in the backup of every group, are only mapped once. The restore fails if a predicate
+	// belongs to more than one group in a manifest.
+	MergeAllGroups bool
+
+	// CompressionOverrides maps the ids of groups to the compression of their backup files,
+	// which is used instead of the compression declared in the manifests. This is meant for
+	// backup sets where the files of some groups were recompressed. The compression can be
+	// "gzip" or "snappy".
+	CompressionOverrides map[uint32]string
+	// UncompressedMapFiles writes the map files without compressing their contents. They are
+	// still written in the snappy format, so the reduce phase reads them as usual. The entries
+	// are transformed by the map phase, so they have to be compressed again even if the backup
+	// is compressed with snappy. That compression takes a large share of the CPU of the
+	// writers. Skipping it makes the map files about as large as the mapped data.
+	UncompressedMapFiles bool
+	// MapFileCompression is the codec of the map files, snappy or gzip. It defaults to snappy.
+	// gzip makes the map files smaller, at a much higher CPU cost, which is worth it if the map
+	// files are shipped to the reduce phase over a slow network. MapFileGzipLevel is the level
+	// of gzip, from gzip.HuffmanOnly to gzip.BestCompression. Zero is gzip.DefaultCompression.
+	MapFileCompression string
+	MapFileGzipLevel   int
+	// MmapMapFiles writes the local map files through a writable memory mapping instead of
+	// write calls, which saves the syscalls of the writers on a fast local disk. The files are
+	// extended ahead of the writes and truncated to their final size once written, so their
+	// contents are the same. It is off by default, as the behavior of the writable mappings,
+	// like the flushes of the dirty pages and the errors of a full disk, which are signals
+	// rather than errors, differs across platforms. It has no effect on a remote map
+	// directory.
+	MmapMapFiles bool
+	// MapFileFormat is the format of the files written by the map phase, "native" or "sst". It
+	// defaults to "native", the map files read by the reduce phase. "sst" writes SST files
+	// in the block based table format of RocksDB instead, with the .sst extension, for the
+	// tools which bulk ingest them. The reduce phase can't read them. See restore_sst.go for
+	// the encoding of the keys and the values. The data blocks are compressed with snappy,
+	// unless UncompressedMapFiles is set.
+	MapFileFormat string
+	// TargetPostingFormat re-encodes the uids of the posting lists with the format before they
+	// are written to the map files. Empty writes them as they are decoded from the backup.
+	// "roaring" re-packs the roaring bitmaps from the sorted uids, which makes them smaller
+	// and faster to read for the lists of the older backups. It is the only target, as it is
+	// the only encoding of this version, and every backup format can be converted to it. See
+	// restore_posting_format.go.
+	TargetPostingFormat string
+	// PartitionBufSize is the number of bytes of entries between two partition keys of a map
+	// file. The reduce phase reads the map files one partition at a time, so a smaller size
+	// makes more, smaller batches, at the cost of a larger header. It defaults to 4 MiB.
+	PartitionBufSize int
+	// SyncMode is when the local map files are synced to disk: SyncAlways, the default, syncs
+	// each one, SyncBatch syncs SyncBatchSize at a time and SyncNone only syncs at the end.
+	SyncMode string
+	// CleanMapDir removes the map files left in the map directory by a previous run before
+	// mapping, and FailIfMapDirNonEmpty fails the map phase if there are any. Otherwise, they
+	// are reduced along with the new ones, which restores their stale data. The map files are
+	// kept when the map phase is resumed from CheckpointFile, as they are part of the run.
+	CleanMapDir          bool
+	FailIfMapDirNonEmpty bool
+
+	// WindowFrom and WindowTo restrict the map phase to the changes made between the backups
+	// with these numbers, both included. Only the manifests in the window are mapped, and only
+	// the posting lists whose version is above the read timestamp of the manifest before
+	// WindowFrom, and up to the one of WindowTo. The schema is the one of the latest manifest
+	// in the window. The drop operations of the manifests after the window are ignored. This
+	// produces the set of keys changed in the window, meant to be applied atop an existing
+	// restore of the backups before it. It is not a standalone dataset. Zero WindowTo maps
+	// all the manifests.
+	WindowFrom uint64
+	WindowTo   uint64
+	// SyncBatchSize is the number of map files synced at a time with SyncBatch. It defaults
+	// to 16.
+	SyncBatchSize int
+	// FlushInterval is the max time for which the mapper holds the mapped entries in memory
+	// while waiting for its buffers to fill up. Buffers holding data for longer are written
+	// out to a map file, provided a writer is free. This is meant for slow sources where the
+	// buffers would otherwise take very long to fill up. Under normal throughput, buffers fill
+	// up well before the interval, so no extra files are written. Zero disables the flushing.
+	FlushInterval time.Duration
+	// PriorityNamespaces are the namespaces whose data is mapped ahead of the data of the
+	// other namespaces. By default, the frames of the backup files are processed in the order
+	// they are read. When this is set, the frames of the priority namespaces are queued
+	// separately, and the processing goroutines always take them before the frames of the
+	// other namespaces. The frames are still read in the order of the backup files, so this
+	// only reorders the frames read ahead of the processing goroutines. It keeps a large
+	// tenant from delaying the restore of the listed ones when they are read concurrently,
+	// as with MergeAllGroups or when the reads are faster than the processing. The namespace
+	// of a frame is the one of its first key. Backups are sorted by namespace, so frames
+	// rarely span namespaces.
+	PriorityNamespaces []uint64
+	// PredicateOrder are the predicates whose data is processed first, in the order of the
+	// list. By default, the data is processed in the order it is read. It can't be used with
+	// PriorityNamespaces nor CheckpointFile. See restore_predicate_order.go.
+	PredicateOrder []string
+	// predicateOrder holds the rank of each predicate of PredicateOrder. It is set by
+	// validate.
+	predicateOrder map[string]int
+
+	// ProcessBufSize is the size of the buffer each processing goroutine maps the KVs into and
+	// ProcessFlushSize is the size at which that buffer is handed over for merging. The flush
+	// size should stay a bit below the buffer size, so the buffer doesn't have to grow while
+	// mapping the last KV list. Each merging goroutine accumulates the handed over buffers
+	// until it has mapFileSz/4 to mapFileSz worth of data before writing out a map file, so
+	// these only change how often the merging happens and not the size of the map files.
+	// The memory used by the map phase is roughly:
+	//   numGo * ProcessBufSize            (buffers being filled by the processors)
+	// + numGo * ProcessFlushSize          (buffers queued up in writeCh)
+	// + numGo/2 * mapFileSz               (buffers being merged, these are file backed)
+	// Zero values default to defaultProcessBufSize and defaultProcessFlushSize.
+	ProcessBufSize   int
+	ProcessFlushSize int
+
+	// inventory turns the mapper into the counting only mode used by Inventory. No map files
+	// are written in this mode.
+	inventory *inventory
+
+	// VerifyStore reduces the map files into a throwaway badger store once they are written,
+	// and checks that the store can be reopened and holds all the keys written. The store is
+	// removed afterwards, while the map files are kept. This exercises the write path of the
+	// restore without touching the store of the cluster. The map phase fails if the
+	// verification fails. It is only supported for a local map directory.
+	VerifyStore bool
+
+	// WriteMapManifest writes a manifest listing all the map files written, named
+	// map_manifest.json, to the map directory at the end of the map phase. It allows the reduce
+	// phase to check that no map file is missing when the map directory can't be listed. The
+	// checksums of the map files are only included if VerifyChecksums is set. The manifest
+	// is not written if the map phase fails.
+	WriteMapManifest bool
+
+	// WriteSummary writes a report of the map phase, named restore-map-summary.json, to the
+	// map directory once the map phase succeeds. It records the request without its
+	// credentials, the manifests mapped, the drop operations found, the map files written,
+	// the duration and the concurrency of the map phase, and the version of the binary.
+	WriteSummary bool
+
+	// CheckpointFile is the path of a local file where the progress of the map phase is saved
+	// every second, and once the map phase ends. If the file exists when the map phase starts,
+	// the map phase resumes from it, keeping the map files recorded in it and removing the
+	// other map files from the map directory. The backup files which were completely mapped
+	// are skipped, and the others are resumed from the last frame whose entries made it to
+	// the map files. If the handler can seek the backup file, and the file is neither
+	// encrypted nor compressed, the mapping seeks to that frame. Otherwise, the file is read
+	// from the start, and the frames before that one are skipped without being mapped. The
+	// report of the predicates without data is not available on resume. It can't be used
+	// with CleanupOnError.
+	CheckpointFile string
+
+	// CleanupOnError removes all the map files created by the mapper if the map phase fails.
+	// Files in the map directory which were not created by the mapper are left untouched.
+	// It is not supported for a remote map directory, where only the map file being uploaded
+	// at the time of the failure is discarded.
+	CleanupOnError bool
+
+	// TargetSchemaFormat converts the names in the schema and type updates to the format used
+	// by an older release, to allow restoring into an older cluster. It can be 2103 for the
+	// <namespace 8 bytes><attribute> format or 2011 for the format without namespaces. The
+	// latter fails for the schema of any namespace other than the galaxy namespace. Zero
+	// keeps the current format.
+	TargetSchemaFormat int
+
+	// VerifyVersions checks that the version each entry is sorted by is below the restoreTs,
+	// and fails the map phase otherwise. This is a debugging aid to catch mis-transformed
+	// timestamps before they corrupt the latest-wins selection of the reduce phase. It must
+	// not be used with the offline restore, which uses a placeholder restoreTs of 1.
+	VerifyVersions bool
+
+	// PreserveVersions writes the KVs at their version in the backup, instead of at restoreTs.
+	// The entries of a key are still sorted by their version in the backup, and only the
+	// latest one is written by the reduce phase. The versions are clamped below restoreTs, so
+	// that the restored data is visible at restoreTs. It must not be used with the offline
+	// restore, which uses a placeholder restoreTs of 1.
+	PreserveVersions bool
+	// VersionOffset is added to the versions preserved with PreserveVersions. It shifts the
+	// logical clock of the restored data, for instance to make a staging cluster look like
+	// production as of a point in time. The same offset is added to all the versions, so it
+	// keeps their order, and the latest-wins selection of the reduce phase is unchanged, as
+	// it is done on the versions in the backup. The offset must keep the versions positive
+	// and below restoreTs. The ones which fall outside are clamped, and counted in the logs.
+	VersionOffset int64
+
+	// PredicateRestoreTs maps the names of predicates to the version their keys are written
+	// at, instead of restoreTs, in every namespace. It allows layering a corrected predicate
+	// over the existing data. The version of every entry of such a predicate in the backup
+	// must be below its override, or the map phase fails. The overrides can't be above
+	// restoreTs, and can't be used with PreserveVersions or with the offline restore.
+	PredicateRestoreTs map[string]uint64
+	// RestoreTsSets maps the backups once into a set of map files per timestamp, each one
+	// holding all the entries written at its timestamp instead of restoreTs. The sets are
+	// written under subdirectories named ts<timestamp> at the top of the map directory, and
+	// restoreTsMapFiles lists them, so that the data restored at two candidate timestamps can
+	// be compared without mapping the backups twice. Every entry is written once per set, so
+	// the map files take as many times the space. The timestamps can't be above restoreTs,
+	// and can't be used with PreserveVersions or PredicateRestoreTs.
+	RestoreTsSets []uint64
+
+	// VerifyChecksums verifies the checksum of every backup file read to completion against
+	// the one recorded in its manifest, and fails the map phase if they differ. The backups
+	// whose manifest doesn't record a checksum are not verified. The checksums of the map files
+	// are recorded in the manifest written with WriteMapManifest as well.
+	VerifyChecksums bool
+	// StrictBackupSettings fails the restore if a backup was taken by a binary whose settings
+	// differ from the ones of this binary, like the size above which the posting lists are
+	// split, or the encoding of their uids. By default, the differences are only logged. The
+	// backups which don't record their settings are not checked. See restore_settings.go.
+	StrictBackupSettings bool
+
+	// Logger logs the progress and the lifecycle events of the map phase as structured fields.
+	// It defaults to logging them as text with glog.
+	Logger MapLogger
+	// BufferAllocator creates the buffers the backup files are read into, and the ones the
+	// entries are mapped and merged into. It defaults to the allocator of z, whose memory
+	// buffers use jemalloc if the binary is built with it. It allows running the mapper with
+	// buffers allocated elsewhere, or instrumented ones. See restore_alloc.go for the
+	// ownership of the buffers.
+	BufferAllocator BufferAllocator
+
+	// MergeConcurrency is the number of goroutines merging the processed buffers into the
+	// buffers of the map files. WriteConcurrency is the number of map files which can be
+	// written at the same time. Both default to half the number of processing goroutines.
+	MergeConcurrency int
+	WriteConcurrency int
+	// Deterministic makes two runs over the same backups write byte-identical map files, with
+	// the same names, to check that a change doesn't alter the mapped output. The KV lists are
+	// mapped by a single goroutine in the order they are read, there is a single merger and
+	// writer, and a map file is only written once its buffer is full, or at the end. It is
+	// much slower, and meant for testing. It can't be used with FlushInterval, whose flushes
+	// depend on the timing, nor with PriorityNamespaces or more than one merger or writer.
+	Deterministic bool
+
+	// CheckSchemaNamespaces is a diagnostic which checks that the predicates of the schema
+	// updates are in the namespace of their keys after the conversion of the older formats,
+	// and that every predicate with data has a schema in the same namespace. The mismatches
+	// are logged.
+	CheckSchemaNamespaces bool
+	// StrictSchema fails the restore if the schema of a predicate has directives unknown to
+	// this binary, like a schema written by a newer version: values of its value type, its
+	// directive or its index rebuild hint which are not in their enumerations, tokenizers
+	// which are not registered, or fields which are not in pb.SchemaUpdate. They are logged
+	// otherwise, and the schema is restored as it is. The unknown fields are still dropped if
+	// the schema is rewritten, like with StripNamespaces or TargetSchemaFormat.
+	StrictSchema bool
+
+	// CollectNamespaces collects the distinct namespaces of the keys read from the backups,
+	// which might be sparse unlike the range up to the max namespace, and reports them at the
+	// end of the map phase. The keys of the dropped namespaces are counted too, as they are
+	// present in the backups. They can't be collected when the map phase is resumed from
+	// CheckpointFile, since the keys mapped before the resume are not read again.
+	CollectNamespaces bool
+	// FailOnPredicateMismatch fails the map phase if a predicate has data but no schema, or a
+	// schema but no data. The mismatches are reported in any case.
+	FailOnPredicateMismatch bool
+
+	// TypeInference maps the names of predicates to the names of the scalar types, like "int"
+	// or "string", to set in their schema. It is meant for migrating untyped predicates to
+	// typed ones. Only the schema of the predicates without a type is changed, unless
+	// ForceTypeInference is set. The values in the backup are not converted.
+	TypeInference      map[string]string
+	ForceTypeInference bool
+	// inferredTypes holds the types of TypeInference. It is set by validate.
+	inferredTypes map[string]types.TypeID
+
+	// IndexRebuildHints maps the names of predicates to "eager" or "lazy", to tell the process
+	// rebuilding the indexes after the restore whether the indexes of the predicate must be
+	// rebuilt before the restore completes, or can be rebuilt in the background. The hint is
+	// recorded in the IndexRebuild field of the schema of the predicate, in every namespace.
+	// The schema of the other predicates is left untouched.
+	IndexRebuildHints map[string]string
+	// indexRebuild holds the hints of IndexRebuildHints. It is set by validate.
+	indexRebuild map[string]pb.SchemaUpdate_IndexRebuild
+
+	// IncludeTypes are the names of the types whose definitions are restored, in every
+	// namespace, and ExcludeTypes are the ones which are skipped. All the types are restored
+	// if IncludeTypes is empty. A type can't be in both lists. They only filter the type keys
+	// which would be mapped otherwise, so they can't bring back the types of the older backups
+	// when keepSchema only maps the schema of the latest one. The schema of the predicates
+	// and their data are not affected, so a predicate stays even if all its types are skipped.
+	IncludeTypes []string
+	ExcludeTypes []string
+	// includeTypes and excludeTypes hold the names of IncludeTypes and ExcludeTypes. They are
+	// set by validate.
+	includeTypes map[string]struct{}
+	excludeTypes map[string]struct{}
+
+	// PredicateBloomFilter checks the predicate of every key against a bloom filter of the
+	// predicates to restore before looking it up in their set, so that the keys of the
+	// predicates which are filtered out, like the dropped ones, mostly skip the lookup. It
+	// never skips a key which would be kept otherwise. It only pays off with tens of thousands
+	// of predicates, when the bits of the filter stay in the CPU cache while the set doesn't.
+	PredicateBloomFilter bool
+
+	// CheckPartitions reads the headers of all the map files once they are written, and logs a
+	// summary of their partition keys, including the files whose keys are out of order and the
+	// max number of files overlapping at the same key. This is a debugging aid.
+	CheckPartitions bool
+	// VerifySortOrder checks that the entries of every map file are ordered once they are
+	// sorted, and fails the map phase with the first adjacent keys out of order. The partition
+	// keys of the map files are only valid if they are. This is a debugging aid, as it costs
+	// another pass over the entries.
+	VerifySortOrder bool
+
+	// DiskWatermarkBytes pauses the writing of the map files while the free space on the file
+	// system of the map directory is below it, instead of failing once the disk is full. This
+	// is only useful if the map files are being consumed concurrently. Zero disables it. It is
+	// only supported on Linux.
+	DiskWatermarkBytes uint64
+	// DiskWatermarkTimeout is how long the writes stay paused before the map phase fails. It
+	// defaults to defaultDiskWatermarkTimeout.
+	DiskWatermarkTimeout time.Duration
+
+	// IgnoreDropOperations ignores the drop operations of the manifests, so that the restore
+	// holds the raw contents of the backups, including the data dropped after they were
+	// taken, like for a legal hold. The manifests before a drop all are mapped, no namespace
+	// is banned, and the predicates and the schema of every manifest are mapped, instead of
+	// only the ones of the latest manifest. This brings back deleted data, so it must only
+	// be set on purpose.
+	IgnoreDropOperations bool
+	// ExtraLocations are the locations holding more backups of the series being restored,
+	// in addition to the location in the restore request. The manifests from all the
+	// locations are merged into a single chain. A manifest number of a series must not be
+	// present in more than one location.
+	ExtraLocations []string
+	// ManifestVerifier, if set, verifies every manifest to restore before anything is mapped,
+	// like with the signatures checked by NewEd25519ManifestVerifier. The restore fails on
+	// the first manifest which fails the verification, so that a tampered manifest can't
+	// change the drop operations or the predicates restored.
+	ManifestVerifier ManifestVerifier
+	// ManifestOverride is the path of a local JSON file describing the backups to restore,
+	// which is used instead of the manifests of the location, like when the manifest of the
+	// location is corrupt but its backup files are intact. The file has the layout of the
+	// master manifest, {"manifests": [...]}, ordered from the oldest to the latest, and each
+	// manifest may record the sizes of its backup files in "file_sizes", by group. The backup
+	// files of every manifest must be present, with these sizes, or nothing is mapped. See
+	// restore_manifest_override.go.
+	ManifestOverride string
+
+	// KmsProvider, if set, decrypts the data keys recorded in the manifests of the backups
+	// whose key is managed by a KMS, like AWS KMS or GCP Cloud KMS. The data key of such a
+	// backup is used instead of the key of the encryption config. The backups without a data
+	// key still use the key of the encryption config. KmsKeyId is the ARN or the resource
+	// name of the KMS key, which overrides the one recorded in the manifests. The KMS is called
+	// once per distinct data key for the whole restore, before anything is mapped.
+	KmsProvider KmsProvider
+	KmsKeyId    string
+
+	// OnProgress, if set, is called with the progress of the map phase once every second, and
+	// a final time once the map phase is over. NewProgressStream adapts it to a gRPC stream.
+	OnProgress func(*MapProgress)
+	// HeartbeatFile, if set, is replaced every second with the time, the elapsed time, the
+	// bytes read and processed, and the id of the last map file, as JSON, so that a watchdog
+	// which can only observe the filesystem can tell the restore is alive. It is a file name
+	// in the map directory, prefixed with the request id like the other files written there,
+	// or an absolute local path. It is written atomically, and done is set in the last one. A
+	// failure to write it is only logged.
+	HeartbeatFile string
+	// StatsFile, if set, is appended a row every second with the time, the bytes read and
+	// processed, the processing rate, the number of busy writers and the bytes allocated by z,
+	// and a last one once the map phase is over, to plot the performance of the restore
+	// offline. It is a file name in the map directory, prefixed with the request id, or an
+	// absolute local path. Every row is flushed as it is written. A failure to write it is only
+	// logged, and stops the rows. StatsFormat is the format of the rows, "csv", with a header,
+	// or "jsonl". It defaults to "csv".
+	StatsFile   string
+	StatsFormat string
+
+	// Sink, if set, receives the map entries as they are processed, in addition to the map
+	// files. It sees the entries of the frames which are mapped again when resuming from a
+	// checkpoint.
+	Sink MapSink
+
+	// SchemaOnly maps only the schema and type keys, skipping all the data. The predicates
+	// are filtered the same way as for a full restore.
+	SchemaOnly bool
+
+	// SkipReverseEdges drops the reverse edges, which can be derived from the forward edges.
+	// The schema keeps the @reverse directive. The cluster doesn't rebuild the reverse edges
+	// on its own, so they must be rebuilt after the restore by removing the @reverse directive
+	// from the schema and adding it back. The queries on the reverse edges return no results
+	// until then.
+	SkipReverseEdges bool
+
+	// RedactValues replaces the scalar values with placeholders, to restore the shape of the
+	// graph without its data. The value postings get a placeholder of the same type, and the
+	// strings, passwords and binary values keep the order of magnitude of their length. The
+	// uid postings, and so the edges, the reverse edges and the count indices, are kept as
+	// they are. The facets of all the postings are removed. The index keys are not restored,
+	// and the index directives are removed from the schema so that they are consistent.
+	RedactValues bool
+	// StripFacets removes the facets of all the postings. The edges and the values are kept.
+	// The schema has no facet directives and facets are not indexed, so nothing else has to
+	// change.
+	StripFacets bool
+
+	// ParallelRollupThreshold is the size in bytes of a complete posting list in the backup,
+	// above which the list is split into parts using multiple goroutines. The resulting parts
+	// are identical to the ones produced by a single goroutine. Zero disables it.
+	ParallelRollupThreshold int
+
+	// MaxPostingListBytes, if set, lowers the size in bytes above which a complete posting
+	// list is split into a multi-part list, the same way the cluster splits its lists. The
+	// parts are written at the restore timestamp. It is a split threshold, not a cap on the
+	// size of the restored values: a list or a part holding a single uid can't be split, and
+	// is written whole however big it is, and the parts of the old backups which are already
+	// split are written as they are, with the sizes of the source cluster.
+	MaxPostingListBytes int
+	// PostingSplitSize, if set, replaces the size in bytes above which this binary splits a
+	// complete posting list into a multi-part list, so that the restored lists match the
+	// split size of the target cluster when it differs from the one of this binary. Unlike
+	// MaxPostingListBytes, it can raise the size as well as lower it, and MaxPostingListBytes
+	// still lowers it further if it is set. As with posting.ShouldSplit, a list is split once
+	// its size reaches PostingSplitSize, unless it holds a single uid, and the parts are split
+	// until they are below it. The lists of the old backups which are already split into
+	// parts are written as they are, with the parts of the source cluster. The target cluster
+	// splits its lists with its own size on their next rollup, but never merges the parts of
+	// a list which was split with a smaller size.
+	PostingSplitSize int
+	// MaxOutputBytes, if set, aborts the map phase once the entries it outputs add up to more
+	// bytes than that, like when a corrupt backup holds far more data than expected.
+	MaxOutputBytes int64
+
+	// KeepEmptyRollups writes an empty posting list for the complete lists whose rollup
+	// returns no KV, so that their keys are still present in the restored store. Otherwise,
+	// they are skipped. Either way, they are counted and logged at the end of the map phase.
+	KeepEmptyRollups bool
+
+	// DedupLatest only writes the latest version of every key to the map files, dropping the
+	// older versions of the keys rewritten by the incremental backups when the entries are
+	// sorted, for a map output meant to be used without the reduce phase. The versions of a key
+	// which end up in different map files are all written, and left to the reduce phase. The
+	// parts of a multi-part list are keys of their own, so they are all kept, but the parts of
+	// an older version of a list are too, even if its latest version is not split. It can't be
+	// used with PreserveVersions or RestoreTsSets, which keep several versions of a key on
+	// purpose, nor with a KeyComparator, which might not order the latest version first.
+	DedupLatest bool
+
+	// SampleRate, if set, is the fraction of the posting lists mapped, in (0, 1]. A posting
+	// list is kept depending on the hash of its key, so that the same keys are kept in every
+	// run. The schema and type keys are all kept. This is meant for quick smoke tests of a
+	// restore against a huge backup, as the restored data is incomplete.
+	SampleRate float64
+
+	// StripNamespaces restores the keys of all the namespaces into the galaxy namespace, to
+	// flatten a multi-tenant backup. It fails if a predicate or a type is found in more than one
+	// namespace, unless MergeNamespaceCollisions is set, in which case their keys are restored
+	// together. The reserved predicates and types are always merged. The schema keys of the
+	// dropped namespaces are skipped along with their data.
+	StripNamespaces          bool
+	MergeNamespaceCollisions bool
+
+	// KeyComparator, if set, replaces y.CompareKeys as the order of the entries of the map
+	// files, and so of their partition keys. It returns a negative number, zero or a positive
+	// number if a is less than, equal to or greater than b, like y.CompareKeys. This is an
+	// experimental knob for custom storage layouts. The reduce phase must read the map files
+	// with the same order, which the reducer of this package does not do, as it always uses
+	// y.CompareKeys.
+	KeyComparator func(a, b []byte) int
+}
+
+const (
+	defaultProcessBufSize       = 256 << 20
+	defaultProcessFlushSize     = 228 << 20
+	defaultDiskWatermarkTimeout = 30 * time.Minute
+	defaultSyncBatchSize        = 16
+)
+
+// The values of MapOptions.SyncMode.
+const (
+	SyncAlways = "always"
+	SyncBatch  = "batch"
+	SyncNone   = "none"
+)
+
+// validate fills in the defaults for the options which are not set and checks that the options
+// are consistent with each other.
+func (opts *MapOptions) validate() error {
+	if opts.ProcessBufSize == 0 {
+		opts.ProcessBufSize = defaultProcessBufSize
+	}
+	if opts.ProcessFlushSize == 0 {
+		opts.ProcessFlushSize = defaultProcessFlushSize
+	}
+	if opts.DiskWatermarkTimeout == 0 {
+		opts.DiskWatermarkTimeout = defaultDiskWatermarkTimeout
+	}
+	if opts.MergeConcurrency < 0 || opts.WriteConcurrency < 0 {
+		return errors.Errorf("MergeConcurrency: %d and WriteConcurrency: %d can't be negative",
+			opts.MergeConcurrency, opts.WriteConcurrency)
+	}
+	if opts.Deterministic {
+		switch {
+		case opts.FlushInterval > 0:
+			return errors.New("FlushInterval can't be used with Deterministic")
+		case len(opts.PriorityNamespaces) > 0:
+			return errors.New("PriorityNamespaces can't be used with Deterministic")
+		case opts.MergeConcurrency > 1 || opts.WriteConcurrency > 1:
+			return errors.Errorf("MergeConcurrency: %d and WriteConcurrency: %d must be at most"+
+				" one with Deterministic", opts.MergeConcurrency, opts.WriteConcurrency)
+		}
+		opts.MergeConcurrency, opts.WriteConcurrency = 1, 1
+	}
+	switch {
+	case opts.PartitionBufSize < 0:
+		return errors.Errorf("PartitionBufSize: %d can't be negative", opts.PartitionBufSize)
+	case opts.PartitionBufSize == 0:
+		opts.PartitionBufSize = partitionBufSz
+	case opts.PartitionBufSize < minPartitionBufSz:
+		glog.Warningf("PartitionBufSize: %s is so small that nearly every key might become a"+
+			" partition key, which bloats the headers of the map files",
+			humanize.IBytes(uint64(opts.PartitionBufSize)))
+	}
+	if opts.LargeValueThreshold < 0 {
+		return errors.Errorf("LargeValueThreshold: %d can't be negative",
+			opts.LargeValueThreshold)
+	}
+	if opts.LargeValueThreshold == 0 {
+		opts.LargeValueThreshold = defaultLargeValueThreshold
+	}
+	if opts.ProcessBufSize < 0 || opts.ProcessFlushSize < 0 {
+		return errors.Errorf("ProcessBufSize: %d and ProcessFlushSize: %d can't be negative",
+			opts.ProcessBufSize, opts.ProcessFlushSize)
+	}
+	if opts.SampleRate < 0 || opts.SampleRate > 1 || math.IsNaN(opts.SampleRate) {
+		return errors.Errorf("SampleRate: %v is not in (0, 1]", opts.SampleRate)
+	}
+	if opts.KeyComparator != nil && opts.VerifyStore {
+		return errors.New("KeyComparator can't be used with VerifyStore, as the reducer" +
+			" always orders the keys with y.CompareKeys")
+	}
+	if opts.ManifestOverride != "" && len(opts.ExtraLocations) > 0 {
+		return errors.New("ManifestOverride can't be used with ExtraLocations")
+	}
+	if opts.ManifestOverride != "" && opts.ManifestVerifier != nil {
+		return errors.New("ManifestOverride can't be used with ManifestVerifier, as the" +
+			" manifests of the override are not signed")
+	}
+	if opts.MergeNamespaceCollisions && !opts.StripNamespaces {
+		return errors.New("MergeNamespaceCollisions requires StripNamespaces")
+	}
+	switch opts.StatsFormat {
+	case "":
+		if opts.StatsFile != "" {
+			opts.StatsFormat = statsFormatCSV
+		}
+	case statsFormatCSV, statsFormatJSONL:
+		if opts.StatsFile == "" {
+			return errors.New("StatsFormat requires StatsFile")
+		}
+	default:
+		return errors.Errorf("StatsFormat: %q is not supported. Use %q or %q",
+			opts.StatsFormat, statsFormatCSV, statsFormatJSONL)
+	}
+	switch opts.MapFileFormat {
+	case "":
+		opts.MapFileFormat = mapFormatNative
+	case mapFormatNative:
+	case mapFormatSST:
+		if opts.MapFileCompression == mapCodecGzip {
+			return errors.New("gzip MapFileCompression can't be used with sst MapFileFormat")
+		}
+		if opts.KeyComparator != nil {
+			return errors.New("KeyComparator can't be used with sst MapFileFormat, as the" +
+				" SST files are ordered with the bytewise comparator")
+		}
+		if opts.VerifyStore {
+			return errors.New("VerifyStore can't be used with sst MapFileFormat, as the" +
+				" reduce phase can't read the SST files")
+		}
+	default:
+		return errors.Errorf("MapFileFormat: %q is not supported. Use %q or %q",
+			opts.MapFileFormat, mapFormatNative, mapFormatSST)
+	}
+	switch opts.TargetPostingFormat {
+	case "", postingFormatRoaring:
+	default:
+		return errors.Errorf("TargetPostingFormat: %q is not supported. Use %q",
+			opts.TargetPostingFormat, postingFormatRoaring)
+	}
+	switch opts.MapFileCompression {
+	case "":
+		opts.MapFileCompression = mapCodecSnappy
+	case mapCodecSnappy, mapCodecGzip:
+	default:
+		return errors.Errorf("MapFileCompression: %q is not supported. Use %q or %q",
+			opts.MapFileCompression, mapCodecSnappy, mapCodecGzip)
+	}
+	if opts.MapFileCompression == mapCodecGzip {
+		if opts.UncompressedMapFiles {
+			return errors.New("UncompressedMapFiles can't be used with gzip map files")
+		}
+		if opts.MapFileGzipLevel == 0 {
+			opts.MapFileGzipLevel = gzip.DefaultCompression
+		}
+		if opts.MapFileGzipLevel < gzip.HuffmanOnly ||
+			opts.MapFileGzipLevel > gzip.BestCompression {
+			return errors.Errorf("MapFileGzipLevel: %d is not a valid gzip level",
+				opts.MapFileGzipLevel)
+		}
+	} else if opts.MapFileGzipLevel != 0 {
+		return errors.New("MapFileGzipLevel requires gzip MapFileCompression")
+	}
+	if opts.DedupLatest {
+		switch {
+		case opts.PreserveVersions:
+			return errors.New("DedupLatest can't be used with PreserveVersions")
+		case len(opts.RestoreTsSets) > 0:
+			return errors.New("DedupLatest can't be used with RestoreTsSets")
+		case opts.KeyComparator != nil:
+			return errors.New("DedupLatest can't be used with KeyComparator")
+		}
+	}
+	if opts.MaxOutputBytes < 0 {
+		return errors.Errorf("MaxOutputBytes: %d can't be negative", opts.MaxOutputBytes)
+	}
+	if opts.PostingSplitSize < 0 {
+		return errors.Errorf("PostingSplitSize: %d can't be negative", opts.PostingSplitSize)
+	}
+	if opts.MaxPostingListBytes < 0 {
+		return errors.Errorf("MaxPostingListBytes: %d can't be negative",
+			opts.MaxPostingListBytes)
+	}
+	switch opts.TargetSchemaFormat {
+	case 0, schemaFormat2011, schemaFormat2103:
+	default:
+		return errors.Errorf("TargetSchemaFormat: %d is not supported. Use %d or %d",
+			opts.TargetSchemaFormat, schemaFormat2011, schemaFormat2103)
+	}
+	if len(opts.TypeInference) > 0 {
+		opts.inferredTypes = make(map[string]types.TypeID, len(opts.TypeInference))
+		for pred, name := range opts.TypeInference {
+			typ, ok := types.TypeForName(name)
+			if !ok || !typ.IsScalar() {
+				return errors.Errorf("TypeInference: %q is not a scalar type for predicate: %s",
+					name, pred)
+			}
+			opts.inferredTypes[pred] = typ
+		}
+	}
+	if len(opts.IndexRebuildHints) > 0 {
+		opts.indexRebuild = make(map[string]pb.SchemaUpdate_IndexRebuild,
+			len(opts.IndexRebuildHints))
+		for pred, hint := range opts.IndexRebuildHints {
+			switch strings.ToLower(hint) {
+			case "eager":
+				opts.indexRebuild[pred] = pb.SchemaUpdate_EAGER
+			case "lazy":
+				opts.indexRebuild[pred] = pb.SchemaUpdate_LAZY
+			default:
+				return errors.Errorf("IndexRebuildHints: %q is not eager or lazy for"+
+					" predicate: %s", hint, pred)
+			}
+		}
+	}
+	if len(opts.PredicateOrder) > 0 {
+		if len(opts.PriorityNamespaces) > 0 {
+			return errors.New("PredicateOrder can't be used with PriorityNamespaces")
+		}
+		if opts.CheckpointFile != "" {
+			return errors.New("PredicateOrder can't be used with CheckpointFile")
+		}
+		opts.predicateOrder = make(map[string]int, len(opts.PredicateOrder))
+		for i, pred := range opts.PredicateOrder {
+			if pred == "" {
+				return errors.New("PredicateOrder: the name of a predicate can't be empty")
+			}
+			if _, ok := opts.predicateOrder[pred]; ok {
+				return errors.Errorf("PredicateOrder: predicate: %s is listed twice", pred)
+			}
+			opts.predicateOrder[pred] = i
+		}
+	}
+	if len(opts.IncludeTypes) > 0 {
+		opts.includeTypes = make(map[string]struct{}, len(opts.IncludeTypes))
+		for _, typ := range opts.IncludeTypes {
+			if typ == "" {
+				return errors.New("IncludeTypes: the name of a type can't be empty")
+			}
+			opts.includeTypes[typ] = struct{}{}
+		}
+	}
+	if len(opts.ExcludeTypes) > 0 {
+		opts.excludeTypes = make(map[string]struct{}, len(opts.ExcludeTypes))
+		for _, typ := range opts.ExcludeTypes {
+			if _, ok := opts.includeTypes[typ]; ok {
+				return errors.Errorf("type: %s can't be in both IncludeTypes and ExcludeTypes",
+					typ)
+			}
+			opts.excludeTypes[typ] = struct{}{}
+		}
+	}
+	for gid, comp := range opts.CompressionOverrides {
+		if !isSupportedCompression(comp) {
+			return errors.Errorf("CompressionOverrides: unknown compression: %q for group: %d",
+				comp, gid)
+		}
+	}
+	if opts.VersionOffset != 0 && !opts.PreserveVersions {
+		return errors.New("VersionOffset can only be used with PreserveVersions")
+	}
+	for pred, ts := range opts.PredicateRestoreTs {
+		if ts == 0 {
+			return errors.Errorf("PredicateRestoreTs: the version of predicate: %s must be"+
+				" positive", pred)
+		}
+	}
+	if len(opts.PredicateRestoreTs) > 0 && opts.PreserveVersions {
+		return errors.New("PredicateRestoreTs can't be used with PreserveVersions")
+	}
+	if len(opts.RestoreTsSets) > 0 {
+		if opts.PreserveVersions || len(opts.PredicateRestoreTs) > 0 {
+			return errors.New("RestoreTsSets can't be used with PreserveVersions or" +
+				" PredicateRestoreTs")
+		}
+		seen := make(map[uint64]struct{})
+		for _, ts := range opts.RestoreTsSets {
+			if ts == 0 {
+				return errors.New("RestoreTsSets: the timestamps must be positive")
+			}
+			if _, ok := seen[ts]; ok {
+				return errors.Errorf("RestoreTsSets: timestamp: %d is repeated", ts)
+			}
+			seen[ts] = struct{}{}
+		}
+	}
+	if opts.FailOnPredicateMismatch && opts.SchemaOnly {
+		return errors.New("FailOnPredicateMismatch can't be used with SchemaOnly")
+	}
+	switch opts.SyncMode {
+	case "":
+		opts.SyncMode = SyncAlways
+	case SyncAlways, SyncNone:
+	case SyncBatch:
+		if opts.SyncBatchSize == 0 {
+			opts.SyncBatchSize = defaultSyncBatchSize
+		}
+	default:
+		return errors.Errorf("SyncMode: %q is not supported. Use %q, %q or %q", opts.SyncMode,
+			SyncAlways, SyncBatch, SyncNone)
+	}
+	if opts.SyncBatchSize < 0 {
+		return errors.Errorf("SyncBatchSize: %d can't be negative", opts.SyncBatchSize)
+	}
+	if opts.CheckpointFile != "" && opts.SyncMode != SyncAlways {
+		// A checkpoint must only record the map files which are on disk.
+		return errors.Errorf("SyncMode: %q can't be used with CheckpointFile", opts.SyncMode)
+	}
+	if opts.WindowTo > 0 && (opts.WindowFrom == 0 || opts.WindowFrom > opts.WindowTo) {
+		return errors.Errorf("WindowFrom: %d and WindowTo: %d are not a valid window of"+
+			" manifests", opts.WindowFrom, opts.WindowTo)
+	}
+	if opts.CleanMapDir && opts.FailIfMapDirNonEmpty {
+		return errors.New("CleanMapDir and FailIfMapDirNonEmpty can't be used together")
+	}
+	if opts.CheckpointFile != "" && opts.CleanupOnError {
+		return errors.New("CheckpointFile can't be used with CleanupOnError")
+	}
+	if opts.ProcessFlushSize > opts.ProcessBufSize {
+		return errors.Errorf("ProcessFlushSize: %d must not be larger than ProcessBufSize: %d",
+			opts.ProcessFlushSize, opts.ProcessBufSize)
+	}
+	return nil
+}
+
+// mapOption is an option of the map phase which can be set as a key=value pair of the superflag
+// parsed by ParseMapOptions.
+type mapOption struct {
+	name string
+	help string
+	set  func(opts *MapOptions, val string) error
+}
+
+// mapOptionList is the list of the options of the map phase which can be set by a superflag.
+// The hooks of MapOptions, like Logger or Sink, can only be set by the callers of RunMapper.
+var mapOptionList = []mapOption{
+	{"per-manifest-subdirs", "Write the map files of each manifest into their own subdirectory.",
+		boolOption(func(o *MapOptions) *bool { return &o.PerManifestSubdirs })},
+	{"per-namespace-subdirs", "Write the entries of each namespace into their own map files.",
+		boolOption(func(o *MapOptions) *bool { return &o.PerNamespaceSubdirs })},
+	{"split-large-values", "Write the large entries into their own map files.",
+		boolOption(func(o *MapOptions) *bool { return &o.SplitLargeValues })},
+	{"large-value-threshold", "The size in bytes above which an entry is large.",
+		intOption(func(o *MapOptions) *int { return &o.LargeValueThreshold })},
+	{"skip-bad-keys", "Skip the keys which can't be parsed instead of failing.",
+		boolOption(func(o *MapOptions) *bool { return &o.SkipBadKeys })},
+	{"skip-unreadable-files", "Skip the backup files which can't be opened instead of failing.",
+		boolOption(func(o *MapOptions) *bool { return &o.SkipUnreadableFiles })},
+	{"max-allowed-uid", "The largest uid a restored key can have. Zero means no limit.",
+		uint64Option(func(o *MapOptions) *uint64 { return &o.MaxAllowedUid })},
+	{"sniff-compression", "Use the compression found in the backup files over the manifests.",
+		boolOption(func(o *MapOptions) *bool { return &o.SniffCompression })},
+	{"max-map-duration", "The max time the map phase can take. Zero means no limit.",
+		durationOption(func(o *MapOptions) *time.Duration { return &o.MaxMapDuration })},
+	{"merge-all-groups", "Map the backup files of all the groups into this group.",
+		boolOption(func(o *MapOptions) *bool { return &o.MergeAllGroups })},
+	{"uncompressed-map-files", "Write the map files without compressing their contents.",
+		boolOption(func(o *MapOptions) *bool { return &o.UncompressedMapFiles })},
+	{"map-file-compression", "The codec of the map files, snappy or gzip.",
+		stringOption(func(o *MapOptions) *string { return &o.MapFileCompression })},
+	{"map-file-gzip-level", "The level of gzip with map-file-compression=gzip.",
+		intOption(func(o *MapOptions) *int { return &o.MapFileGzipLevel })},
+	{"mmap-map-files", "Write the local map files through a memory mapping.",
+		boolOption(func(o *MapOptions) *bool { return &o.MmapMapFiles })},
+	{"map-file-format", "The format of the map files, native or sst.",
+		stringOption(func(o *MapOptions) *string { return &o.MapFileFormat })},
+	{"target-posting-format", "Re-encode the uids of the posting lists, with roaring.",
+		stringOption(func(o *MapOptions) *string { return &o.TargetPostingFormat })},
+	{"partition-buf-size", "The bytes of entries between two partition keys of a map file.",
+		intOption(func(o *MapOptions) *int { return &o.PartitionBufSize })},
+	{"sync-mode", "When the map files are synced to disk: always, batch or none.",
+		stringOption(func(o *MapOptions) *string { return &o.SyncMode })},
+	{"sync-batch-size", "The number of map files synced at a time with sync-mode=batch.",
+		intOption(func(o *MapOptions) *int { return &o.SyncBatchSize })},
+	{"clean-map-dir", "Remove the map files left by a previous run before mapping.",
+		boolOption(func(o *MapOptions) *bool { return &o.CleanMapDir })},
+	{"fail-if-map-dir-non-empty", "Fail if the map directory holds map files.",
+		boolOption(func(o *MapOptions) *bool { return &o.FailIfMapDirNonEmpty })},
+	{"window-from", "The number of the first backup of the window of changes to map.",
+		uint64Option(func(o *MapOptions) *uint64 { return &o.WindowFrom })},
+	{"window-to", "The number of the last backup of the window of changes to map.",
+		uint64Option(func(o *MapOptions) *uint64 { return &o.WindowTo })},
+	{"flush-interval", "The max time the mapped entries are held in memory.",
+		durationOption(func(o *MapOptions) *time.Duration { return &o.FlushInterval })},
+	{"priority-namespaces", "The comma separated namespaces whose data is mapped first.",
+		uint64sOption(func(o *MapOptions) *[]uint64 { return &o.PriorityNamespaces })},
+	{"predicate-order", "The comma separated predicates whose data is processed first.",
+		stringsOption(func(o *MapOptions) *[]string { return &o.PredicateOrder })},
+	{"process-buf-size", "The size of the buffer of each processing goroutine.",
+		intOption(func(o *MapOptions) *int { return &o.ProcessBufSize })},
+	{"process-flush-size", "The size at which a processed buffer is handed over for merging.",
+		intOption(func(o *MapOptions) *int { return &o.ProcessFlushSize })},
+	{"verify-store", "Reduce the map files into a throwaway store to verify them.",
+		boolOption(func(o *MapOptions) *bool { return &o.VerifyStore })},
+	{"write-map-manifest", "Write the manifest of the map files to the map directory.",
+		boolOption(func(o *MapOptions) *bool { return &o.WriteMapManifest })},
+	{"write-summary", "Write a report of the map phase to the map directory.",
+		boolOption(func(o *MapOptions) *bool { return &o.WriteSummary })},
+	{"checkpoint-file", "The local file the progress is saved to, and resumed from.",
+		stringOption(func(o *MapOptions) *string { return &o.CheckpointFile })},
+	{"cleanup-on-error", "Remove the map files if the map phase fails.",
+		boolOption(func(o *MapOptions) *bool { return &o.CleanupOnError })},
+	{"target-schema-format", "Convert the schema to the format of 2103 or 2011.",
+		intOption(func(o *MapOptions) *int { return &o.TargetSchemaFormat })},
+	{"verify-versions", "Check that the version of every entry is below the restoreTs.",
+		boolOption(func(o *MapOptions) *bool { return &o.VerifyVersions })},
+	{"preserve-versions", "Write the entries at their version in the backup.",
+		boolOption(func(o *MapOptions) *bool { return &o.PreserveVersions })},
+	{"version-offset", "The offset added to the versions kept with preserve-versions.",
+		int64Option(func(o *MapOptions) *int64 { return &o.VersionOffset })},
+	{"predicate-restore-ts", "The comma separated predicate:ts versions of the predicates.",
+		uint64MapOption(func(o *MapOptions) *map[string]uint64 { return &o.PredicateRestoreTs })},
+	{"restore-ts-sets", "The comma separated timestamps to write a set of map files at.",
+		uint64sOption(func(o *MapOptions) *[]uint64 { return &o.RestoreTsSets })},
+	{"verify-checksums", "Verify the backup files against the checksums of the manifests.",
+		boolOption(func(o *MapOptions) *bool { return &o.VerifyChecksums })},
+	{"strict-backup-settings", "Fail if a backup was taken with other settings.",
+		boolOption(func(o *MapOptions) *bool { return &o.StrictBackupSettings })},
+	{"merge-concurrency", "The number of goroutines merging the processed buffers.",
+		intOption(func(o *MapOptions) *int { return &o.MergeConcurrency })},
+	{"write-concurrency", "The number of map files written at the same time.",
+		intOption(func(o *MapOptions) *int { return &o.WriteConcurrency })},
+	{"deterministic", "Write byte-identical map files across runs.",
+		boolOption(func(o *MapOptions) *bool { return &o.Deterministic })},
+	{"check-schema-namespaces", "Log the schema updates in the wrong namespace.",
+		boolOption(func(o *MapOptions) *bool { return &o.CheckSchemaNamespaces })},
+	{"strict-schema", "Fail if a schema has directives unknown to this binary.",
+		boolOption(func(o *MapOptions) *bool { return &o.StrictSchema })},
+	{"collect-namespaces", "Report the distinct namespaces of the keys read.",
+		boolOption(func(o *MapOptions) *bool { return &o.CollectNamespaces })},
+	{"fail-on-predicate-mismatch", "Fail if a predicate has data but no schema, or vice versa.",
+		boolOption(func(o *MapOptions) *bool { return &o.FailOnPredicateMismatch })},
+	{"type-inference", "The comma separated predicate:type types set in the schema.",
+		stringMapOption(func(o *MapOptions) *map[string]string { return &o.TypeInference })},
+	{"force-type-inference", "Set the types of type-inference on the typed predicates too.",
+		boolOption(func(o *MapOptions) *bool { return &o.ForceTypeInference })},
+	{"index-rebuild-hints", "The comma separated predicate:eager or predicate:lazy hints.",
+		stringMapOption(func(o *MapOptions) *map[string]string { return &o.IndexRebuildHints })},
+	{"include-types", "The comma separated types whose definitions are restored.",
+		stringsOption(func(o *MapOptions) *[]string { return &o.IncludeTypes })},
+	{"exclude-types", "The comma separated types whose definitions are skipped.",
+		stringsOption(func(o *MapOptions) *[]string { return &o.ExcludeTypes })},
+	{"predicate-bloom-filter", "Check the predicates of the keys against a bloom filter.",
+		boolOption(func(o *MapOptions) *bool { return &o.PredicateBloomFilter })},
+	{"check-partitions", "Log a summary of the partition keys of the map files.",
+		boolOption(func(o *MapOptions) *bool { return &o.CheckPartitions })},
+	{"verify-sort-order", "Check that the entries of every map file are ordered.",
+		boolOption(func(o *MapOptions) *bool { return &o.VerifySortOrder })},
+	{"disk-watermark-bytes", "Pause the writes while the free disk space is below it.",
+		uint64Option(func(o *MapOptions) *uint64 { return &o.DiskWatermarkBytes })},
+	{"disk-watermark-timeout", "How long the writes stay paused before failing.",
+		durationOption(func(o *MapOptions) *time.Duration { return &o.DiskWatermarkTimeout })},
+	{"ignore-drop-operations", "Ignore the drop operations of the manifests.",
+		boolOption(func(o *MapOptions) *bool { return &o.IgnoreDropOperations })},
+	{"extra-locations", "The comma separated locations holding more backups of the series.",
+		stringsOption(func(o *MapOptions) *[]string { return &o.ExtraLocations })},
+	{"manifest-verifier-key", "The PEM file of the Ed25519 key verifying the manifests.",
+		setManifestVerifierKey},
+	{"manifest-override", "The local JSON file describing the backups to restore.",
+		stringOption(func(o *MapOptions) *string { return &o.ManifestOverride })},
+	{"heartbeat-file", "The file replaced every second with the progress.",
+		stringOption(func(o *MapOptions) *string { return &o.HeartbeatFile })},
+	{"stats-file", "The file appended a row of stats every second.",
+		stringOption(func(o *MapOptions) *string { return &o.StatsFile })},
+	{"stats-format", "The format of the rows of stats-file, csv or jsonl.",
+		stringOption(func(o *MapOptions) *string { return &o.StatsFormat })},
+	{"skip-reverse-edges", "Drop the reverse edges.",
+		boolOption(func(o *MapOptions) *bool { return &o.SkipReverseEdges })},
+	{"redact-values", "Replace the scalar values with placeholders.",
+		boolOption(func(o *MapOptions) *bool { return &o.RedactValues })},
+	{"strip-facets", "Remove the facets of all the postings.",
+		boolOption(func(o *MapOptions) *bool { return &o.StripFacets })},
+	{"parallel-rollup-threshold", "The size above which a list is split by several goroutines.",
+		intOption(func(o *MapOptions) *int { return &o.ParallelRollupThreshold })},
+	{"max-posting-list-bytes", "Lower the size above which a posting list is split. Not a cap.",
+		intOption(func(o *MapOptions) *int { return &o.MaxPostingListBytes })},
+	{"posting-split-size", "Replace the size above which a posting list is split.",
+		intOption(func(o *MapOptions) *int { return &o.PostingSplitSize })},
+	{"max-output-bytes", "Abort once the entries output add up to more bytes than that.",
+		int64Option(func(o *MapOptions) *int64 { return &o.MaxOutputBytes })},
+	{"keep-empty-rollups", "Write an empty posting list for the lists which roll up empty.",
+		boolOption(func(o *MapOptions) *bool { return &o.KeepEmptyRollups })},
+	{"dedup-latest", "Only write the latest version of every key to the map files.",
+		boolOption(func(o *MapOptions) *bool { return &o.DedupLatest })},
+	{"strip-namespaces", "Restore the keys of all the namespaces into the galaxy namespace.",
+		boolOption(func(o *MapOptions) *bool { return &o.StripNamespaces })},
+	{"merge-namespace-collisions", "Merge the predicates found in several namespaces.",
+		boolOption(func(o *MapOptions) *bool { return &o.MergeNamespaceCollisions })},
+}
+
+// mapOptionsByName indexes mapOptionList by the names of the options.
+var mapOptionsByName = func() map[string]mapOption {
+	res := make(map[string]mapOption, len(mapOptionList))
+	for _, opt := range mapOptionList {
+		res[opt.name] = opt
+	}
+	return res
+}()
+
+// MapOptionsHelp returns the help of the superflag parsed by ParseMapOptions.
+func MapOptionsHelp() string {
+	help := z.NewSuperFlagHelp("").Head("Map options")
+	for _, opt := range mapOptionList {
+		help = help.Flag(opt.name, opt.help)
+	}
+	return help.String()
+}
+
+// localMapOptions are the options which name files on the host of the alpha, or remove them.
+// They are only set from the command line or by the callers of RunMapper: a restore request
+// must not read or write arbitrary files of the alphas.
+var localMapOptions = map[string]struct{}{
+	"checkpoint-file":       {},
+	"clean-map-dir":         {},
+	"extra-locations":       {},
+	"heartbeat-file":        {},
+	"manifest-override":     {},
+	"manifest-verifier-key": {},
+	"stats-file":            {},
+}
+
+// ParseMapOptions parses the options of the map phase from a superflag, like
+// "skip-bad-keys=true; write-summary=true". The lists are comma separated, and the entries of
+// the maps are key:value pairs. Unlike z.SuperFlag, an invalid flag is returned as an error,
+// as it may come from a restore request.
+func ParseMapOptions(flag string) (MapOptions, error) {
+	return parseMapOptions(flag, false)
+}
+
+// parseMapOptions parses the superflag of ParseMapOptions. The options of localMapOptions are
+// rejected if fromRequest is set.
+func parseMapOptions(flag string, fromRequest bool) (MapOptions, error) {
+	var opts MapOptions
+	seen := make(map[string]struct{})
+	for _, kv := range strings.Split(flag, ";") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		idx := strings.Index(kv, "=")
+		if idx < 0 {
+			return opts, errors.Errorf("map option: %q is not of the form key=value", kv)
+		}
+		name := strings.ToLower(strings.TrimSpace(kv[:idx]))
+		name = strings.ReplaceAll(name, "_", "-")
+		opt, ok := mapOptionsByName[name]
+		if !ok {
+			return opts, errors.Errorf("unknown map option: %q", name)
+		}
+		if _, ok := localMapOptions[name]; ok && fromRequest {
+			return opts, errors.Errorf("map option: %q can't be set by a restore request", name)
+		}
+		if _, ok := seen[name]; ok {
+			return opts, errors.Errorf("map option: %q is set more than once", name)
+		}
+		seen[name] = struct{}{}
+		if err := opt.set(&opts, strings.TrimSpace(kv[idx+1:])); err != nil {
+			return opts, errors.Wrapf(err, "invalid value of map option: %q", name)
+		}
+	}
+	return opts, nil
+}
+
+// MapOptionsFromRequest returns the options of the map phase set by the restore request, in
+// its map_options superflag and in its fields. The options of localMapOptions are rejected.
+func MapOptionsFromRequest(req *pb.RestoreRequest) (MapOptions, error) {
+	opts, err := parseMapOptions(req.MapOptions, true)
+	if err != nil {
+		return opts, errors.Wrapf(err, "while parsing the map options of the restore request")
+	}
+	opts.SchemaOnly = req.SchemaOnly
+	opts.CompressionOverrides = req.CompressionOverrides
+	opts.SampleRate = req.SampleRate
+	return opts, nil
+}
+
+// runRequestMapper maps the backups of the restore request to mapDir, with the options of the
+// map phase set by the request. The progress is streamed to the clients of the RestoreProgress
+// RPC watching the request id.
+func runRequestMapper(req *pb.RestoreRequest, mapDir string) (*mapResult, error) {
+	opts, err := MapOptionsFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if id := req.RequestId; id != "" {
+		opts.OnProgress = func(p *MapProgress) {
+			notifyRestoreProgress(id, p)
+		}
+		defer endRestoreProgress(id)
+	}
+	return RunMapper(req, mapDir, opts)
+}
+
+func setManifestVerifierKey(opts *MapOptions, val string) error {
+	pemKey, err := ioutil.ReadFile(val)
+	if err != nil {
+		return errors.Wrapf(err, "while reading the key of the manifest verifier")
+	}
+	opts.ManifestVerifier, err = NewEd25519ManifestVerifier(pemKey)
+	return err
+}
+
+func boolOption(field func(*MapOptions) *bool) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) (err error) {
+		*field(opts), err = strconv.ParseBool(val)
+		return err
+	}
+}
+
+func intOption(field func(*MapOptions) *int) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) (err error) {
+		*field(opts), err = strconv.Atoi(val)
+		return err
+	}
+}
+
+func int64Option(field func(*MapOptions) *int64) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) (err error) {
+		*field(opts), err = strconv.ParseInt(val, 0, 64)
+		return err
+	}
+}
+
+func uint64Option(field func(*MapOptions) *uint64) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) (err error) {
+		*field(opts), err = strconv.ParseUint(val, 0, 64)
+		return err
+	}
+}
+
+func durationOption(field func(*MapOptions) *time.Duration) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) (err error) {
+		*field(opts), err = time.ParseDuration(val)
+		return err
+	}
+}
+
+func stringOption(field func(*MapOptions) *string) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) error {
+		*field(opts) = val
+		return nil
+	}
+}
+
+// splitList splits a comma separated list, dropping the empty items.
+func splitList(val string) []string {
+	var res []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+func stringsOption(field func(*MapOptions) *[]string) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) error {
+		*field(opts) = splitList(val)
+		return nil
+	}
+}
+
+func uint64sOption(field func(*MapOptions) *[]uint64) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) error {
+		var res []uint64
+		for _, item := range splitList(val) {
+			n, err := strconv.ParseUint(item, 0, 64)
+			if err != nil {
+				return err
+			}
+			res = append(res, n)
+		}
+		*field(opts) = res
+		return nil
+	}
+}
+
+// splitMap splits a comma separated list of key:value pairs.
+func splitMap(val string, fn func(key, val string) error) error {
+	for _, item := range splitList(val) {
+		idx := strings.LastIndex(item, ":")
+		if idx <= 0 {
+			return errors.Errorf("%q is not of the form key:value", item)
+		}
+		if err := fn(strings.TrimSpace(item[:idx]), strings.TrimSpace(item[idx+1:])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stringMapOption(field func(*MapOptions) *map[string]string) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) error {
+		res := make(map[string]string)
+		if err := splitMap(val, func(k, v string) error {
+			res[k] = v
+			return nil
+		}); err != nil {
+			return err
+		}
+		*field(opts) = res
+		return nil
+	}
+}
+
+func uint64MapOption(field func(*MapOptions) *map[string]uint64) func(*MapOptions, string) error {
+	return func(opts *MapOptions, val string) error {
+		res := make(map[string]uint64)
+		if err := splitMap(val, func(k, v string) error {
+			n, err := strconv.ParseUint(v, 0, 64)
+			res[k] = n
+			return err
+		}); err != nil {
+			return err
+		}
+		*field(opts) = res
+		return nil
+	}
+}
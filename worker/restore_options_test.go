@@ -0,0 +1,184 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	bpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestParseMapOptions(t *testing.T) {
+	opts, err := ParseMapOptions("skip-bad-keys=true; MERGE_CONCURRENCY=8; max-map-duration=1m;" +
+		" priority-namespaces=1, 2; predicate-restore-ts=name:10,age:20;" +
+		" type-inference=name:string; include-types=Person,Film; version-offset=-5;" +
+		" stats-file=/tmp/stats.csv")
+	require.NoError(t, err)
+	require.Equal(t, MapOptions{
+		SkipBadKeys:        true,
+		MergeConcurrency:   8,
+		MaxMapDuration:     time.Minute,
+		PriorityNamespaces: []uint64{1, 2},
+		PredicateRestoreTs: map[string]uint64{"name": 10, "age": 20},
+		TypeInference:      map[string]string{"name": "string"},
+		IncludeTypes:       []string{"Person", "Film"},
+		VersionOffset:      -5,
+		StatsFile:          "/tmp/stats.csv",
+	}, opts)
+
+	opts, err = ParseMapOptions("")
+	require.NoError(t, err)
+	require.Equal(t, MapOptions{}, opts)
+
+	for flag, msg := range map[string]string{
+		"skip-bad-keys":                         "is not of the form key=value",
+		"skip-bad-keys=yes":                     `invalid value of map option: "skip-bad-keys"`,
+		"no-such-option=1":                      `unknown map option: "no-such-option"`,
+		"strip-facets=true; strip_facets=false": `"strip-facets" is set more than once`,
+		"type-inference=name":                   `"name" is not of the form key:value`,
+		"priority-namespaces=1,two":             `invalid value of map option: "priority-namespaces"`,
+	} {
+		_, err := ParseMapOptions(flag)
+		require.Error(t, err, flag)
+		require.Contains(t, err.Error(), msg, flag)
+	}
+
+	require.Contains(t, MapOptionsHelp(), "skip-bad-keys")
+}
+
+func TestMapOptionsFromRequest(t *testing.T) {
+	name := x.GalaxyAttr("name")
+	bad := nsEdgeKV(t, x.GalaxyNamespace, "name", 2)
+	bad.Key = []byte{0xff, 0xff, 0xff}
+	fixtures := []backupFixture{{
+		manifest: &Manifest{Type: "full", BackupNum: 1, ReadTs: 10, Path: "dgraph.1",
+			Compression: "snappy", Version: 2105, Groups: map[uint32][]string{1: {name}}},
+		kvs: []*bpb.KV{
+			nsEdgeKV(t, x.GalaxyNamespace, "name", 1),
+			bad,
+			nsEdgeKV(t, x.GalaxyNamespace, "name", 3),
+		},
+	}}
+
+	// The options of the request are applied by the group the restore is proposed to.
+	out := runRestoreRequest(t, fixtures, &pb.RestoreRequest{
+		MapOptions: "skip-bad-keys=true; write-summary=true"})
+	require.Equal(t, uint64(1), out.res.badKeys)
+	require.NotNil(t, out.summary)
+	require.Equal(t, map[string]struct{}{name + ":1": {}, name + ":3": {}}, out.mapped)
+
+	_, err := MapOptionsFromRequest(&pb.RestoreRequest{MapOptions: "skip-bad-keys"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "while parsing the map options of the restore request")
+
+	// The options naming files on the alpha can only be set from the command line.
+	for name := range localMapOptions {
+		_, err := MapOptionsFromRequest(&pb.RestoreRequest{MapOptions: name + "=/etc/passwd"})
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), "can't be set by a restore request", name)
+	}
+}
+
+// requestFixture returns a full backup of group 1 holding the schema of name and age, and
+// their posting lists for the uids.
+func requestFixture(t *testing.T, uids ...uint64) backupFixture {
+	name, age := x.GalaxyAttr("name"), x.GalaxyAttr("age")
+	kvs := []*bpb.KV{
+		schemaKV(t, x.GalaxyNamespace, "name"),
+		schemaKV(t, x.GalaxyNamespace, "age"),
+	}
+	for _, uid := range uids {
+		kvs = append(kvs, nsEdgeKV(t, x.GalaxyNamespace, "name", uid),
+			nsEdgeKV(t, x.GalaxyNamespace, "age", uid))
+	}
+	return backupFixture{
+		manifest: &Manifest{Type: "full", BackupNum: 1, ReadTs: 10, Path: "dgraph.1",
+			Compression: "snappy", Version: 2105, Groups: map[uint32][]string{1: {name, age}}},
+		kvs: kvs,
+	}
+}
+
+func TestRestoreSchemaOnly(t *testing.T) {
+	// Only the schema of the predicates to restore is mapped, without their data.
+	fixture := requestFixture(t, 1, 2)
+	name := x.GalaxyAttr("name")
+	fixture.manifest.Groups = map[uint32][]string{1: {name}}
+	out := runRestoreRequest(t, []backupFixture{fixture}, &pb.RestoreRequest{SchemaOnly: true})
+	require.Empty(t, out.mapped)
+	require.Equal(t, map[string]struct{}{name: {}}, out.schema)
+
+	out = runRestoreRequest(t, []backupFixture{fixture}, &pb.RestoreRequest{})
+	require.Len(t, out.mapped, 2)
+	require.Equal(t, map[string]struct{}{name: {}}, out.schema)
+}
+
+func TestRestoreRequestId(t *testing.T) {
+	// The id of the request, as set by the restore mutation, tags the map files.
+	out := runRestoreRequest(t, []backupFixture{requestFixture(t, 1, 2)},
+		&pb.RestoreRequest{RequestId: "tenant/1"})
+	require.NotEmpty(t, out.files)
+	for _, file := range out.files {
+		require.True(t, strings.HasPrefix(file, "tenant_1-"), file)
+	}
+	require.Len(t, out.mapped, 4)
+}
+
+func TestRestoreCompressionOverrides(t *testing.T) {
+	// The backup file is compressed with snappy, while its manifest records gzip, as if it was
+	// recompressed by hand.
+	fixture := requestFixture(t, 1, 2)
+	fixture.manifest.Compression = "gzip"
+	out := runRestoreRequest(t, []backupFixture{fixture}, &pb.RestoreRequest{
+		CompressionOverrides: map[uint32]string{1: "snappy"}})
+	require.Len(t, out.mapped, 4)
+
+	// An unknown codec is rejected before the restore is proposed.
+	opts, err := MapOptionsFromRequest(&pb.RestoreRequest{
+		CompressionOverrides: map[uint32]string{1: "zstd"}})
+	require.NoError(t, err)
+	err = opts.validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown compression: "zstd" for group: 1`)
+}
+
+func TestRestoreSampleRate(t *testing.T) {
+	var uids []uint64
+	for uid := uint64(1); uid <= 200; uid++ {
+		uids = append(uids, uid)
+	}
+	fixtures := []backupFixture{requestFixture(t, uids...)}
+	req := func() *pb.RestoreRequest { return &pb.RestoreRequest{SampleRate: 0.5} }
+
+	// Roughly half of the posting lists are kept, and the same ones on every run. The schema
+	// is kept in full.
+	out := runRestoreRequest(t, fixtures, req())
+	require.Equal(t, uint64(400), out.res.sampleTotal)
+	require.Equal(t, int(out.res.sampledKeys), len(out.mapped))
+	require.InDelta(t, 200, len(out.mapped), 60)
+	require.Len(t, out.schema, 2)
+	require.Equal(t, out.mapped, runRestoreRequest(t, fixtures, req()).mapped)
+
+	opts, err := MapOptionsFromRequest(&pb.RestoreRequest{SampleRate: 1.5})
+	require.NoError(t, err)
+	require.Error(t, opts.validate())
+}
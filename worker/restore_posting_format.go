@@ -0,0 +1,58 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"github.com/dgraph-io/sroar"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/codec"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// The encodings of the uids of the posting lists, see MapOptions.TargetPostingFormat.
+//
+// The backups store the uids of a list either as a plain list (the uids field of older
+// backups) or as delta encoded varints (the uid_bytes field), and posting.FromBackupPostingList
+// decodes both into a roaring bitmap. The UidPack encoding of the releases before v21.03 is
+// gone from pb.PostingList, so the roaring bitmap is the only encoding the posting package
+// reads, and the only target. Every source format can be converted to it.
+const (
+	// postingFormatRoaring re-packs the bitmap from the sorted uids, which drops the unused
+	// space of the containers of a bitmap built one uid at a time, and picks the smallest
+	// container for the uids of each one.
+	postingFormatRoaring = "roaring"
+)
+
+// repackPostingList re-encodes the uids of the list with the format. The postings are left
+// as they are. The parts of a multi-part list are re-packed one by one, as the main list only
+// holds the splits.
+func repackPostingList(pl *pb.PostingList, format string) error {
+	switch format {
+	case "":
+		return nil
+	case postingFormatRoaring:
+		if len(pl.Bitmap) == 0 {
+			return nil
+		}
+		uids := codec.FromBytes(pl.Bitmap).ToArray()
+		pl.Bitmap = sroar.FromSortedList(uids).ToBuffer()
+		return nil
+	default:
+		return errors.Errorf("posting format: %q is not supported", format)
+	}
+}
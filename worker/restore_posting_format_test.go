@@ -0,0 +1,103 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"math"
+	"testing"
+
+	bpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/codec"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestTargetPostingFormat(t *testing.T) {
+	var uids []uint64
+	for uid := uint64(1); uid <= 5000; uid += 3 {
+		uids = append(uids, uid)
+	}
+	// A few uids far apart, which end up in containers of their own.
+	uids = append(uids, 1<<20, 1<<32, 1<<40)
+	var uidBytes []byte
+	var prev uint64
+	for _, uid := range uids {
+		uidBytes = appendUvarint(uidBytes, uid-prev)
+		prev = uid
+	}
+	value := &pb.Posting{Uid: math.MaxUint64, Value: []byte("alice"), ValType: pb.Posting_STRING,
+		PostingType: pb.Posting_VALUE}
+
+	sources := map[string]*pb.BackupPostingList{
+		"uids":      {Uids: uids},
+		"uid_bytes": {UidBytes: uidBytes},
+		"postings":  {Uids: uids, Postings: []*pb.Posting{value}},
+	}
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}, groupId: 1}
+	for name, bl := range sources {
+		for _, format := range []string{"", postingFormatRoaring} {
+			t.Run(name+"/"+format, func(t *testing.T) {
+				key, err := (&pb.BackupKey{Type: pb.BackupKey_DATA, Attr: "name", Uid: 1,
+					Namespace: x.GalaxyNamespace}).Marshal()
+				require.NoError(t, err)
+				val, err := bl.Marshal()
+				require.NoError(t, err)
+				kv := &bpb.KV{Key: key, Value: val, Version: 1,
+					UserMeta: []byte{posting.BitCompletePosting}}
+
+				opts := MapOptions{TargetPostingFormat: format}
+				require.NoError(t, opts.validate())
+				p := newProcessor(newMapper(10, "", opts, 2))
+				buf := z.NewBuffer(1<<10, "TestTargetPostingFormat")
+				defer buf.Release()
+				require.NoError(t, p.processKV(buf, in, kv))
+
+				var lists int
+				require.NoError(t, buf.SliceIterate(func(slice []byte) error {
+					var out bpb.KV
+					require.NoError(t, out.Unmarshal(mapEntry(slice).Data()))
+					var pl pb.PostingList
+					require.NoError(t, pl.Unmarshal(out.Value))
+					require.Equal(t, uids, codec.FromBytes(pl.Bitmap).ToArray())
+					require.Equal(t, len(bl.Postings), len(pl.Postings))
+					for i, p := range bl.Postings {
+						require.Equal(t, p.Value, pl.Postings[i].Value)
+						require.Equal(t, p.ValType, pl.Postings[i].ValType)
+					}
+					lists++
+					return nil
+				}))
+				require.Equal(t, 1, lists)
+			})
+		}
+	}
+
+	// Re-packing a list twice gives the same bitmap.
+	pl := posting.FromBackupPostingList(&pb.BackupPostingList{Uids: uids})
+	require.NoError(t, repackPostingList(pl, postingFormatRoaring))
+	packed := append([]byte{}, pl.Bitmap...)
+	require.NoError(t, repackPostingList(pl, postingFormatRoaring))
+	require.Equal(t, packed, pl.Bitmap)
+	require.Equal(t, len(uids), codec.FromBytes(pl.Bitmap).GetCardinality())
+
+	opts := MapOptions{TargetPostingFormat: "uidpack"}
+	require.Error(t, opts.validate())
+}
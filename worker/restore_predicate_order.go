@@ -0,0 +1,115 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bufio"
+	"io"
+	"sync/atomic"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// predicateSpool holds the frames of a backup file by the rank of their predicate in
+// MapOptions.PredicateOrder, until the whole file is read. The frames are spooled to
+// temporary files, as a backup file doesn't fit in memory.
+type predicateSpool struct {
+	alloc BufferAllocator
+	// bufs are the frames of each rank. The last one holds the frames of the predicates which
+	// are not listed. A buffer is only created once it gets a frame.
+	bufs []*z.Buffer
+}
+
+func newPredicateSpool(alloc BufferAllocator, listed int) *predicateSpool {
+	return &predicateSpool{alloc: alloc, bufs: make([]*z.Buffer, listed+1)}
+}
+
+// add reads the frame of size sz from br into the buffer of the rank.
+func (s *predicateSpool) add(br *bufio.Reader, sz uint64, rank int) error {
+	buf := s.bufs[rank]
+	if buf == nil {
+		var err error
+		if buf, err = s.alloc.NewBufferTmp(bufSz); err != nil {
+			return errors.Wrap(err, "while creating the predicate spool")
+		}
+		s.bufs[rank] = buf
+	}
+	_, err := io.ReadFull(br, buf.SliceAllocate(int(sz)))
+	return err
+}
+
+// release removes the temporary files of the spool.
+func (s *predicateSpool) release() {
+	for i, buf := range s.bufs {
+		if buf != nil {
+			buf.Release()
+			s.bufs[i] = nil
+		}
+	}
+}
+
+// frameRank returns the rank in MapOptions.PredicateOrder of the predicate of the first key of
+// the frame of size sz, which is about to be read from br. The types, the predicates which are
+// not listed and the frames whose key can't be found get the rank after the listed ones. The
+// frame is peeked, not read.
+func (m *mapper) frameRank(br *bufio.Reader, sz uint64) int {
+	unlisted := len(m.opts.predicateOrder)
+	n := maxFramePeek
+	if sz < uint64(n) {
+		n = int(sz)
+	}
+	b, _ := br.Peek(n)
+	key, ok := frameKey(b)
+	if !ok || key.Type == pb.BackupKey_TYPE {
+		return unlisted
+	}
+	if rank, ok := m.opts.predicateOrder[key.Attr]; ok {
+		return rank
+	}
+	return unlisted
+}
+
+// sendSpool sends the frames of the spool for processing, from the first rank to the last one,
+// in batches of the size of the ones sent by Map. offset is the end of the backup file.
+func (m *mapper) sendSpool(s *predicateSpool, in *loadBackupInput, offset uint64) error {
+	zbuf := m.opts.BufferAllocator.NewBuffer(bufSz, "Restore.Map")
+	for _, buf := range s.bufs {
+		if buf == nil {
+			continue
+		}
+		err := buf.SliceIterate(func(frame []byte) error {
+			copy(zbuf.SliceAllocate(len(frame)), frame)
+			if zbuf.LenNoPadding() <= bufSoftLimit {
+				return nil
+			}
+			atomic.AddUint64(&m.bytesRead, uint64(zbuf.LenNoPadding()))
+			if err := m.sendReq(listReq{zbuf, in, nil}, false); err != nil {
+				return err
+			}
+			zbuf = m.opts.BufferAllocator.NewBuffer(bufSz, "Restore.Map")
+			return nil
+		})
+		if err != nil {
+			zbuf.Release()
+			return err
+		}
+	}
+	return m.sendReq(listReq{zbuf, in, in.tracker.newAck(offset, true)}, false)
+}
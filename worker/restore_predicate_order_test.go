@@ -0,0 +1,98 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	bpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestPredicateOrder(t *testing.T) {
+	var stream bytes.Buffer
+	for _, attr := range []string{"age", "name", "email", "name", "age"} {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, attr, 1))
+	}
+	appendKVList(t, &stream, typeKV(t, x.GalaxyNamespace, "email"))
+	in := &loadBackupInput{preds: predicateSet{}}
+
+	// frames maps the stream and returns the predicates of the frames, in the order they are
+	// queued for processing. The requests are only queued, as the pipeline is not started.
+	frames := func(order ...string) []string {
+		opts := MapOptions{PredicateOrder: order}
+		require.NoError(t, opts.validate())
+		m := newMapper(10, "", opts, 8)
+		m.ctx = context.Background()
+		require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+		close(m.reqCh)
+		var out []string
+		for req := range m.reqCh {
+			require.NoError(t, req.lbuf.SliceIterate(func(s []byte) error {
+				var list bpb.KVList
+				require.NoError(t, list.Unmarshal(s))
+				key, ok := frameKey(s)
+				require.True(t, ok)
+				out = append(out, fmt.Sprintf("%s:%s", key.Type, key.Attr))
+				return nil
+			}))
+			req.lbuf.Release()
+		}
+		return out
+	}
+
+	require.Equal(t, []string{"DATA:age", "DATA:name", "DATA:email", "DATA:name", "DATA:age",
+		"TYPE:email"}, frames())
+	// The type named like a listed predicate stays with the unlisted keys.
+	require.Equal(t, []string{"DATA:email", "DATA:age", "DATA:age", "DATA:name", "DATA:name",
+		"TYPE:email"}, frames("email", "age"))
+
+	for _, opts := range []MapOptions{
+		{PredicateOrder: []string{"name", ""}},
+		{PredicateOrder: []string{"name", "name"}},
+		{PredicateOrder: []string{"name"}, PriorityNamespaces: []uint64{1}},
+		{PredicateOrder: []string{"name"}, CheckpointFile: "ckpt"},
+	} {
+		require.Error(t, opts.validate())
+	}
+}
+
+func TestFrameKey(t *testing.T) {
+	list, err := (&bpb.KVList{Kv: []*bpb.KV{nsEdgeKV(t, 2, "name", 7),
+		nsEdgeKV(t, 2, "age", 8)}}).Marshal()
+	require.NoError(t, err)
+	key, ok := frameKey(list)
+	require.True(t, ok)
+	require.Equal(t, "name", key.Attr)
+	require.Equal(t, uint64(7), key.Uid)
+	require.Equal(t, uint64(2), key.Namespace)
+
+	// The key must be whole, but the rest of the frame is not needed.
+	for _, n := range []int{0, 1, 3, 10} {
+		_, ok := frameKey(list[:n])
+		require.False(t, ok, "prefix of %d bytes", n)
+	}
+	_, ok = frameKey(list[:len(list)/2])
+	require.True(t, ok)
+	_, ok = frameKey([]byte{0xff})
+	require.False(t, ok)
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// findEmptyPreds returns the expected predicates which were not seen, leaving out the ones in
+// the dropped namespaces, in sorted order. An incremental restore only maps the backups since
+// IncrementalFrom, so the predicates which didn't change in that time are reported as well.
+func findEmptyPreds(expected, seen map[string]struct{}, dropNs map[uint64]struct{}) []string {
+	var empty []string
+	for attr := range expected {
+		if _, ok := seen[attr]; ok {
+			continue
+		}
+		ns, _ := x.ParseNamespaceAttr(attr)
+		if _, ok := dropNs[ns]; ok {
+			continue
+		}
+		empty = append(empty, attr)
+	}
+	sort.Strings(empty)
+	return empty
+}
+
+// findPredicateMismatches correlates the predicates with data and the ones with a schema key. It
+// returns the predicates with data but no schema, and the ones with a schema but no data, in
+// sorted order. The type keys are not predicates, so they are not part of either set. The
+// reserved predicates have a schema in every namespace whether they are used or not, and the
+// data of the dropped namespaces is not mapped, so their predicates are not reported as having
+// no data.
+func findPredicateMismatches(data, schema map[string]struct{},
+	dropNs map[uint64]struct{}) ([]string, []string) {
+	var noSchema, noData []string
+	for attr := range data {
+		if _, ok := schema[attr]; !ok {
+			noSchema = append(noSchema, attr)
+		}
+	}
+	for attr := range schema {
+		if _, ok := data[attr]; ok {
+			continue
+		}
+		if ns, _, ok := splitNamespaceAttr(attr); ok {
+			if _, dropped := dropNs[ns]; dropped || x.IsReservedPredicate(attr) {
+				continue
+			}
+		}
+		noData = append(noData, attr)
+	}
+	sort.Strings(noSchema)
+	sort.Strings(noData)
+	return noSchema, noData
+}
+
+// reportPredicateMismatches sets the predicates with data but no schema, and the ones with a
+// schema but no data, in the result. It fails with FailOnPredicateMismatch if there are any.
+func (m *mapper) reportPredicateMismatches(res *mapResult, dropNs map[uint64]struct{}) error {
+	res.dataWithoutSchema, res.schemaWithoutData = findPredicateMismatches(
+		m.seenPreds, m.schemaAttrs, dropNs)
+	if len(res.dataWithoutSchema) > 0 {
+		glog.Warningf("%s%d predicates have data but no schema: %v", m.logPrefix,
+			len(res.dataWithoutSchema), res.dataWithoutSchema)
+	}
+	if len(res.schemaWithoutData) > 0 {
+		glog.Infof("%s%d predicates have a schema but no data: %v", m.logPrefix,
+			len(res.schemaWithoutData), res.schemaWithoutData)
+	}
+	if m.opts.FailOnPredicateMismatch &&
+		len(res.dataWithoutSchema)+len(res.schemaWithoutData) > 0 {
+		return errors.Errorf("predicates with data but no schema: %v, predicates with"+
+			" a schema but no data: %v", res.dataWithoutSchema, res.schemaWithoutData)
+	}
+	return nil
+}
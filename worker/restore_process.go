@@ -0,0 +1,327 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+
+	bpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/dgraph-io/badger/v3/y"
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+type processor struct {
+	*mapper
+	maxUid uint64
+	maxNs  uint64
+	// seen is the set of predicates for which this processor mapped at least one posting list.
+	seen map[string]struct{}
+	// schemas is the set of predicates held by the schema updates, and nsMismatches are the
+	// schema updates whose predicate is not the one of their key. They are only collected
+	// when opts.CheckSchemaNamespaces is set.
+	schemas      map[string]struct{}
+	nsMismatches []string
+	// schemaAttrs is the set of predicates for which this processor mapped a schema key.
+	schemaAttrs map[string]struct{}
+	// rollups is the cost of the rollups done by this processor, per predicate.
+	rollups map[string]*rollupCost
+	// stripped is the set of predicates and types of the keys already stripped of their
+	// namespace by this processor.
+	stripped map[string]struct{}
+	// namespaces is the set of namespaces of the keys mapped by this processor. It is only
+	// set with opts.CollectNamespaces.
+	namespaces map[uint64]struct{}
+
+	// inv collects the counts of this processor in the inventory mode.
+	inv *inventory
+}
+
+func newProcessor(m *mapper) *processor {
+	p := &processor{
+		mapper:      m,
+		seen:        make(map[string]struct{}),
+		schemas:     make(map[string]struct{}),
+		schemaAttrs: make(map[string]struct{}),
+		stripped:    make(map[string]struct{}),
+	}
+	if m.opts.inventory != nil {
+		p.inv = newInventory()
+	}
+	if m.opts.CollectNamespaces {
+		p.namespaces = make(map[uint64]struct{})
+	}
+	return p
+}
+
+// backupKV is a KV read from a backup, along with the key it is restored at.
+type backupKV struct {
+	kv *bpb.KV
+	// key is the key the KV is restored at, pk is its parsed form and ns its namespace.
+	key []byte
+	pk  x.ParsedKey
+	ns  uint64
+	// writeTs is the version the KV is written at. It is restoreTs, unless it is overridden
+	// for the predicate with PredicateRestoreTs, in which case overridden is set.
+	writeTs    uint64
+	overridden bool
+}
+
+func (p *processor) processKV(buf *z.Buffer, in *loadBackupInput, kv *bpb.KV) error {
+	if err := injectFault(faultProcess); err != nil {
+		return err
+	}
+	if len(kv.GetUserMeta()) != 1 {
+		return errors.Errorf(
+			"Unexpected meta: %v for key: %s", kv.UserMeta, hex.Dump(kv.Key))
+	}
+	if in.schemaOnly && kv.GetUserMeta()[0] != posting.BitSchemaPosting {
+		return nil
+	}
+	e, err := p.restoreKV(in, kv)
+	if e == nil || err != nil {
+		return err
+	}
+
+	switch kv.GetUserMeta()[0] {
+	case posting.BitEmptyPosting, posting.BitCompletePosting, posting.BitDeltaPosting:
+		return p.processPostingList(buf, in, e)
+	case posting.BitSchemaPosting:
+		return p.processSchema(buf, in, e)
+	default:
+		return errors.Errorf(
+			"Unexpected meta %d for key %s", kv.UserMeta[0], hex.Dump(kv.Key))
+	}
+}
+
+// restoreKV returns the KV along with the key it is restored at, or nil if the KV is not
+// restored, like the KVs of the predicates which are filtered out.
+func (p *processor) restoreKV(in *loadBackupInput, kv *bpb.KV) (*backupKV, error) {
+	restoreKey, ns, err := fromBackupKey(kv.Key)
+	if err != nil {
+		if p.opts.SkipBadKeys {
+			p.skipBadKey(kv.Key, err)
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "fromBackupKey")
+	}
+
+	// Filter keys using the preds set. Do not do this filtering for type keys
+	// as they are meant to be in every group and their Attr value does not
+	// match a predicate name.
+	parsedKey, err := x.Parse(restoreKey)
+	if err != nil {
+		if p.opts.SkipBadKeys {
+			p.skipBadKey(restoreKey, err)
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "could not parse key %s", hex.Dump(restoreKey))
+	}
+
+	// Update the local max uid and max namespace values.
+	p.maxUid = x.Max(p.maxUid, parsedKey.Uid)
+	p.maxNs = x.Max(p.maxNs, ns)
+	if p.namespaces != nil {
+		p.namespaces[ns] = struct{}{}
+	}
+
+	if !in.keepSchema && (parsedKey.IsSchema() || parsedKey.IsType()) {
+		if _, ok := in.schemaPreds[parsedKey.Attr]; !ok || parsedKey.IsType() {
+			return nil, nil
+		}
+	}
+	if !parsedKey.IsType() && !in.hasPred(parsedKey.Attr) {
+		return nil, nil
+	}
+	if p.opts.RedactValues && parsedKey.IsIndex() {
+		// The index keys hold the tokens of the values being redacted.
+		return nil, nil
+	}
+	if p.opts.SkipReverseEdges && parsedKey.IsReverse() {
+		return nil, nil
+	}
+
+	e := &backupKV{kv: kv, key: restoreKey, pk: parsedKey, ns: ns, writeTs: p.restoreTs}
+	if !parsedKey.IsType() {
+		e.writeTs, e.overridden = p.predicateWriteTs(parsedKey.Attr)
+	}
+	return e, nil
+}
+
+// processPostingList maps the posting list of e.
+func (p *processor) processPostingList(buf *z.Buffer, in *loadBackupInput, e *backupKV) error {
+	kv, parsedKey := e.kv, e.pk
+	if _, ok := in.dropNs[e.ns]; ok {
+		return nil
+	}
+	if in.maxVersion > 0 && (kv.Version <= in.minVersion || kv.Version > in.maxVersion) {
+		return nil
+	}
+	if !p.keepSample(e.key, parsedKey) {
+		return nil
+	}
+	if limit := p.opts.MaxAllowedUid; limit > 0 && parsedKey.Uid > limit {
+		// The maxUid is used to bump the UID lease. Abort the restore instead of letting a
+		// corrupt key waste the UID space of the cluster.
+		return errors.Errorf("uid %#x for predicate %s exceeds the max allowed uid %#x",
+			parsedKey.Uid, parsedKey.Attr, limit)
+	}
+	if p.opts.StripNamespaces {
+		if err := p.stripNamespace(e.key, parsedKey); err != nil {
+			return err
+		}
+	}
+	if !parsedKey.IsType() {
+		p.seen[parsedKey.Attr] = struct{}{}
+	}
+	if p.inv != nil {
+		p.inv.countPostingList(e.ns, parsedKey.Attr)
+		return nil
+	}
+
+	backupPl := &pb.BackupPostingList{}
+	if err := backupPl.Unmarshal(kv.Value); err != nil {
+		return errors.Wrapf(err, "while reading backup posting list")
+	}
+	pl := posting.FromBackupPostingList(backupPl)
+	if err := p.transformPostings(pl, parsedKey.Attr); err != nil {
+		return err
+	}
+	if p.shouldSplit(pl) && !parsedKey.HasStartUid && len(pl.GetSplits()) == 0 {
+		// This is a complete list. It should be rolled up to avoid writing
+		// a list that is too big to be read back from disk.
+		return p.rollup(buf, e, pl)
+	}
+
+	// This covers two cases.
+	// 1. The list is not big enough to be split.
+	// 2. This key is storing part of a multi-part list. Write each individual
+	// part without rolling the key first. This part is here for backwards
+	// compatibility. New backups are not affected because there was a change
+	// to roll up lists into a single one.
+	newKv := posting.MarshalPostingList(pl, nil)
+	newKv.Key = e.key
+
+	// We are using kv.Version (from the key-value) to generate the key. But, using
+	// restoreTs to set the version of the KV. This way, when we sort the keys, we
+	// choose the latest key based on kv.Version. But, then set its version to
+	// restoreTs.
+	newKv.Version = e.writeTs
+	return p.toBuffer(buf, e, newKv, kv.Version)
+}
+
+// transformPostings applies the transformations of the options to the postings of the list.
+func (p *processor) transformPostings(pl *pb.PostingList, attr string) error {
+	if p.opts.RedactValues {
+		if err := redactPostings(pl); err != nil {
+			return errors.Wrapf(err, "while redacting %s", attr)
+		}
+	}
+	if p.opts.StripFacets {
+		stripFacets(pl)
+	}
+	if err := repackPostingList(pl, p.opts.TargetPostingFormat); err != nil {
+		return errors.Wrapf(err, "while re-encoding %s", attr)
+	}
+	return nil
+}
+
+// processSchema maps the schema or type update of e.
+func (p *processor) processSchema(buf *z.Buffer, in *loadBackupInput, e *backupKV) error {
+	kv, parsedKey := e.kv, e.pk
+	if skip, err := p.transformSchema(in, e); skip || err != nil {
+		return err
+	}
+	if parsedKey.IsSchema() {
+		p.schemaAttrs[parsedKey.Attr] = struct{}{}
+	}
+	if p.opts.MergeAllGroups && p.mapSchemaKeyOnce(parsedKey, e.key, kv.Value, in.groupId) {
+		// The key was already mapped from the backup of another group.
+		return nil
+	}
+	if p.inv != nil {
+		return p.inv.countSchema(e.ns, parsedKey, kv.Value)
+	}
+
+	// Reset the StreamId to prevent ordering issues while writing to stream writer.
+	kv.StreamId = 0
+	// Schema and type keys are not stored in an intermediate format so their
+	// value can be written as is.
+	version := kv.Version
+	kv.Version = e.writeTs
+	kv.Key = e.key
+	return p.toBuffer(buf, e, kv, version)
+}
+
+// toBuffer appends kv to the buffer as a map entry, sorted by version, the version it was read
+// at from the backup. kv.Version is the version it is written at.
+func (p *processor) toBuffer(buf *z.Buffer, e *backupKV, kv *bpb.KV, version uint64) error {
+	if err := p.checkVersion(e, kv.Key, version); err != nil {
+		return err
+	}
+	if p.opts.PreserveVersions {
+		kv.Version = p.preservedVersion(version)
+	}
+	key := y.KeyWithTs(kv.Key, version)
+	if len(p.opts.RestoreTsSets) > 0 {
+		return p.appendToSets(buf, key, kv, version)
+	}
+	return appendMapEntry(buf, key, kv)
+}
+
+// appendMapEntry appends the map entry of the KV to the buffer, under the key.
+func appendMapEntry(buf *z.Buffer, key []byte, kv *bpb.KV) error {
+	sz := kv.Size()
+	b := buf.SliceAllocate(2 + len(key) + sz)
+
+	binary.BigEndian.PutUint16(b[0:2], uint16(len(key)))
+	x.AssertTrue(copy(b[2:], key) == len(key))
+	_, err := kv.MarshalToSizedBuffer(b[2+len(key):])
+	return err
+}
+
+func fromBackupKey(key []byte) ([]byte, uint64, error) {
+	backupKey := &pb.BackupKey{}
+	if err := backupKey.Unmarshal(key); err != nil {
+		return nil, 0, errors.Wrapf(err, "while reading backup key %s", hex.Dump(key))
+	}
+	return x.FromBackupKey(backupKey), backupKey.Namespace, nil
+}
+
+// maxBadKeySamples is the number of bad keys whose dump is kept for postmortem analysis.
+const maxBadKeySamples = 16
+
+// skipBadKey records a key which could not be parsed. The key is dropped from the restore.
+func (m *mapper) skipBadKey(key []byte, err error) {
+	n := atomic.AddUint64(&m.badKeys, 1)
+	glog.V(2).Infof("%sSkipping bad key. Err: %v\n%s", m.logPrefix, err, hex.Dump(key))
+	if n > maxBadKeySamples {
+		return
+	}
+	m.badKeysMu.Lock()
+	defer m.badKeysMu.Unlock()
+	m.badKeySamples = append(m.badKeySamples,
+		fmt.Sprintf("Err: %v\n%s", err, hex.Dump(key)))
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// RestoreProgressSender sends the progress of a restore to a client. The generated server
+// streams of the gRPC methods returning a stream of pb.RestoreProgress implement it.
+type RestoreProgressSender interface {
+	Send(*pb.RestoreProgress) error
+}
+
+// defaultProgressStreamBuffer is the number of updates buffered for a slow client by default.
+const defaultProgressStreamBuffer = 16
+
+// ProgressStream sends the progress of the map phase to a gRPC server stream. Its OnProgress
+// method is meant to be set as MapOptions.OnProgress, as the RestoreProgress RPC does. The updates are sent by a goroutine of
+// their own, so that a slow or disconnected client never blocks the mapper. Once the buffer is
+// full, the oldest update is dropped for the new one, so the client always gets the latest
+// progress, and the final update.
+type ProgressStream struct {
+	sender  RestoreProgressSender
+	updates chan *pb.RestoreProgress
+	done    chan struct{}
+	// err is the error of the first send which failed. No update is sent after it.
+	err     error
+	dropped uint64
+}
+
+// NewProgressStream returns a ProgressStream sending the updates to sender, with a buffer of
+// the given number of updates. It defaults to 16 if it is not positive. Close must be called
+// once the map phase is over.
+func NewProgressStream(sender RestoreProgressSender, buffer int) *ProgressStream {
+	if buffer <= 0 {
+		buffer = defaultProgressStreamBuffer
+	}
+	ps := &ProgressStream{
+		sender:  sender,
+		updates: make(chan *pb.RestoreProgress, buffer),
+		done:    make(chan struct{}),
+	}
+	go ps.send()
+	return ps
+}
+
+func (ps *ProgressStream) send() {
+	defer close(ps.done)
+	for update := range ps.updates {
+		if ps.err != nil {
+			// The client is gone, the remaining updates are only drained.
+			continue
+		}
+		if err := ps.sender.Send(update); err != nil {
+			glog.Warningf("Unable to send the progress of the restore, no more progress will"+
+				" be sent. Err: %v", err)
+			ps.err = err
+		}
+	}
+}
+
+// OnProgress queues the update to be sent, and never blocks. It must not be called
+// concurrently, which the mapper doesn't do.
+func (ps *ProgressStream) OnProgress(p *MapProgress) {
+	update := &pb.RestoreProgress{
+		BytesRead:      p.BytesRead,
+		BytesProcessed: p.BytesProcessed,
+		Rate:           p.Rate,
+		FileId:         p.FileId,
+		ElapsedNs:      p.Elapsed.Nanoseconds(),
+		Done:           p.Final,
+	}
+	for {
+		select {
+		case ps.updates <- update:
+			return
+		default:
+		}
+		// The buffer is full, drop the oldest update to make room for this one.
+		select {
+		case <-ps.updates:
+			atomic.AddUint64(&ps.dropped, 1)
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of updates dropped because the client was too slow.
+func (ps *ProgressStream) Dropped() uint64 {
+	return atomic.LoadUint64(&ps.dropped)
+}
+
+// Close waits for the queued updates to be sent, and returns the error of the first send which
+// failed. It blocks until the client takes them or its stream is canceled. OnProgress must not
+// be called after it, so it must only be called once the map phase has returned.
+func (ps *ProgressStream) Close() error {
+	close(ps.updates)
+	<-ps.done
+	return ps.err
+}
+
+// progressWatcher is a client of the RestoreProgress RPC. done is closed once the map phase of
+// the restore it watches is over.
+type progressWatcher struct {
+	stream *ProgressStream
+	done   chan struct{}
+}
+
+// progressWatchers holds the clients of the RestoreProgress RPC, by the request id of the
+// restore they watch.
+var progressWatchers = struct {
+	sync.Mutex
+	m map[string][]*progressWatcher
+}{m: make(map[string][]*progressWatcher)}
+
+// watchRestoreProgress registers the stream to get the progress of the restore with the
+// request id. The returned watcher must be passed to unwatchRestoreProgress.
+func watchRestoreProgress(id string, stream *ProgressStream) *progressWatcher {
+	w := &progressWatcher{stream: stream, done: make(chan struct{})}
+	progressWatchers.Lock()
+	defer progressWatchers.Unlock()
+	progressWatchers.m[id] = append(progressWatchers.m[id], w)
+	return w
+}
+
+// unwatchRestoreProgress removes the watcher of the restore with the request id, if the map
+// phase of the restore has not already removed it.
+func unwatchRestoreProgress(id string, w *progressWatcher) {
+	progressWatchers.Lock()
+	defer progressWatchers.Unlock()
+	watchers := progressWatchers.m[id]
+	for i, other := range watchers {
+		if other == w {
+			watchers = append(watchers[:i], watchers[i+1:]...)
+			break
+		}
+	}
+	if len(watchers) == 0 {
+		delete(progressWatchers.m, id)
+	} else {
+		progressWatchers.m[id] = watchers
+	}
+}
+
+// notifyRestoreProgress sends the progress of the restore with the request id to its
+// watchers. It never blocks on a slow client.
+func notifyRestoreProgress(id string, p *MapProgress) {
+	progressWatchers.Lock()
+	defer progressWatchers.Unlock()
+	for _, w := range progressWatchers.m[id] {
+		w.stream.OnProgress(p)
+	}
+}
+
+// endRestoreProgress removes the watchers of the restore with the request id, once its map
+// phase is over, and lets their RPCs return.
+func endRestoreProgress(id string) {
+	progressWatchers.Lock()
+	defer progressWatchers.Unlock()
+	for _, w := range progressWatchers.m[id] {
+		close(w.done)
+	}
+	delete(progressWatchers.m, id)
+}
+
+// RestoreProgress streams the progress of the map phase of the restore with the request id,
+// as mapped by this alpha, until the map phase is over or the client goes away. The client can
+// call it before proposing the restore, so that it gets the progress from the start.
+func (w *grpcWorker) RestoreProgress(req *pb.RestoreProgressRequest,
+	stream pb.Worker_RestoreProgressServer) error {
+	if req.RequestId == "" {
+		return errors.New("the request id of the restore is required to stream its progress")
+	}
+	ps := NewProgressStream(stream, 0)
+	watcher := watchRestoreProgress(req.RequestId, ps)
+	select {
+	case <-watcher.done:
+	case <-stream.Context().Done():
+		unwatchRestoreProgress(req.RequestId, watcher)
+	}
+	// The progress is no longer updated, so the stream can be closed.
+	if err := ps.Close(); err != nil {
+		return err
+	}
+	return stream.Context().Err()
+}
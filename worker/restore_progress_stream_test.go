@@ -0,0 +1,144 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// blockingSender blocks every send until unblock is closed, and then fails with err if it is
+// set.
+type blockingSender struct {
+	unblock chan struct{}
+	err     error
+	sent    []*pb.RestoreProgress
+}
+
+func (s *blockingSender) Send(p *pb.RestoreProgress) error {
+	<-s.unblock
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, p)
+	return nil
+}
+
+func TestProgressStream(t *testing.T) {
+	t.Run("slow client", func(t *testing.T) {
+		sender := &blockingSender{unblock: make(chan struct{})}
+		ps := NewProgressStream(sender, 4)
+		// The updates don't block while the client doesn't take them.
+		for i := 1; i <= 100; i++ {
+			ps.OnProgress(&MapProgress{BytesRead: uint64(i), Elapsed: time.Duration(i)})
+		}
+		ps.OnProgress(&MapProgress{BytesRead: 101, Rate: 10, FileId: 3, Final: true})
+		require.Greater(t, ps.Dropped(), uint64(0))
+		close(sender.unblock)
+		require.NoError(t, ps.Close())
+
+		// The client gets at most the buffered updates, in order, ending with the final one.
+		require.LessOrEqual(t, len(sender.sent), 4+1)
+		for i := 1; i < len(sender.sent); i++ {
+			require.Less(t, sender.sent[i-1].BytesRead, sender.sent[i].BytesRead)
+		}
+		require.Equal(t, &pb.RestoreProgress{BytesRead: 101, Rate: 10, FileId: 3, Done: true},
+			sender.sent[len(sender.sent)-1])
+	})
+
+	t.Run("disconnected client", func(t *testing.T) {
+		sender := &blockingSender{unblock: make(chan struct{}), err: errors.New("gone")}
+		close(sender.unblock)
+		ps := NewProgressStream(sender, 0)
+		for i := 0; i < 100; i++ {
+			ps.OnProgress(&MapProgress{BytesRead: uint64(i)})
+		}
+		require.EqualError(t, ps.Close(), "gone")
+		require.Empty(t, sender.sent)
+	})
+}
+
+// progressServer is the server stream of the RestoreProgress RPC, recording the updates.
+type progressServer struct {
+	grpc.ServerStream
+	ctx  context.Context
+	mu   sync.Mutex
+	sent []*pb.RestoreProgress
+}
+
+func (s *progressServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *progressServer) Send(p *pb.RestoreProgress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, p)
+	return nil
+}
+
+// watchers returns the number of clients of the RestoreProgress RPC watching the request id.
+func watchers(id string) int {
+	progressWatchers.Lock()
+	defer progressWatchers.Unlock()
+	return len(progressWatchers.m[id])
+}
+
+func TestRestoreProgressRPC(t *testing.T) {
+	w := &grpcWorker{}
+	require.Error(t, w.RestoreProgress(&pb.RestoreProgressRequest{},
+		&progressServer{ctx: context.Background()}))
+
+	// The client watches the restore before it is proposed, and gets its progress until the
+	// map phase is over.
+	stream := &progressServer{ctx: context.Background()}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- w.RestoreProgress(&pb.RestoreProgressRequest{RequestId: "watched"}, stream)
+	}()
+	require.Eventually(t, func() bool { return watchers("watched") == 1 }, time.Second,
+		time.Millisecond)
+	out := runRestoreRequest(t, []backupFixture{requestFixture(t, 1, 2)},
+		&pb.RestoreRequest{RequestId: "watched"})
+	require.Len(t, out.mapped, 4)
+	require.NoError(t, <-errCh)
+	require.Zero(t, watchers("watched"))
+	require.NotEmpty(t, stream.sent)
+	last := stream.sent[len(stream.sent)-1]
+	require.True(t, last.Done)
+	require.Greater(t, last.BytesRead, uint64(0))
+
+	// A client which goes away stops watching.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		errCh <- w.RestoreProgress(&pb.RestoreProgressRequest{RequestId: "gone"},
+			&progressServer{ctx: ctx})
+	}()
+	require.Eventually(t, func() bool { return watchers("gone") == 1 }, time.Second,
+		time.Millisecond)
+	cancel()
+	require.Equal(t, context.Canceled, <-errCh)
+	require.Zero(t, watchers("gone"))
+}
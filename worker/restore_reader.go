@@ -0,0 +1,171 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/ee/enc"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+type backupReader struct {
+	file    string
+	toClose []io.Closer
+	r       io.Reader
+	err     error
+	once    sync.Once
+	// raw is the stream returned by the handler. transformed is set once the stream is
+	// decrypted or decompressed, as the offsets in raw no longer match the ones read.
+	raw         io.Reader
+	transformed bool
+}
+
+func readerFrom(h x.UriHandler, file string) *backupReader {
+	br := &backupReader{file: file}
+	reader, err := h.Stream(file)
+	if err != nil {
+		br.setErr(err)
+		return br
+	}
+	if restoreFaults != nil {
+		reader = &faultReader{reader}
+	}
+	br.toClose = append(br.toClose, reader)
+	br.r = reader
+	br.raw = reader
+	return br
+}
+
+func (br *backupReader) Read(p []byte) (n int, err error) {
+	return br.r.Read(p)
+}
+
+// Seek seeks the stream of the backup file. It is only supported if the handler can seek, and
+// the stream is neither encrypted nor compressed.
+func (br *backupReader) Seek(offset int64, whence int) (int64, error) {
+	s, ok := br.raw.(io.Seeker)
+	if !ok || br.transformed {
+		return 0, errors.Errorf("backup file: %s does not support seeking", br.file)
+	}
+	return s.Seek(offset, whence)
+}
+
+func (br *backupReader) Close() (rerr error) {
+	br.once.Do(func() {
+		// Close in reverse order.
+		for i := len(br.toClose) - 1; i >= 0; i-- {
+			if err := br.toClose[i].Close(); err != nil {
+				rerr = err
+			}
+		}
+	})
+	return rerr
+}
+
+func (br *backupReader) setErr(err error) {
+	if br.err == nil {
+		br.err = err
+	}
+}
+
+func (br *backupReader) WithEncryption(encKey x.Sensitive) *backupReader {
+	if len(encKey) == 0 || br.err != nil {
+		return br
+	}
+	r, err := enc.GetReader(encKey, br.r)
+	br.setErr(err)
+	br.r = r
+	br.transformed = true
+	return br
+}
+
+// isSupportedCompression returns true if the backup files can be compressed with comp. An empty
+// compression is gzip.
+func isSupportedCompression(comp string) bool {
+	switch comp {
+	case "snappy", "gzip", "":
+		return true
+	}
+	return false
+}
+
+func (br *backupReader) WithCompression(comp string) *backupReader {
+	if br.err != nil {
+		return br
+	}
+	br.transformed = true
+	switch comp {
+	case "snappy":
+		br.r = snappy.NewReader(br.r)
+	case "gzip", "":
+		r, err := gzip.NewReader(br.r)
+		if err != nil {
+			br.setErr(err)
+			return br
+		}
+		br.r = r
+		br.toClose = append(br.toClose, r)
+	default:
+		br.setErr(fmt.Errorf("Unknown compression for backup: %s", comp))
+	}
+	return br
+}
+
+var (
+	gzipMagic   = []byte{0x1f, 0x8b}
+	snappyMagic = []byte("\xff\x06\x00\x00sNaPpY")
+)
+
+// WithSniffedCompression works like WithCompression, but it first peeks at the beginning of the
+// stream. If the peeked bytes belong to a compression other than the declared one, the
+// detected compression is used instead. The declared compression is used if nothing can be
+// detected.
+func (br *backupReader) WithSniffedCompression(comp string) *backupReader {
+	if br.err != nil {
+		return br
+	}
+	bufr := bufio.NewReader(br.r)
+	br.r = bufr
+	br.transformed = true
+
+	declared := comp
+	if declared == "" {
+		declared = "gzip"
+	}
+	var sniffed string
+	if b, err := bufr.Peek(len(snappyMagic)); err == nil && bytes.Equal(b, snappyMagic) {
+		sniffed = "snappy"
+	} else if b, err := bufr.Peek(len(gzipMagic)); err == nil && bytes.Equal(b, gzipMagic) {
+		sniffed = "gzip"
+	}
+	if sniffed != "" && sniffed != declared {
+		glog.Warningf("Backup file: %s is declared to use %s compression but looks like %s."+
+			" Using %s.", br.file, declared, sniffed, sniffed)
+		comp = sniffed
+	}
+	return br.WithCompression(comp)
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// redactedSources are the values that the scalars of fixed size types are replaced with. The
+// strings, passwords and binary values are replaced with placeholders of a similar length.
+var redactedSources = map[types.TypeID]string{
+	types.IntID:      "0",
+	types.FloatID:    "0",
+	types.BoolID:     "false",
+	types.DateTimeID: "1970-01-01T00:00:00Z",
+	types.GeoID:      `{"type":"Point","coordinates":[0,0]}`,
+}
+
+// redactedValues holds redactedSources in the binary format in which they are stored in the
+// postings.
+var redactedValues = func() map[types.TypeID][]byte {
+	vals := make(map[types.TypeID][]byte)
+	for tid, src := range redactedSources {
+		val, err := types.Convert(types.Val{Tid: types.StringID, Value: []byte(src)}, tid)
+		x.Check(err)
+		bin := types.ValueForType(types.BinaryID)
+		x.Check(types.Marshal(val, &bin))
+		vals[tid] = bin.Value.([]byte)
+	}
+	return vals
+}()
+
+// redactedLen returns the length of the placeholder of a value of length n. It is the next
+// power of two, so that only the order of magnitude of the length is kept.
+func redactedLen(n int) int {
+	l := 1
+	for l < n {
+		l <<= 1
+	}
+	return l
+}
+
+// redactValue returns the placeholder for the value of the given type.
+func redactValue(tid types.TypeID, val []byte) ([]byte, error) {
+	if len(val) == 0 {
+		return val, nil
+	}
+	switch tid {
+	case types.DefaultID, types.StringID, types.PasswordID:
+		return bytes.Repeat([]byte{'x'}, redactedLen(len(val))), nil
+	case types.BinaryID:
+		return make([]byte, redactedLen(len(val))), nil
+	}
+	if red, ok := redactedValues[tid]; ok {
+		return red, nil
+	}
+	return nil, errors.Errorf("cannot redact value of type: %s", tid.Name())
+}
+
+// redactPostings replaces the values of the value postings in the list with placeholders of
+// the same type. The uid postings are kept as they are, so the edges between the nodes are
+// preserved. The language tags are kept, but the facets are removed from all the postings as
+// they can hold arbitrary values.
+func redactPostings(pl *pb.PostingList) error {
+	for _, p := range pl.Postings {
+		p.Facets = nil
+		tid := types.TypeID(p.ValType)
+		if tid == types.UidID {
+			continue
+		}
+		val, err := redactValue(tid, p.Value)
+		if err != nil {
+			return err
+		}
+		p.Value = val
+	}
+	return nil
+}
+
+// redactSchema removes the indices from the schema of a scalar predicate, as the index keys
+// are not restored for the redacted values. The count index is kept since redacting doesn't
+// change the number of values. An index can be added back after the restore, which rebuilds
+// it from the placeholders.
+func redactSchema(val []byte) ([]byte, error) {
+	var update pb.SchemaUpdate
+	if err := update.Unmarshal(val); err != nil {
+		return nil, err
+	}
+	if update.ValueType == pb.Posting_UID || update.Directive != pb.SchemaUpdate_INDEX {
+		return val, nil
+	}
+	update.Directive = pb.SchemaUpdate_NONE
+	update.Tokenizer = nil
+	update.Upsert = false
+	return update.Marshal()
+}
+
+// stripFacets removes the facets of the postings of the list. A uid posting is only stored for
+// its facets, as the uids are all in the pack of the list, so the uid postings are removed once
+// they have none, like a rollup would.
+func stripFacets(pl *pb.PostingList) {
+	postings := pl.Postings[:0]
+	for _, p := range pl.Postings {
+		p.Facets = nil
+		if p.PostingType == pb.Posting_REF {
+			continue
+		}
+		postings = append(postings, p)
+	}
+	pl.Postings = postings
+}
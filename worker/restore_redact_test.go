@@ -0,0 +1,71 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgo/v210/protos/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+func TestRedactPostings(t *testing.T) {
+	pl := &pb.PostingList{
+		Postings: []*pb.Posting{
+			{Uid: 1, ValType: pb.Posting_STRING, Value: []byte("Alice"), LangTag: []byte("en")},
+			{Uid: 2, ValType: pb.Posting_INT, Value: []byte{42, 0, 0, 0, 0, 0, 0, 0}},
+			{Uid: 3, ValType: pb.Posting_UID, Facets: []*api.Facet{{Key: "since"}}},
+		},
+	}
+	require.NoError(t, redactPostings(pl))
+
+	require.Equal(t, []byte("xxxxxxxx"), pl.Postings[0].Value)
+	require.Equal(t, []byte("en"), pl.Postings[0].LangTag)
+
+	val, err := types.Convert(types.Val{Tid: types.BinaryID, Value: pl.Postings[1].Value},
+		types.IntID)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), val.Value)
+
+	require.Equal(t, uint64(3), pl.Postings[2].Uid)
+	require.Nil(t, pl.Postings[2].Facets)
+}
+
+func TestRedactSchema(t *testing.T) {
+	in := &pb.SchemaUpdate{
+		Predicate: "name",
+		ValueType: pb.Posting_STRING,
+		Directive: pb.SchemaUpdate_INDEX,
+		Tokenizer: []string{"exact"},
+		Upsert:    true,
+		Count:     true,
+	}
+	data, err := in.Marshal()
+	require.NoError(t, err)
+	data, err = redactSchema(data)
+	require.NoError(t, err)
+
+	var out pb.SchemaUpdate
+	require.NoError(t, out.Unmarshal(data))
+	require.Equal(t, pb.SchemaUpdate_NONE, out.Directive)
+	require.Empty(t, out.Tokenizer)
+	require.False(t, out.Upsert)
+	require.True(t, out.Count)
+}
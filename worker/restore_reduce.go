@@ -19,11 +19,13 @@ package worker
 import (
 	"bufio"
 	"encoding/binary"
+	"fmt"
 	"io"
-	"log"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -32,7 +34,7 @@ import (
 	"github.com/dgraph-io/ristretto/z"
 	"github.com/dustin/go-humanize"
 	"github.com/golang/glog"
-	"github.com/golang/snappy"
+	"github.com/pkg/errors"
 
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/x"
@@ -41,38 +43,165 @@ import (
 const (
 	mapFileSz      int = 2 << 30
 	partitionBufSz int = 4 << 20
+	// minPartitionBufSz is the MapOptions.PartitionBufSize below which a warning is logged.
+	minPartitionBufSz int = 64 << 10
+	// defaultLargeValueThreshold is the default MapOptions.LargeValueThreshold.
+	defaultLargeValueThreshold int = 1 << 20
 )
 
+// largeValueDir is the subdirectory of the map files of the large values with
+// MapOptions.SplitLargeValues.
+const largeValueDir = "large"
+
+const (
+	// mapFormatLegacy is the version of the map files written before the format version was
+	// recorded in the map header. Their layout is the same as mapFormatV1.
+	mapFormatLegacy uint32 = 0
+	mapFormatV1     uint32 = 1
+	// mapFormatV2 delta encodes the partition keys in the map header, see
+	// encodePartitionKeys.
+	mapFormatV2 uint32 = 2
+
+	// mapFormatVersion is the version of the map files written by the mapper.
+	mapFormatVersion = mapFormatV2
+)
+
+// checkMapFormat returns an error if the map file with the given header was written in a
+// format which this version of the reducer doesn't understand.
+func checkMapFormat(filename string, header *pb.MapHeader) error {
+	switch header.FormatVersion {
+	case mapFormatLegacy, mapFormatV1, mapFormatV2:
+		return nil
+	default:
+		return errors.Errorf("map file %s has unsupported format version: %d. "+
+			"Max supported version: %d", filename, header.FormatVersion, mapFormatVersion)
+	}
+}
+
+// encodePartitionKeys delta encodes the sorted partition keys. Adjacent keys usually share a
+// long prefix, like the predicate, so every key is stored as the uvarint length of the prefix
+// it shares with the previous key followed by the rest of the key.
+func encodePartitionKeys(keys [][]byte) [][]byte {
+	res := make([][]byte, 0, len(keys))
+	var prev []byte
+	for _, key := range keys {
+		shared := 0
+		for shared < len(prev) && shared < len(key) && prev[shared] == key[shared] {
+			shared++
+		}
+		enc := make([]byte, binary.MaxVarintLen64+len(key)-shared)
+		n := binary.PutUvarint(enc, uint64(shared))
+		n += copy(enc[n:], key[shared:])
+		res = append(res, enc[:n])
+		prev = key
+	}
+	return res
+}
+
+// decodePartitionKeys reverses encodePartitionKeys.
+func decodePartitionKeys(filename string, encoded [][]byte) ([][]byte, error) {
+	res := make([][]byte, 0, len(encoded))
+	var prev []byte
+	for i, enc := range encoded {
+		shared, n := binary.Uvarint(enc)
+		if n <= 0 || shared > uint64(len(prev)) {
+			return nil, errors.Errorf("map file %s has an invalid partition key at index: %d",
+				filename, i)
+		}
+		key := make([]byte, 0, int(shared)+len(enc)-n)
+		key = append(key, prev[:shared]...)
+		key = append(key, enc[n:]...)
+		res = append(res, key)
+		prev = key
+	}
+	return res, nil
+}
+
 type mapIterator struct {
+	name   string
 	fd     *os.File
 	reader *bufio.Reader
 	meBuf  []byte
+	// offset is the offset of the next entry in the decompressed file.
+	offset int64
 }
 
-func (mi *mapIterator) Next(cbuf *z.Buffer, partitionKey []byte) error {
-	readMapEntry := func() error {
-		if len(mi.meBuf) > 0 {
-			return nil
+// maxMapEntrySize is the max size of an entry in a map file. An entry can't be larger than
+// the buffer the map file is written from.
+const maxMapEntrySize = 2 * mapFileSz
+
+// readMapEntry reads the next entry into meBuf, unless it already holds an entry. It returns
+// io.EOF at the end of the file, and an error naming the file and the offset of the entry if
+// the entry is corrupt.
+func (mi *mapIterator) readMapEntry() error {
+	if len(mi.meBuf) > 0 {
+		return nil
+	}
+	r := mi.reader
+	sizeBuf, err := r.Peek(binary.MaxVarintLen64)
+	// The last entry of the file can be shorter than the max size of a varint.
+	if err != nil && (err != io.EOF || len(sizeBuf) == 0) {
+		return err
+	}
+	sz, n := binary.Uvarint(sizeBuf)
+	if n <= 0 {
+		return errors.Errorf("map file %s has an invalid entry size at offset: %d",
+			mi.name, mi.offset)
+	}
+	if sz < 2 || sz > uint64(maxMapEntrySize) {
+		return errors.Errorf("map file %s has an entry of invalid size: %d at offset: %d",
+			mi.name, sz, mi.offset)
+	}
+	x.Check2(r.Discard(n))
+	if mi.meBuf, err = readFullGrow(r, mi.meBuf, int(sz)); err != nil {
+		mi.meBuf = mi.meBuf[:0]
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errors.Errorf("map file %s has a truncated entry of size: %d at offset: %d",
+				mi.name, sz, mi.offset)
 		}
-		r := mi.reader
-		sizeBuf, err := r.Peek(binary.MaxVarintLen64)
-		if err != nil {
-			return err
+		return err
+	}
+	if keySz := binary.BigEndian.Uint16(mi.meBuf[0:2]); int(keySz) > len(mi.meBuf)-2 {
+		mi.meBuf = mi.meBuf[:0]
+		return errors.Errorf("map file %s has an entry of size: %d with a key of size: %d"+
+			" at offset: %d", mi.name, sz, keySz, mi.offset)
+	}
+	mi.offset += int64(n) + int64(sz)
+	return nil
+}
+
+// readFullGrow reads exactly sz bytes into buf. The buffer is grown as the data is read, so
+// that a corrupt size doesn't allocate much more memory than the data left to read.
+func readFullGrow(r io.Reader, buf []byte, sz int) ([]byte, error) {
+	buf = buf[:0]
+	for len(buf) < sz {
+		if len(buf) == cap(buf) {
+			grow := cap(buf)
+			if grow < 1<<20 {
+				grow = 1 << 20
+			}
+			if grow > sz-len(buf) {
+				grow = sz - len(buf)
+			}
+			n := len(buf)
+			buf = append(buf, make([]byte, grow)...)[:n]
 		}
-		sz, n := binary.Uvarint(sizeBuf)
-		if n <= 0 {
-			log.Fatalf("Could not read uvarint: %d", n)
+		end := cap(buf)
+		if end > sz {
+			end = sz
 		}
-		x.Check2(r.Discard(n))
-		if cap(mi.meBuf) < int(sz) {
-			mi.meBuf = make([]byte, int(sz))
+		n, err := io.ReadFull(r, buf[len(buf):end])
+		buf = buf[:len(buf)+n]
+		if err != nil {
+			return buf, err
 		}
-		mi.meBuf = mi.meBuf[:int(sz)]
-		x.Check2(io.ReadFull(r, mi.meBuf))
-		return nil
 	}
+	return buf, nil
+}
+
+func (mi *mapIterator) Next(cbuf *z.Buffer, partitionKey []byte) error {
 	for {
-		if err := readMapEntry(); err == io.EOF {
+		if err := mi.readMapEntry(); err == io.EOF {
 			break
 		} else if err != nil {
 			return err
@@ -93,32 +222,77 @@ func (mi *mapIterator) Next(cbuf *z.Buffer, partitionKey []byte) error {
 }
 
 func (mi *mapIterator) Close() error {
+	if mi.fd == nil {
+		return nil
+	}
 	return mi.fd.Close()
 }
 
-func newMapIterator(filename string) (*pb.MapHeader, *mapIterator) {
-	fd, err := os.Open(filename)
-	x.Check(err)
-	r := snappy.NewReader(fd)
-
+// readMapHeader reads the header at the beginning of the decompressed map file.
+func readMapHeader(filename string, reader *bufio.Reader) (*pb.MapHeader, error) {
 	// Read the header size.
-	reader := bufio.NewReaderSize(r, 16<<10)
 	headerLenBuf := make([]byte, 4)
-	x.Check2(io.ReadFull(reader, headerLenBuf))
+	if _, err := io.ReadFull(reader, headerLenBuf); err != nil {
+		return nil, errors.Wrapf(err, "while reading the header length of map file %s",
+			filename)
+	}
 	headerLen := binary.BigEndian.Uint32(headerLenBuf)
+	if headerLen > uint32(mapFileSz) {
+		return nil, errors.Errorf("map file %s has a header of invalid size: %d",
+			filename, headerLen)
+	}
 	// Reader the map header.
 	headerBuf := make([]byte, headerLen)
-
-	x.Check2(io.ReadFull(reader, headerBuf))
+	if _, err := io.ReadFull(reader, headerBuf); err != nil {
+		return nil, errors.Wrapf(err, "while reading the header of map file %s", filename)
+	}
 	header := &pb.MapHeader{}
-	err = header.Unmarshal(headerBuf)
-	x.Check(err)
+	if err := header.Unmarshal(headerBuf); err != nil {
+		return nil, errors.Wrapf(err, "while parsing the header of map file %s", filename)
+	}
+	if err := checkMapFormat(filename, header); err != nil {
+		return nil, err
+	}
+	if header.FormatVersion >= mapFormatV2 {
+		keys, err := decodePartitionKeys(filename, header.PartitionKeys)
+		if err != nil {
+			return nil, err
+		}
+		header.PartitionKeys = keys
+	}
+	return header, nil
+}
+
+func newMapIterator(filename string) (*pb.MapHeader, *mapIterator, error) {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "while opening map file %s", filename)
+	}
+	r, codec, err := newMapFileReader(fd)
+	if err != nil {
+		fd.Close()
+		return nil, nil, errors.Wrapf(err, "while opening map file %s", filename)
+	}
+
+	reader := bufio.NewReaderSize(r, 16<<10)
+	header, err := readMapHeader(filename, reader)
+	if err != nil {
+		fd.Close()
+		return nil, nil, err
+	}
+	if recorded := header.Codec; codec != recorded && !(codec == mapCodecSnappy &&
+		recorded == "") {
+		fd.Close()
+		return nil, nil, errors.Errorf("map file %s is a %s stream, but its header records"+
+			" codec: %q", filename, codec, recorded)
+	}
 
 	itr := &mapIterator{
+		name:   filename,
 		fd:     fd,
 		reader: reader,
 	}
-	return header, itr
+	return header, itr, nil
 }
 
 func getBuf() *z.Buffer {
@@ -184,9 +358,9 @@ func (r *reducer) Progress(closer *z.Closer) {
 	}
 }
 
-func (r *reducer) Reduce() error {
+// mapFiles returns the map files under mapDir, along with their total size.
+func mapFiles(mapDir string) ([]string, int64, error) {
 	var files []string
-
 	var total int64
 	f := func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -199,35 +373,230 @@ func (r *reducer) Reduce() error {
 		return nil
 	}
 
-	if err := filepath.Walk(r.mapDir, f); err != nil {
-		return err
+	if err := filepath.Walk(mapDir, f); err != nil {
+		return nil, 0, err
 	}
-	glog.Infof("Got %d map files of compressed size: %s.\n",
-		len(files), humanize.IBytes(uint64(total)))
+	return files, total, nil
+}
 
-	// Pick up map iterators and partition keys.
-	partitions := make(map[string]struct{})
+// namespaceMapFiles groups the map files under mapDir by the namespace subdirectory they were
+// written to with MapOptions.PerNamespaceSubdirs, so that a reduce phase sharded by namespace
+// can pick the map files of its namespaces. It fails if a map file is not in such a
+// subdirectory.
+func namespaceMapFiles(mapDir string) (map[uint64][]string, error) {
+	files, _, err := mapFiles(mapDir)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[uint64][]string)
+	for _, file := range files {
+		rel, err := filepath.Rel(mapDir, file)
+		if err != nil {
+			return nil, err
+		}
+		dir := filepath.Base(filepath.Dir(rel))
+		ns, err := strconv.ParseUint(strings.TrimPrefix(dir, "ns"), 10, 64)
+		if err != nil || dir != namespaceDir(ns) {
+			return nil, errors.Errorf("map file %s is not in a namespace subdirectory", file)
+		}
+		res[ns] = append(res[ns], file)
+	}
+	return res, nil
+}
+
+// largeValueMapFiles splits the map files under mapDir into the ones of the regular values and
+// the ones of the large values written with MapOptions.SplitLargeValues, so that the reduce
+// phase can load each set with its own settings. Both sets are sorted on their own, and all
+// the files are regular ones without the option. mapDir is the directory of a set of
+// MapOptions.RestoreTsSets, if they are used.
+func largeValueMapFiles(mapDir string) (regular, large []string, err error) {
+	files, _, err := mapFiles(mapDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	largeDir := filepath.Join(mapDir, largeValueDir) + string(filepath.Separator)
+	for _, file := range files {
+		if strings.HasPrefix(file, largeDir) {
+			large = append(large, file)
+		} else {
+			regular = append(regular, file)
+		}
+	}
+	return regular, large, nil
+}
+
+// restoreTsMapFiles returns the directories of the sets of map files written under mapDir
+// with MapOptions.RestoreTsSets, by their restore timestamp. Each one is reduced on its own.
+func restoreTsMapFiles(mapDir string) (map[uint64]string, error) {
+	entries, err := ioutil.ReadDir(mapDir)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[uint64]string)
+	for _, entry := range entries {
+		ts, err := strconv.ParseUint(strings.TrimPrefix(entry.Name(), "ts"), 10, 64)
+		if err != nil || !entry.IsDir() || entry.Name() != restoreTsDir(ts) {
+			continue
+		}
+		res[ts] = filepath.Join(mapDir, entry.Name())
+	}
+	return res, nil
+}
+
+// partitionReport summarizes the partition keys of the map files.
+type partitionReport struct {
+	files int
+	// unpartitioned is the number of files without any partition key. Such files are small
+	// enough to be read in a single batch.
+	unpartitioned int
+	// unsorted are the files whose partition keys are not strictly increasing. The reducer
+	// would read their entries out of order.
+	unsorted []string
+	// maxOverlap is the max number of files whose ranges of partition keys include the same
+	// key, and overlapKey is such a key. The reducer has to merge all those files together.
+	maxOverlap int
+	overlapKey []byte
+}
+
+func (pr *partitionReport) String() string {
+	return fmt.Sprintf("files: %d unpartitioned: %d unsorted: %d max overlap: %d at key: %x",
+		pr.files, pr.unpartitioned, len(pr.unsorted), pr.maxOverlap, pr.overlapKey)
+}
+
+// checkPartitions reads the headers of the map files under mapDir and checks that their
+// partition keys are consistent with the order of compare. It is a debugging aid for the
+// ordering issues of the reduce phase.
+func checkPartitions(mapDir string, compare func(a, b []byte) int) (*partitionReport, error) {
+	files, _, err := mapFiles(mapDir)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &partitionReport{files: len(files)}
+	var starts, ends [][]byte
 	for _, fname := range files {
-		header, itr := newMapIterator(fname)
+		header, itr, err := newMapIterator(fname)
+		if err != nil {
+			return nil, err
+		}
+		if err := itr.Close(); err != nil {
+			return nil, err
+		}
+		var keys [][]byte
 		for _, k := range header.PartitionKeys {
-			if len(k) == 0 {
-				continue
+			if len(k) > 0 {
+				keys = append(keys, k)
 			}
-			partitions[string(k)] = struct{}{}
 		}
-		r.mapItrs = append(r.mapItrs, itr)
+		if len(keys) == 0 {
+			pr.unpartitioned++
+			continue
+		}
+		for i := 1; i < len(keys); i++ {
+			if compare(keys[i-1], keys[i]) >= 0 {
+				pr.unsorted = append(pr.unsorted, fname)
+				break
+			}
+		}
+		starts = append(starts, keys[0])
+		ends = append(ends, keys[len(keys)-1])
+	}
+
+	less := func(keys [][]byte) func(i, j int) bool {
+		return func(i, j int) bool { return compare(keys[i], keys[j]) < 0 }
+	}
+	sort.Slice(starts, less(starts))
+	sort.Slice(ends, less(ends))
+	// The number of ranges including a start key is the number of ranges started so far,
+	// minus the ones which ended before it.
+	var ended int
+	for i, start := range starts {
+		for ended < len(ends) && compare(ends[ended], start) < 0 {
+			ended++
+		}
+		if overlap := i + 1 - ended; overlap > pr.maxOverlap {
+			pr.maxOverlap = overlap
+			pr.overlapKey = start
+		}
+	}
+	return pr, nil
+}
+
+// partitionKeySet is the set of the partition keys of the map files.
+type partitionKeySet map[string]struct{}
+
+// add adds the partition keys of the map file with the header. readMapHeader decodes the keys of
+// all the format versions, so the headers of different versions can be added to the same set.
+func (ps partitionKeySet) add(header *pb.MapHeader) {
+	for _, k := range header.PartitionKeys {
+		if len(k) == 0 {
+			continue
+		}
+		ps[string(k)] = struct{}{}
 	}
+}
 
-	keys := make([][]byte, 0, len(partitions))
-	for k := range partitions {
+// sorted returns the partition keys in the order of the keys of the map entries.
+func (ps partitionKeySet) sorted() [][]byte {
+	keys := make([][]byte, 0, len(ps))
+	for k := range ps {
 		keys = append(keys, []byte(k))
 	}
 	sort.Slice(keys, func(i, j int) bool {
 		return y.CompareKeys(keys[i], keys[j]) < 0
 	})
+	return keys
+}
+
+// ReadPartitionKeys returns the partition keys of all the map files in mapDir, sorted and
+// without duplicates. These are the boundaries of the partitions the reduce phase reads the map
+// files in, so they can be used to plan a reduce outside of Dgraph. Only the headers of the map
+// files are read.
+func ReadPartitionKeys(mapDir string) ([][]byte, error) {
+	files, _, err := mapFiles(mapDir)
+	if err != nil {
+		return nil, err
+	}
+	partitions := make(partitionKeySet)
+	for _, fname := range files {
+		header, itr, err := newMapIterator(fname)
+		if err != nil {
+			return nil, err
+		}
+		if err := itr.Close(); err != nil {
+			return nil, err
+		}
+		partitions.add(header)
+	}
+	return partitions.sorted(), nil
+}
+
+func (r *reducer) Reduce() error {
+	files, total, err := mapFiles(r.mapDir)
+	if err != nil {
+		return err
+	}
+	glog.Infof("Got %d map files of compressed size: %s.\n",
+		len(files), humanize.IBytes(uint64(total)))
+
+	// Pick up map iterators and partition keys.
+	partitions := make(partitionKeySet)
+	for _, fname := range files {
+		header, itr, err := newMapIterator(fname)
+		if err != nil {
+			// Close the map files opened so far, as the reduce doesn't get to read them.
+			for _, itr := range r.mapItrs {
+				itr.Close()
+			}
+			r.mapItrs = nil
+			return err
+		}
+		partitions.add(header)
+		r.mapItrs = append(r.mapItrs, itr)
+	}
+
 	// Append nil for the last entries.
-	keys = append(keys, nil)
-	r.partitionKeys = keys
+	r.partitionKeys = append(partitions.sorted(), nil)
 
 	errCh := make(chan error, 2)
 	go func() {
@@ -237,15 +606,26 @@ func (r *reducer) Reduce() error {
 		errCh <- r.process()
 	}()
 
+	// Both goroutines are waited for, so that the map files are closed once Reduce returns.
+	var rerr error
 	for i := 0; i < 2; i++ {
-		if err := <-errCh; err != nil {
-			return err
+		if err := <-errCh; err != nil && rerr == nil {
+			rerr = err
 		}
 	}
-	return nil
+	return rerr
 }
 
 func (r *reducer) blockingRead() error {
+	// The channel is closed on every return, so that process doesn't wait for more buffers
+	// once the read fails, and the map files are closed as they are no longer read.
+	defer func() {
+		close(r.bufferCh)
+		for _, itr := range r.mapItrs {
+			itr.Close()
+		}
+	}()
+
 	cbuf := getBuf()
 
 	sortAndPush := func(buf *z.Buffer) {
@@ -278,11 +658,17 @@ func (r *reducer) blockingRead() error {
 	} else {
 		cbuf.Release()
 	}
-	close(r.bufferCh)
 	return nil
 }
 
 func (r *reducer) process() error {
+	// The buffers left are drained on every return, so that blockingRead doesn't block on a
+	// full channel once the writes fail.
+	defer func() {
+		for cbuf := range r.bufferCh {
+			cbuf.Release()
+		}
+	}()
 	if r.w == nil {
 		return nil
 	}
@@ -309,11 +695,13 @@ func (r *reducer) process() error {
 			return nil
 		})
 		if err != nil {
+			cbuf.Release()
 			return err
 		}
 
 		atomic.AddUint64(&r.bytesProcessed, uint64(cbuf.LenNoPadding()))
 		if err := writer.Write(kvBuf); err != nil {
+			cbuf.Release()
 			return err
 		}
 		kvBuf.Reset()
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/ristretto/z"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// shouldSplit returns whether the complete posting list is big enough to be rolled up into a
+// multi-part list.
+func (p *processor) shouldSplit(pl *pb.PostingList) bool {
+	shouldSplit := posting.ShouldSplit(pl)
+	if size := p.opts.PostingSplitSize; size > 0 {
+		// The split size of the target cluster replaces the one of this binary.
+		shouldSplit = posting.ShouldSplitAt(pl, size)
+	}
+	if limit := p.opts.MaxPostingListBytes; limit > 0 && !shouldSplit {
+		shouldSplit = posting.ShouldSplitAt(pl, limit)
+	}
+	return shouldSplit
+}
+
+// rollup rolls up the complete posting list of e and appends its parts to the buffer.
+func (p *processor) rollup(buf *z.Buffer, e *backupKV, pl *pb.PostingList) error {
+	kv := e.kv
+	// Rollup will take ownership of the Pack and will free the memory.
+	l := posting.NewList(e.key, pl, kv.Version)
+	start := time.Now()
+	opts := posting.RollupOptions{
+		SplitSize: p.opts.PostingSplitSize,
+		MaxSize:   p.opts.MaxPostingListBytes,
+	}
+	if t := p.opts.ParallelRollupThreshold; t > 0 && len(kv.Value) >= t {
+		// This list is huge. Split it using multiple goroutines so that it doesn't
+		// become a straggler at the end of the map phase.
+		opts.Concurrency = runtime.NumCPU()
+	}
+	kvs, err := l.RollupWith(nil, opts)
+	if err != nil {
+		// TODO: wrap errors in this file for easier debugging.
+		return err
+	}
+	p.addRollupCost(e.pk.Attr, time.Since(start))
+	if len(kvs) == 0 {
+		// The key would silently disappear from the restore otherwise.
+		atomic.AddUint64(&p.emptyRollups, 1)
+		if !p.opts.KeepEmptyRollups {
+			return nil
+		}
+		empty := posting.MarshalPostingList(&pb.PostingList{}, nil)
+		empty.Key, empty.Version = e.key, kv.Version
+		kvs = append(kvs, empty)
+	}
+	for _, kv := range kvs {
+		version := kv.Version
+		kv.Version = e.writeTs
+		if err := p.toBuffer(buf, e, kv, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollupCost is the number of posting lists rolled up and the time spent rolling them up.
+type rollupCost struct {
+	count int64
+	time  time.Duration
+}
+
+// predRollupCost is the rollupCost of a predicate.
+type predRollupCost struct {
+	attr string
+	rollupCost
+}
+
+func (c predRollupCost) String() string {
+	return fmt.Sprintf("%s: %s in %d rollups", c.attr, c.time.Round(time.Millisecond), c.count)
+}
+
+// maxRollupCostPreds is the number of predicates reported in mapResult.rollupCosts.
+const maxRollupCostPreds = 10
+
+// addRollupCost adds the time of a rollup to the cost of the predicate.
+func (p *processor) addRollupCost(attr string, d time.Duration) {
+	if p.rollups == nil {
+		p.rollups = make(map[string]*rollupCost)
+	}
+	cost, ok := p.rollups[attr]
+	if !ok {
+		cost = &rollupCost{}
+		p.rollups[attr] = cost
+	}
+	cost.count++
+	cost.time += d
+}
+
+// topRollupCosts returns the n predicates with the largest total rollup time, most expensive
+// first.
+func topRollupCosts(costs map[string]*rollupCost, n int) []predRollupCost {
+	top := make([]predRollupCost, 0, len(costs))
+	for attr, cost := range costs {
+		top = append(top, predRollupCost{attr: attr, rollupCost: *cost})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].time != top[j].time {
+			return top[i].time > top[j].time
+		}
+		return top[i].attr < top[j].attr
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// emptyRollupCount returns the number of complete posting lists whose rollup returned no KV.
+func (m *mapper) emptyRollupCount() uint64 {
+	return atomic.LoadUint64(&m.emptyRollups)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/dgryski/go-farm"
+	"github.com/golang/glog"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// keepSample returns whether the posting list of the key is kept with SampleRate. The decision
+// only depends on the key, so the same keys are kept in every run. The parts of a multi-part
+// list are kept or skipped together.
+func (m *mapper) keepSample(key []byte, pk x.ParsedKey) bool {
+	rate := m.opts.SampleRate
+	if rate == 0 || rate >= 1 {
+		return true
+	}
+	// Skip the prefix byte, which differs between a list and its parts.
+	base := key[1:]
+	if pk.HasStartUid {
+		base = base[:len(base)-8]
+	}
+	atomic.AddUint64(&m.sampleTotal, 1)
+	if farm.Fingerprint64(base) >= uint64(rate*math.MaxUint64) {
+		return false
+	}
+	atomic.AddUint64(&m.sampledKeys, 1)
+	return true
+}
+
+// sampleCounts returns the number of posting lists kept with SampleRate, and the number of
+// posting lists they were sampled from.
+func (m *mapper) sampleCounts() (uint64, uint64) {
+	sampled, total := atomic.LoadUint64(&m.sampledKeys), atomic.LoadUint64(&m.sampleTotal)
+	if total > 0 {
+		glog.Infof("%sSampled %d posting lists out of %d with SampleRate: %v", m.logPrefix,
+			sampled, total, m.opts.SampleRate)
+	}
+	return sampled, total
+}
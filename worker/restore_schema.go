@@ -0,0 +1,257 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+const (
+	// schemaFormat2011 is the format of the schema in the releases before 2103, which did not
+	// have namespaces.
+	schemaFormat2011 = 2011
+	// schemaFormat2103 is the format of the schema in the 2103 release, where the predicates
+	// in the schema and type updates were stored as <namespace 8 bytes><attribute>.
+	schemaFormat2103 = 2103
+)
+
+// transformSchema applies the transformations of the options to the schema or type update of
+// e. It returns true if the update is skipped.
+func (p *processor) transformSchema(in *loadBackupInput, e *backupKV) (bool, error) {
+	kv, parsedKey := e.kv, e.pk
+	var err error
+	// We changed the format of predicate in 2103 and 2105. SchemaUpdate and TypeUpdate have
+	// predicate stored within them, so they also need to be updated accordingly.
+	switch in.version {
+	case 0:
+		if parsedKey.IsType() {
+			if kv.Value, err = appendTypeNamespace(kv.Value); err != nil {
+				glog.Errorf("Unable to (un)marshal type: %+v. Err=%v\n", parsedKey, err)
+				return true, nil
+			}
+		}
+	case 2103:
+		if kv.Value, err = changeSchemaFormat(kv.Value, parsedKey); err != nil {
+			glog.Errorf("Unable to change format for: %+v Err=%+v", parsedKey, err)
+			return true, nil
+		}
+	default:
+		// for manifest versions >= 2015, do nothing.
+	}
+	if parsedKey.IsType() && !p.keepType(parsedKey.Attr) {
+		return true, nil
+	}
+	if parsedKey.IsSchema() {
+		if err := p.checkSchemaDirectives(parsedKey.Attr, kv.Value); err != nil {
+			return false, err
+		}
+	}
+	if p.opts.CheckSchemaNamespaces && parsedKey.IsSchema() {
+		if err := p.checkSchemaNamespace(parsedKey, kv.Value); err != nil {
+			return false, errors.Wrapf(err, "while checking namespace of schema %s",
+				parsedKey.Attr)
+		}
+	}
+	if len(p.opts.inferredTypes) > 0 && parsedKey.IsSchema() {
+		_, attr := x.ParseNamespaceAttr(parsedKey.Attr)
+		if typ, ok := p.opts.inferredTypes[attr]; ok {
+			if kv.Value, err = inferSchemaType(kv.Value, typ,
+				p.opts.ForceTypeInference); err != nil {
+				return false, errors.Wrapf(err, "while inferring type of %s", parsedKey.Attr)
+			}
+		}
+	}
+	if len(p.opts.indexRebuild) > 0 && parsedKey.IsSchema() {
+		_, attr := x.ParseNamespaceAttr(parsedKey.Attr)
+		if hint, ok := p.opts.indexRebuild[attr]; ok {
+			if kv.Value, err = setIndexRebuild(kv.Value, hint); err != nil {
+				return false, errors.Wrapf(err, "while setting index rebuild of %s",
+					parsedKey.Attr)
+			}
+		}
+	}
+	if p.opts.RedactValues && parsedKey.IsSchema() {
+		if kv.Value, err = redactSchema(kv.Value); err != nil {
+			return false, errors.Wrapf(err, "while redacting schema of %s", parsedKey.Attr)
+		}
+	}
+	if p.opts.StripNamespaces {
+		if skip, err := p.stripSchemaNamespace(in, e); skip || err != nil {
+			return skip, err
+		}
+	}
+	if p.opts.TargetSchemaFormat != 0 {
+		if kv.Value, err = downgradeSchema(kv.Value, parsedKey,
+			p.opts.TargetSchemaFormat); err != nil {
+			return false, errors.Wrapf(err, "while downgrading schema of %s", parsedKey.Attr)
+		}
+	}
+	return false, nil
+}
+
+// keepType returns whether the type key of the namespaced type name is kept with IncludeTypes
+// and ExcludeTypes.
+func (p *processor) keepType(attr string) bool {
+	_, name := x.ParseNamespaceAttr(attr)
+	if _, ok := p.opts.excludeTypes[name]; ok {
+		return false
+	}
+	if len(p.opts.includeTypes) == 0 {
+		return true
+	}
+	_, ok := p.opts.includeTypes[name]
+	return ok
+}
+
+// appendTypeNamespace appends the namespace of the galaxy to the fields of the marshalled
+// TypeUpdate, for the backups taken on the versions before namespaces.
+func appendTypeNamespace(val []byte) ([]byte, error) {
+	var update pb.TypeUpdate
+	if err := update.Unmarshal(val); err != nil {
+		return nil, err
+	}
+	update.TypeName = x.GalaxyAttr(update.TypeName)
+	for _, sch := range update.Fields {
+		sch.Predicate = x.GalaxyAttr(sch.Predicate)
+	}
+	return update.Marshal()
+}
+
+// changeSchemaFormat changes the format of the predicates of the marshalled schema or type
+// update of the key. In the backup taken on 2103, we have the schemaUpdate.Predicate in format
+// <namespace 8 bytes>|<attribute>. That had issues with JSON marshalling. So, we switched over
+// to the format <namespace hex string>-<attribute>.
+func changeSchemaFormat(val []byte, parsedKey x.ParsedKey) ([]byte, error) {
+	var err error
+	if parsedKey.IsSchema() {
+		var update pb.SchemaUpdate
+		if err := update.Unmarshal(val); err != nil {
+			return nil, err
+		}
+		if update.Predicate, err = x.AttrFrom2103(update.Predicate); err != nil {
+			return nil, err
+		}
+		return update.Marshal()
+	}
+	if parsedKey.IsType() {
+		var update pb.TypeUpdate
+		if err := update.Unmarshal(val); err != nil {
+			return nil, err
+		}
+		if update.TypeName, err = x.AttrFrom2103(update.TypeName); err != nil {
+			return nil, err
+		}
+		for _, sch := range update.Fields {
+			if sch.Predicate, err = x.AttrFrom2103(sch.Predicate); err != nil {
+				return nil, err
+			}
+		}
+		return update.Marshal()
+	}
+	return val, nil
+}
+
+// inferSchemaType sets the type of the predicate in the schema update to typ. The type is only
+// overwritten if the schema doesn't have one, unless force is set.
+func inferSchemaType(val []byte, typ types.TypeID, force bool) ([]byte, error) {
+	var update pb.SchemaUpdate
+	if err := update.Unmarshal(val); err != nil {
+		return nil, err
+	}
+	if update.ValueType != pb.Posting_DEFAULT && !force {
+		return val, nil
+	}
+	update.ValueType = typ.Enum()
+	return update.Marshal()
+}
+
+// setIndexRebuild sets the IndexRebuild hint of the marshalled schema update.
+func setIndexRebuild(val []byte, hint pb.SchemaUpdate_IndexRebuild) ([]byte, error) {
+	var update pb.SchemaUpdate
+	if err := update.Unmarshal(val); err != nil {
+		return nil, err
+	}
+	update.IndexRebuild = hint
+	return update.Marshal()
+}
+
+// downgradeSchema converts the names stored within a schema or type update from the current
+// format to the given older format. It is the inverse of the conversions done for backups
+// taken on older versions.
+//
+// Converting to schemaFormat2103 is lossless. Converting to schemaFormat2011 is lossless only
+// for the galaxy namespace, as the older format has no way to represent other namespaces.
+// An error is returned for the schema of any other namespace.
+func downgradeSchema(val []byte, parsedKey x.ParsedKey, target int) ([]byte, error) {
+	var convert func(attr string) (string, error)
+	switch target {
+	case schemaFormat2103:
+		convert = func(attr string) (string, error) {
+			return x.AttrTo2103(attr), nil
+		}
+	case schemaFormat2011:
+		convert = func(attr string) (string, error) {
+			ns, pred := x.ParseNamespaceAttr(attr)
+			if ns != x.GalaxyNamespace {
+				return "", errors.Errorf("cannot convert %s in namespace %#x to format %d",
+					pred, ns, target)
+			}
+			return pred, nil
+		}
+	default:
+		return nil, errors.Errorf("unsupported target schema format: %d", target)
+	}
+	return rewriteSchemaAttrs(val, parsedKey, convert)
+}
+
+// rewriteSchemaAttrs converts the predicates and the type name held by the value of a schema
+// or type key.
+func rewriteSchemaAttrs(val []byte, parsedKey x.ParsedKey,
+	convert func(attr string) (string, error)) ([]byte, error) {
+	var err error
+	switch {
+	case parsedKey.IsSchema():
+		var update pb.SchemaUpdate
+		if err := update.Unmarshal(val); err != nil {
+			return nil, err
+		}
+		if update.Predicate, err = convert(update.Predicate); err != nil {
+			return nil, err
+		}
+		return update.Marshal()
+	case parsedKey.IsType():
+		var update pb.TypeUpdate
+		if err := update.Unmarshal(val); err != nil {
+			return nil, err
+		}
+		if update.TypeName, err = convert(update.TypeName); err != nil {
+			return nil, err
+		}
+		for _, sch := range update.Fields {
+			if sch.Predicate, err = convert(sch.Predicate); err != nil {
+				return nil, err
+			}
+		}
+		return update.Marshal()
+	}
+	return val, nil
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/tok"
+)
+
+// schemaUpdateFields is the set of the field numbers of pb.SchemaUpdate known to this binary.
+var schemaUpdateFields = func() map[int]struct{} {
+	fields := make(map[int]struct{})
+	for _, prop := range proto.GetProperties(reflect.TypeOf(pb.SchemaUpdate{})).Prop {
+		if prop.Tag > 0 {
+			fields[prop.Tag] = struct{}{}
+		}
+	}
+	return fields
+}()
+
+// unknownSchemaDirectives returns the parts of the marshalled schema update which this binary
+// doesn't know, like the ones added by a newer version: the values of its enums which are not
+// in their enumerations, the tokenizers which are not registered, and the fields which are not
+// in pb.SchemaUpdate. Unmarshal silently drops the unknown fields, so they are found from the
+// wire format.
+func unknownSchemaDirectives(val []byte) ([]string, error) {
+	var update pb.SchemaUpdate
+	if err := update.Unmarshal(val); err != nil {
+		return nil, err
+	}
+	var unknown []string
+	if _, ok := pb.Posting_ValType_name[int32(update.ValueType)]; !ok {
+		unknown = append(unknown, fmt.Sprintf("value type: %d", update.ValueType))
+	}
+	if _, ok := pb.SchemaUpdate_Directive_name[int32(update.Directive)]; !ok {
+		unknown = append(unknown, fmt.Sprintf("directive: %d", update.Directive))
+	}
+	if _, ok := pb.SchemaUpdate_IndexRebuild_name[int32(update.IndexRebuild)]; !ok {
+		unknown = append(unknown, fmt.Sprintf("index rebuild: %d", update.IndexRebuild))
+	}
+	for _, name := range update.Tokenizer {
+		if _, ok := tok.GetTokenizer(name); !ok {
+			unknown = append(unknown, fmt.Sprintf("tokenizer: %s", name))
+		}
+	}
+
+	seen := make(map[int]struct{})
+	for b := val; len(b) > 0; {
+		tag, n := proto.DecodeVarint(b)
+		if n == 0 {
+			return nil, errors.New("invalid field tag in schema update")
+		}
+		b = b[n:]
+		field, wireType := int(tag>>3), tag&7
+		if _, ok := schemaUpdateFields[field]; !ok {
+			if _, ok := seen[field]; !ok {
+				seen[field] = struct{}{}
+				unknown = append(unknown, fmt.Sprintf("field: %d", field))
+			}
+		}
+		var skip int
+		switch wireType {
+		case proto.WireVarint:
+			if _, skip = proto.DecodeVarint(b); skip == 0 {
+				return nil, errors.Errorf("invalid varint of field %d in schema update", field)
+			}
+		case proto.WireFixed64:
+			skip = 8
+		case proto.WireFixed32:
+			skip = 4
+		case proto.WireBytes:
+			sz, n := proto.DecodeVarint(b)
+			if n == 0 || sz > uint64(len(b)-n) {
+				return nil, errors.Errorf("invalid length of field %d in schema update", field)
+			}
+			skip = n + int(sz)
+		default:
+			return nil, errors.Errorf("unsupported wire type %d of field %d in schema update",
+				wireType, field)
+		}
+		if skip > len(b) {
+			return nil, errors.Errorf("truncated field %d in schema update", field)
+		}
+		b = b[skip:]
+	}
+	return unknown, nil
+}
+
+// checkSchemaDirectives checks the schema update of the predicate for the directives unknown
+// to this binary. They fail the restore with MapOptions.StrictSchema, and are logged and
+// restored as they are otherwise.
+func (p *processor) checkSchemaDirectives(attr string, val []byte) error {
+	unknown, err := unknownSchemaDirectives(val)
+	if err != nil {
+		if p.opts.StrictSchema {
+			return errors.Wrapf(err, "while checking the schema of %s", describeAttr(attr))
+		}
+		glog.Warningf("%sUnable to check the schema of %s. Err: %v", p.logPrefix,
+			describeAttr(attr), err)
+		return nil
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	if p.opts.StrictSchema {
+		return errors.Errorf("schema of predicate %s has directives unknown to this binary: %s",
+			describeAttr(attr), strings.Join(unknown, ", "))
+	}
+	glog.Warningf("%sSchema of predicate %s has directives unknown to this binary: %s. It is"+
+		" restored as it is", p.logPrefix, describeAttr(attr), strings.Join(unknown, ", "))
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestStrictSchema(t *testing.T) {
+	known, err := (&pb.SchemaUpdate{Predicate: x.GalaxyAttr("name"),
+		ValueType: pb.Posting_STRING, Directive: pb.SchemaUpdate_INDEX,
+		Tokenizer: []string{"exact", "term"}, Count: true}).Marshal()
+	require.NoError(t, err)
+	unknown, err := unknownSchemaDirectives(known)
+	require.NoError(t, err)
+	require.Empty(t, unknown)
+
+	// A schema written by a newer version, with a new directive, a new tokenizer, and a new
+	// repeated field.
+	newer, err := (&pb.SchemaUpdate{Predicate: x.GalaxyAttr("name"),
+		ValueType: pb.Posting_STRING, Directive: pb.SchemaUpdate_Directive(7),
+		Tokenizer: []string{"exact", "vector"}}).Marshal()
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		newer = append(newer, proto.EncodeVarint(30<<3|proto.WireBytes)...)
+		newer = append(newer, proto.EncodeVarint(2)...)
+		newer = append(newer, "on"...)
+	}
+	unknown, err = unknownSchemaDirectives(newer)
+	require.NoError(t, err)
+	require.Equal(t, []string{"directive: 7", "tokenizer: vector", "field: 30"}, unknown)
+
+	_, err = unknownSchemaDirectives(append(known, proto.EncodeVarint(31<<3|proto.WireBytes)...))
+	require.Error(t, err)
+
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		keepSchema: true}
+	for _, strict := range []bool{false, true} {
+		opts := MapOptions{StrictSchema: strict}
+		require.NoError(t, opts.validate())
+		p := newProcessor(newMapper(10, "", opts, 2))
+		buf := z.NewBuffer(1<<10, "TestStrictSchema")
+		kv := schemaKV(t, x.GalaxyNamespace, "name")
+		kv.Value = newer
+		err := p.processKV(buf, in, kv)
+		if strict {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "directive: 7, tokenizer: vector, field: 30")
+		} else {
+			// The schema is restored as it is.
+			require.NoError(t, err)
+			require.NotZero(t, buf.LenNoPadding())
+		}
+		buf.Release()
+	}
+}
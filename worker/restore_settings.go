@@ -0,0 +1,100 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// postingEncodingRoaring is the encoding of the uids of the posting lists of this version.
+const postingEncodingRoaring = "roaring"
+
+// BackupSettings are the settings of the binary which took a backup that the restore depends
+// on. A backup taken with different settings might hold posting lists that the restoring
+// binary doesn't expect, like parts bigger than the ones it would split them into.
+type BackupSettings struct {
+	// MaxListSize is the size in bytes above which the posting lists are split into parts.
+	MaxListSize int `json:"max_list_size"`
+	// MagicVersion is the magic version of the badger stores of the binary.
+	MagicVersion uint16 `json:"magic_version"`
+	// PostingEncoding is the encoding of the uids of the posting lists.
+	PostingEncoding string `json:"posting_encoding"`
+}
+
+// currentBackupSettings returns the settings of this binary.
+func currentBackupSettings() *BackupSettings {
+	return &BackupSettings{
+		MaxListSize:     posting.MaxListSize(),
+		MagicVersion:    x.MagicVersion,
+		PostingEncoding: postingEncodingRoaring,
+	}
+}
+
+// settingsMismatches returns the settings of the backups to restore which differ from the ones
+// of this binary, ordered like the manifests. The backups which don't record their settings
+// are skipped, as nothing is known about them.
+func settingsMismatches(manifests []*Manifest, incrementalFrom uint64) []string {
+	cur := currentBackupSettings()
+	var res []string
+	for _, manifest := range manifests {
+		if manifest.BackupNum < incrementalFrom {
+			break
+		}
+		s := manifest.Settings
+		if s == nil {
+			continue
+		}
+		mismatch := func(name string, got, want interface{}) {
+			res = append(res, fmt.Sprintf("manifest num: %d, path: %s has %s: %v, this binary"+
+				" uses %v", manifest.BackupNum, manifest.Path, name, got, want))
+		}
+		if s.MaxListSize != cur.MaxListSize {
+			mismatch("max_list_size", s.MaxListSize, cur.MaxListSize)
+		}
+		if s.MagicVersion != cur.MagicVersion {
+			mismatch("magic_version", s.MagicVersion, cur.MagicVersion)
+		}
+		if s.PostingEncoding != cur.PostingEncoding {
+			mismatch("posting_encoding", s.PostingEncoding, cur.PostingEncoding)
+		}
+	}
+	return res
+}
+
+// checkBackupSettings logs the settings of the backups which differ from the ones of this
+// binary, or returns an error listing them if strict is set.
+func checkBackupSettings(manifests []*Manifest, incrementalFrom uint64, strict bool) error {
+	mismatches := settingsMismatches(manifests, incrementalFrom)
+	if len(mismatches) == 0 {
+		return nil
+	}
+	if strict {
+		return errors.Errorf("the backups were taken with different settings:\n%s",
+			strings.Join(mismatches, "\n"))
+	}
+	for _, m := range mismatches {
+		glog.Warningf("Backup taken with different settings: %s", m)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBackupSettings(t *testing.T) {
+	cur := currentBackupSettings()
+	other := *cur
+	other.MaxListSize *= 2
+	other.PostingEncoding = "uidpack"
+
+	manifests := []*Manifest{
+		{BackupNum: 3, Path: "inc2", Settings: cur},
+		{BackupNum: 2, Path: "inc1", Settings: &other},
+		// The backups taken before the settings were recorded are not checked.
+		{BackupNum: 1, Path: "full"},
+	}
+	mismatches := settingsMismatches(manifests, 0)
+	require.Len(t, mismatches, 2)
+	require.Contains(t, mismatches[0], "manifest num: 2, path: inc1 has max_list_size")
+	require.Contains(t, mismatches[1], "posting_encoding: uidpack")
+	require.NoError(t, checkBackupSettings(manifests, 0, false))
+	err := checkBackupSettings(manifests, 0, true)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max_list_size")
+
+	// The manifests before the incremental restore are not checked.
+	require.Empty(t, settingsMismatches(manifests, 3))
+	require.NoError(t, checkBackupSettings(manifests[:1], 0, true))
+
+	// The settings round trip through the manifest.
+	b, err := json.Marshal(&Manifest{Settings: cur})
+	require.NoError(t, err)
+	var m Manifest
+	require.NoError(t, json.Unmarshal(b, &m))
+	require.Equal(t, cur, m.Settings)
+}
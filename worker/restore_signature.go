@@ -0,0 +1,114 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// ManifestVerifier verifies the manifests of the backups to restore before anything is mapped,
+// see MapOptions.ManifestVerifier.
+type ManifestVerifier interface {
+	// Verify returns an error if the manifest can't be trusted. h is the handler of the
+	// location holding the backup of the manifest.
+	Verify(h x.UriHandler, manifest *Manifest) error
+}
+
+// manifestSignatureName is the name of the detached signature of a manifest, in the
+// directory of its backup.
+const manifestSignatureName = backupManifest + ".sig"
+
+// ManifestSignedPayload returns the bytes signed for the manifest, which are its JSON as it is
+// read for a restore. For the manifests written by the current version, it is the JSON of the
+// manifest as it was written by the backup.
+func ManifestSignedPayload(manifest *Manifest) ([]byte, error) {
+	return json.Marshal(manifest)
+}
+
+type ed25519ManifestVerifier struct {
+	key ed25519.PublicKey
+}
+
+// NewEd25519ManifestVerifier returns a ManifestVerifier checking the Ed25519 signatures of the
+// manifests with the public key, in the PEM encoded PKIX format. The signature of a manifest
+// is read from the manifest.json.sig file of the directory of its backup, as the base64
+// encoding of the signature of ManifestSignedPayload.
+func NewEd25519ManifestVerifier(pemKey []byte) (ManifestVerifier, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("no PEM block found in the public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "while parsing the public key")
+	}
+	key, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("public key of type %T is not an Ed25519 key", pub)
+	}
+	return &ed25519ManifestVerifier{key: key}, nil
+}
+
+func (v *ed25519ManifestVerifier) Verify(h x.UriHandler, manifest *Manifest) error {
+	path := filepath.Join(manifest.Path, manifestSignatureName)
+	if !h.FileExists(path) {
+		return errors.Errorf("signature %s is missing", path)
+	}
+	b, err := h.Read(path)
+	if err != nil {
+		return errors.Wrapf(err, "while reading signature %s", path)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return errors.Wrapf(err, "while decoding signature %s", path)
+	}
+	payload, err := ManifestSignedPayload(manifest)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(v.key, payload, sig) {
+		return errors.Errorf("signature %s doesn't match the manifest", path)
+	}
+	return nil
+}
+
+// verifyManifestSignatures verifies the manifests with the verifier. handlers holds the
+// handlers of the manifests when they come from several locations, and h is used otherwise.
+func verifyManifestSignatures(verifier ManifestVerifier, manifests []*Manifest, h x.UriHandler,
+	handlers map[*Manifest]x.UriHandler) error {
+	for _, manifest := range manifests {
+		mh := h
+		if handlers != nil {
+			mh = handlers[manifest]
+		}
+		if err := verifier.Verify(mh, manifest); err != nil {
+			return errors.Wrapf(err, "manifest num: %d of backup: %s in %s failed verification",
+				manifest.BackupNum, manifest.BackupId, manifest.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestManifestVerifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-signature")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	verifier, err := NewEd25519ManifestVerifier(
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	_, err = NewEd25519ManifestVerifier([]byte("not a key"))
+	require.Error(t, err)
+
+	sign := func(manifest *Manifest) {
+		payload, err := ManifestSignedPayload(manifest)
+		require.NoError(t, err)
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, payload))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, manifest.Path), 0750))
+		require.NoError(t, ioutil.WriteFile(
+			filepath.Join(dir, manifest.Path, manifestSignatureName), []byte(sig+"\n"), 0600))
+	}
+	h := x.NewFileHandler(&url.URL{Path: dir})
+	full := &Manifest{Type: "full", BackupId: "b", BackupNum: 1, ReadTs: 10, Path: "full",
+		Groups: map[uint32][]string{1: {x.GalaxyAttr("name")}}}
+	inc := &Manifest{Type: "incremental", BackupId: "b", BackupNum: 2, ReadTs: 20, Path: "inc",
+		Groups: map[uint32][]string{1: {x.GalaxyAttr("name")}}}
+	sign(full)
+	require.NoError(t, verifier.Verify(h, full))
+	require.Error(t, verifier.Verify(h, inc))
+
+	// A drop operation added to the signed manifest fails the verification.
+	sign(inc)
+	require.NoError(t, verifier.Verify(h, inc))
+	inc.DropOperations = []*pb.DropOperation{{DropOp: pb.DropOperation_ALL}}
+	err = verifyManifestSignatures(verifier, []*Manifest{inc, full}, h, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "manifest num: 2 of backup: b in inc")
+	require.Contains(t, err.Error(), "doesn't match the manifest")
+}
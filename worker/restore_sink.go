@@ -0,0 +1,104 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/pkg/errors"
+)
+
+// MapSink receives the map entries as they are processed, alongside the map files written to
+// the map directory. It lets a caller stream the entries into a live store without reading the
+// backups a second time.
+type MapSink interface {
+	// Consume is called by the processors with every batch of entries sent for writing. It
+	// takes the ownership of the batch, even if it returns an error, and must call
+	// batch.Release once done with it. The batch can be kept past the return of Consume, but
+	// the processor calling it is blocked until it returns. An error fails the map phase.
+	Consume(batch *MapBatch) error
+}
+
+// MapBatch is a batch of map entries handed to a MapSink. The entries are not sorted, and a
+// key might have several versions in a batch, or be spread over several batches. The batch
+// shares its buffer with the merger writing the entries to the map files, and the buffer is
+// only reused once both of them are done with it.
+type MapBatch struct {
+	mb   *mapBuffer
+	once sync.Once
+}
+
+// BackupNum returns the number of the manifest the entries were read from.
+func (b *MapBatch) BackupNum() uint64 {
+	return b.mb.backupNum
+}
+
+// Iterate calls fn with the key and the marshalled pb.KV of every entry of the batch. The
+// slices are only valid until the batch is released.
+func (b *MapBatch) Iterate(fn func(key, kv []byte) error) error {
+	return b.mb.buf.SliceIterate(func(s []byte) error {
+		me := mapEntry(s)
+		return fn(me.Key(), me.Data())
+	})
+}
+
+// Release gives the buffer of the batch back. It is a no-op if called more than once.
+func (b *MapBatch) Release() {
+	b.once.Do(b.mb.release)
+}
+
+// release drops a reference to the buffer, and gives it back to its pool once no one holds it.
+func (mb *mapBuffer) release() {
+	if atomic.AddInt32(&mb.refs, -1) == 0 {
+		mb.pool.put(mb.buf)
+	}
+}
+
+// newMapBuffer returns the mapBuffer of buf, a buffer of m.procBufs.
+func (m *mapper) newMapBuffer(buf *z.Buffer, backupNum uint64, acks []*frameAck) *mapBuffer {
+	return &mapBuffer{buf: buf, backupNum: backupNum, acks: acks, refs: 1, pool: m.procBufs}
+}
+
+// sendBuffer sends mb to the mergers, and to opts.Sink if there is one. mb is released if it
+// can't be sent to the mergers.
+func (m *mapper) sendBuffer(ctx context.Context, mb *mapBuffer) error {
+	if m.opts.Sink != nil && !mb.buf.IsEmpty() {
+		atomic.AddInt32(&mb.refs, 1)
+		if err := m.opts.Sink.Consume(&MapBatch{mb: mb}); err != nil {
+			mb.release()
+			return errors.Wrapf(err, "while sending the map entries to the sink")
+		}
+	}
+	select {
+	case m.writeCh <- mb:
+		return nil
+	default:
+	}
+	start := time.Now()
+	defer m.addWait(waitWriteCh, start)
+	select {
+	case m.writeCh <- mb:
+		return nil
+	case <-ctx.Done():
+		mb.release()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	bpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// chanSink sends the batches to a channel, so that they are consumed and released by another
+// goroutine while the mergers write out the same buffers.
+type chanSink chan *MapBatch
+
+func (c chanSink) Consume(batch *MapBatch) error {
+	c <- batch
+	return nil
+}
+
+type failingSink struct{}
+
+func (failingSink) Consume(batch *MapBatch) error {
+	batch.Release()
+	return errors.New("sink is full")
+}
+
+func TestMapSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-sink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 1000; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	in := &loadBackupInput{
+		preds:     predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		groupId:   1,
+		backupNum: 3,
+	}
+
+	sink := make(chanSink, 4)
+	sunk := make(map[string]struct{})
+	var sinkErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for batch := range sink {
+			if sinkErr == nil && batch.BackupNum() != 3 {
+				sinkErr = errors.Errorf("unexpected backup num: %d", batch.BackupNum())
+			}
+			err := batch.Iterate(func(key, kv []byte) error {
+				var out bpb.KV
+				sunk[string(key)] = struct{}{}
+				return out.Unmarshal(kv)
+			})
+			if sinkErr == nil {
+				sinkErr = err
+			}
+			batch.Release()
+			batch.Release()
+		}
+	}()
+
+	m := newMapper(10, dir, MapOptions{Sink: sink, ProcessFlushSize: 1 << 10}, 2)
+	m.startPipeline(2)
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.NoError(t, m.stopPipeline())
+	close(sink)
+	wg.Wait()
+	require.NoError(t, sinkErr)
+
+	// The sink got the same keys as the map files.
+	mapped := make(map[string]struct{})
+	files, _, err := mapFiles(dir)
+	require.NoError(t, err)
+	for _, file := range files {
+		_, itr, err := newMapIterator(file)
+		require.NoError(t, err)
+		cbuf := z.NewBuffer(1<<10, "TestMapSink")
+		require.NoError(t, itr.Next(cbuf, nil))
+		require.NoError(t, cbuf.SliceIterate(func(me []byte) error {
+			mapped[string(mapEntry(me).Key())] = struct{}{}
+			return nil
+		}))
+		cbuf.Release()
+		require.NoError(t, itr.Close())
+	}
+	require.Len(t, mapped, 1000)
+	require.Equal(t, mapped, sunk)
+
+	// An error of the sink fails the map phase.
+	m = newMapper(10, dir, MapOptions{Sink: failingSink{}}, 2)
+	m.startPipeline(2)
+	err = m.Map(bytes.NewReader(stream.Bytes()), in)
+	if err == nil {
+		err = m.stopPipeline()
+	}
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "sink is full")
+	m.stopPipeline()
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// The codecs of the map files, recorded in the Codec of their header. The map files written
+// before the codec was recorded are all snappy.
+const (
+	mapCodecSnappy = "snappy"
+	mapCodecGzip   = "gzip"
+)
+
+// The constants below are the ones of the snappy framing format, see
+// https://github.com/google/snappy/blob/master/framing_format.txt. A stream starts with
+// snappyMagic, its stream identifier chunk.
+const (
+	snappyChunkUncompressed = 0x01
+	// snappyMaxChunkLen is the max length of the data of a chunk.
+	snappyMaxChunkLen = 65536
+)
+
+var snappyCrcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// snappyChecksum returns the masked CRC-32C checksum of the data of a chunk.
+func snappyChecksum(b []byte) uint32 {
+	c := crc32.Update(0, snappyCrcTable, b)
+	return c>>15 | c<<17 + 0xa282ead8
+}
+
+// storedSnappyWriter writes a snappy stream whose chunks are all uncompressed. The stream can be
+// read by snappy.NewReader like any other snappy stream, but writing it only costs the checksum
+// of the data, which is much cheaper than compressing it.
+type storedSnappyWriter struct {
+	w           io.Writer
+	wroteHeader bool
+	hdr         [8]byte
+}
+
+// Write writes p as uncompressed chunks. The writes are meant to be buffered, as every write
+// ends with a chunk of its own.
+func (sw *storedSnappyWriter) Write(p []byte) (int, error) {
+	if !sw.wroteHeader {
+		if _, err := sw.w.Write(snappyMagic); err != nil {
+			return 0, err
+		}
+		sw.wroteHeader = true
+	}
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > snappyMaxChunkLen {
+			chunk = chunk[:snappyMaxChunkLen]
+		}
+		// The length of the chunk covers the checksum and the data.
+		n := len(chunk) + 4
+		sw.hdr[0] = snappyChunkUncompressed
+		sw.hdr[1], sw.hdr[2], sw.hdr[3] = byte(n), byte(n>>8), byte(n>>16)
+		binary.LittleEndian.PutUint32(sw.hdr[4:], snappyChecksum(chunk))
+		if _, err := sw.w.Write(sw.hdr[:]); err != nil {
+			return written, err
+		}
+		if _, err := sw.w.Write(chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// mapFileWriter is the writer of the snappy stream of a map file.
+type mapFileWriter interface {
+	io.Writer
+	Close() error
+}
+
+// bufferedStoredWriter buffers the writes to a storedSnappyWriter, so that the chunks are as
+// large as possible.
+type bufferedStoredWriter struct {
+	*bufio.Writer
+}
+
+func (bw bufferedStoredWriter) Close() error {
+	return bw.Flush()
+}
+
+// newMapFileWriter returns the writer of the snappy stream of a map file. The chunks are left
+// uncompressed if stored is set. Close doesn't close w.
+func newMapFileWriter(w io.Writer, stored bool) mapFileWriter {
+	if stored {
+		return bufferedStoredWriter{bufio.NewWriterSize(&storedSnappyWriter{w: w},
+			snappyMaxChunkLen)}
+	}
+	return snappy.NewBufferedWriter(w)
+}
+
+// gzipMapWriter buffers the small writes of the map entries to a gzip writer.
+type gzipMapWriter struct {
+	*bufio.Writer
+	gw *gzip.Writer
+}
+
+func (gw gzipMapWriter) Close() error {
+	if err := gw.Flush(); err != nil {
+		return err
+	}
+	return gw.gw.Close()
+}
+
+// newGzipMapFileWriter returns the writer of the gzip stream of a map file, compressed with
+// the level. Close doesn't close w.
+func newGzipMapFileWriter(w io.Writer, level int) (mapFileWriter, error) {
+	gw, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err
+	}
+	return gzipMapWriter{bufio.NewWriterSize(gw, snappyMaxChunkLen), gw}, nil
+}
+
+// newMapFileReader returns the reader of the stream of a map file, along with the codec it is
+// compressed with. The codec is found from the start of the stream, as the header recording it
+// is compressed as well.
+func newMapFileReader(r io.Reader) (io.Reader, string, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, "", err
+	}
+	if string(magic) == string(gzipMagic) {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "while reading the gzip stream")
+		}
+		return gr, mapCodecGzip, nil
+	}
+	return snappy.NewReader(br), mapCodecSnappy, nil
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// mapFileData returns sz bytes which look like map entries, with repeated keys and random
+// values.
+func mapFileData(sz int) []byte {
+	r := rand.New(rand.NewSource(1))
+	var b bytes.Buffer
+	for i := 0; b.Len() < sz; i++ {
+		fmt.Fprintf(&b, "\x00\x00\x00\x00\x00\x00\x00\x00\x0dname-%08d", i)
+		val := make([]byte, 32)
+		r.Read(val)
+		b.Write(val)
+	}
+	return b.Bytes()[:sz]
+}
+
+func TestStoredSnappyWriter(t *testing.T) {
+	data := mapFileData(3*snappyMaxChunkLen + 100)
+	for _, stored := range []bool{false, true} {
+		var out bytes.Buffer
+		w := newMapFileWriter(&out, stored)
+		// The data is written in pieces of different sizes, like the entries of a map file.
+		for rest := data; len(rest) > 0; {
+			n := 1 + len(rest)%1000
+			if len(rest) > 2*snappyMaxChunkLen {
+				n = snappyMaxChunkLen + 1
+			}
+			if n > len(rest) {
+				n = len(rest)
+			}
+			_, err := w.Write(rest[:n])
+			require.NoError(t, err)
+			rest = rest[n:]
+		}
+		require.NoError(t, w.Close())
+		if stored {
+			require.Greater(t, out.Len(), len(data))
+		}
+
+		read, err := ioutil.ReadAll(snappy.NewReader(&out))
+		require.NoError(t, err)
+		require.Equal(t, data, read)
+	}
+}
+
+func TestGzipMapFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-gzip")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := MapOptions{MapFileCompression: mapCodecGzip, MapFileGzipLevel: gzip.BestSpeed}
+	require.NoError(t, opts.validate())
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 100; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	m := newMapper(10, dir, opts, 2)
+	m.startPipeline(2)
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}}
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.NoError(t, m.stopPipeline())
+
+	files, _, err := mapFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := ioutil.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Equal(t, gzipMagic, data[:2])
+	header, itr, err := newMapIterator(files[0])
+	require.NoError(t, err)
+	require.Equal(t, mapCodecGzip, header.Codec)
+	cbuf := z.NewBuffer(1<<10, "TestGzipMapFiles")
+	defer cbuf.Release()
+	require.NoError(t, itr.Next(cbuf, nil))
+	require.NoError(t, itr.Close())
+	var n int
+	require.NoError(t, cbuf.SliceIterate(func([]byte) error {
+		n++
+		return nil
+	}))
+	require.Equal(t, 100, n)
+
+	// A snappy map file whose header records gzip is rejected.
+	headerBuf, err := (&pb.MapHeader{FormatVersion: mapFormatVersion,
+		Codec: mapCodecGzip}).Marshal()
+	require.NoError(t, err)
+	var out bytes.Buffer
+	w := snappy.NewBufferedWriter(&out)
+	require.NoError(t, binary.Write(w, binary.BigEndian, uint32(len(headerBuf))))
+	_, err = w.Write(headerBuf)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	bad := filepath.Join(dir, "bad.map")
+	require.NoError(t, ioutil.WriteFile(bad, out.Bytes(), 0600))
+	_, _, err = newMapIterator(bad)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "records codec")
+
+	for _, opts := range []MapOptions{
+		{MapFileCompression: "lz4"},
+		{MapFileCompression: mapCodecGzip, MapFileGzipLevel: 10},
+		{MapFileCompression: mapCodecGzip, UncompressedMapFiles: true},
+		{MapFileGzipLevel: gzip.BestSpeed},
+	} {
+		require.Error(t, opts.validate())
+	}
+}
+
+// BenchmarkMapFileCodecs compares the size of the map files and the CPU cost of writing and
+// reading them back with every codec.
+func BenchmarkMapFileCodecs(b *testing.B) {
+	data := mapFileData(64 << 20)
+	type codec struct {
+		name  string
+		write func(w io.Writer) (mapFileWriter, error)
+	}
+	codecs := []codec{
+		{"snappy", func(w io.Writer) (mapFileWriter, error) {
+			return newMapFileWriter(w, false), nil
+		}},
+		{"stored", func(w io.Writer) (mapFileWriter, error) {
+			return newMapFileWriter(w, true), nil
+		}},
+	}
+	for _, level := range []int{gzip.BestSpeed, gzip.DefaultCompression, gzip.BestCompression} {
+		level := level
+		codecs = append(codecs, codec{fmt.Sprintf("gzip-%d", level),
+			func(w io.Writer) (mapFileWriter, error) {
+				return newGzipMapFileWriter(w, level)
+			}})
+	}
+	write := func(b *testing.B, w mapFileWriter) {
+		for off := 0; off < len(data); off += 1 << 10 {
+			if _, err := w.Write(data[off : off+1<<10]); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for _, c := range codecs {
+		var compressed bytes.Buffer
+		w, err := c.write(&compressed)
+		if err != nil {
+			b.Fatal(err)
+		}
+		write(b, w)
+		ratio := float64(compressed.Len()) / float64(len(data))
+
+		b.Run(c.name+"/write", func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportMetric(ratio, "ratio")
+			for i := 0; i < b.N; i++ {
+				w, err := c.write(ioutil.Discard)
+				if err != nil {
+					b.Fatal(err)
+				}
+				write(b, w)
+			}
+		})
+		b.Run(c.name+"/read", func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				r, _, err := newMapFileReader(bytes.NewReader(compressed.Bytes()))
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := io.Copy(ioutil.Discard, r); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMapFileWriter(b *testing.B) {
+	data := mapFileData(64 << 20)
+	for _, stored := range []bool{false, true} {
+		b.Run(fmt.Sprintf("stored=%v", stored), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				w := newMapFileWriter(ioutil.Discard, stored)
+				for off := 0; off < len(data); off += 1 << 10 {
+					if _, err := w.Write(data[off : off+1<<10]); err != nil {
+						b.Fatal(err)
+					}
+				}
+				if err := w.Close(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/dgraph-io/badger/v3/y"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// The formats of the files written by the map phase, see MapOptions.MapFileFormat.
+const (
+	mapFormatNative = "native"
+	mapFormatSST    = "sst"
+)
+
+// The SST files are written in the block based table format of RocksDB, with format_version 1,
+// the way the SstFileWriter of RocksDB writes them, so that they can be bulk ingested with
+// IngestExternalFile. See https://github.com/facebook/rocksdb/wiki/Rocksdb-BlockBasedTable-Format.
+//
+// The user key of an entry is the Dgraph key of the map entry, without its version, as the
+// keys of an SST file must be unique. The value is the bpb.KV of the map entry, marshalled
+// with protobuf, which holds the key, the value, the user meta and the version to write to
+// badger. The keys are ordered with the bytewise comparator of RocksDB, which is the order of
+// y.CompareKeys for the keys without their version.
+const (
+	sstFileExt = ".sst"
+	// sstMagic is the magic number of the block based tables.
+	sstMagic         = 0x88e241b785f4cff7
+	sstFormatVersion = 1
+	// sstBlockSize is the size of the data blocks, which is the default of RocksDB.
+	sstBlockSize       = 4 << 10
+	sstRestartInterval = 16
+	// The compression types of the blocks.
+	sstNoCompression     = 0
+	sstSnappyCompression = 1
+	// sstChecksumCRC32c is the checksum type of the blocks, in the footer.
+	sstChecksumCRC32c = 1
+	// sstBlockTrailerLen is the length of the compression type and the checksum which follow
+	// every block.
+	sstBlockTrailerLen = 5
+	// sstMaxHandleLen is the max length of an encoded block handle, an offset and a size.
+	sstMaxHandleLen = 2 * binary.MaxVarintLen64
+	sstFooterLen    = 1 + 2*sstMaxHandleLen + 4 + 8
+	// sstTypeValue is the type of the internal keys of the values. The internal keys are all
+	// written with the sequence number 0, which the ingestion replaces with the global one.
+	sstTypeValue = 1
+)
+
+// sstHandle is the location of a block in an SST file. The size excludes the trailer.
+type sstHandle struct {
+	offset, size uint64
+}
+
+func (h sstHandle) encode(dst []byte) []byte {
+	dst = appendUvarint(dst, h.offset)
+	return appendUvarint(dst, h.size)
+}
+
+func appendUvarint(dst []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(dst, buf[:n]...)
+}
+
+// sstBlock builds a block of sorted entries, whose keys share their prefix with the key before
+// them, except at the restart points.
+type sstBlock struct {
+	restartInterval int
+	buf             []byte
+	restarts        []uint32
+	// counter is the number of entries since the last restart point.
+	counter int
+	entries int
+	lastKey []byte
+}
+
+func newSSTBlock(restartInterval int) *sstBlock {
+	b := &sstBlock{restartInterval: restartInterval}
+	b.reset()
+	return b
+}
+
+func (b *sstBlock) reset() {
+	b.buf = b.buf[:0]
+	b.restarts = append(b.restarts[:0], 0)
+	b.counter = 0
+	b.entries = 0
+	b.lastKey = b.lastKey[:0]
+}
+
+func (b *sstBlock) add(key, value []byte) {
+	shared := 0
+	if b.counter < b.restartInterval {
+		for shared < len(key) && shared < len(b.lastKey) && key[shared] == b.lastKey[shared] {
+			shared++
+		}
+	} else {
+		b.restarts = append(b.restarts, uint32(len(b.buf)))
+		b.counter = 0
+	}
+	b.buf = appendUvarint(b.buf, uint64(shared))
+	b.buf = appendUvarint(b.buf, uint64(len(key)-shared))
+	b.buf = appendUvarint(b.buf, uint64(len(value)))
+	b.buf = append(b.buf, key[shared:]...)
+	b.buf = append(b.buf, value...)
+	b.lastKey = append(b.lastKey[:0], key...)
+	b.counter++
+	b.entries++
+}
+
+func (b *sstBlock) size() int {
+	return len(b.buf) + 4*len(b.restarts) + 4
+}
+
+// finish appends the restart points to the entries, and returns the contents of the block. They
+// are only valid until the next reset.
+func (b *sstBlock) finish() []byte {
+	for _, r := range b.restarts {
+		b.buf = appendFixed32(b.buf, r)
+	}
+	return appendFixed32(b.buf, uint32(len(b.restarts)))
+}
+
+func appendFixed32(dst []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(dst, buf[:]...)
+}
+
+func appendFixed64(dst []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(dst, buf[:]...)
+}
+
+// sstWriter writes the sorted entries to an SST file. The keys must be added in strictly
+// increasing order.
+type sstWriter struct {
+	w        io.Writer
+	compress bool
+	offset   uint64
+	data     *sstBlock
+	index    *sstBlock
+	// lastKey is the internal key of the last entry added.
+	lastKey []byte
+
+	numEntries, numDataBlocks uint64
+	rawKeySize, rawValueSize  uint64
+	dataSize, indexSize       uint64
+}
+
+// newSSTWriter returns a writer of an SST file to w. The data blocks are compressed with snappy
+// if compress is set.
+func newSSTWriter(w io.Writer, compress bool) *sstWriter {
+	return &sstWriter{
+		w:        w,
+		compress: compress,
+		data:     newSSTBlock(sstRestartInterval),
+		// The index blocks of RocksDB have a restart point at every entry.
+		index: newSSTBlock(1),
+	}
+}
+
+// add adds the entry of the user key.
+func (sw *sstWriter) add(key, value []byte) error {
+	ikey := appendFixed64(append([]byte{}, key...), sstTypeValue)
+	if len(sw.lastKey) > 0 && bytes.Compare(ikey[:len(ikey)-8],
+		sw.lastKey[:len(sw.lastKey)-8]) <= 0 {
+		return errors.Errorf("key %x is not greater than the previous key", key)
+	}
+	sw.data.add(ikey, value)
+	sw.lastKey = ikey
+	sw.numEntries++
+	sw.rawKeySize += uint64(len(ikey))
+	sw.rawValueSize += uint64(len(value))
+	if sw.data.size() >= sstBlockSize {
+		return sw.flush()
+	}
+	return nil
+}
+
+// flush writes the data block, and adds it to the index. The key of its index entry is the last
+// key of the block, which is not shortened like RocksDB does, as any separator will do.
+func (sw *sstWriter) flush() error {
+	if sw.data.entries == 0 {
+		return nil
+	}
+	h, err := sw.writeBlock(sw.data.finish(), sw.compress)
+	if err != nil {
+		return err
+	}
+	sw.index.add(sw.lastKey, h.encode(nil))
+	sw.data.reset()
+	sw.numDataBlocks++
+	sw.dataSize = sw.offset
+	return nil
+}
+
+// writeBlock writes the contents of a block and its trailer. The block is only kept compressed
+// if that saves at least 1/8 of its size, like RocksDB does.
+func (sw *sstWriter) writeBlock(contents []byte, compress bool) (sstHandle, error) {
+	typ := byte(sstNoCompression)
+	if compress {
+		if c := snappy.Encode(nil, contents); len(c) < len(contents)-len(contents)/8 {
+			contents, typ = c, sstSnappyCompression
+		}
+	}
+	// The checksum covers the compression type as well. It is masked like the ones of the
+	// snappy chunks.
+	trailer := []byte{typ}
+	trailer = appendFixed32(trailer, snappyChecksum(append(append([]byte{}, contents...), typ)))
+	h := sstHandle{offset: sw.offset, size: uint64(len(contents))}
+	if _, err := sw.w.Write(contents); err != nil {
+		return h, err
+	}
+	if _, err := sw.w.Write(trailer); err != nil {
+		return h, err
+	}
+	sw.offset += uint64(len(contents)) + sstBlockTrailerLen
+	return h, nil
+}
+
+// finish writes the last data block, the index, the properties, the meta index and the footer.
+func (sw *sstWriter) finish() error {
+	if err := sw.flush(); err != nil {
+		return err
+	}
+	indexHandle, err := sw.writeBlock(sw.index.finish(), false)
+	if err != nil {
+		return errors.Wrap(err, "while writing the index block")
+	}
+	sw.indexSize = indexHandle.size
+
+	propsHandle, err := sw.writeBlock(sw.properties(), false)
+	if err != nil {
+		return errors.Wrap(err, "while writing the properties block")
+	}
+	meta := newSSTBlock(1)
+	meta.add([]byte("rocksdb.properties"), propsHandle.encode(nil))
+	metaHandle, err := sw.writeBlock(meta.finish(), false)
+	if err != nil {
+		return errors.Wrap(err, "while writing the meta index block")
+	}
+
+	footer := []byte{sstChecksumCRC32c}
+	footer = metaHandle.encode(footer)
+	footer = indexHandle.encode(footer)
+	footer = append(footer, make([]byte, 1+2*sstMaxHandleLen-len(footer))...)
+	footer = appendFixed32(footer, sstFormatVersion)
+	footer = appendFixed64(footer, sstMagic)
+	_, err = sw.w.Write(footer)
+	return errors.Wrap(err, "while writing the footer")
+}
+
+// properties returns the contents of the properties block. The external_sst_file properties are
+// the ones which RocksDB requires to ingest a file.
+func (sw *sstWriter) properties() []byte {
+	compression := "NoCompression"
+	if sw.compress {
+		compression = "Snappy"
+	}
+	props := map[string][]byte{
+		"rocksdb.comparator":                     []byte("leveldb.BytewiseComparator"),
+		"rocksdb.compression":                    []byte(compression),
+		"rocksdb.data.size":                      appendUvarint(nil, sw.dataSize),
+		"rocksdb.index.size":                     appendUvarint(nil, sw.indexSize),
+		"rocksdb.num.data.blocks":                appendUvarint(nil, sw.numDataBlocks),
+		"rocksdb.num.entries":                    appendUvarint(nil, sw.numEntries),
+		"rocksdb.raw.key.size":                   appendUvarint(nil, sw.rawKeySize),
+		"rocksdb.raw.value.size":                 appendUvarint(nil, sw.rawValueSize),
+		"rocksdb.external_sst_file.version":      appendFixed32(nil, 2),
+		"rocksdb.external_sst_file.global_seqno": appendFixed64(nil, 0),
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b := newSSTBlock(1)
+	for _, name := range names {
+		b.add([]byte(name), props[name])
+	}
+	return b.finish()
+}
+
+// writeSSTFile writes the entries passed to fn by iterate to a new SST file, instead of a map
+// file. Only the first entry of a key is written, which is the one of its latest version, as
+// the reduce phase would keep it.
+func (m *mapper) writeSSTFile(iterate func(fn func([]byte) error) error, backupNum uint64,
+	set mapFileSet) (rerr error) {
+	mf, err := m.newMapFile(backupNum, set)
+	if err != nil {
+		return errors.Wrap(err, "openOutputFile")
+	}
+	if m.opts.VerifyChecksums {
+		mf.h = sha256.New()
+	}
+	defer func() {
+		if rerr != nil {
+			mf.abort(rerr)
+		}
+	}()
+
+	bw := bufio.NewWriterSize(mf, sstBlockSize<<4)
+	sw := newSSTWriter(bw, !m.opts.UncompressedMapFiles)
+	var lastKey []byte
+	err = iterate(func(slice []byte) error {
+		me := mapEntry(slice)
+		key := y.ParseKey(me.Key())
+		if lastKey != nil && bytes.Equal(key, lastKey) {
+			return nil
+		}
+		lastKey = append(lastKey[:0], key...)
+		return sw.add(key, me.Data())
+	})
+	if err != nil {
+		return errors.Wrap(err, "while adding the entries to the SST file")
+	}
+	if err := sw.finish(); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "while flushing the SST file")
+	}
+	return m.finishMapFile(mf, backupNum, set, 0)
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+type sstEntry struct {
+	key, value []byte
+}
+
+// readSSTFile reads the entries and the properties of an SST file, checking the checksums of
+// its blocks. The keys of the entries are the internal keys.
+func readSSTFile(t *testing.T, file string) ([]sstEntry, map[string][]byte) {
+	b, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	require.Greater(t, len(b), sstFooterLen)
+	footer := b[len(b)-sstFooterLen:]
+	require.Equal(t, byte(sstChecksumCRC32c), footer[0])
+	require.Equal(t, uint32(sstFormatVersion),
+		binary.LittleEndian.Uint32(footer[sstFooterLen-12:]))
+	require.Equal(t, uint64(sstMagic), binary.LittleEndian.Uint64(footer[sstFooterLen-8:]))
+
+	handle := func(buf []byte) (sstHandle, []byte) {
+		offset, n := binary.Uvarint(buf)
+		require.Greater(t, n, 0)
+		size, m := binary.Uvarint(buf[n:])
+		require.Greater(t, m, 0)
+		return sstHandle{offset: offset, size: size}, buf[n+m:]
+	}
+	block := func(h sstHandle) []byte {
+		contents := b[h.offset : h.offset+h.size]
+		typ := b[h.offset+h.size]
+		sum := binary.LittleEndian.Uint32(b[h.offset+h.size+1:])
+		require.Equal(t, snappyChecksum(append(append([]byte{}, contents...), typ)), sum)
+		if typ == sstSnappyCompression {
+			contents, err = snappy.Decode(nil, contents)
+			require.NoError(t, err)
+		} else {
+			require.Equal(t, byte(sstNoCompression), typ)
+		}
+		return contents
+	}
+	entries := func(contents []byte) []sstEntry {
+		restarts := binary.LittleEndian.Uint32(contents[len(contents)-4:])
+		data := contents[:len(contents)-4-4*int(restarts)]
+		var out []sstEntry
+		var key []byte
+		for len(data) > 0 {
+			shared, n1 := binary.Uvarint(data)
+			nonShared, n2 := binary.Uvarint(data[n1:])
+			valueLen, n3 := binary.Uvarint(data[n1+n2:])
+			data = data[n1+n2+n3:]
+			key = append(append([]byte{}, key[:shared]...), data[:nonShared]...)
+			out = append(out, sstEntry{key: key, value: data[nonShared : nonShared+valueLen]})
+			data = data[nonShared+valueLen:]
+		}
+		return out
+	}
+
+	metaHandle, rest := handle(footer[1:])
+	indexHandle, _ := handle(rest)
+	props := make(map[string][]byte)
+	for _, meta := range entries(block(metaHandle)) {
+		require.Equal(t, "rocksdb.properties", string(meta.key))
+		h, _ := handle(meta.value)
+		for _, e := range entries(block(h)) {
+			props[string(e.key)] = e.value
+		}
+	}
+	var out []sstEntry
+	for _, idx := range entries(block(indexHandle)) {
+		h, _ := handle(idx.value)
+		data := entries(block(h))
+		// The key of the index entry is the last key of the block.
+		require.Equal(t, idx.key, data[len(data)-1].key)
+		out = append(out, data...)
+	}
+	return out, props
+}
+
+func TestMapFileFormatSST(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-sst")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Every KV is in the stream twice, and only written once to a file.
+	const numUids = 500
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= numUids; uid++ {
+		kv := nsEdgeKV(t, x.GalaxyNamespace, "name", uid)
+		appendKVList(t, &stream, kv, kv)
+	}
+	in := &loadBackupInput{preds: predicateSet{x.GalaxyAttr("name"): struct{}{}}, groupId: 1}
+	opts := MapOptions{MapFileFormat: mapFormatSST}
+	require.NoError(t, opts.validate())
+	m := newMapper(10, dir, opts, 2)
+	m.startPipeline(2)
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.NoError(t, m.stopPipeline())
+
+	files, _, err := mapFiles(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+	infos := m.writtenFiles()
+	require.NotEmpty(t, infos)
+
+	// The keys of a file are strictly increasing, so a key is only written once per file.
+	seen := make(map[string]struct{})
+	var dataBlocks uint64
+	for _, info := range infos {
+		require.Equal(t, sstFileExt, filepath.Ext(info.Name))
+		entries, props := readSSTFile(t, filepath.Join(dir, info.Name))
+		require.Equal(t, appendUvarint(nil, uint64(len(entries))),
+			props["rocksdb.num.entries"])
+		require.Equal(t, appendFixed32(nil, 2), props["rocksdb.external_sst_file.version"])
+		n, _ := binary.Uvarint(props["rocksdb.num.data.blocks"])
+		dataBlocks += n
+		for i, e := range entries {
+			key, trailer := e.key[:len(e.key)-8], e.key[len(e.key)-8:]
+			require.Equal(t, appendFixed64(nil, sstTypeValue), trailer)
+			if i > 0 {
+				require.Less(t, bytes.Compare(entries[i-1].key, e.key), 0)
+			}
+			pk, err := x.Parse(key)
+			require.NoError(t, err)
+			require.Equal(t, x.GalaxyAttr("name"), pk.Attr)
+			var kv bpb.KV
+			require.NoError(t, kv.Unmarshal(e.value))
+			require.Equal(t, uint64(10), kv.Version)
+			seen[string(key)] = struct{}{}
+		}
+	}
+	require.Len(t, seen, numUids)
+	require.Greater(t, dataBlocks, uint64(len(infos)))
+
+	for _, opts := range []MapOptions{
+		{MapFileFormat: "parquet"},
+		{MapFileFormat: mapFormatSST, MapFileCompression: mapCodecGzip},
+		{MapFileFormat: mapFormatSST, VerifyStore: true},
+	} {
+		require.Error(t, opts.validate())
+	}
+}
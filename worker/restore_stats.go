@@ -0,0 +1,177 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/golang/glog"
+)
+
+// The values of MapOptions.StatsFormat.
+const (
+	statsFormatCSV   = "csv"
+	statsFormatJSONL = "jsonl"
+)
+
+// mapStatsColumns is the header of the CSV stats file.
+var mapStatsColumns = []string{"time", "bytes_read", "bytes_processed", "rate", "writers",
+	"alloc_bytes"}
+
+// mapStatsRow is a row of the stats file, see MapOptions.StatsFile.
+type mapStatsRow struct {
+	Time           time.Time `json:"time"`
+	BytesRead      uint64    `json:"bytes_read"`
+	BytesProcessed uint64    `json:"bytes_processed"`
+	Rate           uint64    `json:"rate"`
+	Writers        int       `json:"writers"`
+	AllocBytes     uint64    `json:"alloc_bytes"`
+}
+
+// mapStats appends the rows of the stats file. It is only used by the Progress goroutine.
+type mapStats struct {
+	name   string
+	format string
+	f      *os.File
+	// cw is the writer of the CSV rows. It is nil for JSONL.
+	cw *csv.Writer
+}
+
+// openStatsFile opens the stats file, if MapOptions.StatsFile is set. It returns nil if it is
+// not set, or if it can't be opened, which is only logged, as the stats must never abort the
+// restore.
+func (m *mapper) openStatsFile() *mapStats {
+	file := m.opts.StatsFile
+	if file == "" {
+		return nil
+	}
+	if !filepath.IsAbs(file) {
+		if m.mapStore != nil {
+			glog.Warningf("%sNot writing the stats file: %s, as the map directory is remote."+
+				" Use an absolute local path", m.logPrefix, file)
+			return nil
+		}
+		if m.reqId != "" {
+			file = m.reqId + "-" + file
+		}
+		file = filepath.Join(m.mapDir, file)
+		if err := os.MkdirAll(m.mapDir, 0750); err != nil {
+			glog.Warningf("%sUnable to open the stats file: %s. Err: %v", m.logPrefix, file, err)
+			return nil
+		}
+	}
+	s, err := openMapStats(file, m.opts.StatsFormat)
+	if err != nil {
+		glog.Warningf("%sUnable to open the stats file: %s. Err: %v", m.logPrefix, file, err)
+		return nil
+	}
+	return s
+}
+
+// openMapStats opens the stats file for appending, so that a resumed map phase continues the
+// series. The CSV header is only written to an empty file.
+func openMapStats(file, format string) (*mapStats, error) {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s := &mapStats{name: file, format: format, f: f}
+	if format == statsFormatJSONL {
+		return s, nil
+	}
+	s.cw = csv.NewWriter(f)
+	fi, err := f.Stat()
+	if err == nil && fi.Size() == 0 {
+		s.cw.Write(mapStatsColumns)
+		s.cw.Flush()
+		err = s.cw.Error()
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// write appends the row to the file. Every row is flushed, so that the file can be read while
+// the map phase is running.
+func (s *mapStats) write(row *mapStatsRow) error {
+	if s.cw == nil {
+		b, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		_, err = s.f.Write(append(b, '\n'))
+		return err
+	}
+	s.cw.Write([]string{
+		row.Time.Format(time.RFC3339Nano),
+		strconv.FormatUint(row.BytesRead, 10),
+		strconv.FormatUint(row.BytesProcessed, 10),
+		strconv.FormatUint(row.Rate, 10),
+		strconv.Itoa(row.Writers),
+		strconv.FormatUint(row.AllocBytes, 10),
+	})
+	s.cw.Flush()
+	return s.cw.Error()
+}
+
+// close closes the file. It does nothing on a nil mapStats.
+func (s *mapStats) close() {
+	if s == nil {
+		return
+	}
+	if err := s.f.Close(); err != nil {
+		glog.Warningf("Unable to close the stats file: %s. Err: %v", s.name, err)
+	}
+}
+
+// HistogramSnapshot is a copy of the buckets of a histogram. Counts has one more element than
+// Bounds. Counts[i] is the number of values below Bounds[i] and not below Bounds[i-1]. The last
+// one is the number of values not below the last bound.
+type HistogramSnapshot struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []int64   `json:"counts"`
+	Count  int64     `json:"count"`
+	Min    int64     `json:"min"`
+	Max    int64     `json:"max"`
+	Sum    int64     `json:"sum"`
+}
+
+func newHistogramSnapshot(h *z.HistogramData) *HistogramSnapshot {
+	return &HistogramSnapshot{
+		Bounds: append([]float64{}, h.Bounds...),
+		Counts: append([]int64{}, h.CountPerBucket...),
+		Count:  h.Count,
+		Min:    h.Min,
+		Max:    h.Max,
+		Sum:    h.Sum,
+	}
+}
+
+// InputSizeHist returns a snapshot of the histogram of the sizes of the KV lists read so far.
+func (m *mapper) InputSizeHist() *HistogramSnapshot {
+	m.szHistMu.Lock()
+	defer m.szHistMu.Unlock()
+	return newHistogramSnapshot(m.szHist)
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-stats")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// A file name is written to the map directory, with the prefix of the request id. The
+	// rows are appended, and the header is only written once.
+	now := time.Now().UTC()
+	for i := 0; i < 2; i++ {
+		m := newMapper(10, dir, MapOptions{StatsFile: "stats.csv"}, 2)
+		m.setRequestId("req")
+		s := m.openStatsFile()
+		require.NotNil(t, s)
+		require.NoError(t, s.write(&mapStatsRow{Time: now, BytesRead: 100, BytesProcessed: 80,
+			Rate: 40, Writers: 1, AllocBytes: 1 << 20}))
+		s.close()
+	}
+	f, err := os.Open(filepath.Join(dir, "req-stats.csv"))
+	require.NoError(t, err)
+	rows, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	f.Close()
+	require.Len(t, rows, 3)
+	require.Equal(t, mapStatsColumns, rows[0])
+	require.Equal(t, []string{now.Format(time.RFC3339Nano), "100", "80", "40", "1", "1048576"},
+		rows[2])
+
+	// Progress writes a JSONL row every second, and a last one once the map phase is over.
+	file := filepath.Join(dir, "stats", "stats.jsonl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0750))
+	opts := MapOptions{StatsFile: file, StatsFormat: statsFormatJSONL}
+	require.NoError(t, opts.validate())
+	m := newMapper(10, dir, opts, 2)
+	atomic.StoreUint64(&m.bytesRead, 300)
+	go m.Progress()
+	m.closer.SignalAndWait()
+	b, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(b), []byte("\n"))
+	var row mapStatsRow
+	require.NoError(t, json.Unmarshal(lines[len(lines)-1], &row))
+	require.Equal(t, uint64(300), row.BytesRead)
+
+	// A failure to open the stats file is only logged.
+	m = newMapper(10, dir, MapOptions{StatsFile: filepath.Join(dir, "missing", "stats")}, 2)
+	require.Nil(t, m.openStatsFile())
+	go m.Progress()
+	m.closer.SignalAndWait()
+
+	for _, opts := range []MapOptions{
+		{StatsFormat: statsFormatJSONL},
+		{StatsFile: "stats", StatsFormat: "xml"},
+	} {
+		require.Error(t, opts.validate())
+	}
+}
+
+func TestReadRateHist(t *testing.T) {
+	// The histogram is owned by the mapper, so only a snapshot of it is kept.
+	var final *HistogramSnapshot
+	opts := MapOptions{OnProgress: func(p *MapProgress) {
+		if p.Final {
+			final = newHistogramSnapshot(p.ReadRateHist)
+		}
+	}}
+	m := newMapper(10, "", opts, 2)
+	atomic.StoreUint64(&m.bytesRead, 1<<20)
+	go m.Progress()
+	m.closer.SignalAndWait()
+
+	require.NotNil(t, final)
+	require.Equal(t, int64(1), final.Count)
+	require.Greater(t, final.Max, int64(0))
+	require.Equal(t, final.Max, final.Sum)
+}
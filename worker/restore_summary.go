@@ -0,0 +1,163 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// mapSummaryName is the name of the summary of the map phase written in the map directory.
+const mapSummaryName = "restore-map-summary.json"
+
+// mapSummary is the report of a map phase written with MapOptions.WriteSummary.
+type mapSummary struct {
+	Version     string                `json:"version"`
+	Request     mapSummaryRequest     `json:"request"`
+	Manifests   []mapSummaryBackup    `json:"manifests"`
+	MaxUid      uint64                `json:"max_uid"`
+	MaxNs       uint64                `json:"max_ns"`
+	Drops       mapSummaryDrops       `json:"drops"`
+	MapFiles    int                   `json:"map_files"`
+	MapBytes    int64                 `json:"map_bytes"`
+	Duration    string                `json:"duration"`
+	DurationMs  int64                 `json:"duration_ms"`
+	Concurrency mapSummaryConcurrency `json:"concurrency"`
+	// UnreadableFiles are the backup files skipped with MapOptions.SkipUnreadableFiles.
+	UnreadableFiles []string `json:"unreadable_files,omitempty"`
+	// Namespaces are the namespaces collected with MapOptions.CollectNamespaces.
+	Namespaces []uint64 `json:"namespaces,omitempty"`
+}
+
+// mapSummaryRequest is the restore request, without the credentials and the secrets.
+type mapSummaryRequest struct {
+	GroupId         uint32 `json:"group_id"`
+	RestoreTs       uint64 `json:"restore_ts"`
+	Location        string `json:"location"`
+	BackupId        string `json:"backup_id,omitempty"`
+	BackupNum       uint64 `json:"backup_num,omitempty"`
+	IncrementalFrom uint64 `json:"incremental_from,omitempty"`
+	IsPartial       bool   `json:"is_partial,omitempty"`
+	RequestId       string `json:"request_id,omitempty"`
+	// Encrypted is set if a key file or a vault was given to decrypt the backups.
+	Encrypted bool `json:"encrypted"`
+}
+
+// mapSummaryBackup is a manifest processed by the map phase.
+type mapSummaryBackup struct {
+	BackupNum uint64 `json:"backup_num"`
+	Path      string `json:"path"`
+	Type      string `json:"type"`
+	ReadTs    uint64 `json:"read_ts"`
+}
+
+// mapSummaryDrops are the drop operations found in the manifests processed.
+type mapSummaryDrops struct {
+	DropAll    bool     `json:"drop_all"`
+	Attrs      []string `json:"attrs,omitempty"`
+	Namespaces []uint64 `json:"namespaces,omitempty"`
+}
+
+// mapSummaryConcurrency is the effective concurrency of the map phase.
+type mapSummaryConcurrency struct {
+	Processors int `json:"processors"`
+	Mergers    int `json:"mergers"`
+	Writers    int `json:"writers"`
+}
+
+// sanitizeLocation removes the user info, the query and the fragment from the location, as
+// they can hold credentials.
+func sanitizeLocation(location string) string {
+	uri, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+	uri.User = nil
+	uri.RawQuery = ""
+	uri.Fragment = ""
+	return uri.String()
+}
+
+// newMapSummary returns the summary of the map phase of req, which mapped the manifests.
+func (m *mapper) newMapSummary(req *pb.RestoreRequest, manifests []*Manifest, res *mapResult,
+	numGo int, took time.Duration) *mapSummary {
+	s := &mapSummary{
+		Version: x.Version(),
+		Request: mapSummaryRequest{
+			GroupId:         req.GroupId,
+			RestoreTs:       req.RestoreTs,
+			Location:        sanitizeLocation(req.Location),
+			BackupId:        req.BackupId,
+			BackupNum:       req.BackupNum,
+			IncrementalFrom: req.IncrementalFrom,
+			IsPartial:       req.IsPartial,
+			RequestId:       req.RequestId,
+			Encrypted:       req.EncryptionKeyFile != "" || req.VaultAddr != "",
+		},
+		Manifests:  []mapSummaryBackup{},
+		MaxUid:     res.maxUid,
+		MaxNs:      res.maxNs,
+		Drops:      mapSummaryDrops{DropAll: res.shouldDropAll},
+		Duration:   took.String(),
+		DurationMs: took.Milliseconds(),
+
+		UnreadableFiles: res.unreadableFiles,
+		Namespaces:      res.namespaces,
+	}
+	for _, manifest := range manifests {
+		s.Manifests = append(s.Manifests, mapSummaryBackup{
+			BackupNum: manifest.BackupNum,
+			Path:      manifest.Path,
+			Type:      manifest.Type,
+			ReadTs:    manifest.ValidReadTs(),
+		})
+	}
+	for attr := range res.dropAttr {
+		s.Drops.Attrs = append(s.Drops.Attrs, attr)
+	}
+	sort.Strings(s.Drops.Attrs)
+	for ns := range res.dropNs {
+		s.Drops.Namespaces = append(s.Drops.Namespaces, ns)
+	}
+	sort.Slice(s.Drops.Namespaces, func(i, j int) bool {
+		return s.Drops.Namespaces[i] < s.Drops.Namespaces[j]
+	})
+	for _, info := range m.writtenFiles() {
+		s.MapFiles++
+		s.MapBytes += info.Size
+	}
+	s.Concurrency = mapSummaryConcurrency{
+		Processors: numGo,
+		Mergers:    m.opts.MergeConcurrency,
+		Writers:    m.opts.WriteConcurrency,
+	}
+	return s
+}
+
+// writeMapSummary writes the summary to the map directory.
+func (m *mapper) writeMapSummary(s *mapSummary) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return m.writeMapDirFile(mapSummaryName, b)
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// VerifyStoreResult is the result of restoring the map files into a throwaway store.
+type VerifyStoreResult struct {
+	// WrittenKeys is the number of keys written by the reduce phase, and StoredKeys is the
+	// number of keys found in the store once it is reopened.
+	WrittenKeys uint64
+	StoredKeys  uint64
+}
+
+// countingWriter counts the keys written to a Writer.
+type countingWriter struct {
+	w    Writer
+	keys uint64
+}
+
+func (cw *countingWriter) Write(buf *z.Buffer) error {
+	if err := buf.SliceIterate(func([]byte) error {
+		cw.keys++
+		return nil
+	}); err != nil {
+		return err
+	}
+	return cw.w.Write(buf)
+}
+
+// verifyStore reduces the map files in mapDir into a temporary badger store, reopens the store
+// and checks that it holds all the keys written. The store is removed afterwards. It exercises
+// the write path of a restore without touching the store of the cluster. The map files are
+// left as they are.
+func verifyStore(mapDir string) (*VerifyStoreResult, error) {
+	dir, err := ioutil.TempDir(x.WorkerConfig.TmpDir, "restore-verify")
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating the directory of the verification store")
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			glog.Errorf("Unable to remove the verification store: %s. Err: %v", dir, err)
+		}
+	}()
+
+	opts := badger.DefaultOptions(dir).
+		WithSyncWrites(false).
+		WithNumVersionsToKeep(math.MaxInt32).
+		WithNamespaceOffset(x.NamespaceOffset).
+		WithExternalMagic(x.MagicVersion).
+		WithLogger(nil)
+	db, err := badger.OpenManaged(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "while opening the verification store")
+	}
+	res := &VerifyStoreResult{}
+	err = func() error {
+		defer db.Close()
+		sw := db.NewStreamWriter()
+		if err := sw.Prepare(); err != nil {
+			return errors.Wrap(err, "while preparing the verification store")
+		}
+		cw := &countingWriter{w: sw}
+		if err := RunReducer(cw, mapDir); err != nil {
+			return errors.Wrap(err, "while reducing into the verification store")
+		}
+		if err := sw.Flush(); err != nil {
+			return errors.Wrap(err, "while flushing the verification store")
+		}
+		res.WrittenKeys = cw.keys
+		return nil
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	// Reopen the store, to check that what was written can be read back.
+	db, err = badger.OpenManaged(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "while reopening the verification store")
+	}
+	defer db.Close()
+	txn := db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+	iopt := badger.DefaultIteratorOptions
+	iopt.PrefetchValues = false
+	// All the versions are iterated over, so that the keys which badger considers deleted or
+	// expired are counted as well. They were written all the same.
+	iopt.AllVersions = true
+	itr := txn.NewIterator(iopt)
+	defer itr.Close()
+	var lastKey []byte
+	for itr.Rewind(); itr.Valid(); itr.Next() {
+		key := itr.Item().Key()
+		if bytes.Equal(key, lastKey) {
+			continue
+		}
+		lastKey = append(lastKey[:0], key...)
+		res.StoredKeys++
+	}
+
+	if res.StoredKeys != res.WrittenKeys {
+		return res, errors.Errorf("the verification store holds %d keys, but %d were written",
+			res.StoredKeys, res.WrittenKeys)
+	}
+	return res, nil
+}
+
+// verifySorted checks that the entries of the buffer are ordered by cmp, and returns an error
+// with the first pair of adjacent keys out of order. Equal keys are allowed, as the same version
+// of a key can be read from more than one backup.
+func verifySorted(buf *z.Buffer, cmp func(a, b []byte) int) error {
+	var last []byte
+	return buf.SliceIterate(func(slice []byte) error {
+		key := mapEntry(slice).Key()
+		if last != nil && cmp(last, key) > 0 {
+			return errors.Errorf("map entries are out of order after sorting: key %x is"+
+				" followed by key %x", last, key)
+		}
+		last = key
+		return nil
+	})
+}
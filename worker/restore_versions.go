@@ -0,0 +1,96 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+
+	bpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/dgraph-io/ristretto/z"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// preservedVersion returns the version the entry read at version is written at with
+// PreserveVersions. It is shifted by VersionOffset, and clamped to [1, restoreTs).
+func (m *mapper) preservedVersion(version uint64) uint64 {
+	v := int64(version) + m.opts.VersionOffset
+	switch {
+	case v < 1:
+		atomic.AddUint64(&m.clampedVersions, 1)
+		return 1
+	case uint64(v) >= m.restoreTs:
+		atomic.AddUint64(&m.clampedVersions, 1)
+		return m.restoreTs - 1
+	}
+	return uint64(v)
+}
+
+// restoreTsDir is the subdirectory of the map files of the restore timestamp with
+// RestoreTsSets.
+func restoreTsDir(ts uint64) string {
+	return fmt.Sprintf("ts%d", ts)
+}
+
+// predicateWriteTs returns the version the keys of the namespaced predicate are written at, and
+// whether it is overridden with PredicateRestoreTs.
+func (m *mapper) predicateWriteTs(attr string) (uint64, bool) {
+	if len(m.opts.PredicateRestoreTs) > 0 {
+		_, attr = x.ParseNamespaceAttr(attr)
+		if ts, ok := m.opts.PredicateRestoreTs[attr]; ok {
+			return ts, true
+		}
+	}
+	return m.restoreTs, false
+}
+
+// checkVersion checks that the version the key was read at is below the version it is written
+// at.
+func (m *mapper) checkVersion(e *backupKV, key []byte, version uint64) error {
+	if m.opts.VerifyVersions && version >= m.restoreTs {
+		// The entries are sorted by their original version to pick the latest one in the
+		// reduce phase, and then written at restoreTs. That only works if all the original
+		// versions are below restoreTs.
+		return errors.Errorf("version %d of key %s is not below restoreTs %d",
+			version, hex.Dump(key), m.restoreTs)
+	}
+	if e.overridden && version >= e.writeTs {
+		// The same goes for the version the predicate is written at.
+		return errors.Errorf("version %d of key %s is not below its PredicateRestoreTs %d",
+			version, hex.Dump(key), e.writeTs)
+	}
+	return nil
+}
+
+// appendToSets appends the map entry of the KV to the buffer once per set of RestoreTsSets, at
+// the timestamp of the set.
+func (m *mapper) appendToSets(buf *z.Buffer, key []byte, kv *bpb.KV, version uint64) error {
+	for _, ts := range m.opts.RestoreTsSets {
+		if m.opts.VerifyVersions && version >= ts {
+			return errors.Errorf("version %d of key %s is not below the restore"+
+				" timestamp %d of its set", version, hex.Dump(kv.Key), ts)
+		}
+		kv.Version = ts
+		if err := appendMapEntry(buf, key, kv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// MapWaits is the time spent blocked at each stage of the map pipeline, summed over all the
+// goroutines of the stage.
+type MapWaits struct {
+	// ReqCh is the time the processors spent waiting for the KV lists read from the backups.
+	ReqCh time.Duration
+	// WriteCh is the time the processors spent handing their buffers over to the mergers.
+	WriteCh time.Duration
+	// Writers is the time the mergers spent waiting for a slot to write a map file.
+	Writers time.Duration
+	// Disk is the time the mergers spent writing the map files.
+	Disk time.Duration
+}
+
+func (w MapWaits) String() string {
+	return fmt.Sprintf("req_ch: %s, write_ch: %s, writers: %s, disk: %s",
+		w.ReqCh.Round(time.Millisecond), w.WriteCh.Round(time.Millisecond),
+		w.Writers.Round(time.Millisecond), w.Disk.Round(time.Millisecond))
+}
+
+// The stages of the pipeline whose waits are tracked in mapper.waits.
+const (
+	waitReqCh = iota
+	waitWriteCh
+	waitWriters
+	waitDisk
+	numWaitStages
+)
+
+// addWait adds the time since start to the time spent blocked at the stage.
+func (m *mapper) addWait(stage int, start time.Time) {
+	atomic.AddInt64(&m.waits[stage], int64(time.Since(start)))
+}
+
+// pipelineWaits returns the time spent blocked at each stage so far.
+func (m *mapper) pipelineWaits() MapWaits {
+	load := func(stage int) time.Duration {
+		return time.Duration(atomic.LoadInt64(&m.waits[stage]))
+	}
+	return MapWaits{
+		ReqCh:   load(waitReqCh),
+		WriteCh: load(waitWriteCh),
+		Writers: load(waitWriters),
+		Disk:    load(waitDisk),
+	}
+}
+
+// acquireWriter takes a slot in m.writers. The clock is only read if no slot is free.
+func (m *mapper) acquireWriter() {
+	select {
+	case m.writers <- struct{}{}:
+		return
+	default:
+	}
+	start := time.Now()
+	m.writers <- struct{}{}
+	m.addWait(waitWriters, start)
+}
+
+// logWaits logs the time spent blocked at each stage, along with its share of the time of the
+// goroutines of the stage, so that the concurrency of the bottleneck can be tuned.
+func (m *mapper) logWaits(elapsed time.Duration) {
+	w := m.pipelineWaits()
+	share := func(d time.Duration, goroutines int) float64 {
+		total := elapsed * time.Duration(goroutines)
+		if total <= 0 {
+			return 0
+		}
+		return 100 * float64(d) / float64(total)
+	}
+	procs, mergers := m.numProcessors, m.opts.MergeConcurrency
+	glog.Infof("%sTime blocked in the map pipeline. Processors waiting for KV lists: %s"+
+		" (%.0f%%), sending to the mergers: %s (%.0f%%). Mergers waiting for writer slots: %s"+
+		" (%.0f%%), writing map files: %s (%.0f%%)", m.logPrefix,
+		w.ReqCh.Round(time.Millisecond), share(w.ReqCh, procs),
+		w.WriteCh.Round(time.Millisecond), share(w.WriteCh, procs),
+		w.Writers.Round(time.Millisecond), share(w.Writers, mergers),
+		w.Disk.Round(time.Millisecond), share(w.Disk, mergers))
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2021 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestPipelineWaits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore-waits")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	m := newMapper(10, dir, MapOptions{WriteConcurrency: 1}, 2)
+	// A free writer slot is taken without waiting.
+	m.acquireWriter()
+	require.Zero(t, m.pipelineWaits().Writers)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-m.writers
+	}()
+	m.acquireWriter()
+	require.GreaterOrEqual(t, m.pipelineWaits().Writers, 20*time.Millisecond)
+	<-m.writers
+
+	var stream bytes.Buffer
+	for uid := uint64(1); uid <= 100; uid++ {
+		appendKVList(t, &stream, nsEdgeKV(t, x.GalaxyNamespace, "name", uid))
+	}
+	in := &loadBackupInput{
+		preds:   predicateSet{x.GalaxyAttr("name"): struct{}{}},
+		groupId: 1,
+	}
+	m.startPipeline(2)
+	// The processors wait for the KV lists until Map starts.
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, m.Map(bytes.NewReader(stream.Bytes()), in))
+	require.NoError(t, m.stopPipeline())
+	waits := m.pipelineWaits()
+	require.GreaterOrEqual(t, waits.ReqCh, 10*time.Millisecond)
+	require.Greater(t, waits.Disk, time.Duration(0))
+}
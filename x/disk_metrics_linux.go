@@ -45,3 +45,13 @@ func MonitorDiskMetrics(dirTag string, dir string, lc *z.Closer) {
 	}
 
 }
+
+// FreeDiskSpace returns the number of bytes available to unprivileged users on the file system
+// containing dir.
+func FreeDiskSpace(dir string) (uint64, error) {
+	s := syscall.Statfs_t{}
+	if err := syscall.Statfs(dir, &s); err != nil {
+		return 0, err
+	}
+	return uint64(s.Frsize) * s.Bavail, nil
+}
@@ -5,9 +5,15 @@ package x
 import (
 	"github.com/dgraph-io/ristretto/z"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 )
 
 func MonitorDiskMetrics(_ string, _ string, lc *z.Closer) {
 	defer lc.Done()
 	glog.Infoln("File system metrics are not currently supported on non-Linux platforms")
 }
+
+// FreeDiskSpace is only supported on Linux.
+func FreeDiskSpace(_ string) (uint64, error) {
+	return 0, errors.New("free disk space is not currently supported on non-Linux platforms")
+}
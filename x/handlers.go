@@ -66,6 +66,14 @@ type UriHandler interface {
 	Stream(path string) (io.ReadCloser, error)
 }
 
+// FileAborter is implemented by the writers returned by CreateFile which can discard the file
+// being written, instead of finalizing it on Close.
+type FileAborter interface {
+	// Abort discards the file and releases the resources of the writer. The writer must not be
+	// used after that.
+	Abort(err error) error
+}
+
 // NewUriHandler parses the requested URI and finds the corresponding UriHandler.
 // If the passed credentials are not nil, they will be used to override the
 // default credentials (only for backups to minio or S3).
@@ -277,7 +285,9 @@ func (h *s3Handler) ListPaths(path string) []string {
 type s3Writer struct {
 	pwriter    *io.PipeWriter
 	preader    *io.PipeReader
+	mc         *MinioClient
 	bucketName string
+	object     string
 	cerr       chan error
 }
 
@@ -294,6 +304,23 @@ func (sw *s3Writer) Close() error {
 	return <-sw.cerr
 }
 
+// Abort fails the upload, so that the object is not created. The parts of a multipart upload
+// which were already sent are removed.
+func (sw *s3Writer) Abort(err error) error {
+	if sw.pwriter == nil {
+		return nil
+	}
+	// PutObject fails as it gets this error instead of an EOF.
+	_ = sw.pwriter.CloseWithError(errors.Wrap(err, "upload aborted"))
+	uerr := <-sw.cerr
+	sw.pwriter = nil
+	if uerr == nil {
+		return errors.Errorf("upload of %s completed despite being aborted", sw.object)
+	}
+	return errors.Wrapf(sw.mc.RemoveIncompleteUpload(sw.bucketName, sw.object),
+		"while removing incomplete upload of %s", sw.object)
+}
+
 // upload will block until it's done or an error occurs.
 func (sw *s3Writer) upload(mc *MinioClient, object string) {
 	f := func() error {
@@ -329,7 +356,9 @@ func (h *s3Handler) CreateFile(path string) (io.WriteCloser, error) {
 	glog.V(2).Infof("Sending data to %s blob %q ...", h.uri.Scheme, objectPath)
 
 	sw := &s3Writer{
+		mc:         h.mc,
 		bucketName: h.bucketName,
+		object:     objectPath,
 		cerr:       make(chan error, 1),
 	}
 	sw.preader, sw.pwriter = io.Pipe()
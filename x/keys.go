@@ -74,6 +74,13 @@ func AttrFrom2103(attr string) (string, error) {
 	return NamespaceAttr(ns, pred), nil
 }
 
+// AttrTo2103 converts the attr to the format used by the 2103 release, which is
+// <namespace 8 bytes><attribute>. It is the inverse of AttrFrom2103.
+func AttrTo2103(attr string) string {
+	ns, pred := ParseNamespaceAttr(attr)
+	return string(NamespaceToBytes(ns)) + pred
+}
+
 func NamespaceToBytes(ns uint64) []byte {
 	buf := make([]byte, 8)
 	binary.BigEndian.PutUint64(buf, ns)